@@ -34,9 +34,31 @@ type KeyProfileSpec struct {
 	// Output defines how the generated key material is stored as a Kubernetes Secret.
 	Output OutputConfig `json:"output"`
 
+	// AdditionalOutputs writes the same key to further Secrets in the namespace,
+	// e.g. a TLS-typed split-pem Secret for an ingress next to an Opaque one for
+	// an application. SecretNames must be unique across Output and
+	// AdditionalOutputs; Immutable and KeepHistorySecrets are only supported on
+	// Output. The Secret of a removed entry is deleted on the next write.
+	// +optional
+	AdditionalOutputs []OutputConfig `json:"additionalOutputs,omitempty"`
+
 	// Publish defines optional targets where public keys are published.
 	// +optional
 	Publish []PublishTarget `json:"publish,omitempty"`
+
+	// Certificate requests a CA-signed certificate for the generated key via cert-manager.
+	// Requires the controller to run with --enable-certificates.
+	// +optional
+	Certificate *CertificateConfig `json:"certificate,omitempty"`
+
+	// DisableFingerprintStatus keeps public key fingerprints out of the status,
+	// hiding them from readers of the KeyProfile. The controller still computes
+	// fingerprints in memory, but without a recorded fingerprint the integrity
+	// check only verifies that the stored key pair is consistent: a Secret whose
+	// key pair was replaced wholesale is not detected, and external tooling
+	// cannot verify Secrets or published keys against the status.
+	// +optional
+	DisableFingerprintStatus bool `json:"disableFingerprintStatus,omitempty"`
 }
 
 // ServiceAccountReference identifies a Kubernetes ServiceAccount.
@@ -53,43 +75,140 @@ type ServiceAccountReference struct {
 // KeySpec defines cryptographic key parameters.
 type KeySpec struct {
 	// Algorithm specifies the asymmetric key algorithm.
-	// +kubebuilder:validation:Enum=EC;RSA
+	// +kubebuilder:validation:Enum=EC;RSA;Ed25519
 	Algorithm string `json:"algorithm"`
 
 	// Params holds algorithm-specific parameters.
-	// For EC: {"curve": "P-256"|"P-384"|"P-521"}
-	// For RSA: {"keySize": "2048"|"3072"|"4096"}
-	Params map[string]string `json:"params"`
+	// For EC: {"curve": "P-256"|"P-384"|"P-521"}, or "brainpoolP256r1"|"brainpoolP384r1"
+	// for use enc when the controller runs with --allow-non-nist-curves
+	// For RSA: {"keySize": "2048"|"3072"|"4096", "publicExponent": "65537" (optional)}
+	// For Ed25519: {} (no parameters)
+	// Unknown keys are rejected.
+	// +optional
+	Params map[string]string `json:"params,omitempty"`
 
 	// Encoding specifies the key encoding format.
 	// +kubebuilder:validation:Enum=PEM;DER;JWK
 	// +kubebuilder:default=PEM
 	Encoding string `json:"encoding,omitempty"`
 
+	// PrivateKeyPEMType selects the PEM structure of the private key.
+	// pkcs8 ("PRIVATE KEY") works for all algorithms; pkcs1 ("RSA PRIVATE KEY")
+	// is RSA only and sec1 ("EC PRIVATE KEY") is EC only, for legacy consumers.
+	// +kubebuilder:validation:Enum=pkcs8;pkcs1;sec1
+	// +kubebuilder:default=pkcs8
+	// +optional
+	PrivateKeyPEMType string `json:"privateKeyPEMType,omitempty"`
+
+	// KeyIDTemplate customizes the generated key ID. Supported placeholders:
+	// {alg}, {param}, {date} (YYYYMMDD), {hex} (6 random hex chars), {uuid} (random UUID).
+	// Must contain {hex} or {uuid}. Defaults to "{alg}-{param}-{date}-{hex}".
+	// {param} is dropped with one adjacent "-" for algorithms without params (Ed25519).
+	// +optional
+	KeyIDTemplate string `json:"keyIDTemplate,omitempty"`
+
 	// AllowLegacyKeySize permits RSA key sizes below 3072 bits.
 	// RSA < 3072 is deprecated per BSI TR-02102-1 (2025) and rejected by default.
 	// Set to true only for documented legacy compatibility requirements.
 	// [COMP:G-1]
 	// +optional
 	AllowLegacyKeySize bool `json:"allowLegacyKeySize,omitempty"`
+
+	// Use declares the intended use of the key and selects the key usages of
+	// generated self-signed certificates: sig (digitalSignature, no extended key
+	// usage), enc (keyEncipherment) or tls (adds the serverAuth extended key usage).
+	// Defaults to sig.
+	// +kubebuilder:validation:Enum=sig;enc;tls
+	// +optional
+	Use string `json:"use,omitempty"`
 }
 
 // RotationPolicy defines the key rotation schedule.
 type RotationPolicy struct {
 	// Interval specifies how often the key is rotated.
-	// Must be at least 3× GracePeriod.
-	Interval metav1.Duration `json:"interval"`
+	// Must be at least 3× GracePeriod. Required unless ScheduleRef is set;
+	// when both are set, Interval takes precedence.
+	// +optional
+	Interval metav1.Duration `json:"interval,omitempty"`
+
+	// ScheduleRef takes the rotation interval from a shared RotationSchedule in
+	// the profile's namespace, or a ClusterRotationSchedule such as an
+	// organization-wide re-keying calendar. Ignored while Interval is set.
+	// +optional
+	ScheduleRef *ScheduleReference `json:"scheduleRef,omitempty"`
 
 	// GracePeriod specifies how long the previous key remains valid after rotation.
 	// Must be at least 5 minutes (NIST SP 800-57).
 	// [COMP:G-4]
 	GracePeriod metav1.Duration `json:"gracePeriod"`
 
+	// GraceMode selects what the grace period covers. With "overlap" the
+	// previous public key stays published next to the new one, so validators
+	// accept both. With "hard" the previous public key is withdrawn at rotation
+	// and the grace period only governs how long the previous private material
+	// lingers in the Secret before it is wiped.
+	// +kubebuilder:validation:Enum=overlap;hard
+	// +kubebuilder:default=overlap
+	// +optional
+	GraceMode string `json:"graceMode,omitempty"`
+
+	// RotateBeforeExpiry rotates this long before the scheduled tick
+	// (LastRotation + Interval), giving validators lead time to pick up the new
+	// key before the current one is due. Must be less than Interval.
+	// +optional
+	RotateBeforeExpiry metav1.Duration `json:"rotateBeforeExpiry,omitempty"`
+
 	// TriggerOnStartup forces an immediate rotation when the controller starts.
 	// +optional
 	TriggerOnStartup bool `json:"triggerOnStartup,omitempty"`
+
+	// PauseUntil suppresses scheduled rotation until the given time, e.g. for a
+	// maintenance window. Integrity verification continues while paused.
+	// +optional
+	PauseUntil *metav1.Time `json:"pauseUntil,omitempty"`
+
+	// PublishNextKey changes RotateBeforeExpiry from rotating early to publishing
+	// the next key early: the upcoming key is generated and published
+	// RotateBeforeExpiry before the scheduled tick while the current key stays
+	// active, then promoted at LastRotation + Interval.
+	// +optional
+	PublishNextKey bool `json:"publishNextKey,omitempty"`
+
+	// MigrateOnAlgorithmChange rotates as soon as KeySpec.Algorithm no longer
+	// matches the stored key (e.g. RSA to EC) instead of at the next scheduled
+	// tick. The old-algorithm key stays the previous key for the full
+	// GracePeriod and is published alongside the new key, so validators accept
+	// both during the migration.
+	// +optional
+	MigrateOnAlgorithmChange bool `json:"migrateOnAlgorithmChange,omitempty"`
 }
 
+// Grace modes accepted by RotationPolicy.GraceMode.
+const (
+	GraceModeOverlap = "overlap"
+	GraceModeHard    = "hard"
+)
+
+// ScheduleReference names a RotationSchedule or ClusterRotationSchedule.
+type ScheduleReference struct {
+	// Kind of the schedule: RotationSchedule, in the profile's namespace, or
+	// the cluster-scoped ClusterRotationSchedule.
+	// +kubebuilder:validation:Enum=RotationSchedule;ClusterRotationSchedule
+	// +kubebuilder:default=RotationSchedule
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the schedule.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// Schedule kinds (ScheduleReference.Kind).
+const (
+	RotationScheduleKind        = "RotationSchedule"
+	ClusterRotationScheduleKind = "ClusterRotationSchedule"
+)
+
 // OutputConfig defines how key material is stored as a Kubernetes Secret.
 type OutputConfig struct {
 	// SecretName is the name of the Kubernetes Secret to create/update.
@@ -104,30 +223,165 @@ type OutputConfig struct {
 	// Labels are additional labels applied to the managed Secret.
 	// +optional
 	Labels map[string]string `json:"labels,omitempty"`
+
+	// SPIFFETrustDomain, if set, adds a SPIFFE URI SAN derived from ServiceAccountRef
+	// to generated certificates: spiffe://{trustDomain}/ns/{namespace}/sa/{name}.
+	// +optional
+	SPIFFETrustDomain string `json:"spiffeTrustDomain,omitempty"`
+
+	// Immutable stores each key in a new immutable Secret named {secretName}-{keyID}.
+	// A ConfigMap named SecretName points consumers at the current and previous
+	// Secret; superseded Secrets are deleted once the grace period ends.
+	// +optional
+	Immutable bool `json:"immutable,omitempty"`
+
+	// Compress gzips rendered JSON entries (e.g. JWKS), storing them as .json.gz
+	// and annotating the Secret with openukr.io/compression=gzip.
+	// +optional
+	Compress bool `json:"compress,omitempty"`
+
+	// KeyNames overrides the Secret data key of named entries, e.g.
+	// {"jwks": "keys.json"} for consumers expecting a different file name.
+	// Supported entries: jwks (default jwks.json).
+	// +optional
+	KeyNames map[string]string `json:"keyNames,omitempty"`
+
+	// KeepHistorySecrets snapshots the Secret into an immutable Secret named
+	// {secretName}-history-{keyID} whenever its key is replaced, keeping the
+	// newest N snapshots for rollback. Snapshots hold private key material
+	// beyond the grace period; keep N as small as the rollback window allows.
+	// Not supported with Immutable, whose per-key Secrets serve the same purpose.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +optional
+	KeepHistorySecrets int `json:"keepHistorySecrets,omitempty"`
+
+	// Encryption encrypts the private key entries of the Secret before they are
+	// stored, e.g. so the Secret can be committed to git. Public entries stay
+	// plaintext. The controller cannot read the private key back, so the
+	// integrity check only covers the public key.
+	// +optional
+	Encryption *OutputEncryption `json:"encryption,omitempty"`
+}
+
+// OutputEncryption selects how private key entries are encrypted.
+type OutputEncryption struct {
+	// Age encrypts private key entries to age recipients. Each entry is stored
+	// ASCII-armored as {key}.age, e.g. tls.key.age.
+	// +optional
+	Age *AgeEncryption `json:"age,omitempty"`
+
+	// AzureKeyVault envelope-encrypts private key entries: a fresh AES-256-GCM
+	// data key encrypts each entry, stored as {key}.kms.json together with the
+	// data key wrapped (RSA-OAEP-256) by a Key Vault key. Exclusive with Age.
+	// +optional
+	AzureKeyVault *AzureKeyVaultEncryption `json:"azureKeyVault,omitempty"`
+}
+
+// AgeEncryption configures age (https://age-encryption.org) encryption.
+type AgeEncryption struct {
+	// Recipients are the age X25519 public keys ("age1...") that can decrypt
+	// the private key entries.
+	// +kubebuilder:validation:MinItems=1
+	Recipients []string `json:"recipients"`
+}
+
+// AzureKeyVaultEncryption configures envelope encryption with an Azure Key
+// Vault key.
+type AzureKeyVaultEncryption struct {
+	// KeyURL is the RSA key wrapping the data keys:
+	// https://<vault>.vault.azure.net/keys/<name>[/<version>].
+	// Without a version the current key version is used.
+	// +kubebuilder:validation:MinLength=1
+	KeyURL string `json:"keyURL"`
+
+	// ClientSecretRef names a Secret in the KeyProfile namespace holding a client
+	// secret under "clientSecret"; TenantID and ClientID are then required.
+	// Workload identity is used if empty.
+	// +optional
+	ClientSecretRef string `json:"clientSecretRef,omitempty"`
+
+	// TenantID is the Microsoft Entra tenant; overrides workload identity.
+	// +optional
+	TenantID string `json:"tenantID,omitempty"`
+
+	// ClientID is the application (client) ID; overrides workload identity.
+	// +optional
+	ClientID string `json:"clientID,omitempty"`
 }
 
 // PublishTarget defines a target where the public key is published.
 type PublishTarget struct {
 	// Type specifies the publisher implementation.
-	// +kubebuilder:validation:Enum=http;filesystem
+	// +kubebuilder:validation:Enum=http;filesystem;azurekeyvault
 	Type string `json:"type"`
 
 	// Config holds publisher-specific configuration.
 	// For http: {"endpoint": "https://..."}
 	// For filesystem: {"path": "/var/keys/"}
+	// For azurekeyvault: {"vaultURL": "https://<vault>.vault.azure.net", "secretName": "..."},
+	// authenticating via workload identity or "clientSecretRef" with "tenantID" and "clientID".
 	Config map[string]string `json:"config"`
 
+	// FailurePolicy decides how a failure of this target affects the publish.
+	// With "Fail" the rotation is retried and the key is not persisted; with
+	// "Ignore" the failure is only reported in the target's publish status.
+	// +kubebuilder:validation:Enum=Fail;Ignore
+	// +kubebuilder:default=Fail
+	// +optional
+	FailurePolicy string `json:"failurePolicy,omitempty"`
+
+	// Outputs publishes the key in several encodings under this target's shared
+	// TLS configuration. Each output's Config is merged over the target Config,
+	// e.g. a distinct "endpoint" or "path" per encoding.
+	// If empty, the target publishes a single PEM output using Config.
+	// +optional
+	Outputs []PublishOutput `json:"outputs,omitempty"`
+
 	// TLS configures transport security for HTTP publishers.
 	// [SEC:T-2]
 	// +optional
 	TLS *TLSConfig `json:"tls,omitempty"`
 }
 
+// Failure policies accepted by PublishTarget.FailurePolicy.
+const (
+	FailurePolicyFail   = "Fail"
+	FailurePolicyIgnore = "Ignore"
+)
+
+// PublishOutput defines one encoding published by a PublishTarget.
+type PublishOutput struct {
+	// Encoding specifies the public key encoding. spki is the SubjectPublicKeyInfo
+	// DER (same as DER); raw-public is only the inner subjectPublicKey bytes
+	// (RSA, EC or Ed25519). ec-compressed publishes the bare SEC1 compressed point and is
+	// only valid for EC keys.
+	// +kubebuilder:validation:Enum=PEM;DER;JWK;spki;raw-public;ec-compressed
+	// +kubebuilder:default=PEM
+	Encoding string `json:"encoding,omitempty"`
+
+	// Config overrides target configuration for this output.
+	// +optional
+	Config map[string]string `json:"config,omitempty"`
+
+	// Compress gzips the published body and sets Content-Encoding: gzip.
+	// Only honored by the http publisher.
+	// +optional
+	Compress bool `json:"compress,omitempty"`
+}
+
 // TLSConfig configures transport-layer security for publishers.
 // [SEC:T-2] Transport integrity for HTTP Publisher.
 type TLSConfig struct {
 	// CACertSecretRef references a Kubernetes Secret containing the CA certificate bundle.
-	CACertSecretRef string `json:"caCertSecretRef"`
+	// +optional
+	CACertSecretRef string `json:"caCertSecretRef,omitempty"`
+
+	// CACertSecretRefs references additional CA Secrets, e.g. when roots and
+	// intermediates are stored separately. All bundles, including CACertSecretRef,
+	// are concatenated into one trust pool.
+	// +optional
+	CACertSecretRefs []string `json:"caCertSecretRefs,omitempty"`
 
 	// ClientCertSecretRef references a Kubernetes Secret containing the mTLS client certificate.
 	// +optional
@@ -139,6 +393,60 @@ type TLSConfig struct {
 	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
 }
 
+// CertificateConfig defines how a certificate for the generated key is requested.
+type CertificateConfig struct {
+	// IssuerRef references the cert-manager issuer that signs the key's CSR.
+	IssuerRef IssuerReference `json:"issuerRef"`
+
+	// Duration is the requested certificate lifetime. Defaults to the issuer's default.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// IssuerReference identifies a cert-manager Issuer or ClusterIssuer.
+type IssuerReference struct {
+	// Name of the issuer.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Kind of the issuer.
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default=Issuer
+	Kind string `json:"kind,omitempty"`
+
+	// Group of the issuer.
+	// +kubebuilder:default=cert-manager.io
+	Group string `json:"group,omitempty"`
+}
+
+// Condition types reported in KeyProfileStatus.Conditions.
+const (
+	// ConditionClockSkew is True when Status.LastRotation was found in the future
+	// beyond the tolerated skew and the key was rotated to recover.
+	ConditionClockSkew = "ClockSkew"
+
+	// ConditionCertificateReady is True once the signed certificate for the
+	// current key has been stored in the Secret.
+	ConditionCertificateReady = "CertificateReady"
+
+	// ConditionSuspended is True while rotation is paused by Spec.Rotation.PauseUntil.
+	ConditionSuspended = "Suspended"
+
+	// ConditionSecretRenamed is True once Spec.Output.SecretName has changed. The
+	// message names the Secret that is no longer updated.
+	ConditionSecretRenamed = "SecretRenamed"
+
+	// ConditionDegraded is True while the controller cannot write the profile's
+	// Secret, e.g. because the rendered data exceeds the size limit.
+	ConditionDegraded = "Degraded"
+
+	// ConditionIntegrityVerified is True while the Secret's key material passes
+	// its integrity check against the recorded fingerprint, and False with reason
+	// Tampered once it failed, until the Secret verifies again or a corrective
+	// rotation replaced the material (reason Corrected). [SEC:T-1]
+	ConditionIntegrityVerified = "IntegrityVerified"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
@@ -146,6 +454,7 @@ type TLSConfig struct {
 // +kubebuilder:printcolumn:name="Algorithm",type=string,JSONPath=`.spec.keySpec.algorithm`
 // +kubebuilder:printcolumn:name="KeyID",type=string,JSONPath=`.status.currentKeyID`
 // +kubebuilder:printcolumn:name="LastRotation",type=date,JSONPath=`.status.lastRotation`
+// +kubebuilder:printcolumn:name="Summary",type=string,JSONPath=`.status.summary`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // KeyProfile is the Schema for the keyprofiles API.
@@ -161,7 +470,7 @@ type KeyProfile struct {
 // KeyProfileStatus defines the observed state of a KeyProfile.
 type KeyProfileStatus struct {
 	// Phase indicates the current rotation phase.
-	// +kubebuilder:validation:Enum=Idle;Active;Generating;Publishing;Distributing;GracePeriod;Error
+	// +kubebuilder:validation:Enum=Idle;Active;Generating;Publishing;Distributing;GracePeriod;Suspended;Error
 	// +optional
 	Phase string `json:"phase,omitempty"`
 
@@ -179,24 +488,110 @@ type KeyProfileStatus struct {
 	// +optional
 	CurrentKeyFingerprint string `json:"currentKeyFingerprint,omitempty"`
 
+	// CurrentKeyThumbprint is the RFC 7638 JWK thumbprint of the current key's
+	// public component, recorded when the controller runs with --record-key-thumbprint.
+	// +optional
+	CurrentKeyThumbprint string `json:"currentKeyThumbprint,omitempty"`
+
 	// PreviousKeyFingerprint is the SHA-256 fingerprint of the previous key's public component.
 	// [SEC:T-1]
 	// +optional
 	PreviousKeyFingerprint string `json:"previousKeyFingerprint,omitempty"`
 
+	// LastForcedRotation is the openukr.io/force-rotate annotation value of the
+	// last forced rotation. An annotation with this value has been handled, even
+	// if removing it failed.
+	// +optional
+	LastForcedRotation string `json:"lastForcedRotation,omitempty"`
+
 	// LastRotation is the timestamp of the last successful rotation.
 	// +optional
 	LastRotation *metav1.Time `json:"lastRotation,omitempty"`
 
+	// LastRotationReason explains why the last rotation happened, e.g. an expired
+	// interval or initial key generation.
+	// +optional
+	LastRotationReason string `json:"lastRotationReason,omitempty"`
+
+	// LastVerifyTime is when an integrity check requested with the
+	// openukr.io/verify-now annotation last ran.
+	// +optional
+	LastVerifyTime *metav1.Time `json:"lastVerifyTime,omitempty"`
+
+	// Mode is "Observe" while the operator runs with --mode=observe and the
+	// status reflects the existing Secret without any rotation. Empty otherwise.
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
 	// NextRotation is the timestamp of the next scheduled rotation.
 	// +optional
 	NextRotation *metav1.Time `json:"nextRotation,omitempty"`
 
+	// PublishStatus records the outcome of the last publish to each configured target,
+	// in the same order as Spec.Publish.
+	// +optional
+	PublishStatus []TargetStatus `json:"publishStatus,omitempty"`
+
+	// CertificateRequest is the name of the cert-manager CertificateRequest for the current key.
+	// +optional
+	CertificateRequest string `json:"certificateRequest,omitempty"`
+
+	// SecretName is the Spec.Output.SecretName the controller last wrote to.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// PendingKeyID is a key that was published but could not be persisted yet.
+	// The controller retries persisting this key rather than publishing a new one.
+	// +optional
+	PendingKeyID string `json:"pendingKeyID,omitempty"`
+
+	// NextKeyID is the key published ahead of its promotion (Spec.Rotation.PublishNextKey).
+	// +optional
+	NextKeyID string `json:"nextKeyID,omitempty"`
+
+	// NextKeyFingerprint is the SHA-256 fingerprint of the next key's public key.
+	// +optional
+	NextKeyFingerprint string `json:"nextKeyFingerprint,omitempty"`
+
+	// Summary is a one-line health summary derived from the phase, next rotation
+	// and publish status, e.g. "Active, next rotation at 2026-01-02T15:04:05Z, 2/2
+	// targets published". "rotation overdue" is as of the last status update.
+	// Set by the controller only.
+	// +optional
+	Summary string `json:"summary,omitempty"`
+
 	// Conditions represent the latest available observations of the KeyProfile's state.
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// TargetStatus records the publish state of a single PublishTarget.
+type TargetStatus struct {
+	// Type is the publisher type of the target.
+	Type string `json:"type"`
+
+	// Target identifies the destination (endpoint or path).
+	// +optional
+	Target string `json:"target,omitempty"`
+
+	// LastPublishedKeyID is the KeyID last successfully published to this target.
+	// +optional
+	LastPublishedKeyID string `json:"lastPublishedKeyID,omitempty"`
+
+	// LastPublishTime is when a key was last successfully published to this target.
+	// +optional
+	LastPublishTime *metav1.Time `json:"lastPublishTime,omitempty"`
+
+	// Error is the error of the last publish attempt. Empty if it succeeded.
+	// +optional
+	Error string `json:"error,omitempty"`
+
+	// CircuitOpenUntil is set while the target is skipped after repeated failures;
+	// it is attempted again after this time.
+	// +optional
+	CircuitOpenUntil *metav1.Time `json:"circuitOpenUntil,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 
 // KeyProfileList contains a list of KeyProfile.
@@ -0,0 +1,81 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RotationScheduleSpec defines a rotation cadence shared by KeyProfiles. It is
+// the spec of both RotationSchedule and ClusterRotationSchedule.
+type RotationScheduleSpec struct {
+	// Interval specifies how often keys of referencing KeyProfiles are rotated.
+	// Must be at least 3× the GracePeriod of every referencing KeyProfile.
+	Interval metav1.Duration `json:"interval"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Interval",type=string,JSONPath=`.spec.interval`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// RotationSchedule is the Schema for the rotationschedules API: a rotation
+// cadence for the KeyProfiles of its namespace, which reference it through
+// Spec.Rotation.ScheduleRef.
+type RotationSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RotationScheduleSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RotationScheduleList contains a list of RotationSchedule.
+type RotationScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RotationSchedule `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Interval",type=string,JSONPath=`.spec.interval`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ClusterRotationSchedule is the Schema for the clusterrotationschedules API: a
+// rotation cadence shared by KeyProfiles of every namespace, e.g. an
+// organization-wide re-keying calendar.
+type ClusterRotationSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RotationScheduleSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterRotationScheduleList contains a list of ClusterRotationSchedule.
+type ClusterRotationScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterRotationSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RotationSchedule{}, &RotationScheduleList{})
+	SchemeBuilder.Register(&ClusterRotationSchedule{}, &ClusterRotationScheduleList{})
+}
@@ -25,6 +25,135 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgeEncryption) DeepCopyInto(out *AgeEncryption) {
+	*out = *in
+	if in.Recipients != nil {
+		in, out := &in.Recipients, &out.Recipients
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgeEncryption.
+func (in *AgeEncryption) DeepCopy() *AgeEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(AgeEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureKeyVaultEncryption) DeepCopyInto(out *AzureKeyVaultEncryption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureKeyVaultEncryption.
+func (in *AzureKeyVaultEncryption) DeepCopy() *AzureKeyVaultEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureKeyVaultEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateConfig) DeepCopyInto(out *CertificateConfig) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateConfig.
+func (in *CertificateConfig) DeepCopy() *CertificateConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRotationSchedule) DeepCopyInto(out *ClusterRotationSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRotationSchedule.
+func (in *ClusterRotationSchedule) DeepCopy() *ClusterRotationSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRotationSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterRotationSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterRotationScheduleList) DeepCopyInto(out *ClusterRotationScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterRotationSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterRotationScheduleList.
+func (in *ClusterRotationScheduleList) DeepCopy() *ClusterRotationScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterRotationScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterRotationScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IssuerReference) DeepCopyInto(out *IssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IssuerReference.
+func (in *IssuerReference) DeepCopy() *IssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(IssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeyProfile) DeepCopyInto(out *KeyProfile) {
 	*out = *in
@@ -89,8 +218,15 @@ func (in *KeyProfileSpec) DeepCopyInto(out *KeyProfileSpec) {
 	*out = *in
 	out.ServiceAccountRef = in.ServiceAccountRef
 	in.KeySpec.DeepCopyInto(&out.KeySpec)
-	out.Rotation = in.Rotation
+	in.Rotation.DeepCopyInto(&out.Rotation)
 	in.Output.DeepCopyInto(&out.Output)
+	if in.AdditionalOutputs != nil {
+		in, out := &in.AdditionalOutputs, &out.AdditionalOutputs
+		*out = make([]OutputConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Publish != nil {
 		in, out := &in.Publish, &out.Publish
 		*out = make([]PublishTarget, len(*in))
@@ -98,6 +234,11 @@ func (in *KeyProfileSpec) DeepCopyInto(out *KeyProfileSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = new(CertificateConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KeyProfileSpec.
@@ -117,10 +258,21 @@ func (in *KeyProfileStatus) DeepCopyInto(out *KeyProfileStatus) {
 		in, out := &in.LastRotation, &out.LastRotation
 		*out = (*in).DeepCopy()
 	}
+	if in.LastVerifyTime != nil {
+		in, out := &in.LastVerifyTime, &out.LastVerifyTime
+		*out = (*in).DeepCopy()
+	}
 	if in.NextRotation != nil {
 		in, out := &in.NextRotation, &out.NextRotation
 		*out = (*in).DeepCopy()
 	}
+	if in.PublishStatus != nil {
+		in, out := &in.PublishStatus, &out.PublishStatus
+		*out = make([]TargetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -172,6 +324,18 @@ func (in *OutputConfig) DeepCopyInto(out *OutputConfig) {
 			(*out)[key] = val
 		}
 	}
+	if in.KeyNames != nil {
+		in, out := &in.KeyNames, &out.KeyNames
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(OutputEncryption)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputConfig.
@@ -184,6 +348,53 @@ func (in *OutputConfig) DeepCopy() *OutputConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OutputEncryption) DeepCopyInto(out *OutputEncryption) {
+	*out = *in
+	if in.Age != nil {
+		in, out := &in.Age, &out.Age
+		*out = new(AgeEncryption)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AzureKeyVault != nil {
+		in, out := &in.AzureKeyVault, &out.AzureKeyVault
+		*out = new(AzureKeyVaultEncryption)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OutputEncryption.
+func (in *OutputEncryption) DeepCopy() *OutputEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(OutputEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublishOutput) DeepCopyInto(out *PublishOutput) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublishOutput.
+func (in *PublishOutput) DeepCopy() *PublishOutput {
+	if in == nil {
+		return nil
+	}
+	out := new(PublishOutput)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PublishTarget) DeepCopyInto(out *PublishTarget) {
 	*out = *in
@@ -194,10 +405,17 @@ func (in *PublishTarget) DeepCopyInto(out *PublishTarget) {
 			(*out)[key] = val
 		}
 	}
+	if in.Outputs != nil {
+		in, out := &in.Outputs, &out.Outputs
+		*out = make([]PublishOutput, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.TLS != nil {
 		in, out := &in.TLS, &out.TLS
 		*out = new(TLSConfig)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 }
 
@@ -216,6 +434,16 @@ func (in *RotationPolicy) DeepCopyInto(out *RotationPolicy) {
 	*out = *in
 	out.Interval = in.Interval
 	out.GracePeriod = in.GracePeriod
+	out.RotateBeforeExpiry = in.RotateBeforeExpiry
+	if in.ScheduleRef != nil {
+		in, out := &in.ScheduleRef, &out.ScheduleRef
+		*out = new(ScheduleReference)
+		**out = **in
+	}
+	if in.PauseUntil != nil {
+		in, out := &in.PauseUntil, &out.PauseUntil
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RotationPolicy.
@@ -228,6 +456,95 @@ func (in *RotationPolicy) DeepCopy() *RotationPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RotationSchedule) DeepCopyInto(out *RotationSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RotationSchedule.
+func (in *RotationSchedule) DeepCopy() *RotationSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(RotationSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RotationSchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RotationScheduleList) DeepCopyInto(out *RotationScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RotationSchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RotationScheduleList.
+func (in *RotationScheduleList) DeepCopy() *RotationScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(RotationScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RotationScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RotationScheduleSpec) DeepCopyInto(out *RotationScheduleSpec) {
+	*out = *in
+	out.Interval = in.Interval
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RotationScheduleSpec.
+func (in *RotationScheduleSpec) DeepCopy() *RotationScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RotationScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleReference) DeepCopyInto(out *ScheduleReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleReference.
+func (in *ScheduleReference) DeepCopy() *ScheduleReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceAccountReference) DeepCopyInto(out *ServiceAccountReference) {
 	*out = *in
@@ -246,6 +563,11 @@ func (in *ServiceAccountReference) DeepCopy() *ServiceAccountReference {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLSConfig) DeepCopyInto(out *TLSConfig) {
 	*out = *in
+	if in.CACertSecretRefs != nil {
+		in, out := &in.CACertSecretRefs, &out.CACertSecretRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfig.
@@ -257,3 +579,26 @@ func (in *TLSConfig) DeepCopy() *TLSConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetStatus) DeepCopyInto(out *TargetStatus) {
+	*out = *in
+	if in.LastPublishTime != nil {
+		in, out := &in.LastPublishTime, &out.LastPublishTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CircuitOpenUntil != nil {
+		in, out := &in.CircuitOpenUntil, &out.CircuitOpenUntil
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetStatus.
+func (in *TargetStatus) DeepCopy() *TargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
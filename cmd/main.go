@@ -23,42 +23,57 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
 	"github.com/openukr/openukr/internal/controller"
+	"github.com/openukr/openukr/internal/jwks"
+	"github.com/openukr/openukr/internal/logging"
 	webhookopenukrv1alpha1 "github.com/openukr/openukr/internal/webhook/v1alpha1"
 	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/metrics"
 	"github.com/openukr/openukr/pkg/output"
 	"github.com/openukr/openukr/pkg/publish"
 	"github.com/openukr/openukr/pkg/rotation"
+	"github.com/openukr/openukr/pkg/validation"
 	// +kubebuilder:scaffold:imports
 )
 
 var (
-	scheme   = runtime.NewScheme()
+	scheme   = newScheme()
 	setupLog = ctrl.Log.WithName("setup")
 )
 
-func init() {
-	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+// newScheme returns a scheme with the built-in and openUKR types registered.
+func newScheme() *runtime.Scheme {
+	s := runtime.NewScheme()
+	utilruntime.Must(clientgoscheme.AddToScheme(s))
 
-	utilruntime.Must(openukrv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(openukrv1alpha1.AddToScheme(s))
 	// +kubebuilder:scaffold:scheme
+	return s
 }
 
 // verifyEntropy checks that crypto/rand is functional.
@@ -76,6 +91,33 @@ func verifyEntropy() error {
 	return nil
 }
 
+// parseList splits a comma-separated list, dropping empty entries.
+func parseList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseKeySizes parses a comma-separated list of RSA key sizes.
+func parseKeySizes(value string) ([]int, error) {
+	var sizes []int
+	for _, s := range strings.Split(value, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		size, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key size %q: %w", s, err)
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
 //nolint:gocyclo
 func main() {
 	// [SEC:I-1/COMP:G-3] Preflight: verify entropy source before any key operations
@@ -91,6 +133,33 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var watchNamespaces string
+	var profileSelector string
+	var metricsProfileLabels bool
+	var metricsCustomLabels string
+	var allowInsecurePublish bool
+	var enableCertificates bool
+	var recordKeyThumbprint bool
+	var allowNonNISTCurves bool
+	var logLevel, logFormat string
+	var jwksAddr string
+	var jwksMetadata bool
+	var jwksCacheTTL time.Duration
+	var stallWindow time.Duration
+	var deniedPublishPaths string
+	var allowedPublishHosts, deniedPublishHosts string
+	var keyPoolSizes string
+	var keyPoolDepth int
+	var keygenRateLimit int
+	var maxSecretSize int
+	var refuseForeignSecrets bool
+	var publishCircuitThreshold int
+	var publishCircuitCooldown time.Duration
+	var publishSigningSecret string
+	var tolerateUnknownPublishers bool
+	var mode string
+	var minRotationInterval time.Duration
+	var rejectShortInterval bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -109,13 +178,130 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&watchNamespaces, "namespaces", "",
+		"Comma-separated list of namespaces to watch. Leave empty to watch all namespaces.")
+	flag.StringVar(&profileSelector, "keyprofile-selector", "",
+		"Label selector restricting which KeyProfiles are reconciled (e.g. shard=a). Leave empty for all.")
+	flag.BoolVar(&metricsProfileLabels, "metrics-profile-labels", true,
+		"Record per-namespace and per-KeyProfile metric labels. Disable on large fleets to bound "+
+			"Prometheus series cardinality, at the cost of per-namespace and per-profile breakdowns.")
+	flag.StringVar(&metricsCustomLabels, "metrics-keyprofile-labels", "",
+		"Comma-separated KeyProfile label keys recorded as label_<key> on the rotation counters, "+
+			"e.g. team,environment. At most 5; leave empty to record none.")
+	flag.BoolVar(&allowInsecurePublish, "allow-insecure-publish", true,
+		"Allow publish targets with insecureSkipVerify=true (admission warning only). "+
+			"Set to false to reject them at admission.")
+	flag.DurationVar(&minRotationInterval, "min-rotation-interval", validation.DefaultMinInterval,
+		"Cluster-wide minimum for spec.rotation.interval and RotationSchedule intervals. Very short "+
+			"intervals across many KeyProfiles overload key generation and publish targets. Set to 0 to disable.")
+	flag.BoolVar(&rejectShortInterval, "reject-short-interval", false,
+		"Reject KeyProfiles with an interval below --min-rotation-interval at admission, and refuse "+
+			"to rotate on such a RotationSchedule, instead of only warning.")
+	flag.BoolVar(&enableCertificates, "enable-certificates", false,
+		"Request CA-signed certificates via cert-manager CertificateRequests for KeyProfiles "+
+			"with spec.certificate. Requires cert-manager to be installed.")
+	flag.BoolVar(&recordKeyThumbprint, "record-key-thumbprint", false,
+		"Record the RFC 7638 JWK thumbprint of each KeyProfile's current key in "+
+			"status.currentKeyThumbprint, unless the profile sets spec.disableFingerprintStatus.")
+	flag.BoolVar(&allowNonNISTCurves, "allow-non-nist-curves", false,
+		"Allow EC keys on the brainpoolP256r1 and brainpoolP384r1 curves for keySpec.use enc. They lack a "+
+			"constant-time signing implementation, many JWT libraries, cloud KMS and HSMs do not support them, "+
+			"and they cannot be used with jks or pkcs12 output.")
+	flag.StringVar(&jwksAddr, "jwks-bind-address", "0",
+		"The address a read-only JWKS endpoint (/{namespace}/{name}/jwks.json) binds to. "+
+			"Leave as 0 to disable.")
+	flag.DurationVar(&jwksCacheTTL, "jwks-cache-ttl", jwks.DefaultCacheTTL,
+		"How long the JWKS endpoint caches a served document, bounding API server reads and how stale "+
+			"a response may be after a rotation. 0 disables caching.")
+	flag.BoolVar(&jwksMetadata, "jwks-metadata", false,
+		"Add a non-standard \"_openukr\" member (generatedAt, nextRotation, keyProfile) to served JWKS "+
+			"documents for monitoring. Strict JWKS parsers ignore it.")
+	flag.DurationVar(&stallWindow, "stall-window", 30*time.Minute,
+		"Fail the healthz check when a KeyProfile is overdue by this long and no reconcile has "+
+			"started within it (wedged work queue). Set to 0 to disable.")
+	flag.StringVar(&deniedPublishPaths, "publish-path-denylist", strings.Join(validation.DefaultDeniedPublishPaths, ","),
+		"Comma-separated directory prefixes filesystem publish paths may not point into, enforced at "+
+			"admission and publish time. Set to an empty string to disable.")
+	flag.StringVar(&allowedPublishHosts, "publish-host-allowlist", "",
+		"Comma-separated hosts, *.domain wildcards or CIDRs HTTP and Azure Key Vault publish endpoints are "+
+			"restricted to. Leave empty to allow all hosts not in --publish-host-denylist.")
+	flag.StringVar(&deniedPublishHosts, "publish-host-denylist", strings.Join(validation.DefaultDeniedPublishHosts, ","),
+		"Comma-separated hosts, *.domain wildcards or CIDRs HTTP and Azure Key Vault publish endpoints may not "+
+			"reach, enforced at admission and on every connection. Defaults to link-local and cloud metadata addresses. "+
+			"Set to an empty string to disable.")
+	flag.IntVar(&publishCircuitThreshold, "publish-circuit-threshold", publish.DefaultCircuitFailureThreshold,
+		"Consecutive failures after which a publish target is skipped for --publish-circuit-cooldown. "+
+			"Set to 0 to always attempt every target.")
+	flag.DurationVar(&publishCircuitCooldown, "publish-circuit-cooldown", publish.DefaultCircuitCooldown,
+		"How long a repeatedly failing publish target is skipped before it is attempted again.")
+	flag.BoolVar(&tolerateUnknownPublishers, "tolerate-unknown-publishers", false,
+		"Skip publish targets of a type this controller does not know, with a Warning event, instead of "+
+			"failing the rotation. Such targets mean the CRD is newer than the controller.")
+	flag.StringVar(&publishSigningSecret, "publish-signing-secret", "",
+		"Secret (namespace/name) holding a PEM private key under \""+publish.SigningKeySecretKey+"\". When set, "+
+			"HTTP publishes carry an "+publish.SignatureHeader+" header and filesystem publishes a "+
+			publish.SignatureFileSuffix+" file over the key ID and key, so receivers can verify the key came from "+
+			"this controller.")
+	flag.StringVar(&keyPoolSizes, "keygen-pool-rsa-sizes", "",
+		"Comma-separated RSA key sizes (e.g. 3072,4096) to pre-generate in the background so rotations "+
+			"do not wait for RSA generation. Pooled keys are private material held in memory until used; "+
+			"leave empty to disable.")
+	flag.IntVar(&keyPoolDepth, "keygen-pool-depth", 2,
+		"Number of pre-generated keys held per size when --keygen-pool-rsa-sizes is set.")
+	flag.IntVar(&keygenRateLimit, "keygen-rate-limit", 0,
+		"Maximum key generations per minute, spaced evenly, so a burst of rotations does not starve the "+
+			"node of CPU. Rotations over budget are requeued until the budget refills. Set to 0 to disable.")
+	flag.IntVar(&maxSecretSize, "max-secret-size", output.DefaultMaxSecretSize,
+		"Maximum size in bytes of the data written to a KeyProfile Secret. Larger writes fail with a "+
+			"clear error and a Degraded condition instead of an API error at the 1 MiB limit. "+
+			"Set to 0 to disable.")
+	flag.BoolVar(&refuseForeignSecrets, "refuse-foreign-secrets", false,
+		"Fail to write a KeyProfile Secret that already exists but is neither labeled as managed by the "+
+			"profile nor owned by it, instead of taking it over.")
+	flag.StringVar(&mode, "mode", "active",
+		"Operating mode: active, or observe to report rotation schedules from existing Secrets "+
+			"without generating, publishing or writing any keys.")
+	flag.StringVar(&logLevel, "log-level", "",
+		"Log level: debug, info, error, or a verbosity >= 0. Overrides --zap-log-level when set.")
+	flag.StringVar(&logFormat, "log-format", "",
+		"Log format: json or console. Overrides --zap-encoder when set.")
 	opts := zap.Options{
 		Development: true,
 	}
 	opts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
-	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
+	logOpts, err := logging.Options(logLevel, logFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid logging flags: %v\n", err)
+		os.Exit(1)
+	}
+	ctrl.SetLogger(zap.New(append([]zap.Opts{zap.UseFlagOptions(&opts)}, logOpts...)...))
+	publishHosts, err := validation.NewHostPolicy(parseList(allowedPublishHosts), parseList(deniedPublishHosts))
+	if err != nil {
+		setupLog.Error(err, "invalid publish host policy")
+		os.Exit(1)
+	}
+	var signingSecret types.NamespacedName
+	if publishSigningSecret != "" {
+		ns, name, ok := strings.Cut(publishSigningSecret, "/")
+		if !ok || ns == "" || name == "" {
+			setupLog.Error(nil, "invalid --publish-signing-secret, must be namespace/name",
+				"secret", publishSigningSecret)
+			os.Exit(1)
+		}
+		signingSecret = types.NamespacedName{Namespace: ns, Name: name}
+	}
+	if mode != "active" && mode != "observe" {
+		setupLog.Error(nil, "invalid --mode, must be active or observe", "mode", mode)
+		os.Exit(1)
+	}
+	crypto.AllowNonNISTCurves(allowNonNISTCurves)
+	metrics.SetProfileLabels(metricsProfileLabels)
+	if err := metrics.SetCustomLabels(ctrlmetrics.Registry, parseList(metricsCustomLabels)); err != nil {
+		setupLog.Error(err, "invalid --metrics-keyprofile-labels")
+		os.Exit(1)
+	}
 
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -206,8 +392,29 @@ func main() {
 		})
 	}
 
+	// Scope the cache for multi-controller sharding
+	namespaces := parseList(watchNamespaces)
+	selector, err := labels.Parse(profileSelector)
+	if err != nil {
+		setupLog.Error(err, "invalid --keyprofile-selector", "selector", profileSelector)
+		os.Exit(1)
+	}
+	cacheOptions := cache.Options{
+		ByObject: map[client.Object]cache.ByObject{
+			&openukrv1alpha1.KeyProfile{}: {Label: selector},
+		},
+	}
+	if len(namespaces) > 0 {
+		setupLog.Info("Restricting controller to namespaces", "namespaces", namespaces)
+		cacheOptions.DefaultNamespaces = make(map[string]cache.Config, len(namespaces))
+		for _, ns := range namespaces {
+			cacheOptions.DefaultNamespaces[ns] = cache.Config{}
+		}
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
+		Cache:                  cacheOptions,
 		Metrics:                metricsServerOptions,
 		WebhookServer:          webhookServer,
 		HealthProbeBindAddress: probeAddr,
@@ -232,27 +439,89 @@ func main() {
 
 	// [SEC] Initialize Core Logic Components
 	keyGen := crypto.NewKeyGenerator()
+	if keyPoolSizes != "" {
+		sizes, err := parseKeySizes(keyPoolSizes)
+		if err != nil {
+			setupLog.Error(err, "invalid --keygen-pool-rsa-sizes")
+			os.Exit(1)
+		}
+		pool, err := crypto.NewKeyPool(sizes, keyPoolDepth)
+		if err != nil {
+			setupLog.Error(err, "unable to create key pool")
+			os.Exit(1)
+		}
+		// Runs on the leader only; pooled keys are wiped when the manager stops
+		setupLog.Info("Adding RSA key pool to manager", "sizes", sizes, "depth", keyPoolDepth)
+		if err := mgr.Add(pool); err != nil {
+			setupLog.Error(err, "unable to add key pool to manager")
+			os.Exit(1)
+		}
+		keyGen = pool
+	}
 	renderer := output.NewRenderer()
-	publishManager := publish.NewManager(mgr.GetClient())
-	secretWriter := output.NewSecretWriter(mgr.GetClient(), mgr.GetScheme(), renderer)
+	// Non-nil even when empty: an empty flag disables the denylist
+	deniedPaths := append([]string{}, parseList(deniedPublishPaths)...)
+	publishOpts := []publish.Option{
+		publish.WithDeniedPublishPaths(deniedPaths),
+		publish.WithPublishHostPolicy(publishHosts),
+		publish.WithCircuitBreaker(publishCircuitThreshold, publishCircuitCooldown),
+		publish.WithTolerateUnknownPublishers(tolerateUnknownPublishers),
+	}
+	if signingSecret.Name != "" {
+		// Read uncached: the signing Secret may live outside the watched namespaces
+		publishOpts = append(publishOpts,
+			publish.WithPayloadSigner(publish.NewPayloadSigner(mgr.GetClient(), signingSecret)))
+	}
+	publishManager := publish.NewManager(mgr.GetClient(), publishOpts...)
+	secretWriter := output.NewSecretWriter(mgr.GetClient(), mgr.GetScheme(), renderer,
+		output.WithMaxSecretSize(maxSecretSize),
+		output.WithRefuseForeignSecrets(refuseForeignSecrets),
+		output.WithKeyWrapper(publish.NewAzureKeyVaultKMS(mgr.GetClient())))
+	rotationOpts := []rotation.Option{rotation.WithKeygenRateLimit(keygenRateLimit)}
+	if mode == "observe" {
+		setupLog.Info("Running in observe mode, no keys will be generated, published or written")
+		rotationOpts = append(rotationOpts, rotation.WithObserveMode())
+	}
 	rotationManager := rotation.NewManager(
 		ctrl.Log.WithName("rotation-manager"),
 		keyGen,
 		secretWriter,
 		publishManager,
+		rotationOpts...,
 	)
 
+	var progress *controller.ProgressTracker
+	if stallWindow > 0 {
+		progress = controller.NewProgressTracker(stallWindow, nil)
+	}
+
 	if err = (&controller.KeyProfileReconciler{
-		Client:          mgr.GetClient(),
-		Scheme:          mgr.GetScheme(),
-		RotationManager: rotationManager,
+		Client:              mgr.GetClient(),
+		APIReader:           mgr.GetAPIReader(),
+		Scheme:              mgr.GetScheme(),
+		RotationManager:     rotationManager,
+		WatchNamespaces:     namespaces,
+		ProfileSelector:     selector,
+		EnableCertificates:  enableCertificates,
+		RecordKeyThumbprint: recordKeyThumbprint,
+		Progress:            progress,
+		Recorder:            mgr.GetEventRecorderFor("keyprofile-controller"),
+		MinInterval:         minRotationInterval,
+		RejectShortInterval: rejectShortInterval,
+		PublishManager:      publishManager,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KeyProfile")
 		os.Exit(1)
 	}
 	// nolint:goconst
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
-		if err = webhookopenukrv1alpha1.SetupKeyProfileWebhookWithManager(mgr); err != nil {
+		if err = webhookopenukrv1alpha1.SetupKeyProfileWebhookWithManager(mgr, webhookopenukrv1alpha1.ValidationPolicy{
+			RejectInsecurePublish: !allowInsecurePublish,
+			DeniedPublishPaths:    deniedPaths,
+			PublishHosts:          publishHosts,
+			MinInterval:           minRotationInterval,
+			RejectShortInterval:   rejectShortInterval,
+		}); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "KeyProfile")
 			os.Exit(1)
 		}
@@ -275,10 +544,26 @@ func main() {
 		}
 	}
 
+	if jwksAddr != "0" {
+		// Uncached reader: serving JWKS must not cache all Secrets cluster-wide
+		setupLog.Info("Adding JWKS server to manager", "addr", jwksAddr)
+		if err := mgr.Add(jwks.NewServer(jwksAddr, mgr.GetAPIReader(), ctrl.Log.WithName("jwks"),
+			jwks.WithMetadata(jwksMetadata), jwks.WithCacheTTL(jwksCacheTTL))); err != nil {
+			setupLog.Error(err, "unable to add JWKS server to manager")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
 	}
+	if progress != nil {
+		if err := mgr.AddHealthzCheck("rotation-progress", progress.Check); err != nil {
+			setupLog.Error(err, "unable to set up rotation progress check")
+			os.Exit(1)
+		}
+	}
 	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
@@ -0,0 +1,308 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/output"
+	"github.com/openukr/openukr/pkg/rotation"
+)
+
+// certificateRequestGVK is the cert-manager CertificateRequest kind. It is handled as
+// unstructured so openUKR does not depend on the cert-manager API module.
+var certificateRequestGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "CertificateRequest",
+}
+
+// certificatePollInterval is how often a pending CertificateRequest is checked.
+const certificatePollInterval = 10 * time.Second
+
+// invalidNameChars matches characters not allowed in Kubernetes object names.
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// certificateRequestName derives a deterministic CertificateRequest name for a key.
+func certificateRequestName(profileName, keyID string) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(profileName+"-"+keyID), "-")
+	if len(name) > 253 {
+		name = name[:253]
+	}
+	return strings.Trim(name, "-")
+}
+
+// reconcileCertificate creates a CertificateRequest for a newly rotated key, or
+// for the current key when spec.certificate was added to an existing profile, and
+// stores the signed certificate in the Secret once issued. It returns whether the
+// profile status changed and, while the request is pending, a poll interval.
+func (r *KeyProfileReconciler) reconcileCertificate(
+	ctx context.Context,
+	profile *openukrv1alpha1.KeyProfile,
+	res *rotation.RotationResult,
+) (bool, time.Duration, error) {
+	changed := false
+
+	csr := res.CSR
+	request := res.Rotated
+	if !res.Rotated && certificateAdded(profile, res) {
+		// The current key stays in place: request a certificate for it instead
+		// of waiting for the next rotation
+		var err error
+		if csr, err = r.currentKeyCSR(ctx, profile); err != nil {
+			logf.FromContext(ctx).Error(err, "Failed to build certificate signing request", "keyID", res.KeyID)
+		}
+		request = true
+	}
+
+	if request {
+		if len(csr) == 0 {
+			profile.Status.CertificateRequest = ""
+			changed = r.setCertificateCondition(profile, metav1.ConditionFalse, "CSRUnavailable",
+				fmt.Sprintf("no certificate signing request was built for key %s; see controller logs", res.KeyID))
+			return changed, 0, nil
+		}
+		name, err := r.createCertificateRequest(ctx, profile, res.KeyID, csr)
+		if err != nil {
+			// The key is already rotated; report instead of failing the reconcile
+			profile.Status.CertificateRequest = ""
+			changed = r.setCertificateCondition(profile, metav1.ConditionFalse, "RequestFailed", err.Error())
+			return changed, 0, nil
+		}
+		if profile.Status.CertificateRequest != name {
+			profile.Status.CertificateRequest = name
+			changed = true
+		}
+	}
+
+	if profile.Status.CertificateRequest == "" {
+		return changed, 0, nil
+	}
+
+	pending, syncChanged, err := r.syncCertificate(ctx, profile, res.KeyID)
+	if err != nil {
+		return changed, 0, err
+	}
+	changed = changed || syncChanged
+	if pending {
+		pendingChanged := r.setCertificateCondition(profile, metav1.ConditionFalse, "Pending",
+			"waiting for CertificateRequest to be issued")
+		return changed || pendingChanged, certificatePollInterval, nil
+	}
+	return changed, 0, nil
+}
+
+// certificateAdded reports whether spec.certificate was set on a profile whose
+// current key has never had a CertificateRequest.
+func certificateAdded(profile *openukrv1alpha1.KeyProfile, res *rotation.RotationResult) bool {
+	return res.KeyID != "" && profile.Status.CertificateRequest == "" &&
+		meta.FindStatusCondition(profile.Status.Conditions, openukrv1alpha1.ConditionCertificateReady) == nil
+}
+
+// currentKeyCSR builds the certificate signing request for the key already held
+// in the profile's Secret. The key is read back like for a re-render, so
+// encrypted, jks and custom outputs are not supported.
+func (r *KeyProfileReconciler) currentKeyCSR(ctx context.Context, profile *openukrv1alpha1.KeyProfile) ([]byte, error) {
+	kp, err := output.ReadKeyPair(ctx, r.Client, profile)
+	if err != nil {
+		return nil, err
+	}
+	if kp == nil {
+		return nil, fmt.Errorf("secret for key %s not found", profile.Status.CurrentKeyID)
+	}
+	defer kp.Wipe() // [SEC:I-2]
+	spiffeID, err := output.SPIFFEIDForProfile(profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SPIFFE ID: %w", err)
+	}
+	csr, err := output.NewCSR(kp, spiffeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build CSR: %w", err)
+	}
+	return csr, nil
+}
+
+// setCertificateCondition sets the CertificateReady condition. Returns true if changed.
+func (r *KeyProfileReconciler) setCertificateCondition(
+	profile *openukrv1alpha1.KeyProfile,
+	status metav1.ConditionStatus,
+	reason, message string,
+) bool {
+	return meta.SetStatusCondition(&profile.Status.Conditions, metav1.Condition{
+		Type:               openukrv1alpha1.ConditionCertificateReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: profile.Generation,
+	})
+}
+
+// createCertificateRequest creates a cert-manager CertificateRequest for the CSR.
+// It is idempotent: an existing request of the same name is reused.
+func (r *KeyProfileReconciler) createCertificateRequest(
+	ctx context.Context,
+	profile *openukrv1alpha1.KeyProfile,
+	keyID string,
+	csr []byte,
+) (string, error) {
+	cfg := profile.Spec.Certificate
+	issuerKind := cfg.IssuerRef.Kind
+	if issuerKind == "" {
+		issuerKind = "Issuer"
+	}
+	issuerGroup := cfg.IssuerRef.Group
+	if issuerGroup == "" {
+		issuerGroup = certificateRequestGVK.Group
+	}
+
+	cr := &unstructured.Unstructured{}
+	cr.SetGroupVersionKind(certificateRequestGVK)
+	cr.SetName(certificateRequestName(profile.Name, keyID))
+	cr.SetNamespace(profile.Namespace)
+	cr.SetLabels(map[string]string{
+		"app.kubernetes.io/managed-by": "openukr",
+		"openukr.io/key-profile":       profile.Name,
+	})
+	cr.SetAnnotations(map[string]string{"openukr.io/key-id": keyID})
+
+	spec := map[string]interface{}{
+		"request": base64.StdEncoding.EncodeToString(csr),
+		"issuerRef": map[string]interface{}{
+			"name":  cfg.IssuerRef.Name,
+			"kind":  issuerKind,
+			"group": issuerGroup,
+		},
+	}
+	if cfg.Duration != nil {
+		spec["duration"] = cfg.Duration.Duration.String()
+	}
+	if err := unstructured.SetNestedMap(cr.Object, spec, "spec"); err != nil {
+		return "", fmt.Errorf("failed to build CertificateRequest: %w", err)
+	}
+
+	if err := ctrl.SetControllerReference(profile, cr, r.Scheme); err != nil {
+		return "", fmt.Errorf("failed to set controller reference: %w", err)
+	}
+	if err := r.Create(ctx, cr); err != nil && !apierrors.IsAlreadyExists(err) {
+		return "", fmt.Errorf("failed to create CertificateRequest: %w", err)
+	}
+	return cr.GetName(), nil
+}
+
+// syncCertificate checks the current CertificateRequest and, once issued, writes the
+// certificate into the profile's Secret. Returns whether the request is still pending
+// and whether the CertificateReady condition changed.
+func (r *KeyProfileReconciler) syncCertificate(
+	ctx context.Context,
+	profile *openukrv1alpha1.KeyProfile,
+	keyID string,
+) (pending, changed bool, err error) {
+	cr := &unstructured.Unstructured{}
+	cr.SetGroupVersionKind(certificateRequestGVK)
+	key := client.ObjectKey{Name: profile.Status.CertificateRequest, Namespace: profile.Namespace}
+	if err := r.Get(ctx, key, cr); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, r.setCertificateCondition(profile, metav1.ConditionFalse, "RequestNotFound",
+				fmt.Sprintf("CertificateRequest %s not found", key.Name)), nil
+		}
+		return false, false, fmt.Errorf("failed to get CertificateRequest: %w", err)
+	}
+
+	ready, reason, message := certificateRequestReady(cr)
+	switch {
+	case ready:
+	case reason == "Failed" || reason == "Denied":
+		return false, r.setCertificateCondition(profile, metav1.ConditionFalse, "Request"+reason, message), nil
+	default:
+		return true, false, nil
+	}
+
+	cert, err := nestedBytes(cr, "status", "certificate")
+	if err != nil {
+		return false, false, err
+	}
+	if len(cert) == 0 {
+		return true, false, nil
+	}
+	ca, err := nestedBytes(cr, "status", "ca")
+	if err != nil {
+		return false, false, err
+	}
+
+	secret := &corev1.Secret{}
+	secretKey := client.ObjectKey{Name: profile.Spec.Output.SecretName, Namespace: profile.Namespace}
+	if err := r.Get(ctx, secretKey, secret); err != nil {
+		return false, false, fmt.Errorf("failed to get Secret: %w", err)
+	}
+	secretChanged, err := output.SetCertificate(secret, keyID, cert, ca)
+	if err != nil {
+		// The Secret moved on to another key; the stale certificate must not be attached
+		return false, r.setCertificateCondition(profile, metav1.ConditionFalse, "KeyMismatch", err.Error()), nil
+	}
+	if secretChanged {
+		if err := r.Update(ctx, secret); err != nil {
+			return false, false, fmt.Errorf("failed to store certificate: %w", err)
+		}
+	}
+
+	return false, r.setCertificateCondition(profile, metav1.ConditionTrue, "Issued",
+		fmt.Sprintf("certificate from CertificateRequest %s stored in Secret", key.Name)), nil
+}
+
+// certificateRequestReady reads the Ready condition of a CertificateRequest.
+func certificateRequestReady(cr *unstructured.Unstructured) (bool, string, string) {
+	conditions, _, _ := unstructured.NestedSlice(cr.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok || cond["type"] != "Ready" {
+			continue
+		}
+		reason, _ := cond["reason"].(string)
+		message, _ := cond["message"].(string)
+		return cond["status"] == string(metav1.ConditionTrue), reason, message
+	}
+	return false, "", ""
+}
+
+// nestedBytes decodes a base64-encoded string field of an unstructured object.
+func nestedBytes(obj *unstructured.Unstructured, fields ...string) ([]byte, error) {
+	s, found, err := unstructured.NestedString(obj.Object, fields...)
+	if err != nil || !found {
+		return nil, err
+	}
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", strings.Join(fields, "."), err)
+	}
+	return b, nil
+}
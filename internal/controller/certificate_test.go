@@ -0,0 +1,292 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/output"
+	"github.com/openukr/openukr/pkg/rotation"
+)
+
+const testCertKeyID = "ec-P-256-20260101-abc123"
+
+func newCertificateProfile() *openukrv1alpha1.KeyProfile {
+	return &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Output: openukrv1alpha1.OutputConfig{SecretName: "keys"},
+			Certificate: &openukrv1alpha1.CertificateConfig{
+				IssuerRef: openukrv1alpha1.IssuerReference{Name: "internal-ca", Kind: "ClusterIssuer"},
+			},
+		},
+	}
+}
+
+func TestReconcileCreatesCertificateRequest(t *testing.T) {
+	t.Parallel()
+
+	profile := newCertificateProfile()
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		Build()
+	now := time.Now()
+	rm := &fakeRotationManager{result: &rotation.RotationResult{
+		Rotated:      true,
+		KeyID:        testCertKeyID,
+		RotationTime: now,
+		NextRotation: now.Add(time.Hour),
+		CSR:          []byte("test-csr"),
+	}}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm, EnableCertificates: true}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	result, err := r.Reconcile(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if result.RequeueAfter != certificatePollInterval {
+		t.Errorf("RequeueAfter = %s, want %s while pending", result.RequeueAfter, certificatePollInterval)
+	}
+
+	name := certificateRequestName("profile", testCertKeyID)
+	cr := &unstructured.Unstructured{}
+	cr.SetGroupVersionKind(certificateRequestGVK)
+	if err := c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, cr); err != nil {
+		t.Fatalf("CertificateRequest %s not created: %v", name, err)
+	}
+	request, _, _ := unstructured.NestedString(cr.Object, "spec", "request")
+	if request != base64.StdEncoding.EncodeToString([]byte("test-csr")) {
+		t.Errorf("spec.request = %q, want base64 of CSR", request)
+	}
+	issuerName, _, _ := unstructured.NestedString(cr.Object, "spec", "issuerRef", "name")
+	issuerKind, _, _ := unstructured.NestedString(cr.Object, "spec", "issuerRef", "kind")
+	if issuerName != "internal-ca" || issuerKind != "ClusterIssuer" {
+		t.Errorf("issuerRef = %s/%s, want ClusterIssuer/internal-ca", issuerKind, issuerName)
+	}
+	if refs := cr.GetOwnerReferences(); len(refs) != 1 || refs[0].Name != "profile" {
+		t.Errorf("ownerReferences = %+v, want controller reference to profile", refs)
+	}
+
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(context.Background(), req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status.CertificateRequest != name {
+		t.Errorf("Status.CertificateRequest = %q, want %q", got.Status.CertificateRequest, name)
+	}
+	cond := meta.FindStatusCondition(got.Status.Conditions, openukrv1alpha1.ConditionCertificateReady)
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "Pending" {
+		t.Errorf("CertificateReady condition = %+v, want False/Pending", cond)
+	}
+}
+
+func TestReconcileRequestsCertificateForExistingKey(t *testing.T) {
+	t.Parallel()
+
+	kp, err := crypto.NewKeyGenerator().Generate(crypto.GenerateOptions{
+		Algorithm: crypto.AlgorithmEC,
+		Params:    map[string]string{"curve": crypto.CurveP256},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer kp.Wipe()
+	encoder, err := crypto.NewKeyEncoder("PEM")
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	privPEM, err := encoder.EncodePrivate(kp.PrivateKey)
+	if err != nil {
+		t.Fatalf("EncodePrivate() error = %v", err)
+	}
+	fingerprint, err := crypto.ComputeFingerprint(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+
+	// spec.certificate was just added: the key predates it and no request exists
+	now := time.Now()
+	profile := newCertificateProfile()
+	profile.Status = openukrv1alpha1.KeyProfileStatus{
+		CurrentKeyID:          kp.KeyID,
+		CurrentKeyFingerprint: fingerprint.String(),
+		Phase:                 "Active",
+		LastRotation:          &metav1.Time{Time: now},
+		NextRotation:          &metav1.Time{Time: now.Add(time.Hour)},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "keys",
+			Namespace:   "default",
+			Annotations: map[string]string{"openukr.io/key-id": kp.KeyID},
+		},
+		Data: map[string][]byte{"tls.key": privPEM},
+	}
+	scheme := newTestScheme(t)
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile, secret).
+		WithStatusSubresource(profile).
+		Build()
+	rm := &fakeRotationManager{result: &rotation.RotationResult{
+		KeyID:        kp.KeyID,
+		Fingerprint:  fingerprint,
+		RotationTime: now,
+		NextRotation: now.Add(time.Hour),
+	}}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm, EnableCertificates: true}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	name := certificateRequestName("profile", kp.KeyID)
+	cr := &unstructured.Unstructured{}
+	cr.SetGroupVersionKind(certificateRequestGVK)
+	if err := c.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, cr); err != nil {
+		t.Fatalf("CertificateRequest %s not created for the existing key: %v", name, err)
+	}
+	request, _, _ := unstructured.NestedString(cr.Object, "spec", "request")
+	csrPEM, err := base64.StdEncoding.DecodeString(request)
+	if err != nil {
+		t.Fatalf("spec.request is not base64: %v", err)
+	}
+	block, _ := pem.Decode(csrPEM)
+	if block == nil {
+		t.Fatalf("spec.request holds no PEM block")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		t.Fatalf("ParseCertificateRequest() error = %v", err)
+	}
+	if ok, err := fingerprint.Matches(csr.PublicKey); err != nil || !ok {
+		t.Errorf("CSR public key does not match the current key (err = %v)", err)
+	}
+
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(context.Background(), req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status.CertificateRequest != name {
+		t.Errorf("Status.CertificateRequest = %q, want %q", got.Status.CertificateRequest, name)
+	}
+}
+
+func TestReconcileStoresIssuedCertificate(t *testing.T) {
+	t.Parallel()
+
+	name := certificateRequestName("profile", testCertKeyID)
+	now := time.Now()
+	profile := newCertificateProfile()
+	profile.Status = openukrv1alpha1.KeyProfileStatus{
+		CurrentKeyID:       testCertKeyID,
+		CertificateRequest: name,
+		Phase:              "Active",
+		LastRotation:       &metav1.Time{Time: now},
+		NextRotation:       &metav1.Time{Time: now.Add(time.Hour)},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "keys",
+			Namespace:   "default",
+			Annotations: map[string]string{"openukr.io/key-id": testCertKeyID},
+		},
+		Data: map[string][]byte{"tls.key": []byte("private")},
+	}
+	cr := &unstructured.Unstructured{}
+	cr.SetGroupVersionKind(certificateRequestGVK)
+	cr.SetName(name)
+	cr.SetNamespace("default")
+	cr.Object["status"] = map[string]interface{}{
+		"certificate": base64.StdEncoding.EncodeToString([]byte("signed-cert")),
+		"ca":          base64.StdEncoding.EncodeToString([]byte("ca-cert")),
+		"conditions": []interface{}{
+			map[string]interface{}{"type": "Ready", "status": "True", "reason": "Issued"},
+		},
+	}
+
+	scheme := newTestScheme(t)
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile, secret, cr).
+		WithStatusSubresource(profile).
+		Build()
+	rm := &fakeRotationManager{result: &rotation.RotationResult{
+		KeyID:        testCertKeyID,
+		RotationTime: now,
+		NextRotation: now.Add(time.Hour),
+	}}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm, EnableCertificates: true}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	var gotSecret corev1.Secret
+	if err := c.Get(context.Background(), types.NamespacedName{Name: "keys", Namespace: "default"}, &gotSecret); err != nil {
+		t.Fatalf("Get() secret error = %v", err)
+	}
+	if string(gotSecret.Data[output.CertificateDataKey]) != "signed-cert" {
+		t.Errorf("%s = %q, want signed-cert", output.CertificateDataKey, gotSecret.Data[output.CertificateDataKey])
+	}
+	if string(gotSecret.Data[output.CADataKey]) != "ca-cert" {
+		t.Errorf("%s = %q, want ca-cert", output.CADataKey, gotSecret.Data[output.CADataKey])
+	}
+
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(context.Background(), req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !meta.IsStatusConditionTrue(got.Status.Conditions, openukrv1alpha1.ConditionCertificateReady) {
+		t.Errorf("CertificateReady condition not True: %+v", got.Status.Conditions)
+	}
+}
+
+func TestCertificateRequestName(t *testing.T) {
+	t.Parallel()
+
+	if got, want := certificateRequestName("My_Profile", "EC-P-256-x"), "my-profile-ec-p-256-x"; got != want {
+		t.Errorf("certificateRequestName() = %q, want %q", got, want)
+	}
+}
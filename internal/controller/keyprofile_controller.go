@@ -18,83 +18,543 @@ package controller
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/metrics"
+	"github.com/openukr/openukr/pkg/output"
+	"github.com/openukr/openukr/pkg/publish"
 	"github.com/openukr/openukr/pkg/rotation"
+	"github.com/openukr/openukr/pkg/validation"
 )
 
 // KeyProfileReconciler reconciles a KeyProfile object
 type KeyProfileReconciler struct {
 	client.Client
+	// APIReader reads Secrets for VerifyNowAnnotation checks from the API
+	// server, so a check sees the stored material rather than the cache. Nil
+	// uses the Client.
+	APIReader       client.Reader
 	Scheme          *runtime.Scheme
 	RotationManager rotation.RotationManager
+	// Clock is used for requeue scheduling. Defaults to the real clock if nil.
+	Clock clock.PassiveClock
+	// WatchNamespaces restricts reconciliation to these namespaces. Empty means all.
+	WatchNamespaces []string
+	// ProfileSelector restricts reconciliation to matching KeyProfiles. Nil means all.
+	ProfileSelector labels.Selector
+	// EnableCertificates turns on cert-manager CertificateRequests for profiles with Spec.Certificate.
+	EnableCertificates bool
+	// RecordKeyThumbprint records the RFC 7638 JWK thumbprint of the current key
+	// in Status.CurrentKeyThumbprint.
+	RecordKeyThumbprint bool
+	// Progress tracks scheduled reconciles for the stall health check. Nil disables it.
+	Progress *ProgressTracker
+	// Recorder emits Kubernetes events for operator-triggered actions. Nil disables events.
+	Recorder record.EventRecorder
+	// MinInterval is the cluster-wide floor for intervals taken from a
+	// RotationSchedule, which admission cannot check when the schedule changes.
+	// Zero disables the check.
+	MinInterval time.Duration
+	// RejectShortInterval refuses to rotate on a schedule below MinInterval
+	// instead of warning.
+	RejectShortInterval bool
+	// PublishManager forgets the publish state of deleted profiles, such as
+	// their circuit breaker failures. Nil skips the cleanup.
+	PublishManager *publish.Manager
 }
 
+// maxObserveBackoff caps the requeue delay of overdue profiles in observe
+// mode, kept well below the default stall window of the health check.
+const maxObserveBackoff = 5 * time.Minute
+
+// ExpirePreviousAnnotation requests that the previous key's grace period end now.
+// The controller removes the annotation once the previous key material is gone.
+const ExpirePreviousAnnotation = "openukr.io/expire-previous"
+
+// VerifyNowAnnotation requests an immediate integrity check of the stored key
+// material against the recorded fingerprint. The controller removes the
+// annotation once the check ran and records it in Status.LastVerifyTime.
+const VerifyNowAnnotation = "openukr.io/verify-now"
+
 // +kubebuilder:rbac:groups=openukr.openukr.io,resources=keyprofiles,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=openukr.openukr.io,resources=keyprofiles/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=openukr.openukr.io,resources=keyprofiles/finalizers,verbs=update
+// +kubebuilder:rbac:groups=openukr.openukr.io,resources=rotationschedules,verbs=get;list;watch
+// +kubebuilder:rbac:groups=openukr.openukr.io,resources=clusterrotationschedules,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificaterequests,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
-func (r *KeyProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *KeyProfileReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, retErr error) {
 	log := logf.FromContext(ctx)
+	r.Progress.Observe(req.NamespacedName)
 
 	// 1. Fetch KeyProfile
 	var profile openukrv1alpha1.KeyProfile
 	if err := r.Get(ctx, req.NamespacedName, &profile); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Profile deleted: drop its per-profile series and publish state
+			metrics.DeleteProfile(req.Namespace, req.Name)
+			if r.PublishManager != nil {
+				r.PublishManager.Forget(req.NamespacedName)
+			}
+		}
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// Skip profiles sharded to another controller instance
+	if !r.inScope(&profile) {
+		log.V(1).Info("KeyProfile outside controller scope, skipping")
+		metrics.DeleteProfile(req.Namespace, req.Name)
+		if r.PublishManager != nil {
+			r.PublishManager.Forget(req.NamespacedName)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	var rotated bool
+	defer func() { metrics.RecordReconcile(profile.Namespace, rotated, retErr) }()
+
+	// Reject format/algorithm combinations the renderer cannot produce, and keys
+	// that must not sign, e.g. for profiles admitted before the webhook check
+	// existed. Retrying cannot help.
+	err := validation.ValidateFormatAlgorithm(
+		profile.Spec.Output.Format,
+		profile.Spec.KeySpec.Algorithm,
+		profile.Spec.KeySpec.Params,
+	)
+	if err == nil {
+		err = validation.ValidateKeyUse(profile.Spec.KeySpec.Use, profile.Spec.KeySpec.Algorithm, profile.Spec.KeySpec.Params)
+	}
+	if err != nil {
+		log.Error(err, "Key algorithm incompatible with output format or key use")
+		if profile.Status.Phase != "Error" {
+			profile.Status.Phase = "Error"
+			if uerr := r.updateStatus(ctx, &profile); uerr != nil {
+				log.Error(uerr, "Failed to update KeyProfile status")
+			}
+		}
+		return ctrl.Result{}, reconcile.TerminalError(err)
+	}
+
+	// Operator-requested early end of the previous key's grace period
+	var previousExpired bool
+	if _, ok := profile.Annotations[ExpirePreviousAnnotation]; ok {
+		var err error
+		if previousExpired, err = r.expirePrevious(ctx, &profile); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Operator-requested integrity check [SEC:T-1]
+	if _, ok := profile.Annotations[VerifyNowAnnotation]; ok {
+		if err := r.verifyNow(ctx, &profile); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	// Take the interval from a referenced RotationSchedule
+	scheduled, err := r.resolveSchedule(ctx, &profile)
+	if err != nil {
+		log.Error(err, "Failed to resolve rotation schedule")
+		r.event(&profile, corev1.EventTypeWarning, "ScheduleUnavailable", err.Error())
+		return ctrl.Result{}, err
+	}
+
 	// 2. Ensure Key (Rotate if needed)
-	res, err := r.RotationManager.EnsureKey(ctx, &profile)
+	res, err := r.RotationManager.EnsureKey(ctx, scheduled)
+	var throttled *rotation.KeygenThrottledError
+	if errors.As(err, &throttled) {
+		// Not a failure: the rotation runs once the key generation budget refills
+		log.V(1).Info("Key generation throttled, deferring rotation", "retryAfter", throttled.RetryAfter)
+		r.Progress.Scheduled(req.NamespacedName, r.now().Add(throttled.RetryAfter))
+		return ctrl.Result{RequeueAfter: throttled.RetryAfter}, nil
+	}
 	if err != nil {
 		log.Error(err, "Failed to ensure key")
+		// Record partial publish state so failed targets are visible, and a
+		// published but unpersisted key so the retry persists that same key
+		changed := r.setDegradedCondition(&profile, err)
+		if errors.Is(err, output.ErrIntegrity) {
+			changed = r.setIntegrityCondition(&profile, &rotation.RotationResult{IntegrityViolation: err}) || changed
+		}
+		if res != nil && (len(res.PublishResults) > 0 || res.PendingKeyID != "") {
+			r.setPublishStatus(&profile, res)
+			profile.Status.PendingKeyID = res.PendingKeyID
+			changed = true
+		}
+		if changed {
+			r.refreshSummary(&profile)
+			if uerr := r.updateStatus(ctx, &profile); uerr != nil {
+				log.Error(uerr, "Failed to update KeyProfile status")
+			}
+		}
 		// Exponential backoff via controller-runtime default
 		return ctrl.Result{}, err
 	}
+	rotated = res.Rotated
 
-	// 3. Update Status
-	if r.needsStatusUpdate(&profile, res) {
+	// 3. Request a CA-signed certificate for the key, if configured
+	var certChanged bool
+	var certRequeue time.Duration
+	if r.EnableCertificates && profile.Spec.Certificate != nil && !res.Observe {
+		certChanged, certRequeue, err = r.reconcileCertificate(ctx, &profile, res)
+		if err != nil {
+			log.Error(err, "Failed to reconcile certificate")
+			return ctrl.Result{}, err
+		}
+	}
+
+	// 4. Update Status
+	conditionsChanged := r.setClockSkewCondition(&profile, res)
+	conditionsChanged = r.setSuspendedCondition(&profile, res) || conditionsChanged
+	conditionsChanged = r.setSecretName(&profile, r.outputWritten(ctx, &profile, res.KeyID)) || conditionsChanged
+	conditionsChanged = r.setDegradedCondition(&profile, res.IntegrityViolation) || conditionsChanged
+	conditionsChanged = r.setIntegrityCondition(&profile, res) || conditionsChanged
+	if res.IntegrityViolation != nil {
+		r.event(&profile, corev1.EventTypeWarning, "IntegrityViolation",
+			fmt.Sprintf("Stored key material failed its integrity check and was replaced by key %s: %v",
+				res.KeyID, res.IntegrityViolation))
+	}
+	publishChanged := r.setPublishStatus(&profile, res)
+	summary := statusSummary(phaseFor(res), res.NextRotation, profile.Status.PublishStatus, r.now())
+	summaryChanged := profile.Status.Summary != summary
+	pendingChanged := profile.Status.PendingKeyID != ""
+	thumbprint, err := r.keyThumbprint(ctx, &profile, res)
+	if err != nil {
+		// Not fatal: the thumbprint is informational and retried on the next reconcile
+		log.Error(err, "Failed to compute JWK thumbprint", "keyID", res.KeyID)
+	}
+	thumbprintChanged := profile.Status.CurrentKeyThumbprint != thumbprint
+	// A forced rotation is done once the key has been rotated
+	forced := res.Rotated && rotation.ForceRotationRequested(&profile)
+	if conditionsChanged || publishChanged || certChanged || summaryChanged || pendingChanged || previousExpired ||
+		thumbprintChanged || forced || r.needsStatusUpdate(&profile, res) {
 		profile.Status.LastRotation = &metav1.Time{Time: res.RotationTime}
 		profile.Status.NextRotation = &metav1.Time{Time: res.NextRotation}
 		profile.Status.CurrentKeyID = res.KeyID
-		profile.Status.CurrentKeyFingerprint = res.Fingerprint
+		profile.Status.CurrentKeyFingerprint = statusFingerprint(&profile, res.Fingerprint)
+		profile.Status.CurrentKeyThumbprint = thumbprint
+		profile.Status.PreviousKeyID = res.PreviousKeyID
+		profile.Status.PreviousKeyFingerprint = statusFingerprint(&profile, res.PreviousFingerprint)
+		profile.Status.PendingKeyID = ""
+		profile.Status.NextKeyID = res.NextKeyID
+		profile.Status.NextKeyFingerprint = statusFingerprint(&profile, res.NextFingerprint)
+		if res.Rotated {
+			profile.Status.LastRotationReason = res.Reason
+		}
+		if forced {
+			profile.Status.LastForcedRotation = profile.Annotations[rotation.ForceRotateAnnotation]
+		}
 
 		// Set Phase
-		profile.Status.Phase = "Active" // Simplified for MVP
+		profile.Status.Phase = phaseFor(res)
+		profile.Status.Mode = modeFor(res)
+		profile.Status.Summary = summary
 
-		if err := r.Status().Update(ctx, &profile); err != nil {
+		if err := r.updateStatus(ctx, &profile); err != nil {
 			log.Error(err, "Failed to update KeyProfile status")
 			return ctrl.Result{}, err
 		}
 	}
 
-	// 4. Schedule Requeue
+	if forced {
+		r.event(&profile, corev1.EventTypeNormal, "ForcedRotation",
+			fmt.Sprintf("Key rotated to %s on request", res.KeyID))
+	}
+	// Status.LastForcedRotation marks the request handled: removing the
+	// annotation is cleanup, retried on the next reconcile
+	if value, ok := profile.Annotations[rotation.ForceRotateAnnotation]; ok && value == profile.Status.LastForcedRotation {
+		patch := client.MergeFrom(profile.DeepCopy())
+		delete(profile.Annotations, rotation.ForceRotateAnnotation)
+		if err := r.Patch(ctx, &profile, patch); err != nil {
+			log.Error(err, "Failed to remove handled annotation", "annotation", rotation.ForceRotateAnnotation)
+		}
+	}
+
+	// 5. Schedule Requeue
 	if !res.NextRotation.IsZero() {
-		requeueAfter := time.Until(res.NextRotation)
-		if requeueAfter < 0 {
+		metrics.SetNextRotation(profile.Namespace, profile.Name, res.NextRotation)
+		requeueAfter := res.NextRotation.Sub(r.now())
+		// Wake up to publish the next key ahead of the rotation
+		if !res.NextKeyDue.IsZero() && res.NextKeyDue.Before(res.NextRotation) {
+			requeueAfter = res.NextKeyDue.Sub(r.now())
+		}
+		switch {
+		case requeueAfter < 0 && res.Observe:
+			// Observe mode never rotates, so the profile stays overdue: back off
+			// with the time overdue instead of polling every second
+			requeueAfter = min(max(-requeueAfter, time.Second), maxObserveBackoff)
+		case requeueAfter < 0:
 			requeueAfter = 1 * time.Second // Retry immediately if overdue
 		}
+		// Never sleep longer than one interval, even if the schedule is skewed
+		if interval := scheduled.Spec.Rotation.Interval.Duration; interval > 0 && requeueAfter > interval {
+			requeueAfter = interval
+		}
+		// Poll a pending CertificateRequest sooner
+		if certRequeue > 0 && certRequeue < requeueAfter {
+			requeueAfter = certRequeue
+		}
 		log.V(1).Info("Requeue scheduled", "after", requeueAfter)
+		r.Progress.Scheduled(req.NamespacedName, r.now().Add(requeueAfter))
 		return ctrl.Result{RequeueAfter: requeueAfter}, nil
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: certRequeue}, nil
+}
+
+// resolveSchedule returns the profile to rotate: profile itself, or for a
+// profile taking its interval from Spec.Rotation.ScheduleRef a copy carrying
+// the schedule's interval. An inline interval overrides the reference.
+// The copy is only passed to the RotationManager; status is written to profile.
+func (r *KeyProfileReconciler) resolveSchedule(
+	ctx context.Context,
+	profile *openukrv1alpha1.KeyProfile,
+) (*openukrv1alpha1.KeyProfile, error) {
+	ref := profile.Spec.Rotation.ScheduleRef
+	if ref == nil || profile.Spec.Rotation.Interval.Duration > 0 {
+		return profile, nil
+	}
+
+	interval, err := rotation.ScheduleInterval(ctx, r.Client, profile.Namespace, ref)
+	if err != nil {
+		return nil, err
+	}
+	kind := rotation.ScheduleKind(ref)
+	if err := validation.ValidateRotationPolicy(interval, profile.Spec.Rotation.GracePeriod.Duration); err != nil {
+		return nil, fmt.Errorf("%s %q: %w", kind, ref.Name, err)
+	}
+	if err := validation.ValidateRotateBeforeExpiry(interval, profile.Spec.Rotation.RotateBeforeExpiry.Duration); err != nil {
+		return nil, fmt.Errorf("%s %q: %w", kind, ref.Name, err)
+	}
+	if msg := validation.CheckMinInterval(interval, r.MinInterval); msg != "" {
+		if r.RejectShortInterval {
+			return nil, fmt.Errorf("%s %q: %s", kind, ref.Name, msg)
+		}
+		r.event(profile, corev1.EventTypeWarning, "ShortInterval", fmt.Sprintf("%s %q: %s", kind, ref.Name, msg))
+	}
+
+	scheduled := profile.DeepCopy()
+	scheduled.Spec.Rotation.Interval = metav1.Duration{Duration: interval}
+	return scheduled, nil
+}
+
+// profilesForSchedule maps a RotationSchedule or ClusterRotationSchedule event
+// to the in-scope KeyProfiles referencing it, so a cadence change reschedules
+// them immediately.
+func (r *KeyProfileReconciler) profilesForSchedule(ctx context.Context, obj client.Object) []reconcile.Request {
+	kind := openukrv1alpha1.RotationScheduleKind
+	opts := []client.ListOption{client.InNamespace(obj.GetNamespace())}
+	if _, ok := obj.(*openukrv1alpha1.ClusterRotationSchedule); ok {
+		kind, opts = openukrv1alpha1.ClusterRotationScheduleKind, nil
+	}
+	var profiles openukrv1alpha1.KeyProfileList
+	if err := r.List(ctx, &profiles, opts...); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list KeyProfiles for schedule", "kind", kind, "schedule", obj.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for i := range profiles.Items {
+		p := &profiles.Items[i]
+		ref := p.Spec.Rotation.ScheduleRef
+		if ref == nil || ref.Name != obj.GetName() || rotation.ScheduleKind(ref) != kind || !r.inScope(p) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(p)})
+	}
+	return requests
+}
+
+// expirePrevious handles ExpirePreviousAnnotation: it drops the previous key
+// material, removes the annotation and clears the previous key from the
+// in-memory status. Returns true if the status must be written.
+func (r *KeyProfileReconciler) expirePrevious(ctx context.Context, profile *openukrv1alpha1.KeyProfile) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	err := r.RotationManager.ExpirePrevious(ctx, profile)
+	if errors.Is(err, rotation.ErrObserveMode) {
+		// Keep the annotation so the request applies once rotation is enabled
+		log.V(1).Info("Not expiring previous key in observe mode")
+		return false, nil
+	}
+	if err != nil {
+		r.event(profile, corev1.EventTypeWarning, "ExpirePreviousFailed", err.Error())
+		return false, err
+	}
+
+	previous := profile.Status.PreviousKeyID
+	patch := client.MergeFrom(profile.DeepCopy())
+	delete(profile.Annotations, ExpirePreviousAnnotation)
+	if err := r.Patch(ctx, profile, patch); err != nil {
+		return false, fmt.Errorf("failed to remove %s annotation: %w", ExpirePreviousAnnotation, err)
+	}
+	if previous == "" {
+		return false, nil
+	}
+
+	profile.Status.PreviousKeyID = ""
+	profile.Status.PreviousKeyFingerprint = ""
+	r.event(profile, corev1.EventTypeNormal, "PreviousKeyExpired",
+		fmt.Sprintf("Previous key %s expired before the end of its grace period", previous))
+	return true, nil
+}
+
+// verifyNow handles VerifyNowAnnotation: it checks the stored key material,
+// read from the API server, against the recorded fingerprint, writes the
+// IntegrityVerified condition and LastVerifyTime, and removes the annotation.
+// A tampered Secret is replaced by the corrective rotation of the following
+// EnsureKey. Errors other than integrity violations keep the annotation for
+// the retry.
+func (r *KeyProfileReconciler) verifyNow(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error {
+	reader := r.APIReader
+	if reader == nil {
+		reader = r.Client
+	}
+	err := output.VerifyProfile(ctx, reader, profile)
+	if err != nil && !errors.Is(err, output.ErrIntegrity) {
+		r.event(profile, corev1.EventTypeWarning, "VerifyFailed", err.Error())
+		return fmt.Errorf("failed to verify Secret: %w", err)
+	}
+
+	// The result is recorded before the annotation goes, so a failed status
+	// write leaves the request in place for the retry instead of losing it
+	r.setIntegrityCondition(profile, &rotation.RotationResult{IntegrityVerified: err == nil, IntegrityViolation: err})
+	profile.Status.LastVerifyTime = &metav1.Time{Time: r.now()}
+	if uerr := r.updateStatus(ctx, profile); uerr != nil {
+		return fmt.Errorf("failed to record integrity check: %w", uerr)
+	}
+	patch := client.MergeFrom(profile.DeepCopy())
+	delete(profile.Annotations, VerifyNowAnnotation)
+	if perr := r.Patch(ctx, profile, patch); perr != nil {
+		return fmt.Errorf("failed to remove %s annotation: %w", VerifyNowAnnotation, perr)
+	}
+
+	if err != nil {
+		r.event(profile, corev1.EventTypeWarning, "VerifyFailed",
+			fmt.Sprintf("Requested integrity check failed: %v", err))
+	} else {
+		r.event(profile, corev1.EventTypeNormal, "Verified", "Requested integrity check passed")
+	}
+	return nil
+}
+
+// updateStatus writes profile's status, retrying on conflict. A conflict means
+// the object changed since it was read; the computed status is reapplied to a
+// fresh copy so a completed rotation is not lost to a requeue that could
+// misread the stale status.
+func (r *KeyProfileReconciler) updateStatus(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error {
+	status := profile.Status.DeepCopy()
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := r.Status().Update(ctx, profile)
+		if !apierrors.IsConflict(err) {
+			return err
+		}
+		var latest openukrv1alpha1.KeyProfile
+		if gerr := r.Get(ctx, client.ObjectKeyFromObject(profile), &latest); gerr != nil {
+			return gerr
+		}
+		status.DeepCopyInto(&latest.Status)
+		*profile = latest
+		return err
+	})
+}
+
+// event records a Kubernetes event on profile if a Recorder is configured.
+func (r *KeyProfileReconciler) event(profile *openukrv1alpha1.KeyProfile, eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(profile, eventType, reason, message)
+	}
+}
+
+// statusFingerprint returns the status value recording fp: empty if the
+// profile keeps fingerprints out of its status.
+func statusFingerprint(profile *openukrv1alpha1.KeyProfile, fp crypto.Fingerprint) string {
+	if profile.Spec.DisableFingerprintStatus {
+		return ""
+	}
+	return fp.String()
+}
+
+// keyThumbprint returns the status value for Status.CurrentKeyThumbprint: the
+// JWK thumbprint of the key res.KeyID if RecordKeyThumbprint is set, otherwise
+// empty, as it is for profiles that keep fingerprints out of their status. A
+// thumbprint already recorded for the key is kept; a new one is computed from
+// the public key in the profile's Secret. Formats without parseable public key
+// material yield no thumbprint.
+func (r *KeyProfileReconciler) keyThumbprint(ctx context.Context, profile *openukrv1alpha1.KeyProfile,
+	res *rotation.RotationResult) (string, error) {
+	if !r.RecordKeyThumbprint || profile.Spec.DisableFingerprintStatus || res.KeyID == "" {
+		return "", nil
+	}
+	if profile.Status.CurrentKeyID == res.KeyID && profile.Status.CurrentKeyThumbprint != "" {
+		return profile.Status.CurrentKeyThumbprint, nil
+	}
+
+	current, _, err := output.SecretNames(ctx, r.Client, profile)
+	if err != nil || current == "" {
+		return "", client.IgnoreNotFound(err)
+	}
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Name: current, Namespace: profile.Namespace}, &secret); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	info, err := output.InspectSecret(&secret)
+	if err != nil {
+		return "", err
+	}
+	if info.KeyID != res.KeyID || info.PublicKey == nil || res.Fingerprint.IsZero() {
+		return "", nil
+	}
+	// Only the key the rotation vouches for is recorded, not whatever the
+	// Secret holds under its ID [SEC:T-1]
+	ok, err := res.Fingerprint.Matches(info.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", fmt.Errorf("%w: public key of %s in Secret %s does not match fingerprint %s",
+			output.ErrIntegrity, res.KeyID, current, res.Fingerprint)
+	}
+	return crypto.JWKThumbprint(info.PublicKey)
 }
 
 func (r *KeyProfileReconciler) needsStatusUpdate(profile *openukrv1alpha1.KeyProfile, res *rotation.RotationResult) bool {
 	if profile.Status.CurrentKeyID != res.KeyID {
 		return true
 	}
-	if profile.Status.CurrentKeyFingerprint != res.Fingerprint {
+	if profile.Status.CurrentKeyFingerprint != statusFingerprint(profile, res.Fingerprint) {
+		return true
+	}
+	if profile.Status.PreviousKeyID != res.PreviousKeyID {
+		return true
+	}
+	if profile.Status.PreviousKeyFingerprint != statusFingerprint(profile, res.PreviousFingerprint) {
 		return true
 	}
 	if profile.Status.LastRotation == nil || !profile.Status.LastRotation.Time.Equal(res.RotationTime) {
@@ -103,16 +563,332 @@ func (r *KeyProfileReconciler) needsStatusUpdate(profile *openukrv1alpha1.KeyPro
 	if profile.Status.NextRotation == nil || !profile.Status.NextRotation.Time.Equal(res.NextRotation) {
 		return true
 	}
-	if profile.Status.Phase == "" {
+	if profile.Status.Phase != phaseFor(res) {
+		return true
+	}
+	if profile.Status.Mode != modeFor(res) {
+		return true
+	}
+	if profile.Status.NextKeyID != res.NextKeyID {
 		return true
 	}
 	return false
 }
 
+// modeFor derives Status.Mode from the rotation result.
+func modeFor(res *rotation.RotationResult) string {
+	if res.Observe {
+		return "Observe"
+	}
+	return ""
+}
+
+// phaseFor derives Status.Phase from the rotation result.
+func phaseFor(res *rotation.RotationResult) string {
+	if !res.PausedUntil.IsZero() {
+		return "Suspended"
+	}
+	return "Active" // Simplified for MVP
+}
+
+// statusSummary renders Status.Summary, e.g. "Active, next rotation at 2026-01-02T15:04:05Z, 2/2 targets published".
+// Parts without data (no phase, no scheduled rotation, no publish targets) are omitted.
+// The rotation time is absolute so the summary only changes when the schedule does.
+func statusSummary(phase string, next time.Time, targets []openukrv1alpha1.TargetStatus, now time.Time) string {
+	var parts []string
+	if phase != "" {
+		parts = append(parts, phase)
+	}
+	switch {
+	case next.IsZero():
+	case next.After(now):
+		parts = append(parts, "next rotation at "+next.UTC().Format(time.RFC3339))
+	default:
+		parts = append(parts, "rotation overdue")
+	}
+	if len(targets) > 0 {
+		published := 0
+		for _, t := range targets {
+			if t.Error == "" {
+				published++
+			}
+		}
+		parts = append(parts, fmt.Sprintf("%d/%d targets published", published, len(targets)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// refreshSummary recomputes Status.Summary from the current status fields.
+func (r *KeyProfileReconciler) refreshSummary(profile *openukrv1alpha1.KeyProfile) {
+	var next time.Time
+	if profile.Status.NextRotation != nil {
+		next = profile.Status.NextRotation.Time
+	}
+	profile.Status.Summary = statusSummary(profile.Status.Phase, next, profile.Status.PublishStatus, r.now())
+}
+
+// now returns the current time from the injected Clock.
+func (r *KeyProfileReconciler) now() time.Time {
+	if r.Clock == nil {
+		return clock.RealClock{}.Now()
+	}
+	return r.Clock.Now()
+}
+
+// setClockSkewCondition records detected clock skew as a status condition.
+// The condition is only reset to False once it has been raised. Returns true if changed.
+func (r *KeyProfileReconciler) setClockSkewCondition(profile *openukrv1alpha1.KeyProfile, res *rotation.RotationResult) bool {
+	if res.ClockSkew > 0 {
+		return meta.SetStatusCondition(&profile.Status.Conditions, metav1.Condition{
+			Type:               openukrv1alpha1.ConditionClockSkew,
+			Status:             metav1.ConditionTrue,
+			Reason:             "LastRotationInFuture",
+			Message:            fmt.Sprintf("lastRotation was %s in the future; key rotated to recover", res.ClockSkew),
+			ObservedGeneration: profile.Generation,
+		})
+	}
+	if meta.FindStatusCondition(profile.Status.Conditions, openukrv1alpha1.ConditionClockSkew) == nil {
+		return false
+	}
+	return meta.SetStatusCondition(&profile.Status.Conditions, metav1.Condition{
+		Type:               openukrv1alpha1.ConditionClockSkew,
+		Status:             metav1.ConditionFalse,
+		Reason:             "ClockInSync",
+		Message:            "lastRotation is within the tolerated clock skew",
+		ObservedGeneration: profile.Generation,
+	})
+}
+
+// setSuspendedCondition records whether rotation is paused by Spec.Rotation.PauseUntil.
+// The condition is only reset to False once it has been raised. Returns true if changed.
+func (r *KeyProfileReconciler) setSuspendedCondition(profile *openukrv1alpha1.KeyProfile, res *rotation.RotationResult) bool {
+	if !res.PausedUntil.IsZero() {
+		return meta.SetStatusCondition(&profile.Status.Conditions, metav1.Condition{
+			Type:               openukrv1alpha1.ConditionSuspended,
+			Status:             metav1.ConditionTrue,
+			Reason:             "PauseUntil",
+			Message:            fmt.Sprintf("rotation paused until %s", res.PausedUntil.UTC().Format(time.RFC3339)),
+			ObservedGeneration: profile.Generation,
+		})
+	}
+	if meta.FindStatusCondition(profile.Status.Conditions, openukrv1alpha1.ConditionSuspended) == nil {
+		return false
+	}
+	return meta.SetStatusCondition(&profile.Status.Conditions, metav1.Condition{
+		Type:               openukrv1alpha1.ConditionSuspended,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Resumed",
+		Message:            "rotation schedule active",
+		ObservedGeneration: profile.Generation,
+	})
+}
+
+// setDegradedCondition raises the Degraded condition when err shows the Secret
+// cannot be written as rendered or its key material failed the integrity check,
+// and clears it once a reconcile succeeds.
+// Returns true if changed.
+func (r *KeyProfileReconciler) setDegradedCondition(profile *openukrv1alpha1.KeyProfile, err error) bool {
+	if errors.Is(err, output.ErrSecretTooLarge) {
+		return meta.SetStatusCondition(&profile.Status.Conditions, metav1.Condition{
+			Type:               openukrv1alpha1.ConditionDegraded,
+			Status:             metav1.ConditionTrue,
+			Reason:             "SecretTooLarge",
+			Message:            err.Error(),
+			ObservedGeneration: profile.Generation,
+		})
+	}
+	if errors.Is(err, output.ErrIntegrity) {
+		return meta.SetStatusCondition(&profile.Status.Conditions, metav1.Condition{
+			Type:               openukrv1alpha1.ConditionDegraded,
+			Status:             metav1.ConditionTrue,
+			Reason:             "IntegrityViolation",
+			Message:            err.Error(),
+			ObservedGeneration: profile.Generation,
+		})
+	}
+	if err != nil || meta.FindStatusCondition(profile.Status.Conditions, openukrv1alpha1.ConditionDegraded) == nil {
+		return false
+	}
+	return meta.SetStatusCondition(&profile.Status.Conditions, metav1.Condition{
+		Type:               openukrv1alpha1.ConditionDegraded,
+		Status:             metav1.ConditionFalse,
+		Reason:             "SecretWritten",
+		Message:            "Secret written successfully",
+		ObservedGeneration: profile.Generation,
+	})
+}
+
+// setIntegrityCondition records the outcome of the integrity check of the stored
+// key material [SEC:T-1]: False with reason Tampered on a violation, True with
+// reason Corrected once a corrective rotation replaced the material, and True
+// once the Secret verified. Reconciles that did not check leave it unchanged.
+// Returns true if the status changed.
+func (r *KeyProfileReconciler) setIntegrityCondition(profile *openukrv1alpha1.KeyProfile, res *rotation.RotationResult) bool {
+	cond := metav1.Condition{
+		Type:               openukrv1alpha1.ConditionIntegrityVerified,
+		ObservedGeneration: profile.Generation,
+	}
+	switch {
+	case res.IntegrityViolation != nil && res.Rotated:
+		cond.Status, cond.Reason = metav1.ConditionTrue, "Corrected"
+		cond.Message = fmt.Sprintf("Key material that failed its integrity check was replaced by key %s: %v",
+			res.KeyID, res.IntegrityViolation)
+	case res.IntegrityViolation != nil:
+		cond.Status, cond.Reason, cond.Message = metav1.ConditionFalse, "Tampered", res.IntegrityViolation.Error()
+	case res.IntegrityVerified:
+		cond.Status, cond.Reason = metav1.ConditionTrue, "Verified"
+		cond.Message = "Stored key material matches the recorded fingerprint"
+		if profile.Spec.DisableFingerprintStatus {
+			cond.Message = "Stored key pair is consistent; no fingerprint is recorded"
+		}
+	default:
+		return false
+	}
+	return meta.SetStatusCondition(&profile.Status.Conditions, cond)
+}
+
+// outputWritten reports whether the profile's current output Secret holds key
+// keyID. Read errors count as not written; the check is retried on the next reconcile.
+func (r *KeyProfileReconciler) outputWritten(ctx context.Context, profile *openukrv1alpha1.KeyProfile, keyID string) bool {
+	current, _, err := output.SecretNames(ctx, r.Client, profile)
+	if err != nil || current == "" {
+		return false
+	}
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Name: current, Namespace: profile.Namespace}, &secret); err != nil {
+		return false
+	}
+	return secret.Annotations["openukr.io/key-id"] == keyID
+}
+
+// setSecretName records Spec.Output.SecretName in the status once written, the
+// Secret under it holds the current key. When it differs from the name last
+// written, the SecretRenamed condition notes that the old Secret is orphaned: it
+// is no longer updated and only garbage-collected with the profile.
+// Returns true if the status changed.
+func (r *KeyProfileReconciler) setSecretName(profile *openukrv1alpha1.KeyProfile, written bool) bool {
+	previous, current := profile.Status.SecretName, profile.Spec.Output.SecretName
+	if previous == current || !written {
+		return false
+	}
+	profile.Status.SecretName = current
+	if previous != "" {
+		meta.SetStatusCondition(&profile.Status.Conditions, metav1.Condition{
+			Type:               openukrv1alpha1.ConditionSecretRenamed,
+			Status:             metav1.ConditionTrue,
+			Reason:             "SecretNameChanged",
+			Message:            fmt.Sprintf("output moved from Secret %q to %q; %q is no longer updated", previous, current, previous),
+			ObservedGeneration: profile.Generation,
+		})
+	}
+	return true
+}
+
+// setPublishStatus updates Status.PublishStatus from the rotation's publish results
+// and trims entries for targets no longer configured. Returns true if changed.
+func (r *KeyProfileReconciler) setPublishStatus(profile *openukrv1alpha1.KeyProfile, res *rotation.RotationResult) bool {
+	changed := false
+	if res.PublishResults != nil {
+		previous := profile.Status.PublishStatus
+		profile.Status.PublishStatus = buildPublishStatus(previous, res.PublishResults, res.KeyID, r.now())
+		changed = true
+		for i, status := range profile.Status.PublishStatus {
+			if status.CircuitOpenUntil != nil && (i >= len(previous) || previous[i].CircuitOpenUntil == nil) {
+				r.event(profile, corev1.EventTypeWarning, "PublishCircuitOpen", fmt.Sprintf(
+					"Publish target %d (%s %s) failed repeatedly and is skipped until %s",
+					i, status.Type, status.Target, status.CircuitOpenUntil.UTC().Format(time.RFC3339)))
+			}
+		}
+		for i, result := range res.PublishResults {
+			if result.Skipped != "" {
+				r.event(profile, corev1.EventTypeWarning, "PublishTargetSkipped", fmt.Sprintf(
+					"Publish target %d skipped: %s; the controller may be older than the CRD", i, result.Skipped))
+			}
+		}
+	}
+	// Keep status bounded to the configured targets
+	if n := len(profile.Spec.Publish); len(profile.Status.PublishStatus) > n {
+		profile.Status.PublishStatus = profile.Status.PublishStatus[:n]
+		changed = true
+	}
+	return changed
+}
+
+// buildPublishStatus merges publish results into the existing per-target status.
+// Failed and skipped targets keep their last successfully published key and
+// record the error or skip reason.
+func buildPublishStatus(
+	existing []openukrv1alpha1.TargetStatus,
+	results []publish.TargetResult,
+	keyID string,
+	at time.Time,
+) []openukrv1alpha1.TargetStatus {
+	statuses := make([]openukrv1alpha1.TargetStatus, len(results))
+	for i, result := range results {
+		status := openukrv1alpha1.TargetStatus{Type: result.Type, Target: result.Target}
+		if i < len(existing) && existing[i].Type == result.Type && existing[i].Target == result.Target {
+			status.LastPublishedKeyID = existing[i].LastPublishedKeyID
+			status.LastPublishTime = existing[i].LastPublishTime
+		}
+		if !result.CircuitOpenUntil.IsZero() {
+			status.CircuitOpenUntil = &metav1.Time{Time: result.CircuitOpenUntil}
+		}
+		switch {
+		case result.Err != nil:
+			status.Error = result.Err.Error()
+		case result.Skipped != "":
+			status.Error = "skipped: " + result.Skipped
+		default:
+			status.LastPublishedKeyID = keyID
+			status.LastPublishTime = &metav1.Time{Time: at}
+		}
+		statuses[i] = status
+	}
+	return statuses
+}
+
+// inScope reports whether obj falls within the configured namespaces and selector.
+func (r *KeyProfileReconciler) inScope(obj client.Object) bool {
+	if len(r.WatchNamespaces) > 0 {
+		found := false
+		for _, ns := range r.WatchNamespaces {
+			if obj.GetNamespace() == ns {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if r.ProfileSelector != nil && !r.ProfileSelector.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+	return true
+}
+
+// specOrMetadataChanged passes KeyProfile updates that change the spec
+// (generation), annotations (e.g. ExpirePreviousAnnotation) or labels (scope,
+// metric labels). Status-only updates, including the controller's own, are
+// dropped so they cannot re-enter the rotation path. Create, delete and
+// generic events pass, and requeues scheduled through RequeueAfter bypass
+// predicates entirely, so time-based rotation is unaffected.
+var specOrMetadataChanged = predicate.Or(
+	predicate.GenerationChangedPredicate{},
+	predicate.AnnotationChangedPredicate{},
+	predicate.LabelChangedPredicate{},
+)
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *KeyProfileReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&openukrv1alpha1.KeyProfile{}).
+		For(&openukrv1alpha1.KeyProfile{}, builder.WithPredicates(
+			predicate.NewPredicateFuncs(r.inScope),
+			specOrMetadataChanged,
+		)).
+		// Scope is applied to the mapped profiles, as ClusterRotationSchedules have no namespace
+		Watches(&openukrv1alpha1.RotationSchedule{}, handler.EnqueueRequestsFromMapFunc(r.profilesForSchedule)).
+		Watches(&openukrv1alpha1.ClusterRotationSchedule{}, handler.EnqueueRequestsFromMapFunc(r.profilesForSchedule)).
 		Named("keyprofile").
 		Complete(r)
 }
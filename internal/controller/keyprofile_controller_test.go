@@ -0,0 +1,1869 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	clocktesting "k8s.io/utils/clock/testing"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/metrics"
+	"github.com/openukr/openukr/pkg/output"
+	"github.com/openukr/openukr/pkg/publish"
+	"github.com/openukr/openukr/pkg/rotation"
+)
+
+// fakeRotationManager records which profiles were reconciled.
+// If result or err is set, it returns them instead of a successful rotation.
+type fakeRotationManager struct {
+	calls   []string
+	result  *rotation.RotationResult
+	err     error
+	expired []string
+}
+
+func (m *fakeRotationManager) ExpirePrevious(_ context.Context, profile *openukrv1alpha1.KeyProfile) error {
+	m.expired = append(m.expired, profile.Status.PreviousKeyID)
+	return nil
+}
+
+func (m *fakeRotationManager) EnsureKey(_ context.Context, profile *openukrv1alpha1.KeyProfile) (*rotation.RotationResult, error) {
+	m.calls = append(m.calls, profile.Namespace+"/"+profile.Name)
+	if m.result != nil || m.err != nil {
+		return m.result, m.err
+	}
+	now := time.Now()
+	return &rotation.RotationResult{
+		Rotated:      true,
+		KeyID:        "ec-P-256-test",
+		RotationTime: now,
+		NextRotation: now.Add(time.Hour),
+	}, nil
+}
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := openukrv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	return scheme
+}
+
+func TestReconcileRespectsScope(t *testing.T) {
+	t.Parallel()
+
+	selector, err := labels.Parse("shard=a")
+	if err != nil {
+		t.Fatalf("labels.Parse() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		namespace     string
+		labels        map[string]string
+		wantReconcile bool
+	}{
+		{
+			name:          "in namespace and selector",
+			namespace:     "team-a",
+			labels:        map[string]string{"shard": "a"},
+			wantReconcile: true,
+		},
+		{
+			name:          "outside namespace",
+			namespace:     "team-b",
+			labels:        map[string]string{"shard": "a"},
+			wantReconcile: false,
+		},
+		{
+			name:          "outside selector",
+			namespace:     "team-a",
+			labels:        map[string]string{"shard": "b"},
+			wantReconcile: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			profile := &openukrv1alpha1.KeyProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: tt.namespace, Labels: tt.labels},
+			}
+			scheme := newTestScheme(t)
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(profile).
+				WithStatusSubresource(profile).
+				Build()
+			rm := &fakeRotationManager{}
+			r := &KeyProfileReconciler{
+				Client:          c,
+				Scheme:          scheme,
+				RotationManager: rm,
+				WatchNamespaces: []string{"team-a"},
+				ProfileSelector: selector,
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: tt.namespace}}
+			if _, err := r.Reconcile(context.Background(), req); err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if got := len(rm.calls) > 0; got != tt.wantReconcile {
+				t.Errorf("reconciled = %v, want %v", got, tt.wantReconcile)
+			}
+		})
+	}
+}
+
+func TestReconcileRecordsPublishStatus(t *testing.T) {
+	t.Parallel()
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Publish: []openukrv1alpha1.PublishTarget{
+				{Type: "http", Config: map[string]string{"endpoint": "https://ok.example"}},
+				{Type: "http", Config: map[string]string{"endpoint": "https://down.example"}},
+			},
+		},
+	}
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		Build()
+	rm := &fakeRotationManager{
+		result: &rotation.RotationResult{
+			KeyID: "ec-P-256-new",
+			PublishResults: []publish.TargetResult{
+				{Type: "http", Target: "https://ok.example"},
+				{
+					Type:             "http",
+					Target:           "https://down.example",
+					Err:              errors.New("connection refused"),
+					CircuitOpenUntil: time.Date(2026, 1, 1, 0, 10, 0, 0, time.UTC),
+				},
+			},
+		},
+		err: errors.New("failed to publish public key"),
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm, Recorder: recorder}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(context.Background(), req); err == nil {
+		t.Fatal("Reconcile() succeeded, want publish error")
+	}
+
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(context.Background(), req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(got.Status.PublishStatus) != 2 {
+		t.Fatalf("PublishStatus has %d entries, want 2", len(got.Status.PublishStatus))
+	}
+	ok, down := got.Status.PublishStatus[0], got.Status.PublishStatus[1]
+	if ok.LastPublishedKeyID != "ec-P-256-new" || ok.Error != "" || ok.LastPublishTime == nil {
+		t.Errorf("successful target status = %+v, want keyID ec-P-256-new and no error", ok)
+	}
+	if down.LastPublishedKeyID != "" || down.Error == "" {
+		t.Errorf("failed target status = %+v, want error and no keyID", down)
+	}
+	if ok.CircuitOpenUntil != nil || down.CircuitOpenUntil == nil {
+		t.Errorf("CircuitOpenUntil = {%v, %v}, want set for the failed target only",
+			ok.CircuitOpenUntil, down.CircuitOpenUntil)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, "PublishCircuitOpen") {
+			t.Errorf("event = %q, want PublishCircuitOpen", event)
+		}
+	default:
+		t.Error("no PublishCircuitOpen event recorded")
+	}
+}
+
+func TestReconcileRecordsSkippedPublishTarget(t *testing.T) {
+	t.Parallel()
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Publish: []openukrv1alpha1.PublishTarget{{Type: "future"}},
+		},
+	}
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		Build()
+	rm := &fakeRotationManager{
+		result: &rotation.RotationResult{
+			KeyID:          "ec-P-256-new",
+			PublishResults: []publish.TargetResult{{Type: "future", Skipped: `unknown publisher type "future"`}},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm, Recorder: recorder}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(context.Background(), req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(context.Background(), req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(got.Status.PublishStatus) != 1 {
+		t.Fatalf("PublishStatus has %d entries, want 1", len(got.Status.PublishStatus))
+	}
+	if s := got.Status.PublishStatus[0]; s.LastPublishedKeyID != "" || !strings.HasPrefix(s.Error, "skipped:") {
+		t.Errorf("skipped target status = %+v, want a skip reason and no keyID", s)
+	}
+	found := false
+	for len(recorder.Events) > 0 {
+		if strings.Contains(<-recorder.Events, "PublishTargetSkipped") {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("no PublishTargetSkipped event recorded")
+	}
+}
+
+func TestReconcileTwiceLeavesSecretUnchanged(t *testing.T) {
+	t.Parallel()
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			KeySpec: openukrv1alpha1.KeySpec{
+				Algorithm: crypto.AlgorithmEC,
+				Params:    map[string]string{"curve": crypto.CurveP256},
+			},
+			Rotation: openukrv1alpha1.RotationPolicy{
+				Interval:    metav1.Duration{Duration: 24 * time.Hour},
+				GracePeriod: metav1.Duration{Duration: time.Hour},
+			},
+			Output: openukrv1alpha1.OutputConfig{SecretName: "keys", Format: output.FormatSplitPEM},
+		},
+	}
+	scheme := newTestScheme(t)
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		Build()
+	rm := rotation.NewManager(
+		logr.Discard(),
+		crypto.NewKeyGenerator(),
+		output.NewSecretWriter(c, scheme, output.NewRenderer()),
+		publish.NewManager(c),
+	)
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	secretKey := types.NamespacedName{Name: "keys", Namespace: "default"}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("first Reconcile() error = %v", err)
+	}
+	var first corev1.Secret
+	if err := c.Get(ctx, secretKey, &first); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("second Reconcile() error = %v", err)
+	}
+	var second corev1.Secret
+	if err := c.Get(ctx, secretKey, &second); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if second.ResourceVersion != first.ResourceVersion {
+		t.Errorf("Secret changed without rotation: resourceVersion %s → %s",
+			first.ResourceVersion, second.ResourceVersion)
+	}
+	if !reflect.DeepEqual(first.Annotations, second.Annotations) {
+		t.Errorf("annotations changed without rotation:\n%v\n%v", first.Annotations, second.Annotations)
+	}
+	if !reflect.DeepEqual(first.Data, second.Data) {
+		t.Error("data changed without rotation")
+	}
+}
+
+func TestReconcileSetsSuspendedPhase(t *testing.T) {
+	t.Parallel()
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+	}
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		Build()
+	pausedUntil := time.Now().Add(time.Hour).Truncate(time.Second)
+	rm := &fakeRotationManager{result: &rotation.RotationResult{
+		KeyID:        "ec-P-256-current",
+		RotationTime: time.Now().Add(-48 * time.Hour),
+		NextRotation: pausedUntil,
+		PausedUntil:  pausedUntil,
+	}}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status.Phase != "Suspended" {
+		t.Errorf("Phase = %q, want Suspended", got.Status.Phase)
+	}
+	if !meta.IsStatusConditionTrue(got.Status.Conditions, openukrv1alpha1.ConditionSuspended) {
+		t.Errorf("Suspended condition not True: %+v", got.Status.Conditions)
+	}
+
+	// Pause over: phase returns to Active and the condition is cleared
+	rm.result = &rotation.RotationResult{
+		KeyID:        "ec-P-256-next",
+		Rotated:      true,
+		RotationTime: time.Now(),
+		NextRotation: time.Now().Add(24 * time.Hour),
+		Reason:       "interval 24h0m0s expired",
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status.Phase != "Active" {
+		t.Errorf("Phase = %q, want Active", got.Status.Phase)
+	}
+	if got.Status.LastRotationReason != "interval 24h0m0s expired" {
+		t.Errorf("LastRotationReason = %q, want the rotation reason", got.Status.LastRotationReason)
+	}
+	if !meta.IsStatusConditionFalse(got.Status.Conditions, openukrv1alpha1.ConditionSuspended) {
+		t.Errorf("Suspended condition not False: %+v", got.Status.Conditions)
+	}
+}
+
+func TestReconcileRejectsIncompatibleFormat(t *testing.T) {
+	t.Parallel()
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			KeySpec: openukrv1alpha1.KeySpec{Algorithm: "X25519"},
+			Output:  openukrv1alpha1.OutputConfig{SecretName: "keys", Format: output.FormatJKS},
+		},
+	}
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		Build()
+	rm := &fakeRotationManager{}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	_, err := r.Reconcile(ctx, req)
+	if !errors.Is(err, reconcile.TerminalError(nil)) {
+		t.Fatalf("Reconcile() error = %v, want terminal error", err)
+	}
+	if len(rm.calls) != 0 {
+		t.Errorf("EnsureKey called %d times, want 0", len(rm.calls))
+	}
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status.Phase != "Error" {
+		t.Errorf("Phase = %q, want Error", got.Status.Phase)
+	}
+}
+
+func TestReconcileNotesSecretRename(t *testing.T) {
+	t.Parallel()
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Output: openukrv1alpha1.OutputConfig{SecretName: "keys"},
+		},
+	}
+	secret := func(name string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: map[string]string{"openukr.io/key-id": "ec-P-256-current"},
+		}}
+	}
+	scheme := newTestScheme(t)
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile, secret("keys")).
+		WithStatusSubresource(profile).
+		Build()
+	rm := &fakeRotationManager{result: &rotation.RotationResult{
+		KeyID:        "ec-P-256-current",
+		RotationTime: time.Now(),
+		NextRotation: time.Now().Add(24 * time.Hour),
+	}}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	var got openukrv1alpha1.KeyProfile
+	reconcile := func() {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+	reconcile()
+	if got.Status.SecretName != "keys" {
+		t.Errorf("Status.SecretName = %q, want keys", got.Status.SecretName)
+	}
+	if meta.FindStatusCondition(got.Status.Conditions, openukrv1alpha1.ConditionSecretRenamed) != nil {
+		t.Errorf("unexpected SecretRenamed condition on first write: %+v", got.Status.Conditions)
+	}
+
+	// Nothing is noted before the key reaches the new Secret
+	got.Spec.Output.SecretName = "keys-v2"
+	if err := c.Update(ctx, &got); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	reconcile()
+	if got.Status.SecretName != "keys" {
+		t.Errorf("Status.SecretName = %q before the new Secret was written, want keys", got.Status.SecretName)
+	}
+	if meta.FindStatusCondition(got.Status.Conditions, openukrv1alpha1.ConditionSecretRenamed) != nil {
+		t.Errorf("SecretRenamed condition before the new Secret was written: %+v", got.Status.Conditions)
+	}
+
+	if err := c.Create(ctx, secret("keys-v2")); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	reconcile()
+	if got.Status.SecretName != "keys-v2" {
+		t.Errorf("Status.SecretName = %q, want keys-v2", got.Status.SecretName)
+	}
+	cond := meta.FindStatusCondition(got.Status.Conditions, openukrv1alpha1.ConditionSecretRenamed)
+	if cond == nil || cond.Status != metav1.ConditionTrue || !strings.Contains(cond.Message, `"keys"`) {
+		t.Errorf("SecretRenamed condition = %+v, want True naming the old Secret", cond)
+	}
+}
+
+func TestReconcileCountsResult(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		namespace  string
+		result     *rotation.RotationResult
+		err        error
+		wantResult string
+	}{
+		{
+			name:       "rotated",
+			namespace:  "reconciles-rotated",
+			result:     &rotation.RotationResult{Rotated: true, KeyID: "ec-P-256-new", NextRotation: time.Now().Add(time.Hour)},
+			wantResult: metrics.ReconcileRotated,
+		},
+		{
+			name:       "noop",
+			namespace:  "reconciles-noop",
+			result:     &rotation.RotationResult{KeyID: "ec-P-256-current", NextRotation: time.Now().Add(time.Hour)},
+			wantResult: metrics.ReconcileNoop,
+		},
+		{
+			name:       "error",
+			namespace:  "reconciles-error",
+			err:        errors.New("keygen failed"),
+			wantResult: metrics.ReconcileError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			profile := &openukrv1alpha1.KeyProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: tt.namespace},
+			}
+			scheme := newTestScheme(t)
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(profile).
+				WithStatusSubresource(profile).
+				Build()
+			rm := &fakeRotationManager{result: tt.result, err: tt.err}
+			r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: tt.namespace}}
+			_, _ = r.Reconcile(context.Background(), req)
+
+			for _, result := range []string{metrics.ReconcileRotated, metrics.ReconcileNoop, metrics.ReconcileError} {
+				want := 0.0
+				if result == tt.wantResult {
+					want = 1
+				}
+				got := testutil.ToFloat64(metrics.ReconcilesTotal.WithLabelValues(tt.namespace, result))
+				if got != want {
+					t.Errorf("openukr_reconciles_total{result=%q} = %v, want %v", result, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileSetsSummary(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	targets := []openukrv1alpha1.PublishTarget{
+		{Type: "http", Config: map[string]string{"endpoint": "https://ok.example"}},
+		{Type: "http", Config: map[string]string{"endpoint": "https://down.example"}},
+	}
+	tests := []struct {
+		name   string
+		result *rotation.RotationResult
+		err    error
+		want   string
+	}{
+		{
+			name: "healthy",
+			result: &rotation.RotationResult{
+				KeyID:        "ec-P-256-current",
+				RotationTime: now,
+				NextRotation: now.Add(6 * time.Hour),
+				PublishResults: []publish.TargetResult{
+					{Type: "http", Target: "https://ok.example"},
+					{Type: "http", Target: "https://down.example"},
+				},
+			},
+			want: "Active, next rotation at 2026-01-01T18:00:00Z, 2/2 targets published",
+		},
+		{
+			name: "degraded",
+			result: &rotation.RotationResult{
+				KeyID: "ec-P-256-current",
+				PublishResults: []publish.TargetResult{
+					{Type: "http", Target: "https://ok.example"},
+					{Type: "http", Target: "https://down.example", Err: errors.New("connection refused")},
+				},
+			},
+			err:  errors.New("failed to publish public key"),
+			want: "Active, rotation overdue, 1/2 targets published",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			profile := &openukrv1alpha1.KeyProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+				Spec:       openukrv1alpha1.KeyProfileSpec{Publish: targets},
+				Status: openukrv1alpha1.KeyProfileStatus{
+					Phase:        "Active",
+					NextRotation: &metav1.Time{Time: now.Add(-time.Minute)},
+				},
+			}
+			scheme := newTestScheme(t)
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(profile).
+				WithStatusSubresource(profile).
+				Build()
+			r := &KeyProfileReconciler{
+				Client:          c,
+				Scheme:          scheme,
+				RotationManager: &fakeRotationManager{result: tt.result, err: tt.err},
+				Clock:           clocktesting.NewFakePassiveClock(now),
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+			_, _ = r.Reconcile(context.Background(), req)
+
+			var got openukrv1alpha1.KeyProfile
+			if err := c.Get(context.Background(), req.NamespacedName, &got); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got.Status.Summary != tt.want {
+				t.Errorf("Summary = %q, want %q", got.Status.Summary, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileRecordsPendingKey(t *testing.T) {
+	t.Parallel()
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Publish: []openukrv1alpha1.PublishTarget{
+				{Type: "http", Config: map[string]string{"endpoint": "https://ok.example"}},
+			},
+		},
+	}
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		Build()
+	rm := &fakeRotationManager{
+		result: &rotation.RotationResult{
+			KeyID:          "ec-P-256-new",
+			PendingKeyID:   "ec-P-256-new",
+			PublishResults: []publish.TargetResult{{Type: "http", Target: "https://ok.example"}},
+		},
+		err: errors.New("failed to persist key material"),
+	}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err == nil {
+		t.Fatal("Reconcile() succeeded, want persist error")
+	}
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status.PendingKeyID != "ec-P-256-new" {
+		t.Errorf("PendingKeyID = %q, want ec-P-256-new", got.Status.PendingKeyID)
+	}
+
+	// Persisting the key on retry clears it
+	rm.result = &rotation.RotationResult{
+		Rotated:      true,
+		KeyID:        "ec-P-256-new",
+		RotationTime: time.Now(),
+		NextRotation: time.Now().Add(24 * time.Hour),
+	}
+	rm.err = nil
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status.PendingKeyID != "" || got.Status.CurrentKeyID != "ec-P-256-new" {
+		t.Errorf("status = {pending: %q, current: %q}, want pending cleared and key current",
+			got.Status.PendingKeyID, got.Status.CurrentKeyID)
+	}
+}
+
+func TestReconcileSetsDegradedOnOversizedSecret(t *testing.T) {
+	t.Parallel()
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+	}
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		Build()
+	rm := &fakeRotationManager{err: fmt.Errorf("failed to persist key material: %w", output.ErrSecretTooLarge)}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err == nil {
+		t.Fatal("Reconcile() succeeded, want persist error")
+	}
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	cond := meta.FindStatusCondition(got.Status.Conditions, openukrv1alpha1.ConditionDegraded)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "SecretTooLarge" {
+		t.Fatalf("Degraded condition = %+v, want True with reason SecretTooLarge", cond)
+	}
+
+	// A successful write clears it
+	rm.err = nil
+	rm.result = &rotation.RotationResult{
+		KeyID:        "ec-P-256-current",
+		RotationTime: time.Now(),
+		NextRotation: time.Now().Add(24 * time.Hour),
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if cond := meta.FindStatusCondition(got.Status.Conditions, openukrv1alpha1.ConditionDegraded); cond == nil || cond.Status != metav1.ConditionFalse {
+		t.Errorf("Degraded condition = %+v, want False after a successful write", cond)
+	}
+}
+
+func TestReconcileClearsForceRotateAnnotation(t *testing.T) {
+	t.Parallel()
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "profile",
+			Namespace:   "default",
+			Annotations: map[string]string{rotation.ForceRotateAnnotation: "2026-01-01T00:00:00Z"},
+		},
+	}
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		Build()
+	now := time.Now()
+	// Paused: the request stays pending until a rotation happens
+	rm := &fakeRotationManager{result: &rotation.RotationResult{
+		KeyID:        "ec-P-256-current",
+		RotationTime: now,
+		NextRotation: now.Add(24 * time.Hour),
+		PausedUntil:  now.Add(time.Hour),
+	}}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm, Recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	var got openukrv1alpha1.KeyProfile
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, ok := got.Annotations[rotation.ForceRotateAnnotation]; !ok {
+		t.Fatal("force-rotate annotation removed without a rotation")
+	}
+
+	rm.result = &rotation.RotationResult{
+		Rotated:      true,
+		KeyID:        "ec-P-256-forced",
+		RotationTime: now,
+		NextRotation: now.Add(24 * time.Hour),
+		Reason:       "forced by openukr.io/force-rotate annotation",
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, ok := got.Annotations[rotation.ForceRotateAnnotation]; ok {
+		t.Error("force-rotate annotation not removed after the rotation")
+	}
+	if got.Status.CurrentKeyID != "ec-P-256-forced" {
+		t.Errorf("CurrentKeyID = %q, want ec-P-256-forced", got.Status.CurrentKeyID)
+	}
+}
+
+func TestReconcileRecordsHandledForceRotate(t *testing.T) {
+	t.Parallel()
+
+	const requested = "2026-01-01T00:00:00Z"
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "profile",
+			Namespace:   "default",
+			Annotations: map[string]string{rotation.ForceRotateAnnotation: requested},
+		},
+	}
+	scheme := newTestScheme(t)
+	patchErr := errors.New("injected patch failure")
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(context.Context, client.WithWatch, client.Object, client.Patch, ...client.PatchOption) error {
+				return patchErr
+			},
+		}).
+		Build()
+	now := time.Now()
+	rm := &fakeRotationManager{result: &rotation.RotationResult{
+		Rotated:      true,
+		KeyID:        "ec-P-256-forced",
+		RotationTime: now,
+		NextRotation: now.Add(24 * time.Hour),
+		Reason:       "forced by openukr.io/force-rotate annotation",
+	}}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm, Recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v, want the failed annotation removal tolerated", err)
+	}
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status.LastForcedRotation != requested {
+		t.Errorf("LastForcedRotation = %q, want %q", got.Status.LastForcedRotation, requested)
+	}
+	if rotation.ForceRotationRequested(&got) {
+		t.Error("handled force-rotate request still pending")
+	}
+}
+
+func TestReconcileExpiresPreviousOnAnnotation(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "profile",
+			Namespace:   "default",
+			Annotations: map[string]string{ExpirePreviousAnnotation: "true"},
+		},
+		Status: openukrv1alpha1.KeyProfileStatus{
+			CurrentKeyID:           "ec-P-256-current",
+			PreviousKeyID:          "ec-P-256-previous",
+			PreviousKeyFingerprint: "SHA256:previous",
+			LastRotation:           &metav1.Time{Time: now},
+		},
+	}
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		Build()
+	// Within the grace period, the manager would still report the previous key
+	rm := &fakeRotationManager{result: &rotation.RotationResult{
+		KeyID:        "ec-P-256-current",
+		RotationTime: now,
+		NextRotation: now.Add(24 * time.Hour),
+	}}
+	recorder := record.NewFakeRecorder(10)
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm, Recorder: recorder}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if !reflect.DeepEqual(rm.expired, []string{"ec-P-256-previous"}) {
+		t.Errorf("ExpirePrevious calls = %v, want [ec-P-256-previous]", rm.expired)
+	}
+
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, ok := got.Annotations[ExpirePreviousAnnotation]; ok {
+		t.Error("expire-previous annotation not removed")
+	}
+	if got.Status.PreviousKeyID != "" || got.Status.PreviousKeyFingerprint != "" {
+		t.Errorf("previous key status = {%q, %q}, want cleared",
+			got.Status.PreviousKeyID, got.Status.PreviousKeyFingerprint)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, "PreviousKeyExpired") {
+			t.Errorf("event = %q, want PreviousKeyExpired", event)
+		}
+	default:
+		t.Error("no event recorded")
+	}
+
+	// Without the annotation nothing is expired again
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if len(rm.expired) != 1 {
+		t.Errorf("ExpirePrevious called %d times, want 1", len(rm.expired))
+	}
+}
+
+func TestReconcileRetriesStatusUpdateOnConflict(t *testing.T) {
+	t.Parallel()
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+	}
+	scheme := newTestScheme(t)
+	var updates int
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				updates++
+				if updates == 1 {
+					// Another writer changed the object since it was read
+					latest := &openukrv1alpha1.KeyProfile{}
+					if err := c.Get(ctx, client.ObjectKeyFromObject(obj), latest); err != nil {
+						return err
+					}
+					latest.Labels = map[string]string{"touched": "true"}
+					if err := c.Update(ctx, latest); err != nil {
+						return err
+					}
+					return apierrors.NewConflict(openukrv1alpha1.GroupVersion.WithResource("keyprofiles").GroupResource(),
+						obj.GetName(), errors.New("the object has been modified"))
+				}
+				return c.SubResource(subResource).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	rm := &fakeRotationManager{}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v, want the conflict retried", err)
+	}
+	if updates != 2 {
+		t.Errorf("status updates = %d, want 2", updates)
+	}
+
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status.CurrentKeyID != "ec-P-256-test" || got.Status.Phase == "" {
+		t.Errorf("status = {current: %q, phase: %q}, want the rotation recorded", got.Status.CurrentKeyID, got.Status.Phase)
+	}
+	if got.Labels["touched"] != "true" {
+		t.Errorf("labels = %v, want the concurrent change kept", got.Labels)
+	}
+}
+
+func TestReconcileDisableFingerprintStatus(t *testing.T) {
+	t.Parallel()
+
+	kp, err := crypto.NewKeyGenerator().Generate(crypto.GenerateOptions{
+		Algorithm: crypto.AlgorithmEC,
+		Params:    map[string]string{"curve": crypto.CurveP256},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	fingerprint, err := crypto.ComputeFingerprint(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+		Spec:       openukrv1alpha1.KeyProfileSpec{DisableFingerprintStatus: true},
+	}
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		Build()
+	now := time.Now().Truncate(time.Second) // metav1.Time keeps whole seconds
+	rm := &fakeRotationManager{
+		result: &rotation.RotationResult{
+			Rotated:             true,
+			KeyID:               "ec-P-256-new",
+			Fingerprint:         fingerprint,
+			PreviousKeyID:       "ec-P-256-old",
+			PreviousFingerprint: fingerprint,
+			RotationTime:        now,
+			NextRotation:        now.Add(24 * time.Hour),
+		},
+	}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm, Clock: clocktesting.NewFakePassiveClock(now)}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status.CurrentKeyID != "ec-P-256-new" {
+		t.Errorf("CurrentKeyID = %q, want ec-P-256-new", got.Status.CurrentKeyID)
+	}
+	if got.Status.CurrentKeyFingerprint != "" || got.Status.PreviousKeyFingerprint != "" {
+		t.Errorf("status fingerprints = {current: %q, previous: %q}, want empty",
+			got.Status.CurrentKeyFingerprint, got.Status.PreviousKeyFingerprint)
+	}
+
+	// The missing fingerprint alone does not trigger further status writes
+	rm.result.Rotated = false
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var again openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &again); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if again.ResourceVersion != got.ResourceVersion {
+		t.Errorf("status rewritten on no-op reconcile: resourceVersion %s → %s", got.ResourceVersion, again.ResourceVersion)
+	}
+}
+
+func TestReconcileRecordsKeyThumbprint(t *testing.T) {
+	t.Parallel()
+
+	kp, err := crypto.NewKeyGenerator().Generate(crypto.GenerateOptions{
+		Algorithm: crypto.AlgorithmEC,
+		Params:    map[string]string{"curve": crypto.CurveP256},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer kp.Wipe()
+	encoder, err := crypto.NewKeyEncoder("PEM")
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	pubPEM, err := encoder.EncodePublic(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("EncodePublic() error = %v", err)
+	}
+	want, err := crypto.JWKThumbprint(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("JWKThumbprint() error = %v", err)
+	}
+	fp, err := crypto.ComputeFingerprint(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Output: openukrv1alpha1.OutputConfig{SecretName: "profile-keys", Format: "split-pem"},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "profile-keys",
+			Namespace:   "default",
+			Annotations: map[string]string{"openukr.io/key-id": kp.KeyID},
+		},
+		Data: map[string][]byte{"public.pem": pubPEM},
+	}
+	scheme := newTestScheme(t)
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile, secret).
+		WithStatusSubresource(profile).
+		Build()
+	now := time.Now().Truncate(time.Second) // metav1.Time keeps whole seconds
+	rm := &fakeRotationManager{result: &rotation.RotationResult{
+		Rotated:      true,
+		KeyID:        kp.KeyID,
+		Fingerprint:  fp,
+		RotationTime: now,
+		NextRotation: now.Add(24 * time.Hour),
+	}}
+	r := &KeyProfileReconciler{
+		Client:              c,
+		Scheme:              scheme,
+		RotationManager:     rm,
+		RecordKeyThumbprint: true,
+		Clock:               clocktesting.NewFakePassiveClock(now),
+	}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status.CurrentKeyThumbprint != want {
+		t.Errorf("CurrentKeyThumbprint = %q, want %s", got.Status.CurrentKeyThumbprint, want)
+	}
+
+	// A recorded thumbprint does not trigger further status writes
+	rm.result.Rotated = false
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var again openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &again); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if again.ResourceVersion != got.ResourceVersion {
+		t.Errorf("status rewritten on no-op reconcile: resourceVersion %s → %s", got.ResourceVersion, again.ResourceVersion)
+	}
+
+	// Profiles keeping fingerprints out of their status get no thumbprint either
+	again.Spec.DisableFingerprintStatus = true
+	if err := c.Update(ctx, &again); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status.CurrentKeyThumbprint != "" {
+		t.Errorf("CurrentKeyThumbprint = %q with fingerprint status disabled, want empty", got.Status.CurrentKeyThumbprint)
+	}
+}
+
+func TestReconcileSkipsThumbprintOfMismatchedKey(t *testing.T) {
+	t.Parallel()
+
+	gen := crypto.NewKeyGenerator()
+	opts := crypto.GenerateOptions{
+		Algorithm: crypto.AlgorithmEC,
+		Params:    map[string]string{"curve": crypto.CurveP256},
+	}
+	kp, err := gen.Generate(opts)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer kp.Wipe()
+	other, err := gen.Generate(opts)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer other.Wipe()
+	fp, err := crypto.ComputeFingerprint(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+	encoder, err := crypto.NewKeyEncoder("PEM")
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	// The Secret carries the current key ID but someone else's public key
+	otherPEM, err := encoder.EncodePublic(other.PublicKey)
+	if err != nil {
+		t.Fatalf("EncodePublic() error = %v", err)
+	}
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Output: openukrv1alpha1.OutputConfig{SecretName: "profile-keys", Format: "split-pem"},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "profile-keys",
+			Namespace:   "default",
+			Annotations: map[string]string{"openukr.io/key-id": kp.KeyID},
+		},
+		Data: map[string][]byte{"public.pem": otherPEM},
+	}
+	scheme := newTestScheme(t)
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile, secret).
+		WithStatusSubresource(profile).
+		Build()
+	now := time.Now().Truncate(time.Second)
+	r := &KeyProfileReconciler{
+		Client: c,
+		Scheme: scheme,
+		RotationManager: &fakeRotationManager{result: &rotation.RotationResult{
+			Rotated:      true,
+			KeyID:        kp.KeyID,
+			Fingerprint:  fp,
+			RotationTime: now,
+			NextRotation: now.Add(24 * time.Hour),
+		}},
+		RecordKeyThumbprint: true,
+		Clock:               clocktesting.NewFakePassiveClock(now),
+	}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Status.CurrentKeyThumbprint != "" {
+		t.Errorf("CurrentKeyThumbprint = %q for a Secret holding another key, want empty", got.Status.CurrentKeyThumbprint)
+	}
+}
+
+func TestReconcileReportsIntegrityViolation(t *testing.T) {
+	t.Parallel()
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+	}
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		Build()
+	now := time.Now()
+	rm := &fakeRotationManager{
+		result: &rotation.RotationResult{
+			Rotated:            true,
+			KeyID:              "ec-P-256-new",
+			RotationTime:       now,
+			NextRotation:       now.Add(24 * time.Hour),
+			Reason:             "integrity violation",
+			IntegrityViolation: fmt.Errorf("%w: private key: no private key PEM block found", output.ErrIntegrity),
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm, Recorder: recorder}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	cond := meta.FindStatusCondition(got.Status.Conditions, openukrv1alpha1.ConditionDegraded)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "IntegrityViolation" {
+		t.Errorf("Degraded condition = %+v, want True/IntegrityViolation", cond)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, "IntegrityViolation") {
+			t.Errorf("event = %q, want IntegrityViolation", event)
+		}
+	default:
+		t.Error("no IntegrityViolation event recorded")
+	}
+
+	// The next clean reconcile clears the condition
+	rm.result = &rotation.RotationResult{KeyID: "ec-P-256-new", RotationTime: now, NextRotation: now.Add(24 * time.Hour)}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if meta.IsStatusConditionTrue(got.Status.Conditions, openukrv1alpha1.ConditionDegraded) {
+		t.Error("Degraded condition still true after a clean reconcile")
+	}
+}
+
+func TestReconcileVerifyNowAnnotation(t *testing.T) {
+	t.Parallel()
+
+	encoder, err := crypto.NewKeyEncoder("PEM")
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	keyData := func() (map[string][]byte, crypto.Fingerprint) {
+		t.Helper()
+		kp, err := crypto.NewKeyGenerator().Generate(crypto.GenerateOptions{
+			Algorithm: crypto.AlgorithmEC,
+			Params:    map[string]string{"curve": crypto.CurveP256},
+		})
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		defer kp.Wipe()
+		privPEM, err := encoder.EncodePrivate(kp.PrivateKey)
+		if err != nil {
+			t.Fatalf("EncodePrivate() error = %v", err)
+		}
+		pubPEM, err := encoder.EncodePublic(kp.PublicKey)
+		if err != nil {
+			t.Fatalf("EncodePublic() error = %v", err)
+		}
+		fingerprint, err := crypto.ComputeFingerprint(kp.PublicKey)
+		if err != nil {
+			t.Fatalf("ComputeFingerprint() error = %v", err)
+		}
+		return map[string][]byte{"tls.key": privPEM, "public.pem": pubPEM}, fingerprint
+	}
+	data, fingerprint := keyData()
+
+	const keyID = "ec-P-256-current"
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "profile",
+			Namespace:   "default",
+			Annotations: map[string]string{VerifyNowAnnotation: "1"},
+		},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Output: openukrv1alpha1.OutputConfig{SecretName: "profile-keys", Format: "split-pem"},
+		},
+		Status: openukrv1alpha1.KeyProfileStatus{CurrentKeyID: keyID, CurrentKeyFingerprint: fingerprint.String()},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "profile-keys",
+			Namespace:   "default",
+			Annotations: map[string]string{"openukr.io/key-id": keyID},
+		},
+		Data: data,
+	}
+	scheme := newTestScheme(t)
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	var statusErr error
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile, secret).
+		WithStatusSubresource(profile).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, sub string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				if statusErr != nil {
+					return statusErr
+				}
+				return c.SubResource(sub).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	now := time.Now().Truncate(time.Second) // metav1.Time keeps whole seconds
+	// The rotation manager does not check: only the annotation drives the condition
+	rm := &fakeRotationManager{result: &rotation.RotationResult{
+		KeyID:        keyID,
+		Fingerprint:  fingerprint,
+		RotationTime: now,
+		NextRotation: now.Add(24 * time.Hour),
+	}}
+	recorder := record.NewFakeRecorder(10)
+	r := &KeyProfileReconciler{
+		Client:          c,
+		APIReader:       c,
+		Scheme:          scheme,
+		RotationManager: rm,
+		Recorder:        recorder,
+		Clock:           clocktesting.NewFakePassiveClock(now),
+	}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	verify := func(wantStatus metav1.ConditionStatus, wantEvent string) {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got openukrv1alpha1.KeyProfile
+		if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if _, ok := got.Annotations[VerifyNowAnnotation]; ok {
+			t.Error("verify-now annotation not removed after the check")
+		}
+		cond := meta.FindStatusCondition(got.Status.Conditions, openukrv1alpha1.ConditionIntegrityVerified)
+		if cond == nil || cond.Status != wantStatus {
+			t.Errorf("IntegrityVerified condition = %+v, want %s", cond, wantStatus)
+		}
+		if got.Status.LastVerifyTime == nil || !got.Status.LastVerifyTime.Time.Equal(now) {
+			t.Errorf("LastVerifyTime = %v, want %s", got.Status.LastVerifyTime, now)
+		}
+		select {
+		case event := <-recorder.Events:
+			if !strings.Contains(event, wantEvent) {
+				t.Errorf("event = %q, want %s", event, wantEvent)
+			}
+		default:
+			t.Errorf("no %s event recorded", wantEvent)
+		}
+	}
+
+	verify(metav1.ConditionTrue, "Verified")
+
+	// Swap in another key under the same key ID and request a new check
+	secret.Data, _ = keyData()
+	if err := c.Update(ctx, secret); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	var current openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &current); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	current.Annotations = map[string]string{VerifyNowAnnotation: "2"}
+	if err := c.Update(ctx, &current); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	now = now.Add(time.Minute)
+	r.Clock = clocktesting.NewFakePassiveClock(now)
+
+	// A check whose result cannot be recorded keeps the request
+	statusErr = errors.New("status write failed")
+	if _, err := r.Reconcile(ctx, req); !errors.Is(err, statusErr) {
+		t.Fatalf("Reconcile() error = %v, want %v", err, statusErr)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &current); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, ok := current.Annotations[VerifyNowAnnotation]; !ok {
+		t.Error("verify-now annotation removed although the result was not recorded")
+	}
+	statusErr = nil
+
+	verify(metav1.ConditionFalse, "VerifyFailed")
+}
+
+func TestReconcileSetsIntegrityVerifiedCondition(t *testing.T) {
+	t.Parallel()
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+	}
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		Build()
+	now := time.Now()
+	verified := &rotation.RotationResult{
+		KeyID:             "ec-P-256-current",
+		RotationTime:      now,
+		NextRotation:      now.Add(24 * time.Hour),
+		IntegrityVerified: true,
+	}
+	rm := &fakeRotationManager{result: verified}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm, Recorder: record.NewFakeRecorder(10)}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	reconcileCondition := func() *metav1.Condition {
+		t.Helper()
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			t.Fatalf("Reconcile() error = %v", err)
+		}
+		var got openukrv1alpha1.KeyProfile
+		if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		return meta.FindStatusCondition(got.Status.Conditions, openukrv1alpha1.ConditionIntegrityVerified)
+	}
+
+	if cond := reconcileCondition(); cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Fatalf("IntegrityVerified condition = %+v, want True", cond)
+	}
+
+	// Injected tampering flips the condition
+	violation := fmt.Errorf("%w: public key does not match the recorded fingerprint", output.ErrIntegrity)
+	rm.result = &rotation.RotationResult{
+		KeyID:              "ec-P-256-current",
+		RotationTime:       now,
+		NextRotation:       now.Add(24 * time.Hour),
+		IntegrityViolation: violation,
+	}
+	cond := reconcileCondition()
+	if cond == nil || cond.Status != metav1.ConditionFalse || cond.Reason != "Tampered" {
+		t.Fatalf("IntegrityVerified condition = %+v, want False/Tampered", cond)
+	}
+
+	// A reconcile that did not check keeps the last outcome
+	rm.result = &rotation.RotationResult{KeyID: "ec-P-256-current", RotationTime: now, NextRotation: now.Add(24 * time.Hour)}
+	if cond := reconcileCondition(); cond == nil || cond.Status != metav1.ConditionFalse {
+		t.Errorf("IntegrityVerified condition = %+v, want False kept", cond)
+	}
+
+	// A corrective rotation replaces the tampered material
+	rm.result = &rotation.RotationResult{
+		Rotated:            true,
+		KeyID:              "ec-P-256-new",
+		RotationTime:       now,
+		NextRotation:       now.Add(24 * time.Hour),
+		Reason:             "integrity violation",
+		IntegrityViolation: violation,
+	}
+	if cond := reconcileCondition(); cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "Corrected" {
+		t.Errorf("IntegrityVerified condition = %+v, want True/Corrected", cond)
+	}
+
+	// The corrected Secret verifies again
+	rm.result = verified
+	if cond := reconcileCondition(); cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != "Verified" {
+		t.Errorf("IntegrityVerified condition = %+v, want True/Verified", cond)
+	}
+}
+
+func TestReconcileFollowsRotationSchedule(t *testing.T) {
+	t.Parallel()
+
+	schedule := &openukrv1alpha1.ClusterRotationSchedule{
+		ObjectMeta: metav1.ObjectMeta{Name: "quarterly"},
+		Spec:       openukrv1alpha1.RotationScheduleSpec{Interval: metav1.Duration{Duration: 24 * time.Hour}},
+	}
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			KeySpec: openukrv1alpha1.KeySpec{
+				Algorithm: crypto.AlgorithmEC,
+				Params:    map[string]string{"curve": crypto.CurveP256},
+			},
+			Rotation: openukrv1alpha1.RotationPolicy{
+				ScheduleRef: &openukrv1alpha1.ScheduleReference{
+					Kind: openukrv1alpha1.ClusterRotationScheduleKind,
+					Name: "quarterly",
+				},
+				GracePeriod: metav1.Duration{Duration: time.Hour},
+			},
+			Output: openukrv1alpha1.OutputConfig{SecretName: "keys", Format: output.FormatSplitPEM},
+		},
+	}
+	other := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Rotation: openukrv1alpha1.RotationPolicy{ScheduleRef: &openukrv1alpha1.ScheduleReference{Name: "monthly"}},
+		},
+	}
+	// A namespaced schedule of the same name is another schedule
+	namespaced := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespaced", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Rotation: openukrv1alpha1.RotationPolicy{ScheduleRef: &openukrv1alpha1.ScheduleReference{Name: "quarterly"}},
+		},
+	}
+	scheme := newTestScheme(t)
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(schedule, profile, other, namespaced).
+		WithStatusSubresource(profile).
+		Build()
+	rm := rotation.NewManager(
+		logr.Discard(),
+		crypto.NewKeyGenerator(),
+		output.NewSecretWriter(c, scheme, output.NewRenderer()),
+		publish.NewManager(c),
+	)
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("first Reconcile() error = %v", err)
+	}
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if d := got.Status.NextRotation.Sub(got.Status.LastRotation.Time); d != 24*time.Hour {
+		t.Fatalf("next rotation %s after last, want the schedule's 24h", d)
+	}
+	keyID := got.Status.CurrentKeyID
+
+	// A cadence change reschedules referencing profiles without rotating
+	schedule.Spec.Interval.Duration = 48 * time.Hour
+	if err := c.Update(ctx, schedule); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	requests := r.profilesForSchedule(ctx, schedule)
+	if len(requests) != 1 || requests[0] != req {
+		t.Fatalf("profilesForSchedule() = %v, want [%v]", requests, req)
+	}
+	if _, err := r.Reconcile(ctx, requests[0]); err != nil {
+		t.Fatalf("second Reconcile() error = %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if d := got.Status.NextRotation.Sub(got.Status.LastRotation.Time); d != 48*time.Hour {
+		t.Errorf("next rotation %s after last, want the updated 48h", d)
+	}
+	if got.Status.CurrentKeyID != keyID {
+		t.Errorf("CurrentKeyID = %s, want %s unchanged", got.Status.CurrentKeyID, keyID)
+	}
+
+	// An inline interval overrides the schedule
+	got.Spec.Rotation.Interval = metav1.Duration{Duration: 12 * time.Hour}
+	if err := c.Update(ctx, &got); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("third Reconcile() error = %v", err)
+	}
+	if err := c.Get(ctx, req.NamespacedName, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if d := got.Status.NextRotation.Sub(got.Status.LastRotation.Time); d != 12*time.Hour {
+		t.Errorf("next rotation %s after last, want the inline 12h", d)
+	}
+}
+
+func TestReconcileFollowsNamespacedRotationSchedule(t *testing.T) {
+	t.Parallel()
+
+	newSchedule := func(namespace string, interval time.Duration) *openukrv1alpha1.RotationSchedule {
+		return &openukrv1alpha1.RotationSchedule{
+			ObjectMeta: metav1.ObjectMeta{Name: "monthly", Namespace: namespace},
+			Spec:       openukrv1alpha1.RotationScheduleSpec{Interval: metav1.Duration{Duration: interval}},
+		}
+	}
+	schedule := newSchedule("default", 24*time.Hour)
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Rotation: openukrv1alpha1.RotationPolicy{
+				ScheduleRef: &openukrv1alpha1.ScheduleReference{Name: "monthly"},
+				GracePeriod: metav1.Duration{Duration: time.Hour},
+			},
+		},
+	}
+	elsewhere := profile.DeepCopy()
+	elsewhere.Namespace = "other"
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(schedule, newSchedule("other", 12*time.Hour), profile, elsewhere).
+		WithStatusSubresource(profile).
+		Build()
+	rm := &fakeRotationManager{result: &rotation.RotationResult{KeyID: "key"}}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	scheduled, err := r.resolveSchedule(ctx, profile)
+	if err != nil {
+		t.Fatalf("resolveSchedule() error = %v", err)
+	}
+	if got := scheduled.Spec.Rotation.Interval.Duration; got != 24*time.Hour {
+		t.Errorf("interval = %s, want the namespace's 24h schedule", got)
+	}
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	if requests := r.profilesForSchedule(ctx, schedule); len(requests) != 1 || requests[0] != req {
+		t.Errorf("profilesForSchedule() = %v, want [%v]", requests, req)
+	}
+
+	// A schedule below the cluster floor is refused under the deny policy
+	r.MinInterval, r.RejectShortInterval = 48*time.Hour, true
+	if _, err := r.Reconcile(ctx, req); err == nil || !strings.Contains(err.Error(), "below the cluster minimum") {
+		t.Errorf("Reconcile() error = %v, want the schedule below the cluster minimum", err)
+	}
+	if len(rm.calls) != 1 {
+		t.Errorf("EnsureKey called %d times, want no rotation on a schedule below the floor", len(rm.calls))
+	}
+	r.RejectShortInterval = false
+	if _, err := r.Reconcile(ctx, req); err != nil {
+		t.Errorf("Reconcile() error = %v, want only a warning", err)
+	}
+}
+
+func TestReconcileFailsForMissingSchedule(t *testing.T) {
+	t.Parallel()
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Rotation: openukrv1alpha1.RotationPolicy{
+				ScheduleRef: &openukrv1alpha1.ScheduleReference{Name: "missing"},
+				GracePeriod: metav1.Duration{Duration: time.Hour},
+			},
+		},
+	}
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(profile).Build()
+	rm := &fakeRotationManager{}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	if _, err := r.Reconcile(context.Background(), req); !apierrors.IsNotFound(err) {
+		t.Fatalf("Reconcile() error = %v, want a not found RotationSchedule", err)
+	}
+	if len(rm.calls) != 0 {
+		t.Errorf("EnsureKey called %d times, want none without the schedule", len(rm.calls))
+	}
+}
+
+func TestStatusUpdateDoesNotTriggerReconcile(t *testing.T) {
+	t.Parallel()
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", Generation: 1},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Rotation: openukrv1alpha1.RotationPolicy{Interval: metav1.Duration{Duration: 24 * time.Hour}},
+		},
+	}
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(profile).
+		WithStatusSubresource(profile).
+		Build()
+	rm := &fakeRotationManager{}
+	r := &KeyProfileReconciler{Client: c, Scheme: scheme, RotationManager: rm}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+	var before openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &before); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	result, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatalf("Reconcile() error = %v", err)
+	}
+	// The next rotation is still scheduled through a time-based requeue
+	if result.RequeueAfter <= 0 {
+		t.Errorf("RequeueAfter = %s, want a scheduled requeue", result.RequeueAfter)
+	}
+	var after openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, req.NamespacedName, &after); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if after.Status.CurrentKeyID == "" {
+		t.Fatal("status not written by Reconcile")
+	}
+
+	// The controller's own status write must not reach Reconcile again
+	if specOrMetadataChanged.Update(event.UpdateEvent{ObjectOld: &before, ObjectNew: &after}) {
+		t.Error("status-only update passes the predicate, want it filtered")
+	}
+	if len(rm.calls) != 1 {
+		t.Errorf("EnsureKey called %d times, want 1", len(rm.calls))
+	}
+
+	specChange := after.DeepCopy()
+	specChange.Generation++
+	if !specOrMetadataChanged.Update(event.UpdateEvent{ObjectOld: &after, ObjectNew: specChange}) {
+		t.Error("spec update filtered, want it to pass")
+	}
+	annotated := after.DeepCopy()
+	annotated.Annotations = map[string]string{ExpirePreviousAnnotation: ""}
+	if !specOrMetadataChanged.Update(event.UpdateEvent{ObjectOld: &after, ObjectNew: annotated}) {
+		t.Error("annotation update filtered, want it to pass")
+	}
+	relabeled := after.DeepCopy()
+	relabeled.Labels = map[string]string{"shard": "b"}
+	if !specOrMetadataChanged.Update(event.UpdateEvent{ObjectOld: &after, ObjectNew: relabeled}) {
+		t.Error("label update filtered, want it to pass")
+	}
+}
+
+func TestReconcileBacksOffOverdueInObserveMode(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		overdue time.Duration
+		observe bool
+		want    time.Duration
+	}{
+		{overdue: 30 * time.Second, observe: false, want: time.Second},
+		{overdue: 100 * time.Millisecond, observe: true, want: time.Second},
+		{overdue: 30 * time.Second, observe: true, want: 30 * time.Second},
+		{overdue: 2 * time.Hour, observe: true, want: maxObserveBackoff},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("observe=%v/overdue=%s", tt.observe, tt.overdue), func(t *testing.T) {
+			t.Parallel()
+			profile := &openukrv1alpha1.KeyProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+				Spec: openukrv1alpha1.KeyProfileSpec{
+					Rotation: openukrv1alpha1.RotationPolicy{Interval: metav1.Duration{Duration: 24 * time.Hour}},
+				},
+			}
+			scheme := newTestScheme(t)
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(profile).
+				WithStatusSubresource(profile).
+				Build()
+			rm := &fakeRotationManager{result: &rotation.RotationResult{
+				Observe:      tt.observe,
+				KeyID:        "ec-P-256-test",
+				RotationTime: now.Add(-24*time.Hour - tt.overdue),
+				NextRotation: now.Add(-tt.overdue),
+			}}
+			r := &KeyProfileReconciler{
+				Client:          c,
+				Scheme:          scheme,
+				RotationManager: rm,
+				Clock:           clocktesting.NewFakePassiveClock(now),
+			}
+
+			req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "profile", Namespace: "default"}}
+			result, err := r.Reconcile(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Reconcile() error = %v", err)
+			}
+			if result.RequeueAfter != tt.want {
+				t.Errorf("RequeueAfter = %s, want %s", result.RequeueAfter, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/output"
+)
+
+var _ = Describe("KeyProfile reconciler", func() {
+	const (
+		timeout  = 30 * time.Second
+		interval = 250 * time.Millisecond
+	)
+
+	It("creates the key Secret and populates the status", func() {
+		profile := &openukrv1alpha1.KeyProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "integration", Namespace: "default"},
+			Spec: openukrv1alpha1.KeyProfileSpec{
+				ServiceAccountRef: openukrv1alpha1.ServiceAccountReference{Name: "app", Namespace: "default"},
+				KeySpec: openukrv1alpha1.KeySpec{
+					Algorithm: "EC",
+					Params:    map[string]string{"curve": "P-256"},
+				},
+				Rotation: openukrv1alpha1.RotationPolicy{
+					Interval:    metav1.Duration{Duration: 24 * time.Hour},
+					GracePeriod: metav1.Duration{Duration: time.Hour},
+				},
+				Output: openukrv1alpha1.OutputConfig{
+					SecretName: "integration-keys",
+					Format:     output.FormatSplitPEM,
+					Labels:     map[string]string{"team": "payments"},
+				},
+			},
+		}
+		Expect(k8sClient.Create(suiteCtx, profile)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(k8sClient.Delete(suiteCtx, profile)).To(Succeed())
+		})
+
+		By("populating the status through the status subresource")
+		var got openukrv1alpha1.KeyProfile
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(suiteCtx, client.ObjectKeyFromObject(profile), &got)).To(Succeed())
+			g.Expect(got.Status.CurrentKeyID).NotTo(BeEmpty())
+			g.Expect(got.Status.CurrentKeyFingerprint).NotTo(BeEmpty())
+			g.Expect(got.Status.LastRotation).NotTo(BeNil())
+			g.Expect(got.Status.NextRotation).NotTo(BeNil())
+			g.Expect(got.Status.Phase).NotTo(BeEmpty())
+		}, timeout, interval).Should(Succeed())
+
+		By("writing the Secret with key material, labels and an owner reference")
+		var secret corev1.Secret
+		key := types.NamespacedName{Name: "integration-keys", Namespace: "default"}
+		Expect(k8sClient.Get(suiteCtx, key, &secret)).To(Succeed())
+		Expect(secret.Type).To(Equal(corev1.SecretTypeTLS))
+		for _, k := range []string{"tls.key", "tls.crt", "public.pem"} {
+			Expect(secret.Data).To(HaveKeyWithValue(k, Not(BeEmpty())))
+		}
+		Expect(secret.Labels).To(HaveKeyWithValue("team", "payments"))
+		Expect(secret.Labels).To(HaveKeyWithValue("app.kubernetes.io/managed-by", "openukr"))
+		Expect(secret.Labels).To(HaveKeyWithValue("openukr.io/key-profile", "integration"))
+		Expect(secret.Annotations).To(HaveKeyWithValue("openukr.io/key-id", got.Status.CurrentKeyID))
+
+		By("mirroring the rotation schedule from the status onto the Secret")
+		Eventually(func(g Gomega) {
+			g.Expect(k8sClient.Get(suiteCtx, key, &secret)).To(Succeed())
+			g.Expect(k8sClient.Get(suiteCtx, client.ObjectKeyFromObject(profile), &got)).To(Succeed())
+			g.Expect(secret.Annotations).To(HaveKeyWithValue("openukr.io/next-rotation",
+				got.Status.NextRotation.UTC().Format(time.RFC3339)))
+			g.Expect(secret.Annotations).To(HaveKeyWithValue("openukr.io/rotation-interval", "24h0m0s"))
+		}, timeout, interval).Should(Succeed())
+		Expect(secret.OwnerReferences).To(HaveLen(1))
+		owner := secret.OwnerReferences[0]
+		Expect(owner.Kind).To(Equal("KeyProfile"))
+		Expect(owner.UID).To(Equal(got.UID))
+		Expect(owner.Controller).To(HaveValue(BeTrue()))
+
+		By("leaving the Secret untouched on later reconciles")
+		version := secret.ResourceVersion
+		Consistently(func(g Gomega) {
+			g.Expect(k8sClient.Get(suiteCtx, key, &secret)).To(Succeed())
+			g.Expect(secret.ResourceVersion).To(Equal(version))
+		}, 2*time.Second, interval).Should(Succeed())
+	})
+})
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+)
+
+// ProgressTracker detects a wedged work queue: profiles whose scheduled
+// reconcile is overdue by more than the window while no reconcile has started
+// within the window. Clusters without due profiles are always healthy, so an
+// idle controller never flaps. A nil *ProgressTracker is a no-op.
+type ProgressTracker struct {
+	mu            sync.Mutex
+	clock         clock.PassiveClock
+	window        time.Duration
+	lastReconcile time.Time
+	due           map[types.NamespacedName]time.Time
+}
+
+// NewProgressTracker creates a ProgressTracker with the given stall window.
+func NewProgressTracker(window time.Duration, clk clock.PassiveClock) *ProgressTracker {
+	if clk == nil {
+		clk = clock.RealClock{}
+	}
+	return &ProgressTracker{
+		clock:  clk,
+		window: window,
+		due:    make(map[types.NamespacedName]time.Time),
+	}
+}
+
+// Observe records that a reconcile of key started; its pending schedule is fulfilled.
+func (t *ProgressTracker) Observe(key types.NamespacedName) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastReconcile = t.clock.Now()
+	delete(t.due, key)
+}
+
+// Scheduled records that key is expected to be reconciled again at the given time.
+func (t *ProgressTracker) Scheduled(key types.NamespacedName, at time.Time) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.due[key] = at
+}
+
+// Check implements healthz.Checker. It fails if a profile is overdue by more than
+// the window and no reconcile has started within the window.
+func (t *ProgressTracker) Check(_ *http.Request) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	if now.Sub(t.lastReconcile) <= t.window {
+		return nil
+	}
+	overdue := 0
+	var oldest time.Time
+	for _, at := range t.due {
+		if now.Sub(at) > t.window {
+			overdue++
+			if oldest.IsZero() || at.Before(oldest) {
+				oldest = at
+			}
+		}
+	}
+	if overdue == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d KeyProfile(s) overdue since %s with no reconcile for %s",
+		overdue, oldest.UTC().Format(time.RFC3339), now.Sub(t.lastReconcile).Round(time.Second))
+}
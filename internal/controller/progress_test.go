@@ -0,0 +1,73 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestProgressTrackerDetectsStalledQueue(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clocktesting.NewFakePassiveClock(start)
+	tracker := NewProgressTracker(10*time.Minute, clk)
+	key := types.NamespacedName{Namespace: "default", Name: "profile"}
+
+	// Idle cluster: nothing scheduled, healthy however long it stays quiet
+	clk.SetTime(start.Add(24 * time.Hour))
+	if err := tracker.Check(nil); err != nil {
+		t.Fatalf("Check() on idle tracker error = %v", err)
+	}
+
+	// Reconciled and scheduled again in one hour
+	tracker.Observe(key)
+	tracker.Scheduled(key, clk.Now().Add(time.Hour))
+
+	// Due but within the window: healthy
+	clk.SetTime(clk.Now().Add(time.Hour + 5*time.Minute))
+	if err := tracker.Check(nil); err != nil {
+		t.Fatalf("Check() within window error = %v", err)
+	}
+
+	// Queue wedged: overdue beyond the window with no reconcile
+	clk.SetTime(clk.Now().Add(10 * time.Minute))
+	if err := tracker.Check(nil); err == nil {
+		t.Fatal("Check() on stalled queue succeeded, want error")
+	}
+
+	// Queue resumes: the due reconcile runs
+	tracker.Observe(key)
+	if err := tracker.Check(nil); err != nil {
+		t.Fatalf("Check() after reconcile error = %v", err)
+	}
+}
+
+func TestProgressTrackerNilIsHealthy(t *testing.T) {
+	t.Parallel()
+
+	var tracker *ProgressTracker
+	tracker.Observe(types.NamespacedName{Name: "profile"})
+	tracker.Scheduled(types.NamespacedName{Name: "profile"}, time.Now())
+	if err := tracker.Check(nil); err != nil {
+		t.Errorf("Check() on nil tracker error = %v", err)
+	}
+}
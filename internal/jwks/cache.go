@@ -0,0 +1,79 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jwks
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxCachedDocuments bounds the cache, as clients choose the keys.
+const maxCachedDocuments = 1024
+
+// documentCache holds rendered documents per KeyProfile for a short TTL.
+// A nil document records that there is nothing to serve. Safe for concurrent use.
+type documentCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	clock   clock.PassiveClock
+	entries map[client.ObjectKey]cachedDocument
+}
+
+type cachedDocument struct {
+	body      []byte
+	expiresAt time.Time
+}
+
+func newDocumentCache(ttl time.Duration, clk clock.PassiveClock) *documentCache {
+	return &documentCache{ttl: ttl, clock: clk, entries: map[client.ObjectKey]cachedDocument{}}
+}
+
+// get returns the unexpired document cached for key.
+func (c *documentCache) get(key client.ObjectKey) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok || !c.clock.Now().Before(e.expiresAt) {
+		return nil, false
+	}
+	return e.body, true
+}
+
+// put caches body for key. Expired entries are dropped first; a full cache
+// accepts no new keys.
+func (c *documentCache) put(key client.ObjectKey, body []byte) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock.Now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= maxCachedDocuments {
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= maxCachedDocuments {
+			return
+		}
+	}
+	c.entries[key] = cachedDocument{body: body, expiresAt: now.Add(c.ttl)}
+}
@@ -0,0 +1,346 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package jwks serves a read-only JWKS endpoint for KeyProfiles from the
+// public material in their managed Secrets.
+package jwks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/output"
+)
+
+// Public key entries read from the managed Secret, for split-pem and single-pem.
+// Only "PUBLIC KEY" PEM blocks are parsed, so private material is never read
+// into a response. [SEC:S-2]
+var (
+	currentPublicKeys  = []string{"public.pem", "keypair.pem"}
+	previousPublicKeys = []string{"public-previous.pem", "keypair-previous.pem"}
+)
+
+// Server serves GET /{namespace}/{name}/jwks.json with the current key and,
+// while its grace period lasts, the previous key of a KeyProfile.
+type Server struct {
+	addr     string
+	reader   client.Reader
+	log      logr.Logger
+	metadata bool
+	cache    *documentCache
+	limiter  *rate.Limiter
+}
+
+// DefaultCacheTTL is how long served documents are cached by default.
+const DefaultCacheTTL = 10 * time.Second
+
+// Cache misses read the KeyProfile and its Secrets from the API server; they
+// are limited to missRate per second (with bursts of missBurst), so clients
+// requesting unknown profiles cannot flood it.
+const (
+	missRate  = 20
+	missBurst = 50
+)
+
+// Option configures optional behavior of the Server.
+type Option func(*Server)
+
+// WithMetadata controls whether documents carry the non-standard "_openukr"
+// member (see Metadata) next to "keys". Disabled by default, so documents
+// are plain RFC 7517 key sets; strict JWKS parsers only read "keys".
+func WithMetadata(enabled bool) Option {
+	return func(s *Server) {
+		s.metadata = enabled
+	}
+}
+
+// WithCacheTTL sets how long a served document (or its absence) is cached,
+// bounding how stale a response may be after a rotation. Zero disables caching.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(s *Server) {
+		s.cache.ttl = ttl
+	}
+}
+
+// WithClock sets the clock used for cache expiry and document metadata.
+func WithClock(clk clock.PassiveClock) Option {
+	return func(s *Server) {
+		s.cache.clock = clk
+	}
+}
+
+// NewServer creates a JWKS server listening on addr. reader should be an
+// uncached reader (mgr.GetAPIReader()) so Secrets are not cached cluster-wide;
+// the server caches the rendered documents instead (see WithCacheTTL).
+func NewServer(addr string, reader client.Reader, log logr.Logger, opts ...Option) *Server {
+	s := &Server{
+		addr:    addr,
+		reader:  reader,
+		log:     log,
+		cache:   newDocumentCache(DefaultCacheTTL, clock.RealClock{}),
+		limiter: rate.NewLimiter(missRate, missBurst),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Metadata is the "_openukr" member of served documents, for monitoring.
+type Metadata struct {
+	// GeneratedAt is when the document was generated, RFC 3339. Documents are
+	// cached (see WithCacheTTL), so it may predate the response.
+	GeneratedAt string `json:"generatedAt"`
+	// NextRotation is the profile's next scheduled rotation, RFC 3339.
+	NextRotation string `json:"nextRotation,omitempty"`
+	// KeyProfile is the serving KeyProfile as namespace/name.
+	KeyProfile string `json:"keyProfile"`
+}
+
+// document is a JWK Set with the optional metadata member.
+type document struct {
+	Keys     json.RawMessage `json:"keys"`
+	Metadata *Metadata       `json:"_openukr,omitempty"`
+}
+
+// Handler returns the HTTP handler serving the JWKS documents.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{namespace}/{name}/jwks.json", s.serveJWKS)
+	return mux
+}
+
+// Start runs the server until ctx is cancelled. It implements manager.Runnable.
+func (s *Server) Start(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		s.log.Info("Serving JWKS", "addr", s.addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("JWKS server failed: %w", err)
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("JWKS server shutdown failed: %w", err)
+		}
+		return nil
+	}
+}
+
+// NeedLeaderElection reports false: every replica serves the read-only endpoint.
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *Server) serveJWKS(w http.ResponseWriter, r *http.Request) {
+	key := client.ObjectKey{Namespace: r.PathValue("namespace"), Name: r.PathValue("name")}
+
+	body, ok := s.cache.get(key)
+	if !ok {
+		// Misses read from the API server, so they are rate limited
+		if !s.limiter.Allow() {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+			return
+		}
+		var err error
+		if body, err = s.load(r.Context(), key); err != nil {
+			s.log.Error(err, "Failed to load JWKS", "keyprofile", key)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		s.cache.put(key, body)
+	}
+	if body == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/jwk-set+json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if ttl := int(s.cache.ttl.Seconds()); ttl > 0 {
+		w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", ttl))
+	}
+	_, _ = w.Write(body)
+}
+
+// load reads the KeyProfile and its Secrets and renders the document. A nil
+// document without error means there is nothing to serve.
+func (s *Server) load(ctx context.Context, key client.ObjectKey) ([]byte, error) {
+	var profile openukrv1alpha1.KeyProfile
+	if err := s.reader.Get(ctx, key, &profile); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get KeyProfile: %w", err)
+	}
+	keys, err := s.publicKeys(ctx, &profile)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to load public keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	body, err := s.encode(&profile, keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JWKS: %w", err)
+	}
+	return body, nil
+}
+
+// encode renders keys as a JWK Set, adding the metadata member if enabled.
+func (s *Server) encode(profile *openukrv1alpha1.KeyProfile, keys []*crypto.PublicKeyInfo) ([]byte, error) {
+	set, err := crypto.EncodeJWKS(keys)
+	if err != nil || !s.metadata {
+		return set, err
+	}
+	var doc document
+	if err := json.Unmarshal(set, &doc); err != nil {
+		return nil, err
+	}
+	doc.Metadata = &Metadata{
+		GeneratedAt: s.cache.clock.Now().UTC().Format(time.RFC3339),
+		KeyProfile:  profile.Namespace + "/" + profile.Name,
+	}
+	if next := profile.Status.NextRotation; next != nil {
+		doc.Metadata.NextRotation = next.UTC().Format(time.RFC3339)
+	}
+	return json.Marshal(doc)
+}
+
+// publicKeys loads the current and in-grace previous public keys of a KeyProfile.
+// Profiles in the hard grace mode only list the current key.
+func (s *Server) publicKeys(ctx context.Context, profile *openukrv1alpha1.KeyProfile) ([]*crypto.PublicKeyInfo, error) {
+	key := client.ObjectKeyFromObject(profile)
+	currentName, previousName, err := output.SecretNames(ctx, s.reader, profile)
+	if err != nil {
+		return nil, err
+	}
+	if currentName == "" {
+		return nil, nil
+	}
+	secret, err := s.getSecret(ctx, key.Namespace, currentName)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*crypto.PublicKeyInfo
+	if pub := publicKeyInfo(secret, currentPublicKeys, secret.Annotations["openukr.io/key-id"],
+		keyAlgorithm(secret, "openukr.io/algorithm", profile)); pub != nil {
+		keys = append(keys, pub)
+	}
+
+	// The previous key is only served while the profile reports it in its grace
+	// period, and never after a hard cutover
+	if profile.Status.PreviousKeyID == "" || profile.Spec.Rotation.GraceMode == openukrv1alpha1.GraceModeHard {
+		return keys, nil
+	}
+	if !profile.Spec.Output.Immutable {
+		// After an algorithm migration the previous key uses the old algorithm
+		prevID := secret.Annotations["openukr.io/previous-key-id"]
+		prevAlg := keyAlgorithm(secret, "openukr.io/previous-algorithm", profile)
+		if pub := publicKeyInfo(secret, previousPublicKeys, prevID, prevAlg); pub != nil {
+			keys = append(keys, pub)
+		}
+		return keys, nil
+	}
+	// Immutable outputs keep the previous key in its own Secret
+	if previousName == "" {
+		return keys, nil
+	}
+	previous, err := s.getSecret(ctx, key.Namespace, previousName)
+	if err != nil {
+		return keys, client.IgnoreNotFound(err)
+	}
+	if pub := publicKeyInfo(previous, currentPublicKeys, previous.Annotations["openukr.io/key-id"],
+		keyAlgorithm(previous, "openukr.io/algorithm", profile)); pub != nil {
+		keys = append(keys, pub)
+	}
+	return keys, nil
+}
+
+func (s *Server) getSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	var secret corev1.Secret
+	if err := s.reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &secret); err != nil {
+		return nil, fmt.Errorf("failed to get Secret: %w", err)
+	}
+	return &secret, nil
+}
+
+// keyAlgorithm returns the algorithm recorded in the Secret annotation, falling
+// back to the profile's algorithm for Secrets written before it was recorded.
+func keyAlgorithm(secret *corev1.Secret, annotation string, profile *openukrv1alpha1.KeyProfile) string {
+	if alg := secret.Annotations[annotation]; alg != "" {
+		return alg
+	}
+	return profile.Spec.KeySpec.Algorithm
+}
+
+// publicKeyInfo parses the first present data entry among dataKeys as a public key.
+func publicKeyInfo(
+	secret *corev1.Secret,
+	dataKeys []string,
+	keyID string,
+	algorithm string,
+) *crypto.PublicKeyInfo {
+	if keyID == "" {
+		return nil
+	}
+	for _, k := range dataKeys {
+		data, ok := secret.Data[k]
+		if !ok {
+			continue
+		}
+		pub, err := crypto.ParsePublicKeyPEM(data)
+		if err != nil {
+			return nil
+		}
+		return &crypto.PublicKeyInfo{
+			KeyID:     keyID,
+			PublicKey: pub,
+			Algorithm: algorithm,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,435 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jwks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clocktesting "k8s.io/utils/clock/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/output"
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	if err := openukrv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	return scheme
+}
+
+func encodeTestKey(t *testing.T) (priv, pub []byte) {
+	t.Helper()
+	kp, err := crypto.NewKeyGenerator().Generate(crypto.GenerateOptions{
+		Algorithm: crypto.AlgorithmEC,
+		Params:    map[string]string{"curve": crypto.CurveP256},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer kp.Wipe()
+	encoder, err := crypto.NewKeyEncoder("PEM")
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	if priv, err = encoder.EncodePrivate(kp.PrivateKey); err != nil {
+		t.Fatalf("EncodePrivate() error = %v", err)
+	}
+	if pub, err = encoder.EncodePublic(kp.PublicKey); err != nil {
+		t.Fatalf("EncodePublic() error = %v", err)
+	}
+	return priv, pub
+}
+
+func TestServeJWKS(t *testing.T) {
+	t.Parallel()
+
+	currentPriv, currentPub := encodeTestKey(t)
+	previousPriv, previousPub := encodeTestKey(t)
+
+	inGrace := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "in-grace", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			KeySpec: openukrv1alpha1.KeySpec{Algorithm: crypto.AlgorithmEC},
+			Output:  openukrv1alpha1.OutputConfig{SecretName: "in-grace-keys"},
+		},
+		Status: openukrv1alpha1.KeyProfileStatus{CurrentKeyID: "current", PreviousKeyID: "previous"},
+	}
+	expired := inGrace.DeepCopy()
+	expired.Name = "expired"
+	expired.Spec.Output.SecretName = "expired-keys"
+	expired.Status.PreviousKeyID = ""
+	hardCutover := inGrace.DeepCopy()
+	hardCutover.Name = "hard-cutover"
+	hardCutover.Spec.Output.SecretName = "hard-cutover-keys"
+	hardCutover.Spec.Rotation.GraceMode = openukrv1alpha1.GraceModeHard
+	noSecret := inGrace.DeepCopy()
+	noSecret.Name = "no-secret"
+	noSecret.Spec.Output.SecretName = "missing"
+
+	newSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Annotations: map[string]string{
+					"openukr.io/key-id":          "current",
+					"openukr.io/previous-key-id": "previous",
+				},
+			},
+			Data: map[string][]byte{
+				"tls.key":             currentPriv,
+				"public.pem":          currentPub,
+				"tls-previous.key":    previousPriv,
+				"public-previous.pem": previousPub,
+			},
+		}
+	}
+
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(inGrace, expired, hardCutover, noSecret,
+			newSecret("in-grace-keys"), newSecret("expired-keys"), newSecret("hard-cutover-keys")).
+		Build()
+
+	srv := httptest.NewServer(NewServer("", c, logr.Discard()).Handler())
+	t.Cleanup(srv.Close) // outlives the parallel subtests
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantKids   []string
+	}{
+		{
+			name:       "current and previous in grace",
+			path:       "/default/in-grace/jwks.json",
+			wantStatus: http.StatusOK,
+			wantKids:   []string{"current", "previous"},
+		},
+		{
+			name:       "hard cutover in grace",
+			path:       "/default/hard-cutover/jwks.json",
+			wantStatus: http.StatusOK,
+			wantKids:   []string{"current"},
+		},
+		{
+			name:       "grace period over",
+			path:       "/default/expired/jwks.json",
+			wantStatus: http.StatusOK,
+			wantKids:   []string{"current"},
+		},
+		{
+			name:       "unknown profile",
+			path:       "/default/unknown/jwks.json",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "missing secret",
+			path:       "/default/no-secret/jwks.json",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "unknown path",
+			path:       "/default/in-grace/keys",
+			wantStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			resp, err := http.Get(srv.URL + tt.path)
+			if err != nil {
+				t.Fatalf("GET error = %v", err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", resp.StatusCode, tt.wantStatus, body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if got := resp.Header.Get("Content-Type"); got != "application/jwk-set+json" {
+				t.Errorf("Content-Type = %q, want application/jwk-set+json", got)
+			}
+
+			var set struct {
+				Keys []map[string]any `json:"keys"`
+			}
+			if err := json.Unmarshal(body, &set); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if len(set.Keys) != len(tt.wantKids) {
+				t.Fatalf("JWKS has %d keys, want %d", len(set.Keys), len(tt.wantKids))
+			}
+			for i, k := range set.Keys {
+				if k["kid"] != tt.wantKids[i] {
+					t.Errorf("keys[%d].kid = %v, want %s", i, k["kid"], tt.wantKids[i])
+				}
+				if _, ok := k["d"]; ok {
+					t.Errorf("keys[%d] exposes private component", i)
+				}
+			}
+		})
+	}
+}
+
+func TestServeJWKSAlgorithmMigration(t *testing.T) {
+	t.Parallel()
+
+	scheme := newTestScheme(t)
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "migrating", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			KeySpec: openukrv1alpha1.KeySpec{Algorithm: crypto.AlgorithmRSA},
+			Rotation: openukrv1alpha1.RotationPolicy{
+				MigrateOnAlgorithmChange: true,
+			},
+			Output: openukrv1alpha1.OutputConfig{SecretName: "migrating-keys", Format: output.FormatSplitPEM},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(profile).Build()
+	writer := output.NewSecretWriter(c, scheme, output.NewRenderer())
+	ctx := context.Background()
+
+	generate := func(opts crypto.GenerateOptions) *crypto.KeyPair {
+		kp, err := crypto.NewKeyGenerator().Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		t.Cleanup(kp.Wipe)
+		return kp
+	}
+	rsaKey := generate(crypto.GenerateOptions{
+		Algorithm: crypto.AlgorithmRSA,
+		Params:    map[string]string{"keySize": "3072"},
+	})
+	ecKey := generate(crypto.GenerateOptions{
+		Algorithm: crypto.AlgorithmEC,
+		Params:    map[string]string{"curve": crypto.CurveP256},
+	})
+
+	// RSA key in place, then the spec migrates to EC
+	if err := writer.Write(ctx, profile, rsaKey); err != nil {
+		t.Fatalf("Write() RSA error = %v", err)
+	}
+	profile.Spec.KeySpec.Algorithm = crypto.AlgorithmEC
+	if err := writer.Write(ctx, profile, ecKey); err != nil {
+		t.Fatalf("Write() EC error = %v", err)
+	}
+	profile.Status.CurrentKeyID = ecKey.KeyID
+	profile.Status.PreviousKeyID = rsaKey.KeyID
+	if err := c.Update(ctx, profile); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	srv := httptest.NewServer(NewServer("", c, logr.Discard()).Handler())
+	defer srv.Close()
+	resp, err := http.Get(srv.URL + "/default/migrating/jwks.json")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", resp.StatusCode, body)
+	}
+
+	var set struct {
+		Keys []map[string]any `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &set); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	// Both keys are served during the grace period, each under its own kid
+	kty := make(map[any]any, len(set.Keys))
+	for _, k := range set.Keys {
+		kty[k["kid"]] = k["kty"]
+	}
+	if len(set.Keys) != 2 || kty[rsaKey.KeyID] != "RSA" || kty[ecKey.KeyID] != "EC" {
+		t.Errorf("JWKS keys = %v, want RSA %s and EC %s", kty, rsaKey.KeyID, ecKey.KeyID)
+	}
+}
+
+func TestServeJWKSMetadata(t *testing.T) {
+	t.Parallel()
+
+	_, pub := encodeTestKey(t)
+	next := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			KeySpec: openukrv1alpha1.KeySpec{Algorithm: crypto.AlgorithmEC},
+			Output:  openukrv1alpha1.OutputConfig{SecretName: "profile-keys"},
+		},
+		Status: openukrv1alpha1.KeyProfileStatus{
+			CurrentKeyID: "current",
+			NextRotation: &metav1.Time{Time: next},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "profile-keys",
+			Namespace:   "default",
+			Annotations: map[string]string{"openukr.io/key-id": "current"},
+		},
+		Data: map[string][]byte{"public.pem": pub},
+	}
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(profile, secret).Build()
+
+	get := func(t *testing.T, s *Server) map[string]json.RawMessage {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/default/profile/jwks.json", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		var doc map[string]json.RawMessage
+		if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		// The key set itself stays RFC 7517: an array of JWKs with kid and kty
+		var keys []map[string]any
+		if err := json.Unmarshal(doc["keys"], &keys); err != nil {
+			t.Fatalf("keys is not a JWK array: %v", err)
+		}
+		if len(keys) != 1 || keys[0]["kid"] != "current" || keys[0]["kty"] != "EC" {
+			t.Errorf("keys = %v, want the current EC key", keys)
+		}
+		return doc
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Parallel()
+		now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+		doc := get(t, NewServer("", c, logr.Discard(), WithMetadata(true),
+			WithClock(clocktesting.NewFakePassiveClock(now))))
+		var meta Metadata
+		if err := json.Unmarshal(doc["_openukr"], &meta); err != nil {
+			t.Fatalf("_openukr is missing or invalid: %v", err)
+		}
+		if meta.KeyProfile != "default/profile" || meta.NextRotation != "2026-01-02T00:00:00Z" {
+			t.Errorf("_openukr = %+v, want keyProfile default/profile and the next rotation", meta)
+		}
+		if meta.GeneratedAt != "2026-01-01T12:00:00Z" {
+			t.Errorf("generatedAt = %q, want the server clock's time 2026-01-01T12:00:00Z", meta.GeneratedAt)
+		}
+	})
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		doc := get(t, NewServer("", c, logr.Discard()))
+		if len(doc) != 1 {
+			t.Errorf("document members = %v, want only keys", doc)
+		}
+	})
+}
+
+func TestServeJWKSCache(t *testing.T) {
+	t.Parallel()
+
+	_, pub := encodeTestKey(t)
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			KeySpec: openukrv1alpha1.KeySpec{Algorithm: crypto.AlgorithmEC},
+			Output:  openukrv1alpha1.OutputConfig{SecretName: "profile-keys"},
+		},
+		Status: openukrv1alpha1.KeyProfileStatus{CurrentKeyID: "current"},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "profile-keys",
+			Namespace:   "default",
+			Annotations: map[string]string{"openukr.io/key-id": "current"},
+		},
+		Data: map[string][]byte{"public.pem": pub},
+	}
+	scheme := newTestScheme(t)
+	var reads int
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(profile, secret).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				reads++
+				return c.Get(ctx, key, obj, opts...)
+			},
+		}).Build()
+
+	clk := clocktesting.NewFakePassiveClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewServer("", c, logr.Discard(), WithClock(clk), WithCacheTTL(10*time.Second))
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	if rec := get("/default/profile/jwks.json"); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	} else if got := rec.Header().Get("Cache-Control"); got != "public, max-age=10" {
+		t.Errorf("Cache-Control = %q, want public, max-age=10", got)
+	}
+	first := reads
+	for range 5 {
+		if rec := get("/default/profile/jwks.json"); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200", rec.Code)
+		}
+		if rec := get("/default/unknown/jwks.json"); rec.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want 404", rec.Code)
+		}
+	}
+	// One read for the unknown profile, none for the cached document
+	if reads != first+1 {
+		t.Errorf("API reads = %d, want %d", reads, first+1)
+	}
+
+	clk.SetTime(clk.Now().Add(10 * time.Second))
+	if rec := get("/default/profile/jwks.json"); rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if reads == first+1 {
+		t.Error("expired document was served from the cache")
+	}
+}
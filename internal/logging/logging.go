@@ -0,0 +1,80 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package logging translates the --log-level and --log-format flags into
+// controller-runtime zap options.
+package logging
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap/zapcore"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+// Supported --log-format values.
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// Options returns zap options for the given level and format. Empty values
+// leave the corresponding setting to the --zap-* flags.
+//
+// level is one of debug, info, error, or a non-negative logr verbosity
+// (0 = info, 1 = debug, 2+ = more detail, enabling log.V(n) calls up to n).
+func Options(level, format string) ([]zap.Opts, error) {
+	var opts []zap.Opts
+
+	if level != "" {
+		lvl, err := parseLevel(level)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, zap.Level(lvl))
+	}
+
+	switch format {
+	case "":
+	case FormatJSON:
+		opts = append(opts, zap.JSONEncoder())
+	case FormatConsole:
+		opts = append(opts, zap.ConsoleEncoder())
+	default:
+		return nil, fmt.Errorf("unsupported log format %q, must be %s or %s", format, FormatJSON, FormatConsole)
+	}
+
+	return opts, nil
+}
+
+// parseLevel maps a level name or logr verbosity to a zap level.
+// logr verbosity n corresponds to zap level -n.
+func parseLevel(level string) (zapcore.Level, error) {
+	switch level {
+	case "debug":
+		return zapcore.DebugLevel, nil
+	case "info":
+		return zapcore.InfoLevel, nil
+	case "error":
+		return zapcore.ErrorLevel, nil
+	}
+	v, err := strconv.Atoi(level)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("unsupported log level %q, must be debug, info, error or a verbosity >= 0", level)
+	}
+	return zapcore.Level(-v), nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+)
+
+func TestOptionsConfiguresLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		level       string
+		wantInfo    bool
+		wantVerbose bool
+	}{
+		{level: "error", wantInfo: false, wantVerbose: false},
+		{level: "info", wantInfo: true, wantVerbose: false},
+		{level: "debug", wantInfo: true, wantVerbose: true},
+		{level: "2", wantInfo: true, wantVerbose: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level, func(t *testing.T) {
+			t.Parallel()
+			opts, err := Options(tt.level, FormatJSON)
+			if err != nil {
+				t.Fatalf("Options() error = %v", err)
+			}
+			var buf bytes.Buffer
+			log := zap.New(append(opts, zap.WriteTo(&buf))...)
+
+			log.Error(errors.New("boom"), "error message")
+			log.Info("info message")
+			log.V(1).Info("verbose message")
+
+			out := buf.String()
+			if !strings.Contains(out, "error message") {
+				t.Errorf("error not logged at level %s: %s", tt.level, out)
+			}
+			if got := strings.Contains(out, "info message"); got != tt.wantInfo {
+				t.Errorf("info logged = %v, want %v", got, tt.wantInfo)
+			}
+			if got := strings.Contains(out, "verbose message"); got != tt.wantVerbose {
+				t.Errorf("V(1) logged = %v, want %v", got, tt.wantVerbose)
+			}
+
+			// JSON format: every line is a JSON object
+			for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+				if !json.Valid([]byte(line)) {
+					t.Errorf("log line is not JSON: %s", line)
+				}
+			}
+		})
+	}
+}
+
+func TestOptionsRejectsInvalidValues(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Options("verbose", ""); err == nil {
+		t.Error("Options(level=verbose) succeeded, want error")
+	}
+	if _, err := Options("-1", ""); err == nil {
+		t.Error("Options(level=-1) succeeded, want error")
+	}
+	if _, err := Options("", "xml"); err == nil {
+		t.Error("Options(format=xml) succeeded, want error")
+	}
+	if opts, err := Options("", ""); err != nil || len(opts) != 0 {
+		t.Errorf("Options(\"\", \"\") = %d opts, %v; want none", len(opts), err)
+	}
+}
@@ -19,78 +19,184 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
 	pkgcrypto "github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/output"
+	"github.com/openukr/openukr/pkg/rotation"
 	"github.com/openukr/openukr/pkg/validation"
 )
 
-var keyprofilelog = logf.Log.WithName("keyprofile-webhook") //nolint:unused
+var keyprofilelog = logf.Log.WithName("keyprofile-webhook")
+
+// ValidationPolicy holds cluster-wide admission policy for KeyProfiles.
+// The zero value is the permissive, backward-compatible policy.
+type ValidationPolicy struct {
+	// RejectInsecurePublish turns the insecureSkipVerify warning into a hard error.
+	// [SEC:T-2]
+	RejectInsecurePublish bool
+
+	// DeniedPublishPaths are directory prefixes filesystem publish paths may not
+	// point into. Nil uses validation.DefaultDeniedPublishPaths; an empty,
+	// non-nil list disables the check. [SEC:S-3]
+	DeniedPublishPaths []string
+
+	// PublishHosts restricts the hosts HTTP publish endpoints and Key Vaults may point to.
+	// Nil uses validation.DefaultHostPolicy. [SEC:S-3]
+	PublishHosts *validation.HostPolicy
+
+	// MinInterval is the cluster-wide floor for Spec.Rotation.Interval, protecting
+	// the controller from fleets of very short intervals. Zero disables the check.
+	MinInterval time.Duration
+
+	// RejectShortInterval turns the below-MinInterval warning into a hard error.
+	RejectShortInterval bool
+}
 
 // SetupKeyProfileWebhookWithManager registers the webhook for KeyProfile in the manager.
-func SetupKeyProfileWebhookWithManager(mgr ctrl.Manager) error {
+func SetupKeyProfileWebhookWithManager(mgr ctrl.Manager, policy ValidationPolicy) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&openukrv1alpha1.KeyProfile{}).
-		WithValidator(&KeyProfileCustomValidator{}).
-		WithDefaulter(&KeyProfileCustomDefaulter{}).
+		WithValidator(&KeyProfileCustomValidator{Policy: policy, Reader: mgr.GetAPIReader()}).
+		WithDefaulter(&KeyProfileCustomDefaulter{Reader: mgr.GetAPIReader()}).
 		Complete()
 }
 
 // +kubebuilder:webhook:path=/mutate-openukr-openukr-io-v1alpha1-keyprofile,mutating=true,failurePolicy=fail,sideEffects=None,groups=openukr.openukr.io,resources=keyprofiles,verbs=create;update,versions=v1alpha1,name=mkeyprofile-v1alpha1.kb.io,admissionReviewVersions=v1
 
 // KeyProfileCustomDefaulter sets defaults on KeyProfile resources.
-type KeyProfileCustomDefaulter struct{}
+type KeyProfileCustomDefaulter struct {
+	// Reader reads the namespace defaults ConfigMap (see NamespaceDefaultsConfigMap).
+	// Nil applies built-in defaults only.
+	Reader client.Reader
+}
 
 var _ webhook.CustomDefaulter = &KeyProfileCustomDefaulter{}
 
 // Default sets default values for KeyProfile fields.
-func (d *KeyProfileCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+func (d *KeyProfileCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
 	keyprofile, ok := obj.(*openukrv1alpha1.KeyProfile)
 	if !ok {
 		return fmt.Errorf("webhook defaulter: expected KeyProfile but got %T", obj)
 	}
 
+	// Namespace defaults fill omitted fields of new profiles before the
+	// built-in defaults below; updates keep the values chosen at creation
+	if d.Reader != nil && isCreate(ctx) {
+		if err := applyNamespaceDefaults(ctx, d.Reader, keyprofile); err != nil {
+			return fmt.Errorf("webhook defaulter: %w", err)
+		}
+	}
+
+	// Canonical casing, e.g. algorithm "ec" → "EC", curve "p-256" → "P-256"
+	keyprofile.Spec.KeySpec.Algorithm, keyprofile.Spec.KeySpec.Params = pkgcrypto.NormalizeKeySpec(
+		keyprofile.Spec.KeySpec.Algorithm, keyprofile.Spec.KeySpec.Params)
+
 	// Default encoding to PEM if not set
 	if keyprofile.Spec.KeySpec.Encoding == "" {
 		keyprofile.Spec.KeySpec.Encoding = "PEM"
 	}
 
+	// Default private key PEM type to PKCS#8 if not set
+	if keyprofile.Spec.KeySpec.PrivateKeyPEMType == "" {
+		keyprofile.Spec.KeySpec.PrivateKeyPEMType = pkgcrypto.PrivateKeyPEMTypePKCS8
+	}
+
+	// Default grace mode to overlap if not set
+	if keyprofile.Spec.Rotation.GraceMode == "" {
+		keyprofile.Spec.Rotation.GraceMode = openukrv1alpha1.GraceModeOverlap
+	}
+
 	// Default output format to split-pem if not set
 	if keyprofile.Spec.Output.Format == "" {
 		keyprofile.Spec.Output.Format = "split-pem"
 	}
+	for i := range keyprofile.Spec.AdditionalOutputs {
+		if keyprofile.Spec.AdditionalOutputs[i].Format == "" {
+			keyprofile.Spec.AdditionalOutputs[i].Format = "split-pem"
+		}
+	}
 
 	return nil
 }
 
+// isCreate reports whether the admission request in ctx creates the object.
+// Without a request (direct calls) the object is treated as new.
+func isCreate(ctx context.Context) bool {
+	req, err := admission.RequestFromContext(ctx)
+	return err != nil || req.Operation == admissionv1.Create
+}
+
 // +kubebuilder:webhook:path=/validate-openukr-openukr-io-v1alpha1-keyprofile,mutating=false,failurePolicy=fail,sideEffects=None,groups=openukr.openukr.io,resources=keyprofiles,verbs=create;update,versions=v1alpha1,name=vkeyprofile-v1alpha1.kb.io,admissionReviewVersions=v1
 
 // KeyProfileCustomValidator validates KeyProfile resources.
-type KeyProfileCustomValidator struct{}
+type KeyProfileCustomValidator struct {
+	Policy ValidationPolicy
+	// Reader reads the schedule a profile references (Spec.Rotation.ScheduleRef)
+	// to check its interval. Nil leaves the check to the controller.
+	Reader client.Reader
+}
 
 var _ webhook.CustomValidator = &KeyProfileCustomValidator{}
 
 // ValidateCreate validates a KeyProfile upon creation.
-func (v *KeyProfileCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+func (v *KeyProfileCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 	keyprofile, ok := obj.(*openukrv1alpha1.KeyProfile)
 	if !ok {
 		return nil, fmt.Errorf("webhook validator: expected KeyProfile but got %T", obj)
 	}
-	return validateKeyProfile(keyprofile)
+	warnings, err := validateKeyProfile(keyprofile, v.Policy)
+	if err != nil {
+		return nil, err
+	}
+	scheduleWarnings, err := v.validateSchedule(ctx, keyprofile)
+	if err != nil {
+		return nil, err
+	}
+	return append(warnings, scheduleWarnings...), nil
 }
 
 // ValidateUpdate validates a KeyProfile upon update.
-func (v *KeyProfileCustomValidator) ValidateUpdate(_ context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+func (v *KeyProfileCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	keyprofile, ok := newObj.(*openukrv1alpha1.KeyProfile)
 	if !ok {
 		return nil, fmt.Errorf("webhook validator: expected KeyProfile but got %T", newObj)
 	}
-	return validateKeyProfile(keyprofile)
+	old, ok := oldObj.(*openukrv1alpha1.KeyProfile)
+	if !ok {
+		return nil, fmt.Errorf("webhook validator: expected KeyProfile but got %T", oldObj)
+	}
+	warnings, err := validateKeyProfile(keyprofile, v.Policy)
+	if err != nil {
+		return nil, err
+	}
+	scheduleWarnings, err := v.validateSchedule(ctx, keyprofile)
+	if err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, scheduleWarnings...)
+
+	// Renaming the output does not migrate consumers; the old Secret keeps its
+	// last key until the profile is deleted
+	if oldName, newName := old.Spec.Output.SecretName, keyprofile.Spec.Output.SecretName; oldName != newName {
+		warnings = append(warnings, fmt.Sprintf(
+			"spec.output.secretName changed from %q to %q: the old Secret is no longer updated "+
+				"and consumers must be switched to the new name", oldName, newName))
+	}
+	return warnings, nil
 }
 
 // ValidateDelete validates a KeyProfile upon deletion.
@@ -99,11 +205,110 @@ func (v *KeyProfileCustomValidator) ValidateDelete(_ context.Context, _ runtime.
 	return nil, nil
 }
 
+// validateSchedule checks the interval of the schedule a profile without an
+// inline interval references, as validateKeyProfile checks an inline one. The
+// controller checks it again on every reconcile, as the schedule may change. A
+// missing schedule only warns: it may be created after the profile.
+func (v *KeyProfileCustomValidator) validateSchedule(
+	ctx context.Context,
+	kp *openukrv1alpha1.KeyProfile,
+) (admission.Warnings, error) {
+	ref := kp.Spec.Rotation.ScheduleRef
+	if v.Reader == nil || ref == nil || kp.Spec.Rotation.Interval.Duration > 0 {
+		return nil, nil
+	}
+	kind := rotation.ScheduleKind(ref)
+	interval, err := rotation.ScheduleInterval(ctx, v.Reader, kp.Namespace, ref)
+	if apierrors.IsNotFound(err) {
+		return admission.Warnings{fmt.Sprintf(
+			"rotation.scheduleRef: %s %q not found; keys are not rotated until it exists", kind, ref.Name)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := validation.ValidateRotationPolicy(interval, kp.Spec.Rotation.GracePeriod.Duration); err != nil {
+		return nil, fmt.Errorf("validation failed: %s %q: %w", kind, ref.Name, err)
+	}
+	if err := validation.ValidateRotateBeforeExpiry(interval, kp.Spec.Rotation.RotateBeforeExpiry.Duration); err != nil {
+		return nil, fmt.Errorf("validation failed: %s %q: %w", kind, ref.Name, err)
+	}
+	if msg := validation.CheckMinInterval(interval, v.Policy.MinInterval); msg != "" {
+		if v.Policy.RejectShortInterval {
+			return nil, fmt.Errorf("validation failed: %s %q: %s", kind, ref.Name, msg)
+		}
+		return admission.Warnings{fmt.Sprintf("%s %q: %s", kind, ref.Name, msg)}, nil
+	}
+	return nil, nil
+}
+
+// validateAdditionalOutputs checks Spec.AdditionalOutputs: Secret names must be
+// unique across all outputs, and each output must be able to hold the key.
+func validateAdditionalOutputs(kp *openukrv1alpha1.KeyProfile) error {
+	secretNames := map[string]bool{kp.Spec.Output.SecretName: true}
+	for i, out := range kp.Spec.AdditionalOutputs {
+		if secretNames[out.SecretName] {
+			return fmt.Errorf("additionalOutputs[%d]: secretName %q is already used by another output", i, out.SecretName)
+		}
+		secretNames[out.SecretName] = true
+
+		if out.Immutable || out.KeepHistorySecrets > 0 {
+			return fmt.Errorf("additionalOutputs[%d]: immutable and keepHistorySecrets are only supported on output", i)
+		}
+		if err := validation.ValidateFormatAlgorithm(
+			out.Format,
+			kp.Spec.KeySpec.Algorithm,
+			kp.Spec.KeySpec.Params,
+		); err != nil {
+			return fmt.Errorf("additionalOutputs[%d]: %w", i, err)
+		}
+		if err := output.ValidateKeyNames(out.KeyNames); err != nil {
+			return fmt.Errorf("additionalOutputs[%d].%w", i, err)
+		}
+		if err := validateEncryption(out); err != nil {
+			return fmt.Errorf("additionalOutputs[%d].%w", i, err)
+		}
+		if td := out.SPIFFETrustDomain; td != "" {
+			if _, err := output.NewSPIFFEID(
+				td,
+				kp.Spec.ServiceAccountRef.Namespace,
+				kp.Spec.ServiceAccountRef.Name,
+			); err != nil {
+				return fmt.Errorf("additionalOutputs[%d]: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateEncryption checks the encryption settings of an output.
+func validateEncryption(out openukrv1alpha1.OutputConfig) error {
+	enc := out.Encryption
+	switch {
+	case enc == nil:
+		return nil
+	case (enc.Age == nil) == (enc.AzureKeyVault == nil):
+		return fmt.Errorf("encryption: exactly one of age and azureKeyVault must be set")
+	case enc.Age != nil:
+		return output.ValidateAgeRecipients(enc.Age.Recipients)
+	}
+	kv := enc.AzureKeyVault
+	if _, _, err := validation.ValidateKeyVaultKeyURL(kv.KeyURL); err != nil {
+		return fmt.Errorf("encryption.azureKeyVault.keyURL: %w", err)
+	}
+	if kv.ClientSecretRef != "" && (kv.TenantID == "" || kv.ClientID == "") {
+		return fmt.Errorf("encryption.azureKeyVault: tenantID and clientID are required with clientSecretRef")
+	}
+	return nil
+}
+
 // validateKeyProfile runs all validation rules against a KeyProfile.
 // All validation is delegated to shared packages (DRY):
-//   - pkg/validation — namespace match, rotation policy
+//   - pkg/validation — namespace match, rotation policy, format/algorithm
 //   - pkg/crypto     — algorithm/key spec validation
-func validateKeyProfile(kp *openukrv1alpha1.KeyProfile) (admission.Warnings, error) {
+//   - pkg/output     — SPIFFE ID format
+//
+// policy controls checks that are configurable per cluster.
+func validateKeyProfile(kp *openukrv1alpha1.KeyProfile, policy ValidationPolicy) (admission.Warnings, error) {
 	var allWarnings admission.Warnings
 
 	// [SEC:S-1] Namespace match — prevents cross-namespace key requests
@@ -114,12 +319,46 @@ func validateKeyProfile(kp *openukrv1alpha1.KeyProfile) (admission.Warnings, err
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
 
-	// [COMP:G-4] Rotation policy — interval/gracePeriod constraints
-	if err := validation.ValidateRotationPolicy(
-		kp.Spec.Rotation.Interval.Duration,
-		kp.Spec.Rotation.GracePeriod.Duration,
-	); err != nil {
-		return nil, fmt.Errorf("validation failed: %w", err)
+	// [COMP:G-4] Rotation policy — interval/gracePeriod constraints. A profile
+	// taking its interval from a schedule is checked against it by validateSchedule.
+	if kp.Spec.Rotation.Interval.Duration == 0 && kp.Spec.Rotation.ScheduleRef != nil {
+		if err := validation.ValidateGracePeriod(kp.Spec.Rotation.GracePeriod.Duration); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		if kp.Spec.Rotation.RotateBeforeExpiry.Duration < 0 {
+			return nil, fmt.Errorf("validation failed: rotateBeforeExpiry %s must not be negative",
+				kp.Spec.Rotation.RotateBeforeExpiry.Duration)
+		}
+	} else {
+		if err := validation.ValidateRotationPolicy(
+			kp.Spec.Rotation.Interval.Duration,
+			kp.Spec.Rotation.GracePeriod.Duration,
+		); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		if err := validation.ValidateRotateBeforeExpiry(
+			kp.Spec.Rotation.Interval.Duration,
+			kp.Spec.Rotation.RotateBeforeExpiry.Duration,
+		); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+		if msg := validation.CheckMinInterval(kp.Spec.Rotation.Interval.Duration, policy.MinInterval); msg != "" {
+			if policy.RejectShortInterval {
+				return nil, fmt.Errorf("validation failed: %s", msg)
+			}
+			allWarnings = append(allWarnings, msg)
+		}
+	}
+	if kp.Spec.Rotation.PublishNextKey && kp.Spec.Rotation.RotateBeforeExpiry.Duration == 0 {
+		allWarnings = append(allWarnings,
+			"rotation.publishNextKey has no effect without rotation.rotateBeforeExpiry")
+	}
+
+	// Pause window — a pauseUntil far in the past is likely a typo
+	if pause := kp.Spec.Rotation.PauseUntil; pause != nil {
+		if w := validation.CheckPauseUntil(pause.Time, time.Now()); w != "" {
+			allWarnings = append(allWarnings, w)
+		}
 	}
 
 	// [COMP:G-1] Key spec — algorithm/parameters, BSI TR-02102-1 compliance
@@ -133,9 +372,160 @@ func validateKeyProfile(kp *openukrv1alpha1.KeyProfile) (admission.Warnings, err
 	}
 	allWarnings = append(allWarnings, warnings...)
 
-	// [SEC:T-2] TLS configuration warnings for HTTP publishers
+	// Private key PEM type must match the algorithm (pkcs1 → RSA, sec1 → EC)
+	if err := pkgcrypto.ValidatePrivateKeyPEMType(
+		kp.Spec.KeySpec.Algorithm,
+		kp.Spec.KeySpec.PrivateKeyPEMType,
+	); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// Output format must be able to hold the key (keystores need a certificate path)
+	if err := validation.ValidateFormatAlgorithm(
+		kp.Spec.Output.Format,
+		kp.Spec.KeySpec.Algorithm,
+		kp.Spec.KeySpec.Params,
+	); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// Keys on curves without constant-time signing must not sign
+	if err := validation.ValidateKeyUse(
+		kp.Spec.KeySpec.Use,
+		kp.Spec.KeySpec.Algorithm,
+		kp.Spec.KeySpec.Params,
+	); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// Immutable Secrets cannot receive the certificate issued after rotation
+	if kp.Spec.Output.Immutable && kp.Spec.Certificate != nil {
+		return nil, fmt.Errorf("validation failed: output.immutable cannot be combined with certificate")
+	}
+	// Immutable outputs already keep one Secret per key
+	if kp.Spec.Output.Immutable && kp.Spec.Output.KeepHistorySecrets > 0 {
+		return nil, fmt.Errorf("validation failed: output.immutable cannot be combined with output.keepHistorySecrets")
+	}
+
+	// Secret data key overrides must be known entries and valid keys
+	if err := output.ValidateKeyNames(kp.Spec.Output.KeyNames); err != nil {
+		return nil, fmt.Errorf("validation failed: output.%w", err)
+	}
+
+	// Encryption recipients must be valid age public keys
+	if err := validateEncryption(kp.Spec.Output); err != nil {
+		return nil, fmt.Errorf("validation failed: output.%w", err)
+	}
+
+	// Additional outputs — one Secret each, rendered like Output
+	if err := validateAdditionalOutputs(kp); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// Key ID template — known placeholders, path-safe, unique per rotation
+	if err := pkgcrypto.ValidateKeyIDTemplate(kp.Spec.KeySpec.KeyIDTemplate); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// SPIFFE URI SAN — trust domain and ServiceAccount must form a valid SPIFFE ID
+	if td := kp.Spec.Output.SPIFFETrustDomain; td != "" {
+		if _, err := output.NewSPIFFEID(
+			td,
+			kp.Spec.ServiceAccountRef.Namespace,
+			kp.Spec.ServiceAccountRef.Name,
+		); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
+	// Publish encodings must fit the key, e.g. ec-compressed is EC only
+	for i, pub := range kp.Spec.Publish {
+		for j, out := range pub.Outputs {
+			if err := validation.ValidatePublishEncoding(out.Encoding, kp.Spec.KeySpec.Algorithm); err != nil {
+				return nil, fmt.Errorf("validation failed: publish[%d].outputs[%d]: %w", i, j, err)
+			}
+		}
+	}
+
+	// [SEC:S-3] Filesystem publish paths must stay out of system directories
+	denied := policy.DeniedPublishPaths
+	if denied == nil {
+		denied = validation.DefaultDeniedPublishPaths
+	}
+	for i, pub := range kp.Spec.Publish {
+		if pub.Type != "filesystem" {
+			continue
+		}
+		for _, path := range publishConfigValues(pub, "path") {
+			if err := validation.ValidatePublishPath(path, denied); err != nil {
+				return nil, fmt.Errorf("validation failed: publish[%d]: %w", i, err)
+			}
+		}
+		for _, name := range publishConfigValues(pub, "filename") {
+			if err := validation.ValidatePublishFilename(name); err != nil {
+				return nil, fmt.Errorf("validation failed: publish[%d]: %w", i, err)
+			}
+		}
+		for _, maxBytes := range publishConfigValues(pub, "maxBytes") {
+			if _, err := validation.ParsePublishMaxBytes(maxBytes); err != nil {
+				return nil, fmt.Errorf("validation failed: publish[%d]: %w", i, err)
+			}
+		}
+		for _, prune := range publishConfigValues(pub, "pruneOldest") {
+			if _, err := strconv.ParseBool(prune); err != nil {
+				return nil, fmt.Errorf("validation failed: publish[%d]: invalid pruneOldest %q", i, prune)
+			}
+		}
+	}
+
+	// [SEC:S-3] HTTP endpoints and Key Vaults must not point at denied hosts
+	// such as cloud metadata
+	hosts := policy.PublishHosts
+	if hosts == nil {
+		hosts = validation.DefaultHostPolicy()
+	}
+	hostKeys := map[string]string{"http": "endpoint", "azurekeyvault": "vaultURL"}
+	for i, pub := range kp.Spec.Publish {
+		key, ok := hostKeys[pub.Type]
+		if !ok {
+			continue
+		}
+		for _, endpoint := range publishConfigValues(pub, key) {
+			u, err := url.Parse(endpoint)
+			if err != nil {
+				return nil, fmt.Errorf("validation failed: publish[%d]: invalid endpoint %q: %w", i, endpoint, err)
+			}
+			if err := hosts.CheckHost(u.Hostname()); err != nil {
+				return nil, fmt.Errorf("validation failed: publish[%d]: %w", i, err)
+			}
+		}
+	}
+
+	// [SEC:S-3] Key Vault tokens must only ever be sent to Key Vault
+	for i, pub := range kp.Spec.Publish {
+		if pub.Type != "azurekeyvault" {
+			continue
+		}
+		for _, vaultURL := range publishConfigValues(pub, "vaultURL") {
+			if _, _, err := validation.ValidateKeyVaultURL(vaultURL); err != nil {
+				return nil, fmt.Errorf("validation failed: publish[%d]: %w", i, err)
+			}
+		}
+	}
+	if err := validateKeyVaultSecretNames(kp.Spec.Publish); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+	if err := validatePublishFilenames(kp.Spec.Publish); err != nil {
+		return nil, fmt.Errorf("validation failed: %w", err)
+	}
+
+	// [SEC:T-2] TLS configuration warnings for HTTP publishers, errors under strict policy
 	for i, pub := range kp.Spec.Publish {
 		if pub.Type == "http" && pub.TLS != nil && pub.TLS.InsecureSkipVerify {
+			if policy.RejectInsecurePublish {
+				return nil, fmt.Errorf(
+					"validation failed: publish[%d]: insecureSkipVerify=true is forbidden by cluster policy", i)
+			}
 			allWarnings = append(allWarnings, fmt.Sprintf(
 				"publish[%d]: insecureSkipVerify=true disables TLS verification — not recommended for production", i))
 		}
@@ -143,3 +533,94 @@ func validateKeyProfile(kp *openukrv1alpha1.KeyProfile) (admission.Warnings, err
 
 	return allWarnings, nil
 }
+
+// validateKeyVaultSecretNames rejects Key Vault secrets written by more than
+// one output, which would overwrite each other on every publish. Names are
+// case-insensitive in Key Vault.
+func validateKeyVaultSecretNames(targets []openukrv1alpha1.PublishTarget) error {
+	seen := map[string]string{}
+	for i, pub := range targets {
+		if pub.Type != "azurekeyvault" {
+			continue
+		}
+		outputs := pub.Outputs
+		if len(outputs) == 0 {
+			outputs = []openukrv1alpha1.PublishOutput{{}}
+		}
+		for j, out := range outputs {
+			vaultURL, ok := out.Config["vaultURL"]
+			if !ok {
+				vaultURL = pub.Config["vaultURL"]
+			}
+			name, ok := out.Config["secretName"]
+			if !ok {
+				name = pub.Config["secretName"]
+			}
+			key := strings.ToLower(strings.TrimSuffix(vaultURL, "/") + "/" + name)
+			where := fmt.Sprintf("publish[%d].outputs[%d]", i, j)
+			if prev, ok := seen[key]; ok {
+				return fmt.Errorf("%s: Key Vault secret %q is already written by %s", where, name, prev)
+			}
+			seen[key] = where
+		}
+	}
+	return nil
+}
+
+// validatePublishFilenames rejects a filesystem "filename" override shared by
+// more than one output. It names a single file, so outputs of different
+// encodings would overwrite each other; set it per output instead of on the target.
+func validatePublishFilenames(targets []openukrv1alpha1.PublishTarget) error {
+	seen := map[string]string{}
+	for i, pub := range targets {
+		if pub.Type != "filesystem" {
+			continue
+		}
+		outputs := pub.Outputs
+		if len(outputs) == 0 {
+			outputs = []openukrv1alpha1.PublishOutput{{}}
+		}
+		for j, out := range outputs {
+			name, ok := out.Config["filename"]
+			if !ok {
+				name = pub.Config["filename"]
+			}
+			if name == "" {
+				continue
+			}
+			path, ok := out.Config["path"]
+			if !ok {
+				path = pub.Config["path"]
+			}
+			key := filepath.Join(path, name)
+			where := fmt.Sprintf("publish[%d].outputs[%d]", i, j)
+			if prev, ok := seen[key]; ok {
+				return fmt.Errorf("%s: publish file %q is already written by %s", where, key, prev)
+			}
+			seen[key] = where
+		}
+	}
+	return nil
+}
+
+// publishConfigValues returns the non-empty values of a config key (e.g. "path")
+// a target publishes with. Each output's Config is merged over the target Config.
+func publishConfigValues(target openukrv1alpha1.PublishTarget, key string) []string {
+	if len(target.Outputs) == 0 {
+		if v := target.Config[key]; v != "" {
+			return []string{v}
+		}
+		return nil
+	}
+	var values []string
+	for _, out := range target.Outputs {
+		v, ok := out.Config[key]
+		if !ok {
+			v = target.Config[key]
+		}
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
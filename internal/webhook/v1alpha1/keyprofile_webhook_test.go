@@ -0,0 +1,600 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/validation"
+)
+
+func newInsecurePublishProfile() *openukrv1alpha1.KeyProfile {
+	return &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			ServiceAccountRef: openukrv1alpha1.ServiceAccountReference{Name: "app", Namespace: "default"},
+			KeySpec: openukrv1alpha1.KeySpec{
+				Algorithm: "EC",
+				Params:    map[string]string{"curve": "P-256"},
+			},
+			Rotation: openukrv1alpha1.RotationPolicy{
+				Interval:    metav1.Duration{Duration: 24 * time.Hour},
+				GracePeriod: metav1.Duration{Duration: 1 * time.Hour},
+			},
+			Output: openukrv1alpha1.OutputConfig{SecretName: "keys"},
+			Publish: []openukrv1alpha1.PublishTarget{{
+				Type:   "http",
+				Config: map[string]string{"endpoint": "https://keys.example.com"},
+				TLS: &openukrv1alpha1.TLSConfig{
+					CACertSecretRef:    "ca",
+					InsecureSkipVerify: true,
+				},
+			}},
+		},
+	}
+}
+
+var _ = Describe("KeyProfile validation policy", func() {
+	It("warns about insecureSkipVerify under the default policy", func() {
+		validator := &KeyProfileCustomValidator{}
+		warnings, err := validator.ValidateCreate(ctx, newInsecurePublishProfile())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ContainElement(ContainSubstring("insecureSkipVerify=true")))
+	})
+
+	It("rejects insecureSkipVerify when insecure publishing is disallowed", func() {
+		validator := &KeyProfileCustomValidator{Policy: ValidationPolicy{RejectInsecurePublish: true}}
+		_, err := validator.ValidateCreate(ctx, newInsecurePublishProfile())
+		Expect(err).To(MatchError(ContainSubstring("forbidden by cluster policy")))
+
+		_, err = validator.ValidateUpdate(ctx, newInsecurePublishProfile(), newInsecurePublishProfile())
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("accepts verified TLS under the strict policy", func() {
+		validator := &KeyProfileCustomValidator{Policy: ValidationPolicy{RejectInsecurePublish: true}}
+		profile := newInsecurePublishProfile()
+		profile.Spec.Publish[0].TLS.InsecureSkipVerify = false
+		warnings, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+})
+
+var _ = Describe("KeyProfile HTTP publish host", func() {
+	It("rejects the cloud metadata endpoint under the default policy", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newInsecurePublishProfile()
+		profile.Spec.Publish[0].Config["endpoint"] = "http://169.254.169.254/latest/meta-data"
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("denied by cluster policy")))
+	})
+
+	It("rejects a denied endpoint set on an output", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newInsecurePublishProfile()
+		profile.Spec.Publish[0].Outputs = []openukrv1alpha1.PublishOutput{
+			{Encoding: "JWK", Config: map[string]string{"endpoint": "https://[fd00:ec2::254]/keys"}},
+		}
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("accepts an external endpoint in the allowlist", func() {
+		hosts, err := validation.NewHostPolicy([]string{"*.example.com"}, validation.DefaultDeniedPublishHosts)
+		Expect(err).NotTo(HaveOccurred())
+		validator := &KeyProfileCustomValidator{Policy: ValidationPolicy{PublishHosts: hosts}}
+		_, err = validator.ValidateCreate(ctx, newInsecurePublishProfile())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("rejects an endpoint outside the allowlist", func() {
+		hosts, err := validation.NewHostPolicy([]string{"keys.internal.corp"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		validator := &KeyProfileCustomValidator{Policy: ValidationPolicy{PublishHosts: hosts}}
+		_, err = validator.ValidateCreate(ctx, newInsecurePublishProfile())
+		Expect(err).To(MatchError(ContainSubstring("not in the allowed hosts")))
+	})
+})
+
+var _ = Describe("KeyProfile Azure Key Vault publish", func() {
+	newKeyVaultProfile := func(vaultURL string) *openukrv1alpha1.KeyProfile {
+		profile := newInsecurePublishProfile()
+		profile.Spec.Publish = []openukrv1alpha1.PublishTarget{{
+			Type:   "azurekeyvault",
+			Config: map[string]string{"vaultURL": vaultURL, "secretName": "signing-key"},
+		}}
+		return profile
+	}
+
+	It("accepts a sovereign cloud vault", func() {
+		validator := &KeyProfileCustomValidator{}
+		_, err := validator.ValidateCreate(ctx, newKeyVaultProfile("https://my-vault.vault.usgovcloudapi.net"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("rejects a vault outside the publish host allowlist", func() {
+		hosts, err := validation.NewHostPolicy([]string{"*.vault.azure.net"}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		validator := &KeyProfileCustomValidator{Policy: ValidationPolicy{PublishHosts: hosts}}
+		_, err = validator.ValidateCreate(ctx, newKeyVaultProfile("https://my-vault.vault.azure.net"))
+		Expect(err).NotTo(HaveOccurred())
+		_, err = validator.ValidateCreate(ctx, newKeyVaultProfile("https://my-vault.vault.azure.cn"))
+		Expect(err).To(MatchError(ContainSubstring("not in the allowed hosts")))
+	})
+
+	It("rejects a vault URL outside the Key Vault DNS suffixes", func() {
+		validator := &KeyProfileCustomValidator{}
+		_, err := validator.ValidateCreate(ctx, newKeyVaultProfile("https://169.254.169.254"))
+		Expect(err).To(MatchError(ContainSubstring("host is not a vault")))
+	})
+
+	It("rejects two outputs writing the same Key Vault secret", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newKeyVaultProfile("https://my-vault.vault.azure.net")
+		profile.Spec.Publish[0].Outputs = []openukrv1alpha1.PublishOutput{
+			{Encoding: "PEM"},
+			{Encoding: "JWK"},
+		}
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("is already written by publish[0].outputs[0]")))
+	})
+
+	It("rejects a filename override shared by two outputs", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newInsecurePublishProfile()
+		profile.Spec.Publish = []openukrv1alpha1.PublishTarget{{
+			Type:   "filesystem",
+			Config: map[string]string{"path": "/srv/keys", "filename": "jwks.json"},
+			Outputs: []openukrv1alpha1.PublishOutput{
+				{Encoding: "PEM"},
+				{Encoding: "JWK"},
+			},
+		}}
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("is already written by publish[0].outputs[0]")))
+
+		profile.Spec.Publish[0].Outputs[0].Config = map[string]string{"filename": "key.pem"}
+		_, err = validator.ValidateCreate(ctx, profile)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("KeyProfile minimum interval", func() {
+	newShortIntervalProfile := func() *openukrv1alpha1.KeyProfile {
+		profile := newInsecurePublishProfile()
+		profile.Spec.Publish = nil
+		profile.Spec.Rotation.Interval = metav1.Duration{Duration: 15 * time.Minute}
+		profile.Spec.Rotation.GracePeriod = metav1.Duration{Duration: 5 * time.Minute}
+		return profile
+	}
+
+	It("warns about an interval below the floor under the warn policy", func() {
+		validator := &KeyProfileCustomValidator{Policy: ValidationPolicy{MinInterval: time.Hour}}
+		warnings, err := validator.ValidateCreate(ctx, newShortIntervalProfile())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ContainElement(ContainSubstring("below the cluster minimum")))
+	})
+
+	It("rejects an interval below the floor under the deny policy", func() {
+		validator := &KeyProfileCustomValidator{Policy: ValidationPolicy{MinInterval: time.Hour, RejectShortInterval: true}}
+		_, err := validator.ValidateCreate(ctx, newShortIntervalProfile())
+		Expect(err).To(MatchError(ContainSubstring("below the cluster minimum")))
+	})
+
+	It("accepts an interval at the floor under the deny policy", func() {
+		validator := &KeyProfileCustomValidator{Policy: ValidationPolicy{MinInterval: time.Hour, RejectShortInterval: true}}
+		profile := newShortIntervalProfile()
+		profile.Spec.Rotation.Interval = metav1.Duration{Duration: time.Hour}
+		warnings, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+})
+
+var _ = Describe("KeyProfile rotation schedule reference", func() {
+	newScheduledProfile := func() *openukrv1alpha1.KeyProfile {
+		profile := newInsecurePublishProfile()
+		profile.Spec.Publish = nil
+		profile.Spec.Rotation.Interval = metav1.Duration{}
+		profile.Spec.Rotation.ScheduleRef = &openukrv1alpha1.ScheduleReference{Name: "quarterly"}
+		return profile
+	}
+
+	It("accepts a scheduleRef without an inline interval", func() {
+		warnings, err := (&KeyProfileCustomValidator{}).ValidateCreate(ctx, newScheduledProfile())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("still enforces the minimum grace period", func() {
+		profile := newScheduledProfile()
+		profile.Spec.Rotation.GracePeriod = metav1.Duration{Duration: time.Minute}
+		_, err := (&KeyProfileCustomValidator{}).ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("gracePeriod")))
+	})
+
+	It("rejects a profile with neither interval nor scheduleRef", func() {
+		profile := newScheduledProfile()
+		profile.Spec.Rotation.ScheduleRef = nil
+		_, err := (&KeyProfileCustomValidator{}).ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("interval")))
+	})
+
+	It("checks the referenced schedule against the cluster minimum", func() {
+		schedule := &openukrv1alpha1.RotationSchedule{
+			ObjectMeta: metav1.ObjectMeta{Name: "quarterly", Namespace: "default"},
+			Spec:       openukrv1alpha1.RotationScheduleSpec{Interval: metav1.Duration{Duration: 30 * time.Minute}},
+		}
+		reader := fake.NewClientBuilder().WithObjects(schedule).Build()
+		profile := newScheduledProfile()
+		profile.Spec.Rotation.GracePeriod = metav1.Duration{Duration: 5 * time.Minute}
+		validator := &KeyProfileCustomValidator{Policy: ValidationPolicy{MinInterval: time.Hour}, Reader: reader}
+		warnings, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ContainElement(ContainSubstring("below the cluster minimum")))
+
+		validator.Policy.RejectShortInterval = true
+		_, err = validator.ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("below the cluster minimum")))
+	})
+
+	It("warns about a missing schedule", func() {
+		profile := newScheduledProfile()
+		profile.Spec.Rotation.ScheduleRef.Kind = openukrv1alpha1.ClusterRotationScheduleKind
+		validator := &KeyProfileCustomValidator{Reader: fake.NewClientBuilder().Build()}
+		warnings, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ContainElement(ContainSubstring(`ClusterRotationSchedule "quarterly" not found`)))
+	})
+
+	It("does not default the interval of a profile with a scheduleRef", func() {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: NamespaceDefaultsConfigMap, Namespace: "default"},
+			Data:       map[string]string{"rotation.interval": "48h"},
+		}
+		defaulter := &KeyProfileCustomDefaulter{Reader: fake.NewClientBuilder().WithObjects(cm).Build()}
+		profile := newScheduledProfile()
+		Expect(defaulter.Default(ctx, profile)).To(Succeed())
+		Expect(profile.Spec.Rotation.Interval.Duration).To(BeZero())
+	})
+})
+
+var _ = Describe("KeyProfile history secrets", func() {
+	It("rejects keepHistorySecrets on an immutable output", func() {
+		profile := newInsecurePublishProfile()
+		profile.Spec.Publish = nil
+		profile.Spec.Output.KeepHistorySecrets = 3
+		_, err := (&KeyProfileCustomValidator{}).ValidateCreate(ctx, profile)
+		Expect(err).NotTo(HaveOccurred())
+
+		profile.Spec.Output.Immutable = true
+		_, err = (&KeyProfileCustomValidator{}).ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("keepHistorySecrets")))
+	})
+})
+
+var _ = Describe("KeyProfile pauseUntil", func() {
+	It("warns when pauseUntil is far in the past", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newInsecurePublishProfile()
+		profile.Spec.Publish = nil
+		profile.Spec.Rotation.PauseUntil = &metav1.Time{Time: time.Now().AddDate(-1, 0, 0)}
+		warnings, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ContainElement(ContainSubstring("pauseUntil")))
+	})
+
+	It("accepts a future pauseUntil without warnings", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newInsecurePublishProfile()
+		profile.Spec.Publish = nil
+		profile.Spec.Rotation.PauseUntil = &metav1.Time{Time: time.Now().Add(time.Hour)}
+		warnings, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+})
+
+var _ = Describe("KeyProfile secretName change", func() {
+	It("warns when spec.output.secretName changes", func() {
+		validator := &KeyProfileCustomValidator{}
+		oldProfile := newInsecurePublishProfile()
+		oldProfile.Spec.Publish = nil
+		newProfile := oldProfile.DeepCopy()
+		newProfile.Spec.Output.SecretName = "keys-v2"
+		warnings, err := validator.ValidateUpdate(ctx, oldProfile, newProfile)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ContainElement(ContainSubstring(`from "keys" to "keys-v2"`)))
+	})
+
+	It("does not warn when secretName is unchanged", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newInsecurePublishProfile()
+		profile.Spec.Publish = nil
+		warnings, err := validator.ValidateUpdate(ctx, profile, profile.DeepCopy())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+})
+
+var _ = Describe("KeyProfile filesystem publish path", func() {
+	newFilesystemProfile := func(path string) *openukrv1alpha1.KeyProfile {
+		profile := newInsecurePublishProfile()
+		profile.Spec.Publish = []openukrv1alpha1.PublishTarget{{
+			Type:   "filesystem",
+			Config: map[string]string{"path": path},
+		}}
+		return profile
+	}
+
+	It("rejects a path under a default denied directory", func() {
+		validator := &KeyProfileCustomValidator{}
+		_, err := validator.ValidateCreate(ctx, newFilesystemProfile("/etc/keys"))
+		Expect(err).To(MatchError(ContainSubstring("denied directory /etc")))
+	})
+
+	It("rejects a denied path set on an output", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newFilesystemProfile("/var/lib/keys")
+		profile.Spec.Publish[0].Outputs = []openukrv1alpha1.PublishOutput{
+			{Encoding: "PEM"},
+			{Encoding: "JWK", Config: map[string]string{"path": "/sys/keys"}},
+		}
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("denied directory /sys")))
+	})
+
+	It("accepts an allowed path", func() {
+		validator := &KeyProfileCustomValidator{}
+		_, err := validator.ValidateCreate(ctx, newFilesystemProfile("/var/lib/keys"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("honors an overridden denylist", func() {
+		validator := &KeyProfileCustomValidator{Policy: ValidationPolicy{DeniedPublishPaths: []string{}}}
+		_, err := validator.ValidateCreate(ctx, newFilesystemProfile("/etc/keys"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+	It("rejects a filename escaping the path", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newFilesystemProfile("/var/lib/keys")
+		profile.Spec.Publish[0].Config["filename"] = "../../etc/jwks.json"
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("must not contain '..'")))
+	})
+
+	It("accepts a filename in a subdirectory", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newFilesystemProfile("/var/lib/keys")
+		profile.Spec.Publish[0].Config["filename"] = ".well-known/jwks.json"
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("validates the quota settings", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newFilesystemProfile("/run/credentials/app")
+		profile.Spec.Publish[0].Config["maxBytes"] = "65536"
+		profile.Spec.Publish[0].Config["pruneOldest"] = "true"
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).NotTo(HaveOccurred())
+
+		profile.Spec.Publish[0].Config["maxBytes"] = "64Ki"
+		_, err = validator.ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("maxBytes must be a positive integer")))
+
+		profile.Spec.Publish[0].Config["maxBytes"] = "65536"
+		profile.Spec.Publish[0].Config["pruneOldest"] = "sometimes"
+		_, err = validator.ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("invalid pruneOldest")))
+	})
+})
+
+var _ = Describe("KeyProfile output key names", func() {
+	It("rejects an invalid Secret data key", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newInsecurePublishProfile()
+		profile.Spec.Output.KeyNames = map[string]string{"jwks": "keys/jwks.json"}
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("not a valid Secret data key")))
+	})
+
+	It("accepts a custom jwks key name", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newInsecurePublishProfile()
+		profile.Spec.Output.KeyNames = map[string]string{"jwks": "keys.json"}
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("KeyProfile additional outputs", func() {
+	It("accepts a second Secret in another format", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newInsecurePublishProfile()
+		profile.Spec.AdditionalOutputs = []openukrv1alpha1.OutputConfig{
+			{SecretName: "keys-app", Format: "single-pem"},
+		}
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("rejects a duplicate secretName", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newInsecurePublishProfile()
+		profile.Spec.AdditionalOutputs = []openukrv1alpha1.OutputConfig{{SecretName: "keys"}}
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring(`secretName "keys" is already used`)))
+	})
+
+	It("rejects immutable additional outputs", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newInsecurePublishProfile()
+		profile.Spec.AdditionalOutputs = []openukrv1alpha1.OutputConfig{{SecretName: "keys-app", Immutable: true}}
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("only supported on output")))
+	})
+
+	It("rejects an invalid age recipient", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newInsecurePublishProfile()
+		profile.Spec.AdditionalOutputs = []openukrv1alpha1.OutputConfig{{
+			SecretName: "keys-app",
+			Encryption: &openukrv1alpha1.OutputEncryption{
+				Age: &openukrv1alpha1.AgeEncryption{Recipients: []string{"age1bogus"}},
+			},
+		}}
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("additionalOutputs[0].encryption.age.recipients[0]")))
+	})
+
+	It("rejects an Azure Key Vault key outside Key Vault", func() {
+		validator := &KeyProfileCustomValidator{}
+		profile := newInsecurePublishProfile()
+		profile.Spec.Output.Encryption = &openukrv1alpha1.OutputEncryption{
+			AzureKeyVault: &openukrv1alpha1.AzureKeyVaultEncryption{KeyURL: "https://keys.example.com/keys/kek"},
+		}
+		_, err := validator.ValidateCreate(ctx, profile)
+		Expect(err).To(MatchError(ContainSubstring("output.encryption.azureKeyVault.keyURL")))
+	})
+})
+
+var _ = Describe("KeyProfile namespace defaults", func() {
+	newDefaulter := func(data map[string]string) *KeyProfileCustomDefaulter {
+		cm := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: NamespaceDefaultsConfigMap, Namespace: "default"},
+			Data:       data,
+		}
+		return &KeyProfileCustomDefaulter{Reader: fake.NewClientBuilder().WithObjects(cm).Build()}
+	}
+
+	It("supplies an omitted curve from the namespace default", func() {
+		defaulter := newDefaulter(map[string]string{
+			"algorithm":         "EC",
+			"params.curve":      "P-384",
+			"rotation.interval": "48h",
+		})
+		profile := newInsecurePublishProfile()
+		profile.Spec.KeySpec = openukrv1alpha1.KeySpec{}
+		profile.Spec.Rotation.Interval = metav1.Duration{}
+
+		Expect(defaulter.Default(ctx, profile)).To(Succeed())
+		Expect(profile.Spec.KeySpec.Algorithm).To(Equal("EC"))
+		Expect(profile.Spec.KeySpec.Params).To(HaveKeyWithValue("curve", "P-384"))
+		Expect(profile.Spec.Rotation.Interval.Duration).To(Equal(48 * time.Hour))
+		Expect(profile.Spec.KeySpec.Encoding).To(Equal("PEM"), "built-in defaults still apply")
+
+		_, err := (&KeyProfileCustomValidator{}).ValidateCreate(ctx, profile)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("keeps inline values over namespace defaults", func() {
+		defaulter := newDefaulter(map[string]string{
+			"algorithm":            "EC",
+			"params.curve":         "P-384",
+			"rotation.gracePeriod": "2h",
+		})
+		profile := newInsecurePublishProfile()
+
+		Expect(defaulter.Default(ctx, profile)).To(Succeed())
+		Expect(profile.Spec.KeySpec.Params).To(HaveKeyWithValue("curve", "P-256"))
+		Expect(profile.Spec.Rotation.GracePeriod.Duration).To(Equal(time.Hour))
+	})
+
+	It("does not apply params defaulted for another algorithm", func() {
+		defaulter := newDefaulter(map[string]string{"algorithm": "EC", "params.curve": "P-384"})
+		profile := newInsecurePublishProfile()
+		profile.Spec.KeySpec = openukrv1alpha1.KeySpec{Algorithm: "RSA", Params: map[string]string{"size": "3072"}}
+
+		Expect(defaulter.Default(ctx, profile)).To(Succeed())
+		Expect(profile.Spec.KeySpec.Params).NotTo(HaveKey("curve"))
+	})
+
+	It("ignores a ConfigMap with unknown keys or malformed durations", func() {
+		profile := newInsecurePublishProfile()
+		profile.Spec.Rotation.GracePeriod = metav1.Duration{}
+		Expect(newDefaulter(map[string]string{"curve": "P-384", "rotation.gracePeriod": "2h"}).
+			Default(ctx, profile)).To(Succeed())
+		Expect(profile.Spec.Rotation.GracePeriod.Duration).To(BeZero(), "no key of an invalid ConfigMap applies")
+
+		profile = newInsecurePublishProfile()
+		profile.Spec.Rotation.Interval = metav1.Duration{}
+		Expect(newDefaulter(map[string]string{"rotation.interval": "2 days"}).Default(ctx, profile)).To(Succeed())
+		Expect(profile.Spec.Rotation.Interval.Duration).To(BeZero())
+	})
+
+	It("applies namespace defaults on create only", func() {
+		defaulter := newDefaulter(map[string]string{"rotation.gracePeriod": "2h"})
+		profile := newInsecurePublishProfile()
+		profile.Spec.Rotation.GracePeriod = metav1.Duration{}
+
+		updateCtx := admission.NewContextWithRequest(ctx, admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Update},
+		})
+		Expect(defaulter.Default(updateCtx, profile)).To(Succeed())
+		Expect(profile.Spec.Rotation.GracePeriod.Duration).To(BeZero())
+
+		createCtx := admission.NewContextWithRequest(ctx, admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create},
+		})
+		Expect(defaulter.Default(createCtx, profile)).To(Succeed())
+		Expect(profile.Spec.Rotation.GracePeriod.Duration).To(Equal(2 * time.Hour))
+	})
+
+	It("applies built-in defaults without a ConfigMap", func() {
+		defaulter := &KeyProfileCustomDefaulter{Reader: fake.NewClientBuilder().Build()}
+		profile := newInsecurePublishProfile()
+		Expect(defaulter.Default(ctx, profile)).To(Succeed())
+		Expect(profile.Spec.KeySpec.Params).To(HaveKeyWithValue("curve", "P-256"))
+	})
+})
+
+var _ = Describe("KeyProfile key spec normalization", func() {
+	It("accepts lowercase and mixed-case algorithm and curve names", func() {
+		for _, tc := range [][2]string{{"ec", "p-256"}, {"Ec", "P-384"}, {"EC", "p-521"}} {
+			profile := newInsecurePublishProfile()
+			profile.Spec.KeySpec.Algorithm = tc[0]
+			profile.Spec.KeySpec.Params = map[string]string{"curve": tc[1]}
+
+			Expect((&KeyProfileCustomDefaulter{}).Default(ctx, profile)).To(Succeed())
+			Expect(profile.Spec.KeySpec.Algorithm).To(Equal("EC"))
+			Expect(profile.Spec.KeySpec.Params["curve"]).To(Equal(strings.ToUpper(tc[1])))
+
+			_, err := (&KeyProfileCustomValidator{}).ValidateCreate(ctx, profile)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+
+	It("stores lowercase rsa in canonical form", func() {
+		profile := newInsecurePublishProfile()
+		profile.Spec.KeySpec = openukrv1alpha1.KeySpec{Algorithm: "rsa", Params: map[string]string{"keySize": "3072"}}
+		Expect((&KeyProfileCustomDefaulter{}).Default(ctx, profile)).To(Succeed())
+		Expect(profile.Spec.KeySpec.Algorithm).To(Equal("RSA"))
+	})
+})
@@ -0,0 +1,155 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+)
+
+// NamespaceDefaultsConfigMap is the ConfigMap holding per-namespace defaults for
+// KeyProfiles created in its namespace. Supported keys:
+//
+//	algorithm, params.<name>, encoding, privateKeyPEMType, keyIDTemplate, use,
+//	rotation.interval, rotation.gracePeriod, rotation.rotateBeforeExpiry
+//
+// Defaults only apply when a KeyProfile is created, so editing the ConfigMap
+// never changes existing profiles. A ConfigMap with unknown keys or malformed
+// values is logged and ignored as a whole.
+//
+// Precedence: fields set inline > namespace defaults > built-in defaults.
+// rotation.interval is not defaulted for profiles with a rotation.scheduleRef,
+// since an inline interval would override the referenced schedule.
+// When the ConfigMap sets an algorithm, params are only defaulted for profiles
+// using that algorithm.
+const NamespaceDefaultsConfigMap = "openukr-defaults"
+
+const paramsKeyPrefix = "params."
+
+// applyNamespaceDefaults fills omitted KeySpec and Rotation fields from the
+// namespace's defaults ConfigMap. A missing ConfigMap is not an error. Unknown
+// keys and malformed durations are logged, so typos do not go unnoticed, and
+// leave the profile unchanged.
+func applyNamespaceDefaults(ctx context.Context, reader client.Reader, kp *openukrv1alpha1.KeyProfile) error {
+	cm := &corev1.ConfigMap{}
+	key := client.ObjectKey{Namespace: kp.Namespace, Name: NamespaceDefaultsConfigMap}
+	if err := reader.Get(ctx, key, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read namespace defaults %s: %w", key, err)
+	}
+
+	spec := kp.Spec.DeepCopy()
+	if err := defaultSpec(spec, cm.Data); err != nil {
+		keyprofilelog.Info("Ignoring invalid namespace defaults", "reason", "InvalidNamespaceDefaults",
+			"configMap", key, "keyprofile", kp.Name, "error", err.Error())
+		return nil
+	}
+	kp.Spec = *spec
+	return nil
+}
+
+// defaultSpec fills omitted fields of spec from the namespace defaults data.
+func defaultSpec(spec *openukrv1alpha1.KeyProfileSpec, data map[string]string) error {
+	keySpec := &spec.KeySpec
+	defaultAlgorithm := data["algorithm"]
+	if keySpec.Algorithm == "" {
+		keySpec.Algorithm = defaultAlgorithm
+	}
+
+	// Sorted for deterministic error messages
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := data[name]
+		switch name {
+		case "algorithm":
+		case "encoding":
+			setIfEmpty(&keySpec.Encoding, value)
+		case "privateKeyPEMType":
+			setIfEmpty(&keySpec.PrivateKeyPEMType, value)
+		case "keyIDTemplate":
+			setIfEmpty(&keySpec.KeyIDTemplate, value)
+		case "use":
+			setIfEmpty(&keySpec.Use, value)
+		case "rotation.interval":
+			if spec.Rotation.ScheduleRef != nil {
+				continue
+			}
+			if err := durationIfZero(&spec.Rotation.Interval, name, value); err != nil {
+				return err
+			}
+		case "rotation.gracePeriod":
+			if err := durationIfZero(&spec.Rotation.GracePeriod, name, value); err != nil {
+				return err
+			}
+		case "rotation.rotateBeforeExpiry":
+			if err := durationIfZero(&spec.Rotation.RotateBeforeExpiry, name, value); err != nil {
+				return err
+			}
+		default:
+			param, ok := strings.CutPrefix(name, paramsKeyPrefix)
+			if !ok || param == "" {
+				return fmt.Errorf("unknown key %q", name)
+			}
+			// A curve default means nothing to an RSA profile
+			if defaultAlgorithm != "" && keySpec.Algorithm != defaultAlgorithm {
+				continue
+			}
+			if _, set := keySpec.Params[param]; !set {
+				if keySpec.Params == nil {
+					keySpec.Params = map[string]string{}
+				}
+				keySpec.Params[param] = value
+			}
+		}
+	}
+	return nil
+}
+
+func setIfEmpty(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
+
+func durationIfZero(field *metav1.Duration, name, value string) error {
+	if field.Duration != 0 {
+		return nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s %q: %w", name, value, err)
+	}
+	field.Duration = d
+	return nil
+}
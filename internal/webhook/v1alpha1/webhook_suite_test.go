@@ -109,7 +109,7 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	err = SetupKeyProfileWebhookWithManager(mgr)
+	err = SetupKeyProfileWebhookWithManager(mgr, ValidationPolicy{})
 	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:webhook
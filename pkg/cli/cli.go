@@ -0,0 +1,178 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cli provides the operations behind the kubectl openukr plugin:
+// describing a KeyProfile, forcing a rotation and verifying stored keys.
+// It has no command-line framework dependency; callers bring their own
+// client and flag handling.
+package cli
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/output"
+	"github.com/openukr/openukr/pkg/rotation"
+)
+
+// Describe renders a human-readable summary of profile's spec and status,
+// in the style of kubectl describe.
+func Describe(profile *openukrv1alpha1.KeyProfile) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 8, 2, ' ', 0)
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%s:\t%s\n", name, value)
+		}
+	}
+
+	spec, status := &profile.Spec, &profile.Status
+	field("Name", profile.Name)
+	field("Namespace", profile.Namespace)
+	field("Algorithm", describeKeySpec(spec.KeySpec))
+	field("Secret", spec.Output.SecretName)
+	field("Format", spec.Output.Format)
+	field("Interval", describeDuration(spec.Rotation.Interval))
+	field("Grace Period", describeDuration(spec.Rotation.GracePeriod))
+	field("Phase", status.Phase)
+	field("Mode", status.Mode)
+	field("Summary", status.Summary)
+	field("Current Key", describeKey(status.CurrentKeyID, status.CurrentKeyFingerprint))
+	field("Current Key Thumbprint", status.CurrentKeyThumbprint)
+	field("Previous Key", describeKey(status.PreviousKeyID, status.PreviousKeyFingerprint))
+	field("Next Key", describeKey(status.NextKeyID, status.NextKeyFingerprint))
+	field("Last Rotation", describeTime(status.LastRotation))
+	field("Last Rotation Reason", status.LastRotationReason)
+	field("Next Rotation", describeTime(status.NextRotation))
+	field("Last Verified", describeTime(status.LastVerifyTime))
+	if rotation.ForceRotationRequested(profile) {
+		field("Forced Rotation", "requested")
+	}
+
+	if len(status.PublishStatus) > 0 {
+		fmt.Fprintln(w, "Publish Targets:")
+		for _, t := range status.PublishStatus {
+			state := "published " + t.LastPublishedKeyID
+			if t.Error != "" {
+				state = "failed: " + t.Error
+			}
+			fmt.Fprintf(w, "  %s\t%s\t%s\n", t.Type, t.Target, state)
+		}
+	}
+	if len(status.Conditions) > 0 {
+		fmt.Fprintln(w, "Conditions:")
+		for _, c := range status.Conditions {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", c.Type, c.Status, c.Reason, c.Message)
+		}
+	}
+	_ = w.Flush()
+	return b.String()
+}
+
+func describeKeySpec(spec openukrv1alpha1.KeySpec) string {
+	if len(spec.Params) == 0 {
+		return spec.Algorithm
+	}
+	params := make([]string, 0, len(spec.Params))
+	for _, k := range slices.Sorted(maps.Keys(spec.Params)) {
+		params = append(params, k+"="+spec.Params[k])
+	}
+	return fmt.Sprintf("%s (%s)", spec.Algorithm, strings.Join(params, ", "))
+}
+
+func describeKey(keyID, fingerprint string) string {
+	if keyID == "" || fingerprint == "" {
+		return keyID
+	}
+	return fmt.Sprintf("%s (%s)", keyID, fingerprint)
+}
+
+func describeDuration(d metav1.Duration) string {
+	if d.Duration == 0 {
+		return ""
+	}
+	return d.Duration.String()
+}
+
+func describeTime(t *metav1.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// ForceRotate requests an immediate rotation of the KeyProfile named by key by
+// setting rotation.ForceRotateAnnotation. The controller rotates on its next
+// reconcile, records the request in Status.LastForcedRotation and removes the
+// annotation; a rotation pause still applies.
+func ForceRotate(ctx context.Context, c client.Client, key client.ObjectKey) error {
+	var profile openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, key, &profile); err != nil {
+		return fmt.Errorf("failed to get KeyProfile: %w", err)
+	}
+	patch := client.MergeFrom(profile.DeepCopy())
+	if profile.Annotations == nil {
+		profile.Annotations = map[string]string{}
+	}
+	// A fresh value re-triggers the watch even if a request is still pending,
+	// and never equals the value of a request already handled
+	profile.Annotations[rotation.ForceRotateAnnotation] = time.Now().UTC().Format(time.RFC3339Nano)
+	if err := c.Patch(ctx, &profile, patch); err != nil {
+		return fmt.Errorf("failed to set %s annotation: %w", rotation.ForceRotateAnnotation, err)
+	}
+	return nil
+}
+
+// VerifyResult is the outcome of verifying one KeyProfile's stored key.
+type VerifyResult struct {
+	// Profile identifies the KeyProfile.
+	Profile client.ObjectKey
+	// KeyID is the current key recorded in the profile's status.
+	KeyID string
+	// Err is nil if the Secret passed verification or does not exist yet.
+	// Integrity violations wrap output.ErrIntegrity.
+	Err error
+}
+
+// VerifyAll checks the stored key material of every KeyProfile in namespace
+// (all namespaces if empty) against its recorded fingerprint, see
+// output.VerifyProfile. Per-profile failures are reported in the results, in
+// list order; the error is only set if the profiles could not be listed.
+func VerifyAll(ctx context.Context, c client.Client, namespace string) ([]VerifyResult, error) {
+	var profiles openukrv1alpha1.KeyProfileList
+	if err := c.List(ctx, &profiles, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list KeyProfiles: %w", err)
+	}
+	results := make([]VerifyResult, 0, len(profiles.Items))
+	for i := range profiles.Items {
+		p := &profiles.Items[i]
+		results = append(results, VerifyResult{
+			Profile: client.ObjectKeyFromObject(p),
+			KeyID:   p.Status.CurrentKeyID,
+			Err:     output.VerifyProfile(ctx, c, p),
+		})
+	}
+	return results, nil
+}
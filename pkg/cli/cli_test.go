@@ -0,0 +1,217 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/output"
+	"github.com/openukr/openukr/pkg/rotation"
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	if err := openukrv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	return scheme
+}
+
+func newTestProfile(name, secretName string) *openukrv1alpha1.KeyProfile {
+	return &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			KeySpec: openukrv1alpha1.KeySpec{
+				Algorithm: crypto.AlgorithmEC,
+				Params:    map[string]string{"curve": crypto.CurveP256},
+			},
+			Rotation: openukrv1alpha1.RotationPolicy{
+				Interval:    metav1.Duration{Duration: 24 * time.Hour},
+				GracePeriod: metav1.Duration{Duration: time.Hour},
+			},
+			Output: openukrv1alpha1.OutputConfig{SecretName: secretName, Format: "split-pem"},
+		},
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	t.Parallel()
+
+	profile := newTestProfile("payment-api", "payment-api-keys")
+	profile.Annotations = map[string]string{rotation.ForceRotateAnnotation: "2026-01-01T00:00:00Z"}
+	profile.Status = openukrv1alpha1.KeyProfileStatus{
+		Phase:                 "Active",
+		CurrentKeyID:          "ec-P-256-current",
+		CurrentKeyFingerprint: "SHA256:abc",
+		CurrentKeyThumbprint:  "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs",
+		LastRotation:          &metav1.Time{Time: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		PublishStatus: []openukrv1alpha1.TargetStatus{
+			{Type: "http", Target: "https://keys.example.com", Error: "connection refused"},
+		},
+		Conditions: []metav1.Condition{
+			{Type: openukrv1alpha1.ConditionIntegrityVerified, Status: metav1.ConditionTrue, Reason: "Verified"},
+		},
+	}
+
+	got := Describe(profile)
+	for _, want := range []string{
+		"payment-api",
+		"EC (curve=P-256)",
+		"payment-api-keys",
+		"24h0m0s",
+		"ec-P-256-current (SHA256:abc)",
+		"NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs",
+		"2026-01-01T00:00:00Z",
+		"Forced Rotation:",
+		"failed: connection refused",
+		"IntegrityVerified",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Describe() is missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Previous Key") {
+		t.Errorf("Describe() lists an unset previous key:\n%s", got)
+	}
+}
+
+func TestForceRotate(t *testing.T) {
+	t.Parallel()
+
+	profile := newTestProfile("profile", "profile-keys")
+	c := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithObjects(profile).Build()
+	ctx := context.Background()
+
+	if err := ForceRotate(ctx, c, client.ObjectKeyFromObject(profile)); err != nil {
+		t.Fatalf("ForceRotate() error = %v", err)
+	}
+	var got openukrv1alpha1.KeyProfile
+	if err := c.Get(ctx, client.ObjectKeyFromObject(profile), &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, err := time.Parse(time.RFC3339, got.Annotations[rotation.ForceRotateAnnotation]); err != nil {
+		t.Errorf("%s = %q, want an RFC 3339 timestamp", rotation.ForceRotateAnnotation,
+			got.Annotations[rotation.ForceRotateAnnotation])
+	}
+
+	err := ForceRotate(ctx, c, client.ObjectKey{Name: "missing", Namespace: "default"})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("ForceRotate(missing) error = %v, want not found", err)
+	}
+}
+
+func TestVerifyAll(t *testing.T) {
+	t.Parallel()
+
+	kp, err := crypto.NewKeyGenerator().Generate(crypto.GenerateOptions{
+		Algorithm: crypto.AlgorithmEC,
+		Params:    map[string]string{"curve": crypto.CurveP256},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer kp.Wipe()
+	other, err := crypto.NewKeyGenerator().Generate(crypto.GenerateOptions{
+		Algorithm: crypto.AlgorithmEC,
+		Params:    map[string]string{"curve": crypto.CurveP256},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer other.Wipe()
+	encoder, err := crypto.NewKeyEncoder("PEM")
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	privPEM, err := encoder.EncodePrivate(kp.PrivateKey)
+	if err != nil {
+		t.Fatalf("EncodePrivate() error = %v", err)
+	}
+	pubPEM, err := encoder.EncodePublic(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("EncodePublic() error = %v", err)
+	}
+	fingerprint, err := crypto.ComputeFingerprint(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+	otherFingerprint, err := crypto.ComputeFingerprint(other.PublicKey)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+
+	newSecret := func(name string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: map[string]string{"openukr.io/key-id": kp.KeyID},
+			},
+			Data: map[string][]byte{"tls.key": privPEM, "public.pem": pubPEM},
+		}
+	}
+	intact := newTestProfile("intact", "intact-keys")
+	intact.Status = openukrv1alpha1.KeyProfileStatus{CurrentKeyID: kp.KeyID, CurrentKeyFingerprint: fingerprint.String()}
+	tampered := newTestProfile("tampered", "tampered-keys")
+	tampered.Status = openukrv1alpha1.KeyProfileStatus{CurrentKeyID: kp.KeyID, CurrentKeyFingerprint: otherFingerprint.String()}
+	pending := newTestProfile("pending", "pending-keys")
+	elsewhere := newTestProfile("elsewhere", "elsewhere-keys")
+	elsewhere.Namespace = "other"
+
+	c := fake.NewClientBuilder().
+		WithScheme(newTestScheme(t)).
+		WithObjects(intact, tampered, pending, elsewhere, newSecret("intact-keys"), newSecret("tampered-keys")).
+		Build()
+
+	results, err := VerifyAll(context.Background(), c, "default")
+	if err != nil {
+		t.Fatalf("VerifyAll() error = %v", err)
+	}
+	got := map[string]VerifyResult{}
+	for _, r := range results {
+		got[r.Profile.Name] = r
+	}
+	if len(got) != 3 {
+		t.Fatalf("VerifyAll() returned %d results, want the 3 profiles in the namespace", len(results))
+	}
+	if r := got["intact"]; r.Err != nil || r.KeyID != kp.KeyID {
+		t.Errorf("intact = %+v, want verified %s", r, kp.KeyID)
+	}
+	if r := got["tampered"]; !errors.Is(r.Err, output.ErrIntegrity) {
+		t.Errorf("tampered error = %v, want an integrity violation", r.Err)
+	}
+	if r := got["pending"]; r.Err != nil {
+		t.Errorf("pending error = %v, want a missing Secret to pass", r.Err)
+	}
+}
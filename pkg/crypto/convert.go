@@ -0,0 +1,285 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// Convert re-encodes a single key from one encoding to another. Both encodings
+// are PEM, DER or JWK; output encodings may additionally be any public-only
+// encoding accepted by NewKeyEncoder. isPrivate selects whether in holds a
+// private key (PKCS#8/PKCS#1/SEC 1 PEM, PKCS#8 DER, private JWK) or a public
+// key (PKIX PEM or DER, public JWK). Private keys are emitted in the target
+// encoding's default form: PKCS#8 for PEM and DER.
+func Convert(in []byte, fromEncoding, toEncoding string, isPrivate bool) ([]byte, error) {
+	encoder, err := NewKeyEncoder(toEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	if isPrivate {
+		key, err := decodePrivate(in, fromEncoding)
+		if err != nil {
+			return nil, err
+		}
+		return encoder.EncodePrivate(key)
+	}
+
+	key, err := decodePublic(in, fromEncoding)
+	if err != nil {
+		return nil, err
+	}
+	return encoder.EncodePublic(key)
+}
+
+func decodePrivate(in []byte, encoding string) (crypto.PrivateKey, error) {
+	switch encoding {
+	case "PEM":
+		return ParsePrivateKeyPEM(in)
+	case "DER":
+		key, err := parsePKCS8PrivateKey(in)
+		if err != nil {
+			return nil, fmt.Errorf("parse PKCS8 DER private key: %w", err)
+		}
+		if err := validatePrivateKey(key); err != nil {
+			return nil, err
+		}
+		return key, nil
+	case "JWK":
+		return parsePrivateJWK(in)
+	default:
+		return nil, fmt.Errorf("unsupported input encoding: %s", encoding)
+	}
+}
+
+func decodePublic(in []byte, encoding string) (crypto.PublicKey, error) {
+	switch encoding {
+	case "PEM":
+		return ParsePublicKeyPEM(in)
+	case "DER", EncodingSPKI:
+		key, err := parsePKIXPublicKey(in)
+		if err != nil {
+			return nil, fmt.Errorf("parse PKIX DER public key: %w", err)
+		}
+		return key, nil
+	case "JWK":
+		return parsePublicJWK(in)
+	default:
+		return nil, fmt.Errorf("unsupported input encoding: %s", encoding)
+	}
+}
+
+func parsePublicJWK(in []byte) (crypto.PublicKey, error) {
+	var j jwk
+	if err := json.Unmarshal(in, &j); err != nil {
+		return nil, fmt.Errorf("parse JWK: %w", err)
+	}
+	return publicFromJWK(j)
+}
+
+// ParseJWKS parses the public keys of a JWK Set (RFC 7517 §5), as written by
+// EncodeJWKS, with each "kid" as KeyID. Private members are ignored, so the
+// result never holds private material.
+func ParseJWKS(in []byte) ([]*PublicKeyInfo, error) {
+	var set jwkSet
+	if err := json.Unmarshal(in, &set); err != nil {
+		return nil, fmt.Errorf("parse JWKS: %w", err)
+	}
+	keys := make([]*PublicKeyInfo, 0, len(set.Keys))
+	for i, j := range set.Keys {
+		pub, err := publicFromJWK(j)
+		if err != nil {
+			return nil, fmt.Errorf("keys[%d]: %w", i, err)
+		}
+		algorithm := j.Kty
+		if j.Kty == jwkKtyOKP {
+			algorithm = AlgorithmEd25519
+		}
+		keys = append(keys, &PublicKeyInfo{KeyID: j.Kid, PublicKey: pub, Algorithm: algorithm})
+	}
+	return keys, nil
+}
+
+func publicFromJWK(j jwk) (crypto.PublicKey, error) {
+	switch j.Kty {
+	case "EC":
+		return ecPublicFromJWK(j)
+	case "RSA":
+		return rsaPublicFromJWK(j)
+	case jwkKtyOKP:
+		return ed25519PublicFromJWK(j)
+	default:
+		return nil, fmt.Errorf("unsupported JWK key type: %q", j.Kty)
+	}
+}
+
+func parsePrivateJWK(in []byte) (crypto.PrivateKey, error) {
+	var j jwk
+	if err := json.Unmarshal(in, &j); err != nil {
+		return nil, fmt.Errorf("parse JWK: %w", err)
+	}
+	if j.Kty == jwkKtyOKP {
+		return ed25519PrivateFromJWK(j)
+	}
+	d, err := jwkInt(j.D, "d")
+	if err != nil {
+		return nil, err
+	}
+
+	switch j.Kty {
+	case "EC":
+		pub, err := ecPublicFromJWK(j)
+		if err != nil {
+			return nil, err
+		}
+		priv := &ecdsa.PrivateKey{PublicKey: *pub, D: d}
+		// Reject a "d" that does not belong to the embedded public point.
+		if err := validateECPrivateKey(priv); err != nil {
+			return nil, fmt.Errorf("invalid EC JWK private key: %w", err)
+		}
+		return priv, nil
+
+	case "RSA":
+		pub, err := rsaPublicFromJWK(j)
+		if err != nil {
+			return nil, err
+		}
+		p, err := jwkInt(j.P, "p")
+		if err != nil {
+			return nil, err
+		}
+		q, err := jwkInt(j.Q, "q")
+		if err != nil {
+			return nil, err
+		}
+		priv := &rsa.PrivateKey{PublicKey: *pub, D: d, Primes: []*big.Int{p, q}}
+		if err := validatePrivateKey(priv); err != nil {
+			return nil, fmt.Errorf("invalid RSA JWK: %w", err)
+		}
+		return priv, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported JWK key type: %q", j.Kty)
+	}
+}
+
+func ecPublicFromJWK(j jwk) (*ecdsa.PublicKey, error) {
+	if j.Crv == nil {
+		return nil, fmt.Errorf("JWK is missing \"crv\"")
+	}
+	c, ok := curveForJWK(*j.Crv)
+	if !ok {
+		return nil, fmt.Errorf("unsupported JWK curve: %q", *j.Crv)
+	}
+	x, err := jwkInt(j.X, "x")
+	if err != nil {
+		return nil, err
+	}
+	y, err := jwkInt(j.Y, "y")
+	if err != nil {
+		return nil, err
+	}
+	pub := &ecdsa.PublicKey{Curve: c.curve(), X: x, Y: y}
+	if err := validateECPublicKey(pub); err != nil {
+		return nil, fmt.Errorf("invalid EC JWK point: %w", err)
+	}
+	return pub, nil
+}
+
+func rsaPublicFromJWK(j jwk) (*rsa.PublicKey, error) {
+	n, err := jwkInt(j.N, "n")
+	if err != nil {
+		return nil, err
+	}
+	e, err := jwkInt(j.E, "e")
+	if err != nil {
+		return nil, err
+	}
+	if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid RSA JWK exponent")
+	}
+	return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+}
+
+func ed25519PublicFromJWK(j jwk) (ed25519.PublicKey, error) {
+	if j.Crv == nil || *j.Crv != jwkCrvEd25519 {
+		return nil, fmt.Errorf("unsupported OKP JWK curve, must be %s", jwkCrvEd25519)
+	}
+	x, err := jwkBytes(j.X, "x", ed25519.PublicKeySize)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.PublicKey(x), nil
+}
+
+func ed25519PrivateFromJWK(j jwk) (ed25519.PrivateKey, error) {
+	pub, err := ed25519PublicFromJWK(j)
+	if err != nil {
+		return nil, err
+	}
+	seed, err := jwkBytes(j.D, "d", ed25519.SeedSize)
+	if err != nil {
+		return nil, err
+	}
+	// [SEC:I-2] The expanded key holds its own copy of the seed
+	defer func() {
+		for i := range seed {
+			seed[i] = 0
+		}
+	}()
+	priv := ed25519.NewKeyFromSeed(seed)
+	// Reject a "d" that does not belong to the embedded public key.
+	if !pub.Equal(priv.Public()) {
+		return nil, fmt.Errorf("invalid Ed25519 JWK private key: public key does not match")
+	}
+	return priv, nil
+}
+
+// jwkBytes decodes a required base64url-encoded member of a fixed length.
+func jwkBytes(v *string, name string, size int) ([]byte, error) {
+	if v == nil || *v == "" {
+		return nil, fmt.Errorf("JWK is missing %q", name)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(*v)
+	if err != nil {
+		return nil, fmt.Errorf("decode JWK %q: %w", name, err)
+	}
+	if len(b) != size {
+		return nil, fmt.Errorf("JWK %q must be %d bytes, got %d", name, size, len(b))
+	}
+	return b, nil
+}
+
+// jwkInt decodes a required base64url-encoded unsigned integer member.
+func jwkInt(v *string, name string) (*big.Int, error) {
+	if v == nil || *v == "" {
+		return nil, fmt.Errorf("JWK is missing %q", name)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(*v)
+	if err != nil {
+		return nil, fmt.Errorf("decode JWK %q: %w", name, err)
+	}
+	return new(big.Int).SetBytes(b), nil
+}
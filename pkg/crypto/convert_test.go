@@ -0,0 +1,145 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestConvertRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	keys := map[string]GenerateOptions{
+		"EC":      {Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP384}},
+		"RSA":     {Algorithm: AlgorithmRSA, Params: map[string]string{"keySize": "2048"}, AllowLegacyKeySize: true},
+		"Ed25519": {Algorithm: AlgorithmEd25519},
+	}
+	encodings := []string{"PEM", "DER", "JWK"}
+
+	for name, opts := range keys {
+		kp, err := NewKeyGenerator().Generate(opts)
+		if err != nil {
+			t.Fatalf("%s: Generate() error = %v", name, err)
+		}
+		for _, isPrivate := range []bool{true, false} {
+			original := map[string][]byte{}
+			for _, enc := range encodings {
+				encoder, err := NewKeyEncoder(enc)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if isPrivate {
+					original[enc], err = encoder.EncodePrivate(kp.PrivateKey)
+				} else {
+					original[enc], err = encoder.EncodePublic(kp.PublicKey)
+				}
+				if err != nil {
+					t.Fatalf("%s %s: encode error = %v", name, enc, err)
+				}
+			}
+
+			for _, from := range encodings {
+				for _, to := range encodings {
+					got, err := Convert(original[from], from, to, isPrivate)
+					if err != nil {
+						t.Errorf("%s private=%v: Convert(%s -> %s) error = %v", name, isPrivate, from, to, err)
+						continue
+					}
+					if !bytes.Equal(got, original[to]) {
+						t.Errorf("%s private=%v: Convert(%s -> %s) does not match direct %s encoding", name, isPrivate, from, to, to)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestConvertRejectsInvalidInput(t *testing.T) {
+	t.Parallel()
+
+	kp, err := NewKeyGenerator().Generate(GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP256}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoder, _ := NewKeyEncoder("JWK")
+	privJWK, err := encoder.EncodePrivate(kp.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Swap in another key's "d" so the private scalar no longer matches x/y.
+	other, err := NewKeyGenerator().Generate(GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP256}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherJWK, _ := encoder.EncodePrivate(other.PrivateKey)
+	var a, b map[string]any
+	if err := json.Unmarshal(privJWK, &a); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(otherJWK, &b); err != nil {
+		t.Fatal(err)
+	}
+	a["d"] = b["d"]
+	mismatched, _ := json.Marshal(a)
+
+	tests := []struct {
+		name      string
+		in        []byte
+		from, to  string
+		isPrivate bool
+	}{
+		{name: "unknown input encoding", in: privJWK, from: "XML", to: "PEM", isPrivate: true},
+		{name: "unknown output encoding", in: privJWK, from: "JWK", to: "XML", isPrivate: true},
+		{name: "garbage DER", in: []byte("not der"), from: "DER", to: "PEM", isPrivate: true},
+		{name: "public PEM read as private", in: mustEncodePublicPEM(t, kp), from: "PEM", to: "DER", isPrivate: true},
+		{name: "mismatched JWK private scalar", in: mismatched, from: "JWK", to: "PEM", isPrivate: true},
+		{name: "JWK missing d", in: mustEncodePublicJWK(t, kp), from: "JWK", to: "PEM", isPrivate: true},
+		{name: "JWK off-curve point", in: []byte(`{"kty":"EC","crv":"P-256","x":"AQ","y":"AQ"}`), from: "JWK", to: "PEM"},
+		{name: "JWK unknown kty", in: []byte(`{"kty":"oct","k":"AQ"}`), from: "JWK", to: "PEM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := Convert(tt.in, tt.from, tt.to, tt.isPrivate); err == nil {
+				t.Fatal("Convert() error = nil, want error")
+			}
+		})
+	}
+}
+
+func mustEncodePublicPEM(t *testing.T, kp *KeyPair) []byte {
+	t.Helper()
+	encoder, _ := NewKeyEncoder("PEM")
+	out, err := encoder.EncodePublic(kp.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func mustEncodePublicJWK(t *testing.T, kp *KeyPair) []byte {
+	t.Helper()
+	encoder, _ := NewKeyEncoder("JWK")
+	out, err := encoder.EncodePublic(kp.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
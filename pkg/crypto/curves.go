@@ -0,0 +1,422 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ProtonMail/go-crypto/brainpool"
+)
+
+// Supported non-NIST EC curves (RFC 5639). They are only accepted after
+// AllowNonNISTCurves(true).
+const (
+	CurveBrainpoolP256r1 = "brainpoolP256r1"
+	CurveBrainpoolP384r1 = "brainpoolP384r1"
+)
+
+// ecCurve describes a supported EC curve.
+type ecCurve struct {
+	// name is the KeySpec "curve" parameter.
+	name string
+	// jwkName is the JWK "crv" value.
+	jwkName string
+	curve   func() elliptic.Curve
+	// oid is the namedCurve identifier in PKIX, PKCS #8 and SEC 1 structures.
+	oid asn1.ObjectIdentifier
+	// nonNIST marks curves unknown to crypto/x509 and crypto/ecdh. Their
+	// encodings are handled in this file, and they require AllowNonNISTCurves.
+	nonNIST bool
+}
+
+// ecCurves is the single table of supported EC curves: parseCurve, curveName,
+// SupportedCurves and the EC parameter validation all derive from it.
+var ecCurves = []ecCurve{
+	{name: CurveP256, jwkName: "P-256", curve: elliptic.P256, oid: asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}},
+	{name: CurveP384, jwkName: "P-384", curve: elliptic.P384, oid: asn1.ObjectIdentifier{1, 3, 132, 0, 34}},
+	{name: CurveP521, jwkName: "P-521", curve: elliptic.P521, oid: asn1.ObjectIdentifier{1, 3, 132, 0, 35}},
+	{
+		name: CurveBrainpoolP256r1, jwkName: "BP-256", curve: brainpool.P256r1,
+		oid: asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 7}, nonNIST: true,
+	},
+	{
+		name: CurveBrainpoolP384r1, jwkName: "BP-384", curve: brainpool.P384r1,
+		oid: asn1.ObjectIdentifier{1, 3, 36, 3, 3, 2, 8, 1, 1, 11}, nonNIST: true,
+	},
+}
+
+var nonNISTCurvesAllowed atomic.Bool
+
+// AllowNonNISTCurves enables the brainpool curves for key generation and
+// ValidateKeySpec. They are off by default: crypto/x509 cannot parse them, so
+// certificates, keystore formats and most TLS and JOSE libraries reject these
+// keys. Intended to be called from main before the controller starts.
+func AllowNonNISTCurves(allow bool) {
+	nonNISTCurvesAllowed.Store(allow)
+}
+
+// NonNISTCurve reports whether name is a supported curve outside crypto/x509,
+// which cannot be used in certificates or keystores.
+func NonNISTCurve(name string) bool {
+	c, ok := lookupCurve(name)
+	return ok && c.nonNIST
+}
+
+// JWKCurveName returns the JWK "crv" value of a supported curve, or "".
+func JWKCurveName(name string) string {
+	c, ok := lookupCurve(name)
+	if !ok {
+		return ""
+	}
+	return c.jwkName
+}
+
+// lookupCurve returns the table entry for a KeySpec curve name.
+func lookupCurve(name string) (ecCurve, bool) {
+	for _, c := range ecCurves {
+		if c.name == name {
+			return c, true
+		}
+	}
+	return ecCurve{}, false
+}
+
+// curveFor returns the table entry for an elliptic.Curve.
+func curveFor(curve elliptic.Curve) (ecCurve, bool) {
+	for _, c := range ecCurves {
+		if c.curve() == curve {
+			return c, true
+		}
+	}
+	return ecCurve{}, false
+}
+
+// curveForJWK returns the table entry for a JWK "crv" value.
+func curveForJWK(crv string) (ecCurve, bool) {
+	for _, c := range ecCurves {
+		if c.jwkName == crv {
+			return c, true
+		}
+	}
+	return ecCurve{}, false
+}
+
+// nonNISTCurveForOID returns the non-NIST table entry with the given OID.
+func nonNISTCurveForOID(oid asn1.ObjectIdentifier) (ecCurve, bool) {
+	for _, c := range ecCurves {
+		if c.nonNIST && c.oid.Equal(oid) {
+			return c, true
+		}
+	}
+	return ecCurve{}, false
+}
+
+// parseCurve maps curve name strings to elliptic.Curve.
+func parseCurve(name string) (elliptic.Curve, error) {
+	c, ok := lookupCurve(name)
+	if !ok {
+		return nil, fmt.Errorf("unsupported curve: %s", name)
+	}
+	return c.curve(), nil
+}
+
+// curveName returns the JWK "crv" value of curve, or "" if it is not supported.
+func curveName(curve elliptic.Curve) string {
+	c, ok := curveFor(curve)
+	if !ok {
+		return ""
+	}
+	return c.jwkName
+}
+
+// nonNISTKey returns the non-NIST table entry of an EC key, if it uses one.
+func nonNISTKey(key any) (ecCurve, *ecdsa.PublicKey, bool) {
+	var pub *ecdsa.PublicKey
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		pub = &k.PublicKey
+	case *ecdsa.PublicKey:
+		pub = k
+	default:
+		return ecCurve{}, nil, false
+	}
+	c, ok := curveFor(pub.Curve)
+	return c, pub, ok && c.nonNIST
+}
+
+// validateECPublicKey checks that pub is a valid point on its curve.
+func validateECPublicKey(pub *ecdsa.PublicKey) error {
+	if _, _, ok := nonNISTKey(pub); ok {
+		if pub.X == nil || pub.Y == nil || !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+			return errors.New("point is not on the curve")
+		}
+		return nil
+	}
+	// ECDH rejects points that are not on the curve.
+	_, err := pub.ECDH()
+	return err
+}
+
+// validateECPrivateKey checks that priv.D is a valid scalar whose public point
+// is priv.PublicKey.
+func validateECPrivateKey(priv *ecdsa.PrivateKey) error {
+	if _, _, ok := nonNISTKey(priv); ok {
+		if priv.D == nil || priv.D.Sign() <= 0 || priv.D.Cmp(priv.Curve.Params().N) >= 0 {
+			return errors.New("private scalar out of range")
+		}
+		x, y := priv.Curve.ScalarBaseMult(priv.D.Bytes())
+		if x.Cmp(priv.X) != 0 || y.Cmp(priv.Y) != 0 {
+			return errors.New("private key does not match its public key")
+		}
+		return nil
+	}
+	ecdhPriv, err := priv.ECDH()
+	if err != nil {
+		return err
+	}
+	ecdhPub, err := priv.PublicKey.ECDH()
+	if err != nil {
+		return err
+	}
+	if !ecdhPriv.PublicKey().Equal(ecdhPub) {
+		return errors.New("private key does not match its public key")
+	}
+	return nil
+}
+
+// The x509-equivalent helpers below handle keys on non-NIST curves themselves
+// and delegate everything else to crypto/x509.
+
+// oidPublicKeyECDSA is id-ecPublicKey (RFC 5480).
+var oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+
+// subjectPublicKeyInfo is the PKIX public key structure (RFC 5280).
+type subjectPublicKeyInfo struct {
+	Algorithm pkix.AlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+// pkcs8 is the PKCS #8 private key structure (RFC 5208).
+type pkcs8 struct {
+	Version    int
+	Algorithm  pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// ecPrivateKey is the SEC 1 private key structure (RFC 5915).
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+func ecAlgorithmIdentifier(c ecCurve) (pkix.AlgorithmIdentifier, error) {
+	params, err := asn1.Marshal(c.oid)
+	if err != nil {
+		return pkix.AlgorithmIdentifier{}, err
+	}
+	return pkix.AlgorithmIdentifier{Algorithm: oidPublicKeyECDSA, Parameters: asn1.RawValue{FullBytes: params}}, nil
+}
+
+// marshalECPoint returns the uncompressed SEC 1 encoding of pub.
+func marshalECPoint(pub *ecdsa.PublicKey) []byte {
+	byteLen := (pub.Curve.Params().BitSize + 7) / 8
+	point := make([]byte, 1, 1+2*byteLen)
+	point[0] = 4
+	point = append(point, padLeft(pub.X.Bytes(), byteLen)...)
+	return append(point, padLeft(pub.Y.Bytes(), byteLen)...)
+}
+
+// unmarshalECPoint parses an uncompressed SEC 1 point on curve.
+func unmarshalECPoint(curve elliptic.Curve, point []byte) (*ecdsa.PublicKey, error) {
+	byteLen := (curve.Params().BitSize + 7) / 8
+	if len(point) != 1+2*byteLen || point[0] != 4 {
+		return nil, errors.New("invalid uncompressed EC point")
+	}
+	pub := &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(point[1 : 1+byteLen]),
+		Y:     new(big.Int).SetBytes(point[1+byteLen:]),
+	}
+	if err := validateECPublicKey(pub); err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
+
+// marshalPKIXPublicKey is x509.MarshalPKIXPublicKey extended to non-NIST curves.
+func marshalPKIXPublicKey(key crypto.PublicKey) ([]byte, error) {
+	c, pub, ok := nonNISTKey(key)
+	if !ok {
+		return x509.MarshalPKIXPublicKey(key)
+	}
+	algorithm, err := ecAlgorithmIdentifier(c)
+	if err != nil {
+		return nil, err
+	}
+	point := marshalECPoint(pub)
+	return asn1.Marshal(subjectPublicKeyInfo{
+		Algorithm: algorithm,
+		PublicKey: asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+}
+
+// parsePKIXPublicKey is x509.ParsePKIXPublicKey extended to non-NIST curves.
+func parsePKIXPublicKey(der []byte) (crypto.PublicKey, error) {
+	key, x509Err := x509.ParsePKIXPublicKey(der)
+	if x509Err == nil {
+		return key, nil
+	}
+	var spki subjectPublicKeyInfo
+	if rest, err := asn1.Unmarshal(der, &spki); err != nil || len(rest) > 0 {
+		return nil, x509Err
+	}
+	c, ok := nonNISTCurveForAlgorithm(spki.Algorithm)
+	if !ok {
+		return nil, x509Err
+	}
+	return unmarshalECPoint(c.curve(), spki.PublicKey.RightAlign())
+}
+
+// marshalECPrivateKey is x509.MarshalECPrivateKey extended to non-NIST curves.
+func marshalECPrivateKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	c, _, ok := nonNISTKey(key)
+	if !ok {
+		return x509.MarshalECPrivateKey(key)
+	}
+	return marshalNonNISTECPrivateKey(c, key, c.oid)
+}
+
+func marshalNonNISTECPrivateKey(c ecCurve, key *ecdsa.PrivateKey, oid asn1.ObjectIdentifier) ([]byte, error) {
+	byteLen := (c.curve().Params().N.BitLen() + 7) / 8
+	point := marshalECPoint(&key.PublicKey)
+	return asn1.Marshal(ecPrivateKey{
+		Version:       1,
+		PrivateKey:    padLeft(key.D.Bytes(), byteLen),
+		NamedCurveOID: oid,
+		PublicKey:     asn1.BitString{Bytes: point, BitLength: 8 * len(point)},
+	})
+}
+
+// parseECPrivateKey is x509.ParseECPrivateKey extended to non-NIST curves.
+func parseECPrivateKey(der []byte) (*ecdsa.PrivateKey, error) {
+	key, x509Err := x509.ParseECPrivateKey(der)
+	if x509Err == nil {
+		return key, nil
+	}
+	var sec1 ecPrivateKey
+	if rest, err := asn1.Unmarshal(der, &sec1); err != nil || len(rest) > 0 {
+		return nil, x509Err
+	}
+	c, ok := nonNISTCurveForOID(sec1.NamedCurveOID)
+	if !ok {
+		return nil, x509Err
+	}
+	return parseNonNISTECPrivateKey(c, sec1)
+}
+
+func parseNonNISTECPrivateKey(c ecCurve, sec1 ecPrivateKey) (*ecdsa.PrivateKey, error) {
+	if sec1.Version != 1 {
+		return nil, fmt.Errorf("unknown EC private key version %d", sec1.Version)
+	}
+	curve := c.curve()
+	priv := &ecdsa.PrivateKey{PublicKey: ecdsa.PublicKey{Curve: curve}, D: new(big.Int).SetBytes(sec1.PrivateKey)}
+	if priv.D.Sign() <= 0 || priv.D.Cmp(curve.Params().N) >= 0 {
+		return nil, errors.New("invalid EC private key value")
+	}
+	priv.X, priv.Y = curve.ScalarBaseMult(priv.D.Bytes())
+	if len(sec1.PublicKey.Bytes) > 0 {
+		pub, err := unmarshalECPoint(curve, sec1.PublicKey.RightAlign())
+		if err != nil {
+			return nil, err
+		}
+		if !pub.Equal(&priv.PublicKey) {
+			return nil, errors.New("EC private key does not match its public key")
+		}
+	}
+	return priv, nil
+}
+
+// marshalPKCS8PrivateKey is x509.MarshalPKCS8PrivateKey extended to non-NIST curves.
+func marshalPKCS8PrivateKey(key crypto.PrivateKey) ([]byte, error) {
+	c, _, ok := nonNISTKey(key)
+	if !ok {
+		return x509.MarshalPKCS8PrivateKey(key)
+	}
+	algorithm, err := ecAlgorithmIdentifier(c)
+	if err != nil {
+		return nil, err
+	}
+	// The curve is named by the algorithm identifier (RFC 5915 §3)
+	inner, err := marshalNonNISTECPrivateKey(c, key.(*ecdsa.PrivateKey), nil)
+	if err != nil {
+		return nil, err
+	}
+	der, err := asn1.Marshal(pkcs8{Algorithm: algorithm, PrivateKey: inner})
+	// [SEC:I-2] The inner encoding is a copy of the private scalar
+	for i := range inner {
+		inner[i] = 0
+	}
+	return der, err
+}
+
+// parsePKCS8PrivateKey is x509.ParsePKCS8PrivateKey extended to non-NIST curves.
+func parsePKCS8PrivateKey(der []byte) (crypto.PrivateKey, error) {
+	key, x509Err := x509.ParsePKCS8PrivateKey(der)
+	if x509Err == nil {
+		return key, nil
+	}
+	var p8 pkcs8
+	if rest, err := asn1.Unmarshal(der, &p8); err != nil || len(rest) > 0 {
+		return nil, x509Err
+	}
+	c, ok := nonNISTCurveForAlgorithm(p8.Algorithm)
+	if !ok {
+		return nil, x509Err
+	}
+	var sec1 ecPrivateKey
+	if _, err := asn1.Unmarshal(p8.PrivateKey, &sec1); err != nil {
+		return nil, fmt.Errorf("malformed EC private key: %w", err)
+	}
+	if len(sec1.NamedCurveOID) > 0 && !sec1.NamedCurveOID.Equal(c.oid) {
+		return nil, errors.New("EC private key curve does not match its algorithm identifier")
+	}
+	return parseNonNISTECPrivateKey(c, sec1)
+}
+
+// nonNISTCurveForAlgorithm returns the non-NIST curve named by an
+// id-ecPublicKey algorithm identifier.
+func nonNISTCurveForAlgorithm(algorithm pkix.AlgorithmIdentifier) (ecCurve, bool) {
+	if !algorithm.Algorithm.Equal(oidPublicKeyECDSA) {
+		return ecCurve{}, false
+	}
+	var oid asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(algorithm.Parameters.FullBytes, &oid); err != nil {
+		return ecCurve{}, false
+	}
+	return nonNISTCurveForOID(oid)
+}
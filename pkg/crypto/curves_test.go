@@ -0,0 +1,190 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestCurveTableConsistency(t *testing.T) {
+	t.Parallel()
+
+	for _, c := range ecCurves {
+		curve, err := parseCurve(c.name)
+		if err != nil {
+			t.Errorf("parseCurve(%s) error = %v", c.name, err)
+			continue
+		}
+		if got := curveName(curve); got != c.jwkName {
+			t.Errorf("curveName(parseCurve(%s)) = %q, want %q", c.name, got, c.jwkName)
+		}
+		if got, ok := curveForJWK(c.jwkName); !ok || got.name != c.name {
+			t.Errorf("curveForJWK(%s) = %q, want %s", c.jwkName, got.name, c.name)
+		}
+		if got := JWKCurveName(c.name); got != c.jwkName {
+			t.Errorf("JWKCurveName(%s) = %q, want %q", c.name, got, c.jwkName)
+		}
+		if NonNISTCurve(c.name) != c.nonNIST {
+			t.Errorf("NonNISTCurve(%s) = %v, want %v", c.name, !c.nonNIST, c.nonNIST)
+		}
+	}
+}
+
+// Not parallel: toggles the process-wide opt-in.
+func TestNonNISTCurvesRequireOptIn(t *testing.T) {
+	params := map[string]string{"curve": CurveBrainpoolP256r1}
+
+	if _, err := ValidateKeySpec(AlgorithmEC, params, false); err == nil {
+		t.Error("ValidateKeySpec(brainpoolP256r1) succeeded without opt-in, want error")
+	}
+	if slices.Contains(SupportedCurves(), CurveBrainpoolP256r1) {
+		t.Error("SupportedCurves() lists brainpoolP256r1 without opt-in")
+	}
+	if _, err := NewKeyGenerator().Generate(GenerateOptions{Algorithm: AlgorithmEC, Params: params}); err == nil {
+		t.Error("Generate(brainpoolP256r1) succeeded without opt-in, want error")
+	}
+
+	AllowNonNISTCurves(true)
+	defer AllowNonNISTCurves(false)
+	warnings, err := ValidateKeySpec(AlgorithmEC, params, false)
+	if err != nil {
+		t.Fatalf("ValidateKeySpec(brainpoolP256r1) error = %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Errorf("warnings = %v, want a limited ecosystem support warning", warnings)
+	}
+	if !slices.Contains(SupportedCurves(), CurveBrainpoolP384r1) {
+		t.Error("SupportedCurves() does not list brainpoolP384r1 after opt-in")
+	}
+}
+
+// Not parallel: toggles the process-wide opt-in.
+func TestGenerateBrainpool(t *testing.T) {
+	AllowNonNISTCurves(true)
+	defer AllowNonNISTCurves(false)
+
+	for _, tt := range []struct {
+		curve   string
+		crv     string
+		byteLen int
+	}{
+		{curve: CurveBrainpoolP256r1, crv: "BP-256", byteLen: 32},
+		{curve: CurveBrainpoolP384r1, crv: "BP-384", byteLen: 48},
+	} {
+		t.Run(tt.curve, func(t *testing.T) {
+			kp, err := NewKeyGenerator().Generate(GenerateOptions{
+				Algorithm: AlgorithmEC,
+				Params:    map[string]string{"curve": tt.curve},
+			})
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			defer kp.Wipe()
+			priv := kp.PrivateKey.(*ecdsa.PrivateKey)
+			if name := priv.Curve.Params().Name; name != tt.curve {
+				t.Errorf("curve = %s, want %s", name, tt.curve)
+			}
+			digest := sha256.Sum256([]byte("payload"))
+			sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+			if err != nil || !ecdsa.VerifyASN1(&priv.PublicKey, digest[:], sig) {
+				t.Errorf("sign/verify failed: %v", err)
+			}
+
+			// JWK export carries the registered crv name and fixed-width coordinates
+			encoder, err := NewKeyEncoder("JWK")
+			if err != nil {
+				t.Fatalf("NewKeyEncoder() error = %v", err)
+			}
+			privJWK, err := encoder.EncodePrivate(kp.PrivateKey)
+			if err != nil {
+				t.Fatalf("EncodePrivate(JWK) error = %v", err)
+			}
+			var j map[string]string
+			if err := json.Unmarshal(privJWK, &j); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if j["kty"] != "EC" || j["crv"] != tt.crv {
+				t.Errorf("JWK kty/crv = %s/%s, want EC/%s", j["kty"], j["crv"], tt.crv)
+			}
+			for _, member := range []string{"x", "y", "d"} {
+				raw, err := base64.RawURLEncoding.DecodeString(j[member])
+				if err != nil || len(raw) != tt.byteLen {
+					t.Errorf("JWK %s is %d bytes (err %v), want %d", member, len(raw), err, tt.byteLen)
+				}
+			}
+			if _, err := JWKThumbprint(kp.PublicKey); err != nil {
+				t.Errorf("JWKThumbprint() error = %v", err)
+			}
+
+			// Every encoding round-trips through the parsers
+			for _, from := range []string{"JWK", "PEM", "DER"} {
+				enc, err := NewKeyEncoder(from)
+				if err != nil {
+					t.Fatal(err)
+				}
+				encoded, err := enc.EncodePrivate(kp.PrivateKey)
+				if err != nil {
+					t.Fatalf("EncodePrivate(%s) error = %v", from, err)
+				}
+				back, err := Convert(encoded, from, "JWK", true)
+				if err != nil {
+					t.Fatalf("Convert(%s -> JWK) error = %v", from, err)
+				}
+				if !bytes.Equal(back, privJWK) {
+					t.Errorf("Convert(%s -> JWK) does not match the direct JWK encoding", from)
+				}
+			}
+			sec1, err := NewKeyEncoder("PEM", WithPrivateKeyPEMType(PrivateKeyPEMTypeSEC1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			sec1PEM, err := sec1.EncodePrivate(kp.PrivateKey)
+			if err != nil {
+				t.Fatalf("EncodePrivate(SEC1) error = %v", err)
+			}
+			pubPEM, err := sec1.EncodePublic(kp.PublicKey)
+			if err != nil {
+				t.Fatalf("EncodePublic(PEM) error = %v", err)
+			}
+			parsedPriv, err := ParsePrivateKeyPEM(sec1PEM)
+			if err != nil {
+				t.Fatalf("ParsePrivateKeyPEM(SEC1) error = %v", err)
+			}
+			parsedPub, err := ParsePublicKeyPEM(pubPEM)
+			if err != nil {
+				t.Fatalf("ParsePublicKeyPEM() error = %v", err)
+			}
+			if err := VerifyKeyPair(parsedPriv, parsedPub); err != nil {
+				t.Errorf("VerifyKeyPair() error = %v", err)
+			}
+			fingerprint, err := ComputeFingerprint(kp.PublicKey)
+			if err != nil {
+				t.Fatalf("ComputeFingerprint() error = %v", err)
+			}
+			if ok, err := fingerprint.Matches(parsedPub); err != nil || !ok {
+				t.Errorf("fingerprint.Matches(parsed) = %v, %v, want true", ok, err)
+			}
+		})
+	}
+}
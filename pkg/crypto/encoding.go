@@ -19,14 +19,19 @@ package crypto
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/ed25519"
 	"crypto/elliptic"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
 	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"math/big"
+	"sort"
 )
 
 // KeyEncoder encodes key material into the specified format.
@@ -35,17 +40,104 @@ type KeyEncoder interface {
 	EncodePrivate(key crypto.PrivateKey) ([]byte, error)
 	// EncodePublic encodes a public key.
 	EncodePublic(key crypto.PublicKey) ([]byte, error)
+	// EncodePublicWithKID encodes a public key, setting "kid" to kid in JWK
+	// encoding. Other encodings carry no key ID and ignore it; an empty kid
+	// is the same as EncodePublic.
+	EncodePublicWithKID(key crypto.PublicKey, kid string) ([]byte, error)
+}
+
+// Private key PEM types.
+const (
+	// PrivateKeyPEMTypePKCS8 emits "PRIVATE KEY" (PKCS#8) for any algorithm. Default.
+	PrivateKeyPEMTypePKCS8 = "pkcs8"
+	// PrivateKeyPEMTypePKCS1 emits "RSA PRIVATE KEY" (PKCS#1). RSA only.
+	PrivateKeyPEMTypePKCS1 = "pkcs1"
+	// PrivateKeyPEMTypeSEC1 emits "EC PRIVATE KEY" (SEC 1). EC only.
+	PrivateKeyPEMTypeSEC1 = "sec1"
+)
+
+// ValidatePrivateKeyPEMType checks that the private key PEM type is known and
+// compatible with the algorithm. An empty type means the PKCS#8 default.
+func ValidatePrivateKeyPEMType(algorithm, pemType string) error {
+	switch pemType {
+	case "", PrivateKeyPEMTypePKCS8:
+		return nil
+	case PrivateKeyPEMTypePKCS1:
+		if algorithm != AlgorithmRSA {
+			return fmt.Errorf("privateKeyPEMType %q is only valid for RSA, got %s", pemType, algorithm)
+		}
+		return nil
+	case PrivateKeyPEMTypeSEC1:
+		if algorithm != AlgorithmEC {
+			return fmt.Errorf("privateKeyPEMType %q is only valid for EC, got %s", pemType, algorithm)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported privateKeyPEMType %q, must be one of: pkcs8, pkcs1, sec1", pemType)
+	}
+}
+
+// EncoderOption configures a KeyEncoder.
+type EncoderOption func(*encoderOptions)
+
+type encoderOptions struct {
+	privateKeyPEMType string
+	indent            bool
+	thumbprintKID     bool
+	kid               string
+}
+
+// WithPrivateKeyPEMType selects the PEM structure for private keys (PEM encoding only).
+func WithPrivateKeyPEMType(pemType string) EncoderOption {
+	return func(o *encoderOptions) {
+		o.privateKeyPEMType = pemType
+	}
+}
+
+// WithIndent emits indented JSON instead of compact JSON (JWK encoding and EncodeJWKS only).
+func WithIndent(indent bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.indent = indent
+	}
+}
+
+// WithThumbprintKID sets "kid" to the RFC 7638 thumbprint of the key
+// (JWK encoding and EncodeJWKS only), so consumers can derive it from the
+// public key alone. EncodeJWKS otherwise uses the KeyID.
+func WithThumbprintKID(enabled bool) EncoderOption {
+	return func(o *encoderOptions) {
+		o.thumbprintKID = enabled
+	}
+}
+
+// WithKeyID sets "kid" to kid (PublicJWK only); WithThumbprintKID takes
+// precedence. Empty omits "kid".
+func WithKeyID(kid string) EncoderOption {
+	return func(o *encoderOptions) {
+		o.kid = kid
+	}
 }
 
 // NewKeyEncoder creates a KeyEncoder for the given encoding format.
-func NewKeyEncoder(encoding string) (KeyEncoder, error) {
+func NewKeyEncoder(encoding string, opts ...EncoderOption) (KeyEncoder, error) {
+	o := encoderOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	switch encoding {
 	case "PEM":
-		return &pemEncoder{}, nil
+		return &pemEncoder{privateKeyPEMType: o.privateKeyPEMType}, nil
 	case "DER":
 		return &derEncoder{}, nil
 	case "JWK":
-		return &jwkEncoder{}, nil
+		return &jwkEncoder{indent: o.indent, thumbprintKID: o.thumbprintKID}, nil
+	case EncodingECCompressed:
+		return &ecCompressedEncoder{}, nil
+	case EncodingSPKI:
+		return &derEncoder{}, nil
+	case EncodingRawPublic:
+		return &rawPublicEncoder{}, nil
 	default:
 		return nil, fmt.Errorf("unsupported encoding: %s", encoding)
 	}
@@ -53,23 +145,45 @@ func NewKeyEncoder(encoding string) (KeyEncoder, error) {
 
 // --- PEM Encoder ---
 
-type pemEncoder struct{}
+type pemEncoder struct {
+	privateKeyPEMType string
+}
 
 func (e *pemEncoder) EncodePrivate(key crypto.PrivateKey) ([]byte, error) {
-	derBytes, err := x509.MarshalPKCS8PrivateKey(key)
-	if err != nil {
-		return nil, fmt.Errorf("marshal private key to PKCS8: %w", err)
-	}
+	switch e.privateKeyPEMType {
+	case "", PrivateKeyPEMTypePKCS8:
+		derBytes, err := marshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("marshal private key to PKCS8: %w", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: derBytes}), nil
+
+	case PrivateKeyPEMTypePKCS1:
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("PKCS1 requires an RSA private key, got %T", key)
+		}
+		derBytes := x509.MarshalPKCS1PrivateKey(rsaKey)
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: derBytes}), nil
+
+	case PrivateKeyPEMTypeSEC1:
+		ecKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("SEC1 requires an EC private key, got %T", key)
+		}
+		derBytes, err := marshalECPrivateKey(ecKey)
+		if err != nil {
+			return nil, fmt.Errorf("marshal private key to SEC1: %w", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: derBytes}), nil
 
-	block := &pem.Block{
-		Type:  "PRIVATE KEY",
-		Bytes: derBytes,
+	default:
+		return nil, fmt.Errorf("unsupported private key PEM type: %s", e.privateKeyPEMType)
 	}
-	return pem.EncodeToMemory(block), nil
 }
 
 func (e *pemEncoder) EncodePublic(key crypto.PublicKey) ([]byte, error) {
-	derBytes, err := x509.MarshalPKIXPublicKey(key)
+	derBytes, err := marshalPKIXPublicKey(key)
 	if err != nil {
 		return nil, fmt.Errorf("marshal public key to PKIX: %w", err)
 	}
@@ -81,12 +195,16 @@ func (e *pemEncoder) EncodePublic(key crypto.PublicKey) ([]byte, error) {
 	return pem.EncodeToMemory(block), nil
 }
 
+func (e *pemEncoder) EncodePublicWithKID(key crypto.PublicKey, _ string) ([]byte, error) {
+	return e.EncodePublic(key)
+}
+
 // --- DER Encoder ---
 
 type derEncoder struct{}
 
 func (e *derEncoder) EncodePrivate(key crypto.PrivateKey) ([]byte, error) {
-	derBytes, err := x509.MarshalPKCS8PrivateKey(key)
+	derBytes, err := marshalPKCS8PrivateKey(key)
 	if err != nil {
 		return nil, fmt.Errorf("marshal private key to PKCS8 DER: %w", err)
 	}
@@ -94,16 +212,84 @@ func (e *derEncoder) EncodePrivate(key crypto.PrivateKey) ([]byte, error) {
 }
 
 func (e *derEncoder) EncodePublic(key crypto.PublicKey) ([]byte, error) {
-	derBytes, err := x509.MarshalPKIXPublicKey(key)
+	derBytes, err := marshalPKIXPublicKey(key)
 	if err != nil {
 		return nil, fmt.Errorf("marshal public key to PKIX DER: %w", err)
 	}
 	return derBytes, nil
 }
 
+func (e *derEncoder) EncodePublicWithKID(key crypto.PublicKey, _ string) ([]byte, error) {
+	return e.EncodePublic(key)
+}
+
+// Public-key wrapping encodings. EncodingSPKI is the full SubjectPublicKeyInfo
+// DER, identical to "DER" for public keys. EncodingRawPublic is only the
+// subjectPublicKey BIT STRING contents: the PKCS#1 RSAPublicKey (modulus and
+// exponent) for RSA, the uncompressed point for EC, the 32 key bytes for Ed25519.
+const (
+	EncodingSPKI      = "spki"
+	EncodingRawPublic = "raw-public"
+)
+
+// --- Raw Public Key Encoder ---
+
+type rawPublicEncoder struct{}
+
+func (e *rawPublicEncoder) EncodePrivate(crypto.PrivateKey) ([]byte, error) {
+	return nil, fmt.Errorf("%s encoding supports public keys only", EncodingRawPublic)
+}
+
+func (e *rawPublicEncoder) EncodePublic(key crypto.PublicKey) ([]byte, error) {
+	derBytes, err := marshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("marshal public key to PKIX DER: %w", err)
+	}
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(derBytes, &spki); err != nil {
+		return nil, fmt.Errorf("parse SubjectPublicKeyInfo: %w", err)
+	}
+	return spki.PublicKey.RightAlign(), nil
+}
+
+func (e *rawPublicEncoder) EncodePublicWithKID(key crypto.PublicKey, _ string) ([]byte, error) {
+	return e.EncodePublic(key)
+}
+
+// --- EC Compressed Point Encoder ---
+
+// EncodingECCompressed emits an EC public key as a bare SEC1 compressed point
+// (0x02/0x03 prefix + X coordinate), as expected by some embedded validators.
+// It carries no curve identifier and cannot encode private keys.
+const EncodingECCompressed = "ec-compressed"
+
+type ecCompressedEncoder struct{}
+
+func (e *ecCompressedEncoder) EncodePrivate(crypto.PrivateKey) ([]byte, error) {
+	return nil, fmt.Errorf("%s encoding supports public keys only", EncodingECCompressed)
+}
+
+func (e *ecCompressedEncoder) EncodePublic(key crypto.PublicKey) ([]byte, error) {
+	pub, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s encoding requires an EC key, got %T", EncodingECCompressed, key)
+	}
+	return elliptic.MarshalCompressed(pub.Curve, pub.X, pub.Y), nil
+}
+
+func (e *ecCompressedEncoder) EncodePublicWithKID(key crypto.PublicKey, _ string) ([]byte, error) {
+	return e.EncodePublic(key)
+}
+
 // --- JWK Encoder ---
 
-type jwkEncoder struct{}
+type jwkEncoder struct {
+	indent        bool
+	thumbprintKID bool
+}
 
 // jwk represents a JSON Web Key (RFC 7517).
 type jwk struct {
@@ -123,97 +309,237 @@ type jwk struct {
 	X   *string `json:"x,omitempty"`
 	Y   *string `json:"y,omitempty"`
 	// EC private: D reused
+
+	// OKP (RFC 8037): Crv, X and D reused, X and D hold the raw key bytes
+}
+
+// JWK values for Ed25519 keys (RFC 8037 §2).
+const (
+	jwkKtyOKP     = "OKP"
+	jwkCrvEd25519 = "Ed25519"
+)
+
+// jwkSet represents a JSON Web Key Set (RFC 7517 §5).
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
 }
 
 func (e *jwkEncoder) EncodePrivate(key crypto.PrivateKey) ([]byte, error) {
+	var j jwk
+	var err error
 	switch k := key.(type) {
 	case *ecdsa.PrivateKey:
-		return encodeECPrivateJWK(k)
+		j, err = ecPrivateJWK(k)
 	case *rsa.PrivateKey:
-		return encodeRSAPrivateJWK(k)
+		j = rsaPrivateJWK(k)
+	case ed25519.PrivateKey:
+		j, err = ed25519PrivateJWK(k)
 	default:
 		return nil, fmt.Errorf("unsupported key type for JWK: %T", key)
 	}
+	if err != nil {
+		return nil, err
+	}
+	if e.thumbprintKID {
+		if j.Kid, err = jwkThumbprint(j); err != nil {
+			return nil, err
+		}
+	}
+	return marshalJSON(j, e.indent)
 }
 
 func (e *jwkEncoder) EncodePublic(key crypto.PublicKey) ([]byte, error) {
+	return e.EncodePublicWithKID(key, "")
+}
+
+func (e *jwkEncoder) EncodePublicWithKID(key crypto.PublicKey, kid string) ([]byte, error) {
+	return PublicJWK(key, WithIndent(e.indent), WithThumbprintKID(e.thumbprintKID), WithKeyID(kid))
+}
+
+// PublicJWK encodes the public half of key as a JWK. A private key is reduced
+// to its public key first, and the result is checked to carry no private
+// members (d, p, q), so this path never emits private material whatever
+// KeySpec.Encoding the Secret uses. Only WithIndent, WithThumbprintKID and
+// WithKeyID are honored among opts. [SEC:S-2]
+func PublicJWK(key any, opts ...EncoderOption) ([]byte, error) {
+	o := encoderOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if signer, ok := key.(crypto.Signer); ok {
+		key = signer.Public()
+	}
+	j, err := publicJWK(key)
+	if err != nil {
+		return nil, err
+	}
+	if j.D != nil || j.P != nil || j.Q != nil {
+		return nil, fmt.Errorf("refusing to encode private JWK members as a public key")
+	}
+	j.Kid = o.kid
+	if o.thumbprintKID {
+		if j.Kid, err = jwkThumbprint(j); err != nil {
+			return nil, err
+		}
+	}
+	return marshalJSON(j, o.indent)
+}
+
+// JWKThumbprint computes the RFC 7638 JWK thumbprint of a public key, the
+// fingerprint scheme used by JOSE tooling: base64url(SHA-256) of the key's
+// required JWK members ("crv","kty","x","y" for EC, "e","kty","n" for RSA) in
+// canonical form. EC coordinates are padded to the curve's byte length as in
+// JWK output, so the thumbprint matches published JWKs. Unlike a Fingerprint
+// it carries no algorithm prefix.
+func JWKThumbprint(pubKey crypto.PublicKey) (string, error) {
+	if pubKey == nil {
+		return "", fmt.Errorf("cannot compute JWK thumbprint: public key is nil")
+	}
+	j, err := publicJWK(pubKey)
+	if err != nil {
+		return "", err
+	}
+	return jwkThumbprint(j)
+}
+
+// jwkThumbprint hashes the required members of j, ordered lexicographically
+// and without whitespace (RFC 7638 §3.2). The struct field order is the member order.
+func jwkThumbprint(j jwk) (string, error) {
+	var members any
+	switch j.Kty {
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{*j.Crv, j.Kty, *j.X, *j.Y}
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{*j.E, j.Kty, *j.N}
+	case jwkKtyOKP:
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{*j.Crv, j.Kty, *j.X}
+	default:
+		return "", fmt.Errorf("unsupported key type for JWK thumbprint: %s", j.Kty)
+	}
+	canonical, err := json.Marshal(members)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal JWK thumbprint input: %w", err)
+	}
+	sum := sha256.Sum256(canonical)
+	return base64Url(sum[:]), nil
+}
+
+// EncodeJWKS encodes public keys as a JWK Set, using each KeyID as "kid", or the
+// RFC 7638 thumbprint with WithThumbprintKID. Keys are sorted by kid so the same
+// key set always yields identical bytes, keeping GitOps diffs clean.
+// Only WithIndent and WithThumbprintKID are honored among opts.
+func EncodeJWKS(keys []*PublicKeyInfo, opts ...EncoderOption) ([]byte, error) {
+	o := encoderOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	set := jwkSet{Keys: make([]jwk, 0, len(keys))}
+	for _, k := range keys {
+		if k == nil {
+			return nil, fmt.Errorf("cannot encode nil public key in JWKS")
+		}
+		j, err := publicJWK(k.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", k.KeyID, err)
+		}
+		j.Kid = k.KeyID
+		if o.thumbprintKID {
+			if j.Kid, err = jwkThumbprint(j); err != nil {
+				return nil, fmt.Errorf("key %s: %w", k.KeyID, err)
+			}
+		}
+		set.Keys = append(set.Keys, j)
+	}
+	sort.SliceStable(set.Keys, func(a, b int) bool {
+		return set.Keys[a].Kid < set.Keys[b].Kid
+	})
+
+	return marshalJSON(set, o.indent)
+}
+
+// marshalJSON marshals v compactly or with two-space indentation.
+func marshalJSON(v any, indent bool) ([]byte, error) {
+	if indent {
+		return json.MarshalIndent(v, "", "  ")
+	}
+	return json.Marshal(v)
+}
+
+func publicJWK(key crypto.PublicKey) (jwk, error) {
 	switch k := key.(type) {
 	case *ecdsa.PublicKey:
-		return encodeECPublicJWK(k)
+		return ecPublicJWK(k)
 	case *rsa.PublicKey:
-		return encodeRSAPublicJWK(k)
+		return rsaPublicJWK(k), nil
+	case ed25519.PublicKey:
+		return ed25519PublicJWK(k)
 	default:
-		return nil, fmt.Errorf("unsupported key type for JWK: %T", key)
+		return jwk{}, fmt.Errorf("unsupported key type for JWK: %T", key)
 	}
 }
 
-func encodeECPublicJWK(pub *ecdsa.PublicKey) ([]byte, error) {
+func ecPublicJWK(pub *ecdsa.PublicKey) (jwk, error) {
 	crv := curveName(pub.Curve)
 	if crv == "" {
-		return nil, fmt.Errorf("unsupported EC curve for JWK")
+		return jwk{}, fmt.Errorf("unsupported EC curve for JWK")
 	}
 
 	byteLen := (pub.Curve.Params().BitSize + 7) / 8
 	x := base64Url(padLeft(pub.X.Bytes(), byteLen))
 	y := base64Url(padLeft(pub.Y.Bytes(), byteLen))
 
-	j := jwk{
+	return jwk{
 		Kty: "EC",
 		Use: "sig",
 		Crv: &crv,
 		X:   &x,
 		Y:   &y,
-	}
-	return json.Marshal(j)
+	}, nil
 }
 
-func encodeECPrivateJWK(priv *ecdsa.PrivateKey) ([]byte, error) {
-	crv := curveName(priv.Curve)
-	if crv == "" {
-		return nil, fmt.Errorf("unsupported EC curve for JWK")
+func ecPrivateJWK(priv *ecdsa.PrivateKey) (jwk, error) {
+	j, err := ecPublicJWK(&priv.PublicKey)
+	if err != nil {
+		return jwk{}, err
 	}
 
 	byteLen := (priv.Curve.Params().BitSize + 7) / 8
-	x := base64Url(padLeft(priv.X.Bytes(), byteLen))
-	y := base64Url(padLeft(priv.Y.Bytes(), byteLen))
 	d := base64Url(padLeft(priv.D.Bytes(), byteLen))
-
-	j := jwk{
-		Kty: "EC",
-		Use: "sig",
-		Crv: &crv,
-		X:   &x,
-		Y:   &y,
-		D:   &d,
-	}
-	return json.Marshal(j)
+	j.D = &d
+	return j, nil
 }
 
-func encodeRSAPublicJWK(pub *rsa.PublicKey) ([]byte, error) {
+func rsaPublicJWK(pub *rsa.PublicKey) jwk {
 	n := base64Url(pub.N.Bytes())
 	e := base64Url(big.NewInt(int64(pub.E)).Bytes())
 
-	j := jwk{
+	return jwk{
 		Kty: "RSA",
 		Use: "sig",
 		N:   &n,
 		E:   &e,
 	}
-	return json.Marshal(j)
 }
 
-func encodeRSAPrivateJWK(priv *rsa.PrivateKey) ([]byte, error) {
-	n := base64Url(priv.N.Bytes())
-	e := base64Url(big.NewInt(int64(priv.E)).Bytes())
+func rsaPrivateJWK(priv *rsa.PrivateKey) jwk {
+	j := rsaPublicJWK(&priv.PublicKey)
 	d := base64Url(priv.D.Bytes())
-
-	j := jwk{
-		Kty: "RSA",
-		Use: "sig",
-		N:   &n,
-		E:   &e,
-		D:   &d,
-	}
+	j.D = &d
 
 	if len(priv.Primes) >= 2 {
 		p := base64Url(priv.Primes[0].Bytes())
@@ -222,20 +548,37 @@ func encodeRSAPrivateJWK(priv *rsa.PrivateKey) ([]byte, error) {
 		j.Q = &q
 	}
 
-	return json.Marshal(j)
+	return j
 }
 
-func curveName(curve elliptic.Curve) string {
-	switch curve {
-	case elliptic.P256():
-		return "P-256"
-	case elliptic.P384():
-		return "P-384"
-	case elliptic.P521():
-		return "P-521"
-	default:
-		return ""
+func ed25519PublicJWK(pub ed25519.PublicKey) (jwk, error) {
+	if len(pub) != ed25519.PublicKeySize {
+		return jwk{}, fmt.Errorf("invalid Ed25519 public key length %d", len(pub))
+	}
+	crv := jwkCrvEd25519
+	x := base64Url(pub)
+
+	return jwk{
+		Kty: jwkKtyOKP,
+		Use: "sig",
+		Crv: &crv,
+		X:   &x,
+	}, nil
+}
+
+func ed25519PrivateJWK(priv ed25519.PrivateKey) (jwk, error) {
+	if len(priv) != ed25519.PrivateKeySize {
+		return jwk{}, fmt.Errorf("invalid Ed25519 private key length %d", len(priv))
+	}
+	j, err := ed25519PublicJWK(priv.Public().(ed25519.PublicKey))
+	if err != nil {
+		return jwk{}, err
 	}
+
+	// "d" is the 32-byte seed, not Go's seed||public expanded form
+	d := base64Url(priv.Seed())
+	j.D = &d
+	return j, nil
 }
 
 func base64Url(data []byte) string {
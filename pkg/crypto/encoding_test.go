@@ -0,0 +1,626 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"slices"
+	"testing"
+)
+
+func TestPEMPrivateKeyTypesRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		opts      GenerateOptions
+		pemType   string
+		wantBlock string
+		parse     func([]byte) (crypto.PrivateKey, error)
+	}{
+		{
+			name:      "EC pkcs8",
+			opts:      GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP256}},
+			pemType:   PrivateKeyPEMTypePKCS8,
+			wantBlock: "PRIVATE KEY",
+			parse:     func(der []byte) (crypto.PrivateKey, error) { return x509.ParsePKCS8PrivateKey(der) },
+		},
+		{
+			name:      "EC sec1",
+			opts:      GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP384}},
+			pemType:   PrivateKeyPEMTypeSEC1,
+			wantBlock: "EC PRIVATE KEY",
+			parse:     func(der []byte) (crypto.PrivateKey, error) { return x509.ParseECPrivateKey(der) },
+		},
+		{
+			name:      "RSA pkcs8",
+			opts:      GenerateOptions{Algorithm: AlgorithmRSA, Params: map[string]string{"keySize": "3072"}},
+			pemType:   PrivateKeyPEMTypePKCS8,
+			wantBlock: "PRIVATE KEY",
+			parse:     func(der []byte) (crypto.PrivateKey, error) { return x509.ParsePKCS8PrivateKey(der) },
+		},
+		{
+			name:      "RSA pkcs1",
+			opts:      GenerateOptions{Algorithm: AlgorithmRSA, Params: map[string]string{"keySize": "3072"}},
+			pemType:   PrivateKeyPEMTypePKCS1,
+			wantBlock: "RSA PRIVATE KEY",
+			parse:     func(der []byte) (crypto.PrivateKey, error) { return x509.ParsePKCS1PrivateKey(der) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			kp, err := NewKeyGenerator().Generate(tt.opts)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			defer kp.Wipe()
+
+			if err := ValidatePrivateKeyPEMType(tt.opts.Algorithm, tt.pemType); err != nil {
+				t.Fatalf("ValidatePrivateKeyPEMType() error = %v", err)
+			}
+
+			encoder, err := NewKeyEncoder("PEM", WithPrivateKeyPEMType(tt.pemType))
+			if err != nil {
+				t.Fatalf("NewKeyEncoder() error = %v", err)
+			}
+			encoded, err := encoder.EncodePrivate(kp.PrivateKey)
+			if err != nil {
+				t.Fatalf("EncodePrivate() error = %v", err)
+			}
+
+			block, _ := pem.Decode(encoded)
+			if block == nil || block.Type != tt.wantBlock {
+				t.Fatalf("PEM block = %v, want type %q", block, tt.wantBlock)
+			}
+			parsed, err := tt.parse(block.Bytes)
+			if err != nil {
+				t.Fatalf("parse error = %v", err)
+			}
+
+			type equaler interface {
+				Equal(crypto.PrivateKey) bool
+			}
+			if !parsed.(equaler).Equal(kp.PrivateKey) {
+				t.Errorf("round-tripped key does not match original")
+			}
+		})
+	}
+}
+
+func TestPEMPrivateKeyTypeMismatch(t *testing.T) {
+	t.Parallel()
+
+	if err := ValidatePrivateKeyPEMType(AlgorithmEC, PrivateKeyPEMTypePKCS1); err == nil {
+		t.Error("ValidatePrivateKeyPEMType(EC, pkcs1) succeeded, want error")
+	}
+	if err := ValidatePrivateKeyPEMType(AlgorithmRSA, PrivateKeyPEMTypeSEC1); err == nil {
+		t.Error("ValidatePrivateKeyPEMType(RSA, sec1) succeeded, want error")
+	}
+	if err := ValidatePrivateKeyPEMType(AlgorithmRSA, "pkcs12"); err == nil {
+		t.Error("ValidatePrivateKeyPEMType(RSA, pkcs12) succeeded, want error")
+	}
+
+	encoder, err := NewKeyEncoder("PEM", WithPrivateKeyPEMType(PrivateKeyPEMTypePKCS1))
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	if _, err := encoder.EncodePrivate(&ecdsa.PrivateKey{}); err == nil {
+		t.Error("EncodePrivate(EC) with pkcs1 succeeded, want error")
+	}
+	encoder, err = NewKeyEncoder("PEM", WithPrivateKeyPEMType(PrivateKeyPEMTypeSEC1))
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	if _, err := encoder.EncodePrivate(&rsa.PrivateKey{}); err == nil {
+		t.Error("EncodePrivate(RSA) with sec1 succeeded, want error")
+	}
+}
+
+func TestJWKIndent(t *testing.T) {
+	t.Parallel()
+
+	kp, err := NewKeyGenerator().Generate(GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP256}})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer kp.Wipe()
+
+	compactEnc, err := NewKeyEncoder("JWK")
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	indentEnc, err := NewKeyEncoder("JWK", WithIndent(true))
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+
+	compact, err := compactEnc.EncodePublic(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("EncodePublic() error = %v", err)
+	}
+	indented, err := indentEnc.EncodePublic(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("EncodePublic() error = %v", err)
+	}
+
+	if bytes.Contains(compact, []byte("\n")) {
+		t.Errorf("compact JWK contains newlines: %s", compact)
+	}
+	if !bytes.Contains(indented, []byte("\n  \"kty\": \"EC\"")) {
+		t.Errorf("indented JWK not indented: %s", indented)
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, indented); err != nil {
+		t.Fatalf("json.Compact() error = %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), compact) {
+		t.Errorf("indented JWK differs from compact JWK in content:\n%s\n%s", buf.Bytes(), compact)
+	}
+}
+
+func TestECCompressedEncoding(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		curve   string
+		wantLen int
+	}{
+		{curve: CurveP256, wantLen: 33},
+		{curve: CurveP384, wantLen: 49},
+		{curve: CurveP521, wantLen: 67},
+	}
+	enc, err := NewKeyEncoder(EncodingECCompressed)
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.curve, func(t *testing.T) {
+			t.Parallel()
+			kp, err := NewKeyGenerator().Generate(GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": tt.curve}})
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			t.Cleanup(kp.Wipe)
+
+			point, err := enc.EncodePublic(kp.PublicKey)
+			if err != nil {
+				t.Fatalf("EncodePublic() error = %v", err)
+			}
+			if len(point) != tt.wantLen {
+				t.Errorf("len = %d, want %d", len(point), tt.wantLen)
+			}
+			pub := kp.PublicKey.(*ecdsa.PublicKey)
+			wantPrefix := byte(0x02) | byte(pub.Y.Bit(0))
+			if point[0] != wantPrefix {
+				t.Errorf("prefix = %#x, want %#x", point[0], wantPrefix)
+			}
+			x, y := elliptic.UnmarshalCompressed(pub.Curve, point)
+			if x == nil || x.Cmp(pub.X) != 0 || y.Cmp(pub.Y) != 0 {
+				t.Error("compressed point does not decode to the public key")
+			}
+		})
+	}
+
+	rsaKey, err := NewKeyGenerator().Generate(GenerateOptions{Algorithm: AlgorithmRSA, Params: map[string]string{"keySize": "2048"}, AllowLegacyKeySize: true})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer rsaKey.Wipe()
+	if _, err := enc.EncodePublic(rsaKey.PublicKey); err == nil {
+		t.Error("EncodePublic(RSA) succeeded, want error")
+	}
+}
+
+func TestPublicKeyWrapping(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		opts     GenerateOptions
+		checkRaw func(t *testing.T, pub crypto.PublicKey, raw []byte)
+	}{
+		{
+			name: "RSA",
+			opts: GenerateOptions{Algorithm: AlgorithmRSA, Params: map[string]string{"keySize": "2048"}, AllowLegacyKeySize: true},
+			checkRaw: func(t *testing.T, pub crypto.PublicKey, raw []byte) {
+				// PKCS#1 RSAPublicKey: SEQUENCE { modulus, publicExponent }
+				got, err := x509.ParsePKCS1PublicKey(raw)
+				if err != nil {
+					t.Fatalf("ParsePKCS1PublicKey() error = %v", err)
+				}
+				if !got.Equal(pub) {
+					t.Error("raw RSA key does not match the public key")
+				}
+			},
+		},
+		{
+			name: "EC",
+			opts: GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP256}},
+			checkRaw: func(t *testing.T, pub crypto.PublicKey, raw []byte) {
+				// Uncompressed point: 0x04 || X || Y
+				if len(raw) != 65 || raw[0] != 0x04 {
+					t.Fatalf("raw EC key = %d bytes with prefix %#x, want 65 bytes with prefix 0x04", len(raw), raw[0])
+				}
+				ecdhPub, err := pub.(*ecdsa.PublicKey).ECDH()
+				if err != nil {
+					t.Fatalf("ECDH() error = %v", err)
+				}
+				if !bytes.Equal(raw, ecdhPub.Bytes()) {
+					t.Error("raw EC point does not match the public key")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			kp, err := NewKeyGenerator().Generate(tt.opts)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			t.Cleanup(kp.Wipe)
+
+			spkiEnc, err := NewKeyEncoder(EncodingSPKI)
+			if err != nil {
+				t.Fatalf("NewKeyEncoder() error = %v", err)
+			}
+			spki, err := spkiEnc.EncodePublic(kp.PublicKey)
+			if err != nil {
+				t.Fatalf("EncodePublic(spki) error = %v", err)
+			}
+			want, err := x509.MarshalPKIXPublicKey(kp.PublicKey)
+			if err != nil {
+				t.Fatalf("MarshalPKIXPublicKey() error = %v", err)
+			}
+			if !bytes.Equal(spki, want) {
+				t.Error("spki encoding differs from PKIX DER")
+			}
+
+			rawEnc, err := NewKeyEncoder(EncodingRawPublic)
+			if err != nil {
+				t.Fatalf("NewKeyEncoder() error = %v", err)
+			}
+			raw, err := rawEnc.EncodePublic(kp.PublicKey)
+			if err != nil {
+				t.Fatalf("EncodePublic(raw-public) error = %v", err)
+			}
+			if !bytes.HasSuffix(spki, raw) {
+				t.Error("raw key is not the trailing subjectPublicKey of the SPKI")
+			}
+			tt.checkRaw(t, kp.PublicKey, raw)
+		})
+	}
+}
+
+func TestEncodeJWKSDeterministic(t *testing.T) {
+	t.Parallel()
+
+	var keys []*PublicKeyInfo
+	for _, opts := range []GenerateOptions{
+		{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP256}},
+		{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP384}},
+		{Algorithm: AlgorithmRSA, Params: map[string]string{"keySize": "3072"}},
+	} {
+		kp, err := NewKeyGenerator().Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		keys = append(keys, kp.Public())
+		kp.Wipe()
+	}
+	reversed := []*PublicKeyInfo{keys[2], keys[1], keys[0]}
+
+	for _, indent := range []bool{false, true} {
+		first, err := EncodeJWKS(keys, WithIndent(indent))
+		if err != nil {
+			t.Fatalf("EncodeJWKS() error = %v", err)
+		}
+		for range 3 {
+			again, err := EncodeJWKS(reversed, WithIndent(indent))
+			if err != nil {
+				t.Fatalf("EncodeJWKS() error = %v", err)
+			}
+			if !bytes.Equal(first, again) {
+				t.Fatalf("EncodeJWKS(indent=%v) not deterministic:\n%s\n%s", indent, first, again)
+			}
+		}
+
+		var set struct {
+			Keys []struct {
+				Kid string `json:"kid"`
+			} `json:"keys"`
+		}
+		if err := json.Unmarshal(first, &set); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		if len(set.Keys) != len(keys) {
+			t.Fatalf("JWKS has %d keys, want %d", len(set.Keys), len(keys))
+		}
+		for i := 1; i < len(set.Keys); i++ {
+			if set.Keys[i-1].Kid > set.Keys[i].Kid {
+				t.Errorf("JWKS keys not sorted by kid: %q before %q", set.Keys[i-1].Kid, set.Keys[i].Kid)
+			}
+		}
+
+		// ParseJWKS reads every key back under its kid
+		parsed, err := ParseJWKS(first)
+		if err != nil {
+			t.Fatalf("ParseJWKS() error = %v", err)
+		}
+		for _, want := range keys {
+			i := slices.IndexFunc(parsed, func(k *PublicKeyInfo) bool { return k.KeyID == want.KeyID })
+			if i < 0 {
+				t.Errorf("ParseJWKS() is missing %s", want.KeyID)
+				continue
+			}
+			pub, ok := parsed[i].PublicKey.(interface{ Equal(crypto.PublicKey) bool })
+			if !ok || !pub.Equal(want.PublicKey) {
+				t.Errorf("ParseJWKS() key %s does not match the encoded key", want.KeyID)
+			}
+			if parsed[i].Algorithm != want.Algorithm {
+				t.Errorf("ParseJWKS() algorithm = %s, want %s", parsed[i].Algorithm, want.Algorithm)
+			}
+		}
+	}
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	t.Parallel()
+
+	// RFC 7638 §3.1 example key and thumbprint
+	n, err := base64.RawURLEncoding.DecodeString("0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4" +
+		"cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v" +
+		"-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4l" +
+		"Fd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw")
+	if err != nil {
+		t.Fatalf("decode modulus: %v", err)
+	}
+	rsaKey := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: 65537}
+	const want = "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"
+
+	got, err := JWKThumbprint(rsaKey)
+	if err != nil {
+		t.Fatalf("JWKThumbprint() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("JWKThumbprint() = %s, want %s", got, want)
+	}
+
+	// The thumbprint option sets kid in JWK output
+	enc, err := NewKeyEncoder("JWK", WithThumbprintKID(true))
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	out, err := enc.EncodePublic(rsaKey)
+	if err != nil {
+		t.Fatalf("EncodePublic() error = %v", err)
+	}
+	var j struct {
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(out, &j); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if j.Kid != want {
+		t.Errorf("kid = %q, want %s", j.Kid, want)
+	}
+
+	// EC: hash of the canonical {"crv","kty","x","y"} members
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	x, y := base64.RawURLEncoding.EncodeToString(ecKey.X.FillBytes(make([]byte, 32))),
+		base64.RawURLEncoding.EncodeToString(ecKey.Y.FillBytes(make([]byte, 32)))
+	sum := sha256.Sum256([]byte(`{"crv":"P-256","kty":"EC","x":"` + x + `","y":"` + y + `"}`))
+	got, err = JWKThumbprint(&ecKey.PublicKey)
+	if err != nil {
+		t.Fatalf("JWKThumbprint() error = %v", err)
+	}
+	if want := base64.RawURLEncoding.EncodeToString(sum[:]); got != want {
+		t.Errorf("EC JWKThumbprint() = %s, want %s", got, want)
+	}
+
+	if _, err := JWKThumbprint(struct{}{}); err == nil {
+		t.Error("JWKThumbprint() accepted an unsupported key type")
+	}
+}
+
+func TestEncodePublicWithKID(t *testing.T) {
+	t.Parallel()
+
+	kp, err := NewKeyGenerator().Generate(GenerateOptions{
+		Algorithm: AlgorithmEC,
+		Params:    map[string]string{"curve": CurveP256},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer kp.Wipe()
+
+	kidOf := func(enc KeyEncoder, kid string) string {
+		t.Helper()
+		out, err := enc.EncodePublicWithKID(kp.PublicKey, kid)
+		if err != nil {
+			t.Fatalf("EncodePublicWithKID() error = %v", err)
+		}
+		var j map[string]any
+		if err := json.Unmarshal(out, &j); err != nil {
+			t.Fatalf("json.Unmarshal() error = %v", err)
+		}
+		got, _ := j["kid"].(string)
+		return got
+	}
+	enc, err := NewKeyEncoder("JWK")
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	if got := kidOf(enc, kp.KeyID); got != kp.KeyID {
+		t.Errorf("kid = %q, want %s", got, kp.KeyID)
+	}
+	if got := kidOf(enc, ""); got != "" {
+		t.Errorf("kid = %q, want it omitted for an empty key ID", got)
+	}
+	thumbprintEnc, err := NewKeyEncoder("JWK", WithThumbprintKID(true))
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	thumbprint, err := JWKThumbprint(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("JWKThumbprint() error = %v", err)
+	}
+	if got := kidOf(thumbprintEnc, kp.KeyID); got != thumbprint {
+		t.Errorf("kid = %q, want the thumbprint %s to take precedence", got, thumbprint)
+	}
+
+	// Encodings without a key ID member ignore it
+	pemEnc, err := NewKeyEncoder("PEM")
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	withKID, err := pemEnc.EncodePublicWithKID(kp.PublicKey, kp.KeyID)
+	if err != nil {
+		t.Fatalf("EncodePublicWithKID(PEM) error = %v", err)
+	}
+	without, err := pemEnc.EncodePublic(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("EncodePublic(PEM) error = %v", err)
+	}
+	if !bytes.Equal(withKID, without) {
+		t.Error("EncodePublicWithKID(PEM) differs from EncodePublic")
+	}
+}
+
+func TestEd25519JWK(t *testing.T) {
+	t.Parallel()
+
+	// RFC 8037 Appendix A.1 key and A.3 thumbprint
+	const (
+		d              = "nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A"
+		x              = "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"
+		wantThumbprint = "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"
+	)
+	seed, err := base64.RawURLEncoding.DecodeString(d)
+	if err != nil {
+		t.Fatalf("decode seed: %v", err)
+	}
+	priv := ed25519.NewKeyFromSeed(seed)
+
+	enc, err := NewKeyEncoder("JWK")
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	out, err := enc.EncodePrivate(priv)
+	if err != nil {
+		t.Fatalf("EncodePrivate() error = %v", err)
+	}
+	var j map[string]string
+	if err := json.Unmarshal(out, &j); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if j["kty"] != "OKP" || j["crv"] != "Ed25519" || j["x"] != x || j["d"] != d {
+		t.Errorf("private JWK = %s, want kty OKP, crv Ed25519, x %s, d %s", out, x, d)
+	}
+
+	pubOut, err := enc.EncodePublic(priv.Public())
+	if err != nil {
+		t.Fatalf("EncodePublic() error = %v", err)
+	}
+	if bytes.Contains(pubOut, []byte(`"d"`)) {
+		t.Errorf("public JWK = %s, contains private member d", pubOut)
+	}
+	got, err := JWKThumbprint(priv.Public())
+	if err != nil {
+		t.Fatalf("JWKThumbprint() error = %v", err)
+	}
+	if got != wantThumbprint {
+		t.Errorf("JWKThumbprint() = %s, want %s", got, wantThumbprint)
+	}
+
+	// A "d" that does not match "x" is rejected
+	other := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	mismatched := []byte(`{"kty":"OKP","crv":"Ed25519","x":"` + x + `","d":"` +
+		base64.RawURLEncoding.EncodeToString(other.Seed()) + `"}`)
+	if _, err := Convert(mismatched, "JWK", "PEM", true); err == nil {
+		t.Error("Convert() accepted an Ed25519 JWK with mismatched d and x")
+	}
+}
+
+func TestPublicJWKOmitsPrivateMembers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts GenerateOptions
+	}{
+		{name: "EC", opts: GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP256}}},
+		{name: "RSA", opts: GenerateOptions{Algorithm: AlgorithmRSA, Params: map[string]string{"keySize": "2048"}, AllowLegacyKeySize: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			kp, err := NewKeyGenerator().Generate(tt.opts)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			fromPublic, err := PublicJWK(kp.PublicKey)
+			if err != nil {
+				t.Fatalf("PublicJWK(public) error = %v", err)
+			}
+			// A private key handed in by mistake is stripped to its public half
+			fromPrivate, err := PublicJWK(kp.PrivateKey)
+			if err != nil {
+				t.Fatalf("PublicJWK(private) error = %v", err)
+			}
+			if !bytes.Equal(fromPublic, fromPrivate) {
+				t.Errorf("PublicJWK(private) = %s, want %s", fromPrivate, fromPublic)
+			}
+
+			encoder, _ := NewKeyEncoder("JWK", WithThumbprintKID(true))
+			viaEncoder, err := encoder.EncodePublic(kp.PrivateKey)
+			if err != nil {
+				t.Fatalf("EncodePublic(private) error = %v", err)
+			}
+			for _, out := range [][]byte{fromPublic, fromPrivate, viaEncoder} {
+				var members map[string]any
+				if err := json.Unmarshal(out, &members); err != nil {
+					t.Fatalf("Unmarshal() error = %v", err)
+				}
+				for _, private := range []string{"d", "p", "q", "dp", "dq", "qi"} {
+					if _, ok := members[private]; ok {
+						t.Errorf("public JWK %s contains private member %q", out, private)
+					}
+				}
+			}
+		})
+	}
+}
@@ -19,33 +19,177 @@ package crypto
 import (
 	"crypto"
 	"crypto/sha256"
-	"crypto/x509"
+	"crypto/sha512"
+	"crypto/subtle"
 	"encoding/base64"
 	"fmt"
+	"strings"
 )
 
 // FingerprintPrefix is the canonical prefix for fingerprints.
 // This format is FIXED and must NEVER change (API versioning invariant).
-const FingerprintPrefix = "SHA256:"
+const FingerprintPrefix = string(FingerprintSHA256) + ":"
 
-// ComputeFingerprint computes a deterministic fingerprint for a public key.
+// FingerprintAlgorithm is the hash algorithm of a Fingerprint. Its name is the
+// prefix of the string form.
+type FingerprintAlgorithm string
+
+// Supported fingerprint algorithms.
+const (
+	FingerprintSHA256 FingerprintAlgorithm = "SHA256"
+	FingerprintSHA384 FingerprintAlgorithm = "SHA384"
+	FingerprintSHA512 FingerprintAlgorithm = "SHA512"
+)
+
+// digestSizes maps supported algorithms to their digest length in bytes.
+var digestSizes = map[FingerprintAlgorithm]int{
+	FingerprintSHA256: sha256.Size,
+	FingerprintSHA384: sha512.Size384,
+	FingerprintSHA512: sha512.Size,
+}
+
+// Fingerprint identifies a public key by a hash of its DER (SPKI) encoding.
+// Its string form is "{algorithm}:{base64url(digest)}", e.g. "SHA256:…".
+// Compare fingerprints with Equal: a raw digest never equals a Fingerprint, and
+// digests of different algorithms never match. The zero value is "no fingerprint".
+type Fingerprint struct {
+	algorithm FingerprintAlgorithm
+	digest    string // raw digest bytes
+}
+
+// ComputeFingerprint computes a deterministic SHA-256 fingerprint for a public key.
 // Format: "SHA256:{base64url(SHA-256(DER(pubkey)))}"
 //
 // This is used for integrity verification: the controller stores the fingerprint
 // in the CRD status and verifies it against the Secret content on every reconcile.
 // [SEC:T-1]
-func ComputeFingerprint(pubKey crypto.PublicKey) (string, error) {
+func ComputeFingerprint(pubKey crypto.PublicKey) (Fingerprint, error) {
+	return ComputeFingerprintWith(pubKey, FingerprintSHA256)
+}
+
+// ComputeFingerprintWith computes the fingerprint of a public key using algorithm.
+func ComputeFingerprintWith(pubKey crypto.PublicKey, algorithm FingerprintAlgorithm) (Fingerprint, error) {
 	if pubKey == nil {
-		return "", fmt.Errorf("cannot compute fingerprint: public key is nil")
+		return Fingerprint{}, fmt.Errorf("cannot compute fingerprint: public key is nil")
+	}
+
+	derBytes, err := marshalPKIXPublicKey(pubKey)
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("marshal public key to DER: %w", err)
+	}
+	return FingerprintFromDERWith(derBytes, algorithm)
+}
+
+// ComputeJWKThumbprint computes the RFC 7638 JWK thumbprint of a public key.
+//
+// Deprecated: Use JWKThumbprint, which it calls.
+func ComputeJWKThumbprint(pubKey crypto.PublicKey) (string, error) {
+	return JWKThumbprint(pubKey)
+}
+
+// FingerprintFromDER returns the string form of the SHA-256 fingerprint of a
+// DER (SPKI) encoded public key, matching ComputeFingerprint for the same key.
+// Use it when the DER is already in hand, e.g. a Secret's stored public key.
+// The DER is hashed as-is, not parsed.
+func FingerprintFromDER(der []byte) string {
+	f, _ := FingerprintFromDERWith(der, FingerprintSHA256) // SHA-256 is always supported
+	return f.String()
+}
+
+// FingerprintFromDERWith computes the fingerprint of a DER (SPKI) encoded
+// public key using algorithm.
+func FingerprintFromDERWith(der []byte, algorithm FingerprintAlgorithm) (Fingerprint, error) {
+	var digest []byte
+	switch algorithm {
+	case FingerprintSHA256:
+		sum := sha256.Sum256(der)
+		digest = sum[:]
+	case FingerprintSHA384:
+		sum := sha512.Sum384(der)
+		digest = sum[:]
+	case FingerprintSHA512:
+		sum := sha512.Sum512(der)
+		digest = sum[:]
+	default:
+		return Fingerprint{}, fmt.Errorf("unsupported fingerprint algorithm %q", algorithm)
+	}
+	return Fingerprint{algorithm: algorithm, digest: string(digest)}, nil
+}
+
+// ParseFingerprint parses the string form of a fingerprint. The algorithm prefix
+// is mandatory and the digest length must match the algorithm. An empty string
+// yields the zero Fingerprint.
+func ParseFingerprint(s string) (Fingerprint, error) {
+	if s == "" {
+		return Fingerprint{}, nil
+	}
+	name, encoded, ok := strings.Cut(s, ":")
+	if !ok {
+		return Fingerprint{}, fmt.Errorf("invalid fingerprint %q: missing algorithm prefix", s)
+	}
+	algorithm := FingerprintAlgorithm(name)
+	size, ok := digestSizes[algorithm]
+	if !ok {
+		return Fingerprint{}, fmt.Errorf("invalid fingerprint %q: unsupported algorithm %q", s, name)
+	}
+	digest, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Fingerprint{}, fmt.Errorf("invalid fingerprint %q: %w", s, err)
 	}
+	if len(digest) != size {
+		return Fingerprint{}, fmt.Errorf("invalid fingerprint %q: %s digest must be %d bytes, got %d",
+			s, algorithm, size, len(digest))
+	}
+	return Fingerprint{algorithm: algorithm, digest: string(digest)}, nil
+}
 
-	derBytes, err := x509.MarshalPKIXPublicKey(pubKey)
+// Algorithm returns the hash algorithm of the fingerprint.
+func (f Fingerprint) Algorithm() FingerprintAlgorithm {
+	return f.algorithm
+}
+
+// IsZero reports whether f is the zero Fingerprint.
+func (f Fingerprint) IsZero() bool {
+	return f.algorithm == "" && f.digest == ""
+}
+
+// String returns the canonical "{algorithm}:{base64url(digest)}" form, or "" for
+// the zero Fingerprint.
+func (f Fingerprint) String() string {
+	if f.IsZero() {
+		return ""
+	}
+	return string(f.algorithm) + ":" + base64.RawURLEncoding.EncodeToString([]byte(f.digest))
+}
+
+// Equal reports whether f and other have the same algorithm and digest.
+// Digests are compared in constant time.
+func (f Fingerprint) Equal(other Fingerprint) bool {
+	return f.algorithm == other.algorithm &&
+		subtle.ConstantTimeCompare([]byte(f.digest), []byte(other.digest)) == 1
+}
+
+// Matches reports whether pubKey hashes to f using f's algorithm.
+func (f Fingerprint) Matches(pubKey crypto.PublicKey) (bool, error) {
+	got, err := ComputeFingerprintWith(pubKey, f.algorithm)
 	if err != nil {
-		return "", fmt.Errorf("marshal public key to DER: %w", err)
+		return false, err
 	}
+	return f.Equal(got), nil
+}
 
-	hash := sha256.Sum256(derBytes)
-	encoded := base64.RawURLEncoding.EncodeToString(hash[:])
+// MarshalText implements encoding.TextMarshaler, so fingerprints serialize as
+// their string form in JSON.
+func (f Fingerprint) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
 
-	return FingerprintPrefix + encoded, nil
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (f *Fingerprint) UnmarshalText(text []byte) error {
+	parsed, err := ParseFingerprint(string(text))
+	if err != nil {
+		return err
+	}
+	*f = parsed
+	return nil
 }
@@ -0,0 +1,237 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestParseFingerprintRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	kp, err := NewKeyGenerator().Generate(GenerateOptions{
+		Algorithm: AlgorithmEC,
+		Params:    map[string]string{"curve": CurveP256},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	t.Cleanup(kp.Wipe)
+
+	for _, alg := range []FingerprintAlgorithm{FingerprintSHA256, FingerprintSHA384, FingerprintSHA512} {
+		t.Run(string(alg), func(t *testing.T) {
+			t.Parallel()
+			fp, err := ComputeFingerprintWith(kp.PublicKey, alg)
+			if err != nil {
+				t.Fatalf("ComputeFingerprintWith() error = %v", err)
+			}
+			if !strings.HasPrefix(fp.String(), string(alg)+":") {
+				t.Errorf("String() = %q, want %s: prefix", fp, alg)
+			}
+			parsed, err := ParseFingerprint(fp.String())
+			if err != nil {
+				t.Fatalf("ParseFingerprint() error = %v", err)
+			}
+			if !parsed.Equal(fp) || parsed.Algorithm() != alg {
+				t.Errorf("ParseFingerprint(%q) = %v, want %v", fp, parsed, fp)
+			}
+			ok, err := parsed.Matches(kp.PublicKey)
+			if err != nil || !ok {
+				t.Errorf("Matches() = %v, %v, want true", ok, err)
+			}
+		})
+	}
+}
+
+func TestFingerprintEqualAcrossAlgorithms(t *testing.T) {
+	t.Parallel()
+
+	kp, err := NewKeyGenerator().Generate(GenerateOptions{
+		Algorithm: AlgorithmEC,
+		Params:    map[string]string{"curve": CurveP256},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer kp.Wipe()
+
+	sha256FP, err := ComputeFingerprint(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+	sha512FP, err := ComputeFingerprintWith(kp.PublicKey, FingerprintSHA512)
+	if err != nil {
+		t.Fatalf("ComputeFingerprintWith() error = %v", err)
+	}
+	if sha256FP.Equal(sha512FP) {
+		t.Error("fingerprints of different algorithms compared equal")
+	}
+	if sha256FP.Equal(Fingerprint{}) {
+		t.Error("fingerprint compared equal to the zero value")
+	}
+	if !strings.HasPrefix(sha256FP.String(), FingerprintPrefix) {
+		t.Errorf("ComputeFingerprint() = %q, want %s prefix", sha256FP, FingerprintPrefix)
+	}
+}
+
+func TestFingerprintFromDER(t *testing.T) {
+	t.Parallel()
+
+	// SHA-256 of empty input, a fixed vector independent of key generation
+	if got, want := FingerprintFromDER(nil), "SHA256:47DEQpj8HBSa-_TImW-5JCeuQeRkm5NMpJWZG3hSuFU"; got != want {
+		t.Errorf("FingerprintFromDER(nil) = %q, want %q", got, want)
+	}
+	if _, err := FingerprintFromDERWith(nil, "MD5"); err == nil {
+		t.Error("FingerprintFromDERWith(MD5) succeeded, want error")
+	}
+
+	encoder, err := NewKeyEncoder("DER")
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	for _, opts := range []GenerateOptions{
+		{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP384}},
+		{Algorithm: AlgorithmRSA, Params: map[string]string{"keySize": "3072"}},
+	} {
+		kp, err := NewKeyGenerator().Generate(opts)
+		if err != nil {
+			t.Fatalf("Generate(%s) error = %v", opts.Algorithm, err)
+		}
+		t.Cleanup(kp.Wipe)
+		der, err := encoder.EncodePublic(kp.PublicKey)
+		if err != nil {
+			t.Fatalf("EncodePublic() error = %v", err)
+		}
+
+		want, err := ComputeFingerprint(kp.PublicKey)
+		if err != nil {
+			t.Fatalf("ComputeFingerprint() error = %v", err)
+		}
+		if got := FingerprintFromDER(der); got != want.String() {
+			t.Errorf("%s: FingerprintFromDER() = %q, want %q", opts.Algorithm, got, want)
+		}
+		for _, alg := range []FingerprintAlgorithm{FingerprintSHA384, FingerprintSHA512} {
+			want, err := ComputeFingerprintWith(kp.PublicKey, alg)
+			if err != nil {
+				t.Fatalf("ComputeFingerprintWith(%s) error = %v", alg, err)
+			}
+			got, err := FingerprintFromDERWith(der, alg)
+			if err != nil || !got.Equal(want) {
+				t.Errorf("%s: FingerprintFromDERWith(%s) = %v, %v, want %v", opts.Algorithm, alg, got, err, want)
+			}
+		}
+	}
+}
+
+func TestParseFingerprintRejectsInvalid(t *testing.T) {
+	t.Parallel()
+
+	fp := "SHA256:" + strings.Repeat("A", 43) // 32 zero bytes
+	if _, err := ParseFingerprint(fp); err != nil {
+		t.Fatalf("ParseFingerprint(%q) error = %v", fp, err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "bare digest", input: strings.Repeat("A", 43)},
+		{name: "unknown algorithm", input: "MD5:" + strings.Repeat("A", 22)},
+		{name: "lowercase algorithm", input: "sha256:" + strings.Repeat("A", 43)},
+		{name: "wrong digest length", input: "SHA384:" + strings.Repeat("A", 43)},
+		{name: "invalid base64", input: "SHA256:" + strings.Repeat("!", 43)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := ParseFingerprint(tt.input); err == nil {
+				t.Errorf("ParseFingerprint(%q) expected error, got nil", tt.input)
+			}
+		})
+	}
+
+	zero, err := ParseFingerprint("")
+	if err != nil || !zero.IsZero() || zero.String() != "" {
+		t.Errorf("ParseFingerprint(\"\") = %v, %v, want zero value", zero, err)
+	}
+}
+
+func TestFingerprintJSON(t *testing.T) {
+	t.Parallel()
+
+	fp, err := ParseFingerprint("SHA256:" + strings.Repeat("A", 43))
+	if err != nil {
+		t.Fatalf("ParseFingerprint() error = %v", err)
+	}
+	data, err := json.Marshal(struct {
+		FP Fingerprint `json:"fp"`
+	}{fp})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if want := `{"fp":"` + fp.String() + `"}`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	var decoded struct {
+		FP Fingerprint `json:"fp"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !decoded.FP.Equal(fp) {
+		t.Errorf("Unmarshal() = %v, want %v", decoded.FP, fp)
+	}
+}
+
+func TestComputeJWKThumbprintPadsECCoordinates(t *testing.T) {
+	t.Parallel()
+
+	// About one P-256 key in 256 has an x coordinate with a leading zero byte
+	var key *ecdsa.PrivateKey
+	for i := 0; i < 1<<14 && (key == nil || key.X.BitLen() > 248); i++ {
+		var err error
+		if key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+			t.Fatalf("GenerateKey() error = %v", err)
+		}
+	}
+	if key.X.BitLen() > 248 {
+		t.Skip("no key with a short x coordinate generated")
+	}
+
+	x := base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32)))
+	y := base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32)))
+	sum := sha256.Sum256([]byte(`{"crv":"P-256","kty":"EC","x":"` + x + `","y":"` + y + `"}`))
+	got, err := ComputeJWKThumbprint(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("ComputeJWKThumbprint() error = %v", err)
+	}
+	if want := base64.RawURLEncoding.EncodeToString(sum[:]); got != want {
+		t.Errorf("ComputeJWKThumbprint() = %s, want %s", got, want)
+	}
+
+	if _, err := ComputeJWKThumbprint(nil); err == nil {
+		t.Error("ComputeJWKThumbprint(nil) succeeded, want error")
+	}
+}
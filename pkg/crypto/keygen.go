@@ -19,13 +19,15 @@ package crypto
 import (
 	"crypto"
 	"crypto/ecdsa"
-	"crypto/elliptic"
+	"crypto/ed25519"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -39,12 +41,14 @@ type KeyGenerator interface {
 
 // GenerateOptions specifies parameters for key generation.
 type GenerateOptions struct {
-	// Algorithm: "EC" or "RSA"
+	// Algorithm: "EC", "RSA" or "Ed25519"
 	Algorithm string
 	// Params: algorithm-specific parameters (e.g., "curve": "P-256", "keySize": "3072")
 	Params map[string]string
 	// AllowLegacyKeySize permits RSA < 3072 (BSI TR-02102-1 G-1)
 	AllowLegacyKeySize bool
+	// KeyIDTemplate overrides the key ID format (see DefaultKeyIDTemplate).
+	KeyIDTemplate string
 }
 
 // KeyPair holds generated key material.
@@ -52,7 +56,7 @@ type GenerateOptions struct {
 // [SEC:I-2]
 type KeyPair struct {
 	// KeyID is a unique identifier for this key pair.
-	// Format: {alg}-{param}-{YYYYMMDD}-{6hex}
+	// Format: GenerateOptions.KeyIDTemplate, default {alg}-{param}-{YYYYMMDD}-{6hex}
 	KeyID string
 
 	// PrivateKey is the generated private key (crypto.PrivateKey).
@@ -61,7 +65,7 @@ type KeyPair struct {
 	// PublicKey is the generated public key (crypto.PublicKey).
 	PublicKey crypto.PublicKey
 
-	// Algorithm is the algorithm used (EC or RSA).
+	// Algorithm is the algorithm used (EC, RSA or Ed25519).
 	Algorithm string
 
 	// CreatedAt is the creation timestamp.
@@ -71,6 +75,48 @@ type KeyPair struct {
 	rawPrivateBytes []byte
 }
 
+// PublicKeyInfo holds the public component of a KeyPair and its metadata.
+// It is what publishers receive instead of the KeyPair so that private key
+// material never leaves the rotation manager. [SEC:S-2]
+type PublicKeyInfo struct {
+	// KeyID is the identifier of the originating key pair.
+	KeyID string
+
+	// PublicKey is the public key (crypto.PublicKey).
+	PublicKey crypto.PublicKey
+
+	// Algorithm is the algorithm used (EC, RSA or Ed25519).
+	Algorithm string
+
+	// CreatedAt is the creation timestamp of the originating key pair.
+	CreatedAt time.Time
+}
+
+// Public returns the public component of the key pair.
+// The returned value does not reference any private key material.
+func (kp *KeyPair) Public() *PublicKeyInfo {
+	if kp == nil {
+		return nil
+	}
+	return &PublicKeyInfo{
+		KeyID:     kp.KeyID,
+		PublicKey: kp.PublicKey,
+		Algorithm: kp.Algorithm,
+		CreatedAt: kp.CreatedAt,
+	}
+}
+
+// IsPrivateKey reports whether key carries private key material.
+// Used as a guard on paths that must only ever handle public keys.
+func IsPrivateKey(key any) bool {
+	switch key.(type) {
+	case crypto.Signer, crypto.Decrypter:
+		return true
+	default:
+		return false
+	}
+}
+
 // Wipe zeroes out private key material from memory.
 // [SEC:I-2] This MUST be called via defer after every Generate().
 func (kp *KeyPair) Wipe() {
@@ -85,20 +131,8 @@ func (kp *KeyPair) Wipe() {
 	kp.rawPrivateBytes = nil
 
 	// Zero key struct internals where possible
-	switch k := kp.PrivateKey.(type) {
-	case *ecdsa.PrivateKey:
-		if k != nil && k.D != nil {
-			k.D.SetInt64(0)
-		}
-	case *rsa.PrivateKey:
-		if k != nil && k.D != nil {
-			k.D.SetInt64(0)
-		}
-		for i := range k.Primes {
-			if k.Primes[i] != nil {
-				k.Primes[i].SetInt64(0)
-			}
-		}
+	if spec, ok := lookupAlgorithm(kp.Algorithm); ok && spec.Wipe != nil && kp.PrivateKey != nil {
+		spec.Wipe(kp.PrivateKey)
 	}
 
 	kp.PrivateKey = nil
@@ -107,114 +141,242 @@ func (kp *KeyPair) Wipe() {
 
 // defaultGenerator is the standard KeyGenerator implementation
 // using exclusively Go standard library crypto.
-type defaultGenerator struct{}
+type defaultGenerator struct {
+	random io.Reader
+}
+
+// GeneratorOption configures a KeyGenerator.
+type GeneratorOption func(*defaultGenerator)
+
+// WithEntropy sets the entropy source used for key material and key IDs.
+// The default is crypto/rand.Reader.
+//
+// WARNING: for tests and air-gapped setups only. A source other than
+// crypto/rand.Reader or an HSM-backed CSPRNG makes keys predictable. Note that
+// recent Go releases ignore custom readers in ecdsa/rsa.GenerateKey unless
+// GODEBUG=cryptocustomrand=1 is set; key IDs always use the configured source.
+func WithEntropy(r io.Reader) GeneratorOption {
+	return func(g *defaultGenerator) {
+		g.random = r
+	}
+}
 
 // NewKeyGenerator creates a new KeyGenerator.
-func NewKeyGenerator() KeyGenerator {
-	return &defaultGenerator{}
+func NewKeyGenerator(opts ...GeneratorOption) KeyGenerator {
+	g := &defaultGenerator{random: rand.Reader}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.random == nil {
+		g.random = rand.Reader
+	}
+	return g
 }
 
 // Generate creates a new key pair.
 // It validates the key spec using the shared ValidateKeySpec function (DRY).
 func (g *defaultGenerator) Generate(opts GenerateOptions) (*KeyPair, error) {
+	spec, params, err := prepareGenerate(opts)
+	if err != nil {
+		return nil, err
+	}
+	key, err := spec.Generate(g.random, params)
+	if err != nil {
+		return nil, fmt.Errorf("%s key generation failed: %w", opts.Algorithm, err)
+	}
+	return g.newKeyPair(opts, spec, key)
+}
+
+// prepareGenerate validates opts and returns the algorithm spec and the
+// normalized params to generate with.
+func prepareGenerate(opts GenerateOptions) (AlgorithmSpec, map[string]string, error) {
 	// DRY: Use shared validation from pkg/crypto/validate.go
 	if _, err := ValidateKeySpec(opts.Algorithm, opts.Params, opts.AllowLegacyKeySize); err != nil {
-		return nil, fmt.Errorf("key generation validation failed: %w", err)
+		return AlgorithmSpec{}, nil, fmt.Errorf("key generation validation failed: %w", err)
+	}
+	if err := ValidateKeyIDTemplate(opts.KeyIDTemplate); err != nil {
+		return AlgorithmSpec{}, nil, fmt.Errorf("key generation validation failed: %w", err)
 	}
 
-	switch opts.Algorithm {
-	case AlgorithmEC:
-		return g.generateEC(opts)
-	case AlgorithmRSA:
-		return g.generateRSA(opts)
-	default:
-		return nil, fmt.Errorf("unsupported algorithm: %s", opts.Algorithm)
+	spec, ok := lookupAlgorithm(opts.Algorithm)
+	if !ok {
+		return AlgorithmSpec{}, nil, fmt.Errorf("unsupported algorithm: %s", opts.Algorithm)
+	}
+
+	// Generate with the same normalized params that passed validation
+	params, err := NormalizeParams(opts.Params)
+	if err != nil {
+		return AlgorithmSpec{}, nil, fmt.Errorf("key generation validation failed: %w", err)
 	}
+	return spec, params, nil
 }
 
-func (g *defaultGenerator) generateEC(opts GenerateOptions) (*KeyPair, error) {
-	curveName := opts.Params["curve"]
-	curve, err := parseCurve(curveName)
+// newKeyPair assigns a key ID to freshly generated key material. The material
+// is wiped if that fails.
+func (g *defaultGenerator) newKeyPair(opts GenerateOptions, spec AlgorithmSpec, key *GeneratedKey) (*KeyPair, error) {
+	keyID, err := generateKeyID(g.random, opts.KeyIDTemplate, strings.ToLower(opts.Algorithm), key.KeyIDParam)
 	if err != nil {
-		return nil, fmt.Errorf("EC key generation failed: %w", err)
+		if spec.Wipe != nil {
+			spec.Wipe(key.PrivateKey)
+		}
+		return nil, fmt.Errorf("key ID generation failed: %w", err)
 	}
 
-	privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+	return &KeyPair{
+		KeyID:           keyID,
+		PrivateKey:      key.PrivateKey,
+		PublicKey:       key.PublicKey,
+		Algorithm:       opts.Algorithm,
+		CreatedAt:       time.Now(),
+		rawPrivateBytes: key.RawPrivateBytes,
+	}, nil
+}
+
+func generateEC(random io.Reader, params map[string]string) (*GeneratedKey, error) {
+	curveName := params["curve"]
+	curve, err := parseCurve(curveName)
 	if err != nil {
-		return nil, fmt.Errorf("ecdsa.GenerateKey failed: %w", err)
+		return nil, err
 	}
 
-	rawBytes, err := x509.MarshalECPrivateKey(privateKey)
+	privateKey, err := ecdsa.GenerateKey(curve, random)
 	if err != nil {
-		return nil, fmt.Errorf("marshal EC private key for wipe tracking: %w", err)
+		return nil, fmt.Errorf("ecdsa.GenerateKey failed: %w", err)
 	}
 
-	keyID, err := generateKeyID("ec", curveName)
+	rawBytes, err := marshalECPrivateKey(privateKey)
 	if err != nil {
-		return nil, fmt.Errorf("key ID generation failed: %w", err)
+		return nil, fmt.Errorf("marshal EC private key for wipe tracking: %w", err)
 	}
 
-	return &KeyPair{
-		KeyID:           keyID,
+	return &GeneratedKey{
 		PrivateKey:      privateKey,
 		PublicKey:       &privateKey.PublicKey,
-		Algorithm:       AlgorithmEC,
-		CreatedAt:       time.Now(),
-		rawPrivateBytes: rawBytes,
+		RawPrivateBytes: rawBytes,
+		KeyIDParam:      curveName,
 	}, nil
 }
 
-func (g *defaultGenerator) generateRSA(opts GenerateOptions) (*KeyPair, error) {
-	keySizeStr := opts.Params["keySize"]
+func wipeEC(key crypto.PrivateKey) {
+	if k, ok := key.(*ecdsa.PrivateKey); ok && k != nil && k.D != nil {
+		k.D.SetInt64(0)
+	}
+}
+
+func generateRSA(random io.Reader, params map[string]string) (*GeneratedKey, error) {
+	keySizeStr := params["keySize"]
 	keySize, err := strconv.Atoi(keySizeStr)
 	if err != nil {
 		return nil, fmt.Errorf("invalid RSA keySize %q: %w", keySizeStr, err)
 	}
 
-	privateKey, err := rsa.GenerateKey(rand.Reader, keySize)
+	privateKey, err := rsa.GenerateKey(random, keySize)
 	if err != nil {
 		return nil, fmt.Errorf("rsa.GenerateKey failed: %w", err)
 	}
 
-	rawBytes := x509.MarshalPKCS1PrivateKey(privateKey)
+	return &GeneratedKey{
+		PrivateKey:      privateKey,
+		PublicKey:       &privateKey.PublicKey,
+		RawPrivateBytes: x509.MarshalPKCS1PrivateKey(privateKey),
+		KeyIDParam:      keySizeStr,
+	}, nil
+}
 
-	keyID, err := generateKeyID("rsa", keySizeStr)
+func wipeRSA(key crypto.PrivateKey) {
+	k, ok := key.(*rsa.PrivateKey)
+	if !ok || k == nil {
+		return
+	}
+	if k.D != nil {
+		k.D.SetInt64(0)
+	}
+	for i := range k.Primes {
+		if k.Primes[i] != nil {
+			k.Primes[i].SetInt64(0)
+		}
+	}
+}
+
+// generateEd25519 creates an Ed25519 key pair. Ed25519 takes no params, so
+// key IDs carry no {param} value.
+func generateEd25519(random io.Reader, _ map[string]string) (*GeneratedKey, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(random)
 	if err != nil {
-		return nil, fmt.Errorf("key ID generation failed: %w", err)
+		return nil, fmt.Errorf("ed25519.GenerateKey failed: %w", err)
 	}
 
-	return &KeyPair{
-		KeyID:           keyID,
-		PrivateKey:      privateKey,
-		PublicKey:       &privateKey.PublicKey,
-		Algorithm:       AlgorithmRSA,
-		CreatedAt:       time.Now(),
-		rawPrivateBytes: rawBytes,
+	return &GeneratedKey{
+		PrivateKey: privateKey,
+		PublicKey:  publicKey,
 	}, nil
 }
 
-// parseCurve maps curve name strings to elliptic.Curve.
-func parseCurve(name string) (elliptic.Curve, error) {
-	switch name {
-	case CurveP256:
-		return elliptic.P256(), nil
-	case CurveP384:
-		return elliptic.P384(), nil
-	case CurveP521:
-		return elliptic.P521(), nil
-	default:
-		return nil, fmt.Errorf("unsupported curve: %s", name)
+// wipeEd25519 zeroes the seed, the secret first half of an ed25519.PrivateKey.
+func wipeEd25519(key crypto.PrivateKey) {
+	k, ok := key.(ed25519.PrivateKey)
+	if !ok || len(k) != ed25519.PrivateKeySize {
+		return
+	}
+	for i := range k[:ed25519.SeedSize] {
+		k[i] = 0
 	}
 }
 
-// generateKeyID creates a unique key identifier.
-// Format: {alg}-{param}-{YYYYMMDD}-{6hex}
-func generateKeyID(alg, param string) (string, error) {
-	randomBytes := make([]byte, 3) // 6 hex chars
-	if _, err := rand.Read(randomBytes); err != nil {
+// generateKeyID creates a unique key identifier from the template, drawing
+// random placeholders from random.
+// An empty template uses DefaultKeyIDTemplate: {alg}-{param}-{YYYYMMDD}-{6hex}
+// An empty param drops {param} together with one adjacent "-", e.g.
+// ed25519-{YYYYMMDD}-{6hex}.
+func generateKeyID(random io.Reader, template, alg, param string) (string, error) {
+	if template == "" {
+		template = DefaultKeyIDTemplate
+	}
+	if param == "" {
+		template = strings.NewReplacer("{param}-", "", "-{param}", "").Replace(template)
+	}
+
+	var err error
+	keyID := keyIDPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		if err != nil {
+			return ""
+		}
+		var value string
+		switch placeholder {
+		case "{alg}":
+			value = alg
+		case "{param}":
+			value = param
+		case "{date}":
+			value = time.Now().Format("20060102")
+		case "{hex}":
+			value, err = randomHex(random, 3) // 6 hex chars
+		case "{uuid}":
+			value, err = randomUUID(random)
+		}
+		return value
+	})
+	if err != nil {
 		return "", fmt.Errorf("generating random bytes for key ID: %w", err)
 	}
+	return keyID, nil
+}
 
-	date := time.Now().Format("20060102")
-	return fmt.Sprintf("%s-%s-%s-%s", alg, param, date, hex.EncodeToString(randomBytes)), nil
+func randomHex(random io.Reader, n int) (string, error) {
+	randomBytes := make([]byte, n)
+	if _, err := io.ReadFull(random, randomBytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(randomBytes), nil
+}
+
+// randomUUID returns a random (version 4) UUID per RFC 9562.
+func randomUUID(random io.Reader) (string, error) {
+	b := make([]byte, 16)
+	if _, err := io.ReadFull(random, b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
 }
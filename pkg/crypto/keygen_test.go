@@ -0,0 +1,298 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	mathrand "math/rand/v2"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestKeyPairPublic(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		opts GenerateOptions
+	}{
+		{
+			name: "EC P-256",
+			opts: GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP256}},
+		},
+		{
+			name: "RSA 3072",
+			opts: GenerateOptions{Algorithm: AlgorithmRSA, Params: map[string]string{"keySize": "3072"}},
+		},
+		{
+			name: "Ed25519",
+			opts: GenerateOptions{Algorithm: AlgorithmEd25519},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			kp, err := NewKeyGenerator().Generate(tt.opts)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			defer kp.Wipe()
+
+			pub := kp.Public()
+			if pub.KeyID != kp.KeyID || pub.Algorithm != kp.Algorithm {
+				t.Errorf("Public() metadata = %+v, want KeyID %q Algorithm %q", pub, kp.KeyID, kp.Algorithm)
+			}
+			if IsPrivateKey(pub.PublicKey) {
+				t.Errorf("Public() exposes private key material: %T", pub.PublicKey)
+			}
+			if !IsPrivateKey(kp.PrivateKey) {
+				t.Errorf("IsPrivateKey(%T) = false, want true", kp.PrivateKey)
+			}
+		})
+	}
+}
+
+func TestKeyIDTemplates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		template string
+		pattern  string
+	}{
+		{
+			name:     "default",
+			template: "",
+			pattern:  `^ec-P-256-\d{8}-[0-9a-f]{6}$`,
+		},
+		{
+			name:     "uuid",
+			template: "{uuid}",
+			pattern:  `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+		},
+		{
+			name:     "prefixed date and hex",
+			template: "svc_{date}.{hex}",
+			pattern:  `^svc_\d{8}\.[0-9a-f]{6}$`,
+		},
+		{
+			name:     "alg and uuid",
+			template: "{alg}-{uuid}",
+			pattern:  `^ec-[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := ValidateKeyIDTemplate(tt.template); err != nil {
+				t.Fatalf("ValidateKeyIDTemplate(%q) error = %v", tt.template, err)
+			}
+
+			re := regexp.MustCompile(tt.pattern)
+			seen := make(map[string]bool)
+			for i := 0; i < 50; i++ {
+				keyID, err := generateKeyID(rand.Reader, tt.template, "ec", CurveP256)
+				if err != nil {
+					t.Fatalf("generateKeyID() error = %v", err)
+				}
+				if !re.MatchString(keyID) {
+					t.Fatalf("generateKeyID(%q) = %q, want match %s", tt.template, keyID, tt.pattern)
+				}
+				if seen[keyID] {
+					t.Fatalf("generateKeyID(%q) produced duplicate %q", tt.template, keyID)
+				}
+				seen[keyID] = true
+			}
+		})
+	}
+}
+
+func TestGenerateEd25519(t *testing.T) {
+	t.Parallel()
+
+	kp, err := NewKeyGenerator().Generate(GenerateOptions{Algorithm: AlgorithmEd25519})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer kp.Wipe()
+	if re := regexp.MustCompile(`^ed25519-\d{8}-[0-9a-f]{6}$`); !re.MatchString(kp.KeyID) {
+		t.Errorf("KeyID = %q, want ed25519-{date}-{hex}", kp.KeyID)
+	}
+
+	priv, ok := kp.PrivateKey.(ed25519.PrivateKey)
+	if !ok {
+		t.Fatalf("PrivateKey is %T, want ed25519.PrivateKey", kp.PrivateKey)
+	}
+	msg := []byte("payload")
+	if !ed25519.Verify(kp.PublicKey.(ed25519.PublicKey), msg, ed25519.Sign(priv, msg)) {
+		t.Error("signature does not verify against the generated public key")
+	}
+
+	kp.Wipe()
+	if !bytes.Equal(priv.Seed(), make([]byte, ed25519.SeedSize)) {
+		t.Error("Wipe() left the Ed25519 seed in memory")
+	}
+}
+
+func TestValidateKeyIDTemplateRejects(t *testing.T) {
+	t.Parallel()
+
+	for _, template := range []string{
+		"{alg}-{date}", // no random component
+		"{alg}-{seq}",  // unknown placeholder
+		"{uuid}/../x",  // path separator
+		"key id {hex}", // whitespace
+	} {
+		if err := ValidateKeyIDTemplate(template); err == nil {
+			t.Errorf("ValidateKeyIDTemplate(%q) succeeded, want error", template)
+		}
+	}
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestGenerateWithFixedEntropy(t *testing.T) {
+	t.Parallel()
+
+	// Key IDs are drawn from the configured source byte for byte
+	fixed := bytes.NewReader([]byte{0xde, 0xad, 0xbe, 0xef})
+	keyID, err := generateKeyID(fixed, "{alg}-{hex}", "ec", CurveP256)
+	if err != nil {
+		t.Fatalf("generateKeyID() error = %v", err)
+	}
+	if keyID != "ec-deadbe" {
+		t.Errorf("generateKeyID() = %q, want ec-deadbe", keyID)
+	}
+
+	// An exhausted source fails instead of silently falling back
+	if _, err := generateKeyID(fixed, "{uuid}", "ec", CurveP256); err == nil {
+		t.Error("generateKeyID() with exhausted reader succeeded, want error")
+	}
+
+	src := &countingReader{r: mathrand.NewChaCha8([32]byte{1})}
+	kp, err := NewKeyGenerator(WithEntropy(src)).Generate(GenerateOptions{
+		Algorithm:     AlgorithmEC,
+		Params:        map[string]string{"curve": CurveP256},
+		KeyIDTemplate: "{alg}-{uuid}",
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer kp.Wipe()
+	if src.n < 16 {
+		t.Errorf("entropy source read %d bytes, want at least the 16 of the UUID", src.n)
+	}
+}
+
+// keySpecCombos enumerates algorithm/param combinations around the supported
+// values: every supported curve and RSA size, plus near misses and malformed input.
+func keySpecCombos() []GenerateOptions {
+	var combos []GenerateOptions
+	for _, curve := range append(SupportedCurves(), "P-224", "p-256", "secp256k1", "", " P-384 ") {
+		combos = append(combos, GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": curve}})
+	}
+	for _, size := range append(SupportedRSASizes(), 1024, 2047, 8192) {
+		for _, legacy := range []bool{false, true} {
+			combos = append(combos, GenerateOptions{
+				Algorithm:          AlgorithmRSA,
+				Params:             map[string]string{"keySize": strconv.Itoa(size)},
+				AllowLegacyKeySize: legacy,
+			})
+		}
+	}
+	for _, exp := range []string{RSAPublicExponent, "3", ""} {
+		combos = append(combos, GenerateOptions{
+			Algorithm: AlgorithmRSA,
+			Params:    map[string]string{"keySize": "3072", "publicExponent": exp},
+		})
+	}
+	return append(combos,
+		// Intentionally invalid: missing, malformed and misplaced params, unknown algorithms
+		GenerateOptions{Algorithm: AlgorithmEC},
+		GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"cruve": CurveP256}},
+		GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP256, "keySize": "3072"}},
+		GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": "   "}},
+		GenerateOptions{Algorithm: AlgorithmRSA},
+		GenerateOptions{Algorithm: AlgorithmRSA, Params: map[string]string{"keySize": "three thousand"}},
+		GenerateOptions{Algorithm: AlgorithmRSA, Params: map[string]string{"keySize": "-3072"}},
+		GenerateOptions{Algorithm: AlgorithmEd25519},
+		GenerateOptions{Algorithm: AlgorithmEd25519, Params: map[string]string{"curve": "Ed25519"}},
+		GenerateOptions{Algorithm: "DSA", Params: map[string]string{"keySize": "3072"}},
+		GenerateOptions{Algorithm: "", Params: nil},
+	)
+}
+
+// TestValidateKeySpecAgreesWithGenerate guards against drift between admission
+// and generation: a spec is accepted by ValidateKeySpec if and only if Generate
+// succeeds, and rejected specs fail validation before any key is generated.
+func TestValidateKeySpecAgreesWithGenerate(t *testing.T) {
+	t.Parallel()
+
+	accepted := make(map[string]bool)
+	for _, opts := range keySpecCombos() {
+		_, validateErr := ValidateKeySpec(opts.Algorithm, opts.Params, opts.AllowLegacyKeySize)
+		if validateErr == nil {
+			accepted[opts.Params["curve"]+opts.Params["keySize"]] = true
+		}
+		name := opts.Algorithm + "/" + strings.ReplaceAll(fmt.Sprint(opts.Params), " ", "_") +
+			"/legacy=" + strconv.FormatBool(opts.AllowLegacyKeySize)
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			kp, err := NewKeyGenerator().Generate(opts)
+			if err == nil {
+				t.Cleanup(kp.Wipe)
+			}
+			switch {
+			case validateErr == nil && err != nil:
+				t.Errorf("ValidateKeySpec accepted, but Generate() error = %v", err)
+			case validateErr != nil && err == nil:
+				t.Errorf("ValidateKeySpec rejected (%v), but Generate() succeeded", validateErr)
+			case validateErr != nil && !strings.HasPrefix(err.Error(), "key generation validation failed"):
+				t.Errorf("Generate() error = %v, want a validation failure before generation", err)
+			}
+		})
+	}
+	// Every supported curve and size must be part of the accepted set
+	for _, curve := range SupportedCurves() {
+		if !accepted[curve] {
+			t.Errorf("supported curve %s was never accepted", curve)
+		}
+	}
+	for _, size := range SupportedRSASizes() {
+		if !accepted[strconv.Itoa(size)] {
+			t.Errorf("supported RSA keySize %d was never accepted", size)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// KeyPool is a KeyGenerator that pre-generates RSA keys of configured sizes in
+// the background, taking RSA prime generation off the reconcile critical path.
+// Requests it cannot serve from the pool (other algorithms or sizes, or an empty
+// pool) fall back to direct generation.
+//
+// SECURITY: pooled keys are private material held in memory before use. Each
+// pooled key is handed out at most once, so no key is shared between profiles,
+// and keys still pooled are wiped when Start returns. [SEC:I-2]
+type KeyPool struct {
+	gen    *defaultGenerator
+	pools  map[string]chan *GeneratedKey // by RSA keySize
+	refill chan struct{}
+	served atomic.Int64
+}
+
+// NewKeyPool creates a pool holding depth pre-generated RSA keys per key size.
+// The pool only fills while Start runs.
+func NewKeyPool(rsaKeySizes []int, depth int, opts ...GeneratorOption) (*KeyPool, error) {
+	if depth < 1 {
+		return nil, fmt.Errorf("key pool depth must be at least 1, got %d", depth)
+	}
+	if len(rsaKeySizes) == 0 {
+		return nil, fmt.Errorf("key pool requires at least one RSA key size")
+	}
+	p := &KeyPool{
+		gen:    NewKeyGenerator(opts...).(*defaultGenerator),
+		pools:  make(map[string]chan *GeneratedKey, len(rsaKeySizes)),
+		refill: make(chan struct{}, 1),
+	}
+	for _, size := range rsaKeySizes {
+		keySize := strconv.Itoa(size)
+		// Legacy sizes are allowed here; Generate still validates each request
+		if _, err := ValidateKeySpec(AlgorithmRSA, map[string]string{"keySize": keySize}, true); err != nil {
+			return nil, fmt.Errorf("invalid key pool size: %w", err)
+		}
+		p.pools[keySize] = make(chan *GeneratedKey, depth)
+	}
+	return p, nil
+}
+
+// Generate returns a pooled key if one matches opts, otherwise generates one.
+func (p *KeyPool) Generate(opts GenerateOptions) (*KeyPair, error) {
+	spec, params, err := prepareGenerate(opts)
+	if err != nil {
+		return nil, err
+	}
+	if pool, ok := p.pools[params["keySize"]]; ok && opts.Algorithm == AlgorithmRSA {
+		p.requestRefill()
+		if key := take(pool); key != nil {
+			p.served.Add(1)
+			return p.gen.newKeyPair(opts, spec, key)
+		}
+	}
+	key, err := spec.Generate(p.gen.random, params)
+	if err != nil {
+		return nil, fmt.Errorf("%s key generation failed: %w", opts.Algorithm, err)
+	}
+	return p.gen.newKeyPair(opts, spec, key)
+}
+
+// Start fills the pool and refills it as keys are handed out until ctx is
+// cancelled, then wipes all keys still pooled. It implements the controller-runtime
+// Runnable interface, so the pool only holds keys on the elected leader.
+func (p *KeyPool) Start(ctx context.Context) error {
+	defer p.drain()
+	for {
+		p.fill(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-p.refill:
+		}
+	}
+}
+
+// fill tops up every pool. Generation errors are left to direct generation,
+// which reports them on the next request.
+func (p *KeyPool) fill(ctx context.Context) {
+	for keySize, pool := range p.pools {
+		for len(pool) < cap(pool) {
+			if ctx.Err() != nil {
+				return
+			}
+			key, err := generateRSA(p.gen.random, map[string]string{"keySize": keySize})
+			if err != nil {
+				return
+			}
+			select {
+			case pool <- key:
+			default:
+				wipeGeneratedKey(key)
+			}
+		}
+	}
+}
+
+// drain wipes all pooled keys.
+func (p *KeyPool) drain() {
+	for _, pool := range p.pools {
+		for key := take(pool); key != nil; key = take(pool) {
+			wipeGeneratedKey(key)
+		}
+	}
+}
+
+// take removes a key from pool without blocking, or returns nil if it is empty.
+func take(pool chan *GeneratedKey) *GeneratedKey {
+	select {
+	case key := <-pool:
+		return key
+	default:
+		return nil
+	}
+}
+
+// requestRefill wakes Start without blocking.
+func (p *KeyPool) requestRefill() {
+	select {
+	case p.refill <- struct{}{}:
+	default:
+	}
+}
+
+// wipeGeneratedKey zeroes an RSA key that was never handed out.
+func wipeGeneratedKey(key *GeneratedKey) {
+	(&KeyPair{PrivateKey: key.PrivateKey, Algorithm: AlgorithmRSA, rawPrivateBytes: key.RawPrivateBytes}).Wipe()
+}
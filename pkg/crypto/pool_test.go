@@ -0,0 +1,118 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"context"
+	"crypto/rsa"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(30 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestKeyPoolServesAndRefills(t *testing.T) {
+	t.Parallel()
+
+	pool, err := NewKeyPool([]int{2048}, 1)
+	if err != nil {
+		t.Fatalf("NewKeyPool() error = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- pool.Start(ctx) }()
+	filled := func() bool { return len(pool.pools["2048"]) == 1 }
+	waitFor(t, "pool to fill", filled)
+
+	opts := GenerateOptions{
+		Algorithm:          AlgorithmRSA,
+		Params:             map[string]string{"keySize": "2048"},
+		AllowLegacyKeySize: true,
+	}
+	first, err := pool.Generate(opts)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer first.Wipe()
+	if got := pool.served.Load(); got != 1 {
+		t.Fatalf("served = %d, want the pooled key to be used", got)
+	}
+	if first.KeyID == "" || first.CreatedAt.IsZero() {
+		t.Errorf("pooled key pair missing metadata: %+v", first)
+	}
+
+	waitFor(t, "pool to refill", filled)
+	second, err := pool.Generate(opts)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer second.Wipe()
+	if got := pool.served.Load(); got != 2 {
+		t.Fatalf("served = %d, want the refilled key to be used", got)
+	}
+	if first.PublicKey.(*rsa.PublicKey).Equal(second.PublicKey) {
+		t.Error("pool handed out the same key twice")
+	}
+
+	// Requests the pool does not hold are generated directly
+	ec, err := pool.Generate(GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP256}})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer ec.Wipe()
+	if got := pool.served.Load(); got != 2 {
+		t.Errorf("served = %d after EC request, want 2", got)
+	}
+
+	// Pooled requests are still validated
+	if _, err := pool.Generate(GenerateOptions{Algorithm: AlgorithmRSA, Params: map[string]string{"keySize": "2048"}}); err == nil {
+		t.Error("Generate() of legacy size without AllowLegacyKeySize succeeded, want error")
+	}
+
+	waitFor(t, "pool to refill", filled)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if n := len(pool.pools["2048"]); n != 0 {
+		t.Errorf("%d keys left in pool after shutdown, want all wiped", n)
+	}
+}
+
+func TestNewKeyPoolRejectsInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewKeyPool([]int{3072}, 0); err == nil {
+		t.Error("expected error for zero depth")
+	}
+	if _, err := NewKeyPool(nil, 1); err == nil {
+		t.Error("expected error for no key sizes")
+	}
+	if _, err := NewKeyPool([]int{1024}, 1); err == nil {
+		t.Error("expected error for unsupported key size")
+	}
+}
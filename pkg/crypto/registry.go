@@ -0,0 +1,104 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"crypto"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// GeneratedKey is the raw output of an algorithm's generator.
+type GeneratedKey struct {
+	// PrivateKey is the generated private key.
+	PrivateKey crypto.PrivateKey
+
+	// PublicKey is the public half of PrivateKey.
+	PublicKey crypto.PublicKey
+
+	// RawPrivateBytes is an encoding of the private key that KeyPair.Wipe zeroes. Optional.
+	RawPrivateBytes []byte
+
+	// KeyIDParam is substituted for {param} in key IDs (e.g. the curve or key size).
+	KeyIDParam string
+}
+
+// AlgorithmSpec bundles everything the key generator needs to support an algorithm.
+type AlgorithmSpec struct {
+	// Validate checks algorithm parameters and returns warnings. Required.
+	// It is the single source of truth used by both admission and generation.
+	Validate func(params map[string]string, allowLegacy bool) ([]string, error)
+
+	// Generate creates a key pair from already validated parameters, drawing
+	// randomness from random (see WithEntropy). Required.
+	Generate func(random io.Reader, params map[string]string) (*GeneratedKey, error)
+
+	// Wipe zeroes algorithm-specific private key internals. Optional. [SEC:I-2]
+	Wipe func(key crypto.PrivateKey)
+}
+
+var (
+	algorithmsMu sync.RWMutex
+	algorithms   = map[string]AlgorithmSpec{
+		AlgorithmEC:      {Validate: validateEC, Generate: generateEC, Wipe: wipeEC},
+		AlgorithmRSA:     {Validate: validateRSA, Generate: generateRSA, Wipe: wipeRSA},
+		AlgorithmEd25519: {Validate: validateEd25519, Generate: generateEd25519, Wipe: wipeEd25519},
+	}
+)
+
+// RegisterAlgorithm adds a key algorithm to the generator and to ValidateKeySpec.
+// Each algorithm may only be registered once, so built-in algorithms cannot be replaced.
+// Intended to be called from main before the controller starts; the
+// KeyProfile CRD's algorithm enum must be extended accordingly.
+func RegisterAlgorithm(name string, spec AlgorithmSpec) error {
+	if name == "" {
+		return fmt.Errorf("algorithm name must not be empty")
+	}
+	if spec.Validate == nil || spec.Generate == nil {
+		return fmt.Errorf("algorithm %q requires Validate and Generate", name)
+	}
+
+	algorithmsMu.Lock()
+	defer algorithmsMu.Unlock()
+	if _, exists := algorithms[name]; exists {
+		return fmt.Errorf("algorithm %q is already registered", name)
+	}
+	algorithms[name] = spec
+	return nil
+}
+
+// RegisteredAlgorithms returns the names of all registered algorithms, sorted.
+func RegisteredAlgorithms() []string {
+	algorithmsMu.RLock()
+	defer algorithmsMu.RUnlock()
+	names := make([]string, 0, len(algorithms))
+	for name := range algorithms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupAlgorithm returns the registered spec for an algorithm, if any.
+func lookupAlgorithm(name string) (AlgorithmSpec, bool) {
+	algorithmsMu.RLock()
+	defer algorithmsMu.RUnlock()
+	spec, ok := algorithms[name]
+	return spec, ok
+}
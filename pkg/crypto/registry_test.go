@@ -0,0 +1,108 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestRegisterAlgorithmEndToEnd(t *testing.T) {
+	t.Parallel()
+
+	const name = "TEST-ED25519"
+	var wiped atomic.Bool
+	err := RegisterAlgorithm(name, AlgorithmSpec{
+		Validate: func(params map[string]string, _ bool) ([]string, error) {
+			if len(params) != 0 {
+				return nil, fmt.Errorf("%s takes no parameters", name)
+			}
+			return []string{"test algorithm"}, nil
+		},
+		Generate: func(random io.Reader, _ map[string]string) (*GeneratedKey, error) {
+			pub, priv, err := ed25519.GenerateKey(random)
+			if err != nil {
+				return nil, err
+			}
+			return &GeneratedKey{PrivateKey: priv, PublicKey: pub, RawPrivateBytes: priv, KeyIDParam: "ed25519"}, nil
+		},
+		Wipe: func(crypto.PrivateKey) { wiped.Store(true) },
+	})
+	if err != nil {
+		t.Fatalf("RegisterAlgorithm() error = %v", err)
+	}
+
+	warnings, err := ValidateKeySpec(name, nil, false)
+	if err != nil || len(warnings) != 1 {
+		t.Fatalf("ValidateKeySpec() = %v, %v; want one warning", warnings, err)
+	}
+	if _, err := ValidateKeySpec(name, map[string]string{"curve": "P-256"}, false); err == nil {
+		t.Error("ValidateKeySpec() with params succeeded, want error")
+	}
+
+	kp, err := NewKeyGenerator().Generate(GenerateOptions{Algorithm: name})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if kp.Algorithm != name {
+		t.Errorf("Algorithm = %q, want %q", kp.Algorithm, name)
+	}
+	if !strings.HasPrefix(kp.KeyID, "test-ed25519-ed25519-") {
+		t.Errorf("KeyID = %q, want prefix test-ed25519-ed25519-", kp.KeyID)
+	}
+	priv, ok := kp.PrivateKey.(ed25519.PrivateKey)
+	if !ok {
+		t.Fatalf("PrivateKey is %T, want ed25519.PrivateKey", kp.PrivateKey)
+	}
+
+	kp.Wipe()
+	if !wiped.Load() {
+		t.Error("registered Wipe handler was not called")
+	}
+	for _, b := range priv {
+		if b != 0 {
+			t.Fatal("raw private bytes were not zeroed")
+		}
+	}
+}
+
+func TestRegisterAlgorithmRejects(t *testing.T) {
+	t.Parallel()
+
+	valid := AlgorithmSpec{
+		Validate: func(map[string]string, bool) ([]string, error) { return nil, nil },
+		Generate: func(io.Reader, map[string]string) (*GeneratedKey, error) { return nil, fmt.Errorf("unused") },
+	}
+
+	if err := RegisterAlgorithm(AlgorithmEC, valid); err == nil {
+		t.Error("overriding built-in EC succeeded, want error")
+	}
+	if err := RegisterAlgorithm("", valid); err == nil {
+		t.Error("empty name succeeded, want error")
+	}
+	if err := RegisterAlgorithm("TEST-INCOMPLETE", AlgorithmSpec{Validate: valid.Validate}); err == nil {
+		t.Error("missing Generate succeeded, want error")
+	}
+	if _, err := ValidateKeySpec("TEST-UNKNOWN", nil, false); err == nil {
+		t.Error("ValidateKeySpec() for unregistered algorithm succeeded, want error")
+	}
+}
@@ -16,18 +16,23 @@ limitations under the License.
 
 // Package crypto provides cryptographic primitives for key generation,
 // encoding, fingerprinting, and validation. All cryptographic operations
-// use exclusively the Go standard library — no external dependencies.
+// use the Go standard library, except the opt-in brainpool curve parameters
+// from github.com/ProtonMail/go-crypto (see AllowNonNISTCurves).
 package crypto
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 // Supported algorithms.
 const (
-	AlgorithmEC  = "EC"
-	AlgorithmRSA = "RSA"
+	AlgorithmEC      = "EC"
+	AlgorithmRSA     = "RSA"
+	AlgorithmEd25519 = "Ed25519"
 )
 
 // Supported EC curves.
@@ -48,13 +53,6 @@ const (
 	RSARecommendedMinKeySize = 3072
 )
 
-// validCurves is the set of accepted NIST curves.
-var validCurves = map[string]bool{
-	CurveP256: true,
-	CurveP384: true,
-	CurveP521: true,
-}
-
 // validRSAKeySizes is the set of accepted RSA key sizes.
 var validRSAKeySizes = map[int]bool{
 	2048: true,
@@ -62,36 +60,200 @@ var validRSAKeySizes = map[int]bool{
 	4096: true,
 }
 
+// SupportedAlgorithms returns the names of all registered key algorithms, sorted.
+// It reflects exactly what ValidateKeySpec accepts, for UI and policy tooling.
+func SupportedAlgorithms() []string {
+	return RegisteredAlgorithms()
+}
+
+// SupportedCurves returns the EC curves ValidateKeySpec accepts, sorted.
+// Non-NIST curves are only included after AllowNonNISTCurves(true).
+func SupportedCurves() []string {
+	curves := make([]string, 0, len(ecCurves))
+	for _, c := range ecCurves {
+		if !c.nonNIST || nonNISTCurvesAllowed.Load() {
+			curves = append(curves, c.name)
+		}
+	}
+	sort.Strings(curves)
+	return curves
+}
+
+// SupportedRSASizes returns the RSA key sizes ValidateKeySpec accepts, ascending.
+// Sizes below RSARecommendedMinKeySize additionally require allowLegacyKeySize.
+func SupportedRSASizes() []int {
+	sizes := make([]int, 0, len(validRSAKeySizes))
+	for size := range validRSAKeySizes {
+		sizes = append(sizes, size)
+	}
+	sort.Ints(sizes)
+	return sizes
+}
+
+// RSAPublicExponent is the only public exponent supported by crypto/rsa.
+const RSAPublicExponent = "65537"
+
+// allowedParams lists the Params keys accepted per algorithm.
+// Unknown keys are rejected to catch manifest typos (e.g. "cruve") at admission.
+var allowedParams = map[string]map[string]bool{
+	AlgorithmEC:  {"curve": true},
+	AlgorithmRSA: {"keySize": true, "publicExponent": true},
+}
+
 // ValidateKeySpec validates the cryptographic parameters for key generation.
 // This is the single source of truth for algorithm validation — used by both
 // the admission webhook and the key generator.
 //
 // [COMP:G-1]: RSA < 3072 requires allowLegacy=true.
 func ValidateKeySpec(algorithm string, params map[string]string, allowLegacy bool) (warnings []string, err error) {
-	switch algorithm {
-	case AlgorithmEC:
-		return validateEC(params)
-	case AlgorithmRSA:
-		return validateRSA(params, allowLegacy)
-	default:
-		return nil, fmt.Errorf("unsupported algorithm %q, must be one of: EC, RSA", algorithm)
+	spec, ok := lookupAlgorithm(algorithm)
+	if !ok {
+		return nil, fmt.Errorf("unsupported algorithm %q, must be one of: %s",
+			algorithm, strings.Join(RegisteredAlgorithms(), ", "))
+	}
+	normalized, err := NormalizeParams(params)
+	if err != nil {
+		return nil, err
+	}
+	return spec.Validate(normalized, allowLegacy)
+}
+
+// NormalizeParams returns a copy of params with surrounding whitespace trimmed
+// from every value, as left behind by YAML block scalars (e.g. " 3072 ").
+// Values that are non-empty but whitespace-only are rejected; empty values are
+// kept so algorithms report them as missing.
+func NormalizeParams(params map[string]string) (map[string]string, error) {
+	if params == nil {
+		return nil, nil
+	}
+	normalized := make(map[string]string, len(params))
+	var blank []string
+	for k, v := range params {
+		trimmed := strings.TrimSpace(v)
+		if trimmed == "" && v != "" {
+			blank = append(blank, k)
+		}
+		normalized[k] = trimmed
+	}
+	if len(blank) > 0 {
+		sort.Strings(blank)
+		return nil, fmt.Errorf("parameter(s) %q must not be whitespace-only", blank)
+	}
+	return normalized, nil
+}
+
+// NormalizeKeySpec maps common casing mistakes to the canonical spelling:
+// the algorithm is matched case-insensitively against the registered algorithms
+// (e.g. "ec" → "EC") and an EC curve against the supported curves
+// (e.g. "p-256" → "P-256"). Unrecognized values are left for ValidateKeySpec
+// to report. params is not modified; a copy is returned when the curve changes.
+func NormalizeKeySpec(algorithm string, params map[string]string) (string, map[string]string) {
+	algorithm = canonicalName(strings.TrimSpace(algorithm), RegisteredAlgorithms())
+	if algorithm != AlgorithmEC {
+		return algorithm, params
+	}
+	curve, ok := params["curve"]
+	if !ok {
+		return algorithm, params
+	}
+	canonical := canonicalName(strings.TrimSpace(curve), SupportedCurves())
+	if canonical == curve {
+		return algorithm, params
+	}
+	normalized := make(map[string]string, len(params))
+	for k, v := range params {
+		normalized[k] = v
+	}
+	normalized["curve"] = canonical
+	return algorithm, normalized
+}
+
+// canonicalName returns the entry of names equal to name ignoring case,
+// preferring an exact match, or name itself if there is none.
+func canonicalName(name string, names []string) string {
+	match := name
+	for _, n := range names {
+		if n == name {
+			return n
+		}
+		if match == name && strings.EqualFold(n, name) {
+			match = n
+		}
+	}
+	return match
+}
+
+// validateParamKeys rejects any Params key not accepted by the given algorithm.
+func validateParamKeys(algorithm string, params map[string]string) error {
+	allowed := allowedParams[algorithm]
+
+	var unknown []string
+	for k := range params {
+		if !allowed[k] {
+			unknown = append(unknown, k)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
 	}
+
+	// Sort for deterministic error messages
+	sort.Strings(unknown)
+	valid := make([]string, 0, len(allowed))
+	for k := range allowed {
+		valid = append(valid, k)
+	}
+	sort.Strings(valid)
+
+	return fmt.Errorf("unknown %s parameter(s) %q, allowed: %s",
+		algorithm, unknown, strings.Join(valid, ", "))
 }
 
-func validateEC(params map[string]string) ([]string, error) {
+func validateEC(params map[string]string, _ bool) ([]string, error) {
+	if err := validateParamKeys(AlgorithmEC, params); err != nil {
+		return nil, err
+	}
+
 	curve, ok := params["curve"]
 	if !ok || curve == "" {
 		return nil, fmt.Errorf("EC algorithm requires 'curve' parameter")
 	}
 
-	if !validCurves[curve] {
-		return nil, fmt.Errorf("unsupported EC curve %q, must be one of: P-256, P-384, P-521", curve)
+	c, ok := lookupCurve(curve)
+	if !ok || (c.nonNIST && !nonNISTCurvesAllowed.Load()) {
+		return nil, fmt.Errorf("unsupported EC curve %q, must be one of: %s",
+			curve, strings.Join(SupportedCurves(), ", "))
+	}
+	if c.nonNIST {
+		return []string{fmt.Sprintf("EC curve %s has limited ecosystem support: it cannot be used for "+
+			"certificates or keystore formats, and many TLS and JOSE libraries reject it", curve)}, nil
 	}
 
 	return nil, nil
 }
 
+// validateEd25519 rejects any params: Ed25519 has a single fixed parameter set.
+func validateEd25519(params map[string]string, _ bool) ([]string, error) {
+	if len(params) > 0 {
+		keys := make([]string, 0, len(params))
+		for k := range params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return nil, fmt.Errorf("%s algorithm takes no parameters, got %q", AlgorithmEd25519, keys)
+	}
+	return nil, nil
+}
+
 func validateRSA(params map[string]string, allowLegacy bool) ([]string, error) {
+	if err := validateParamKeys(AlgorithmRSA, params); err != nil {
+		return nil, err
+	}
+
+	if exp, ok := params["publicExponent"]; ok && exp != RSAPublicExponent {
+		return nil, fmt.Errorf("unsupported RSA publicExponent %q, must be %s", exp, RSAPublicExponent)
+	}
+
 	keySizeStr, ok := params["keySize"]
 	if !ok || keySizeStr == "" {
 		return nil, fmt.Errorf("RSA algorithm requires 'keySize' parameter")
@@ -103,7 +265,12 @@ func validateRSA(params map[string]string, allowLegacy bool) ([]string, error) {
 	}
 
 	if !validRSAKeySizes[keySize] {
-		return nil, fmt.Errorf("unsupported RSA keySize %d, must be one of: 2048, 3072, 4096", keySize)
+		var sizes []string
+		for _, size := range SupportedRSASizes() {
+			sizes = append(sizes, strconv.Itoa(size))
+		}
+		return nil, fmt.Errorf("unsupported RSA keySize %d, must be one of: %s",
+			keySize, strings.Join(sizes, ", "))
 	}
 
 	if keySize < RSAMinKeySize {
@@ -130,3 +297,49 @@ func validateRSA(params map[string]string, allowLegacy bool) ([]string, error) {
 
 	return nil, nil
 }
+
+// DefaultKeyIDTemplate is the key ID format used when no template is configured.
+const DefaultKeyIDTemplate = "{alg}-{param}-{date}-{hex}"
+
+var (
+	// keyIDPlaceholder matches template placeholders such as {uuid}.
+	keyIDPlaceholder = regexp.MustCompile(`\{[a-z]+\}`)
+	// keyIDLiteral matches the characters allowed outside placeholders.
+	// Key IDs end up in annotations and file names, so they are kept path-safe.
+	keyIDLiteral = regexp.MustCompile(`^[A-Za-z0-9._-]*$`)
+)
+
+// keyIDPlaceholders lists supported placeholders; random ones guarantee uniqueness.
+var keyIDPlaceholders = map[string]bool{
+	"{alg}":   false,
+	"{param}": false,
+	"{date}":  false,
+	"{hex}":   true,
+	"{uuid}":  true,
+}
+
+// ValidateKeyIDTemplate checks a key ID template. An empty template is valid
+// (DefaultKeyIDTemplate is used). The template must contain a random
+// placeholder ({hex} or {uuid}) so key IDs remain unique across rotations.
+func ValidateKeyIDTemplate(template string) error {
+	if template == "" {
+		return nil
+	}
+
+	hasRandom := false
+	for _, placeholder := range keyIDPlaceholder.FindAllString(template, -1) {
+		random, ok := keyIDPlaceholders[placeholder]
+		if !ok {
+			return fmt.Errorf("unknown keyIDTemplate placeholder %s, must be one of: {alg}, {param}, {date}, {hex}, {uuid}", placeholder)
+		}
+		hasRandom = hasRandom || random
+	}
+	if !hasRandom {
+		return fmt.Errorf("keyIDTemplate %q must contain {hex} or {uuid} to keep key IDs unique", template)
+	}
+
+	if literal := keyIDPlaceholder.ReplaceAllString(template, ""); !keyIDLiteral.MatchString(literal) {
+		return fmt.Errorf("keyIDTemplate %q may only contain letters, digits, '.', '_' and '-' outside placeholders", template)
+	}
+	return nil
+}
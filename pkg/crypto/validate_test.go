@@ -0,0 +1,306 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestValidateKeySpec(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		algorithm   string
+		params      map[string]string
+		allowLegacy bool
+		wantErr     bool
+		wantWarning bool
+	}{
+		{
+			name:      "valid: EC P-256",
+			algorithm: AlgorithmEC,
+			params:    map[string]string{"curve": "P-256"},
+		},
+		{
+			name:      "invalid: EC unknown key",
+			algorithm: AlgorithmEC,
+			params:    map[string]string{"curve": "P-256", "cruve": "P-384"},
+			wantErr:   true,
+		},
+		{
+			name:      "valid: EC curve with surrounding whitespace",
+			algorithm: AlgorithmEC,
+			params:    map[string]string{"curve": " P-256 "},
+		},
+		{
+			name:      "invalid: EC whitespace-only curve",
+			algorithm: AlgorithmEC,
+			params:    map[string]string{"curve": "  "},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid: EC missing curve",
+			algorithm: AlgorithmEC,
+			params:    map[string]string{},
+			wantErr:   true,
+		},
+		{
+			name:      "valid: RSA 3072",
+			algorithm: AlgorithmRSA,
+			params:    map[string]string{"keySize": "3072"},
+		},
+		{
+			name:      "valid: RSA keySize with surrounding whitespace",
+			algorithm: AlgorithmRSA,
+			params:    map[string]string{"keySize": " 3072 "},
+		},
+		{
+			name:      "invalid: RSA whitespace-only keySize",
+			algorithm: AlgorithmRSA,
+			params:    map[string]string{"keySize": "\n"},
+			wantErr:   true,
+		},
+		{
+			name:      "valid: RSA with publicExponent",
+			algorithm: AlgorithmRSA,
+			params:    map[string]string{"keySize": "3072", "publicExponent": "65537"},
+		},
+		{
+			name:      "invalid: RSA unsupported publicExponent",
+			algorithm: AlgorithmRSA,
+			params:    map[string]string{"keySize": "3072", "publicExponent": "3"},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid: RSA unknown key",
+			algorithm: AlgorithmRSA,
+			params:    map[string]string{"keySize": "3072", "keysize": "4096"},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid: RSA curve key",
+			algorithm: AlgorithmRSA,
+			params:    map[string]string{"keySize": "3072", "curve": "P-256"},
+			wantErr:   true,
+		},
+		{
+			name:      "invalid: RSA 2048 without legacy",
+			algorithm: AlgorithmRSA,
+			params:    map[string]string{"keySize": "2048"},
+			wantErr:   true,
+		},
+		{
+			name:        "valid: RSA 2048 with legacy warns",
+			algorithm:   AlgorithmRSA,
+			params:      map[string]string{"keySize": "2048"},
+			allowLegacy: true,
+			wantWarning: true,
+		},
+		{
+			name:      "valid: Ed25519 without params",
+			algorithm: AlgorithmEd25519,
+		},
+		{
+			name:      "valid: Ed25519 empty params",
+			algorithm: AlgorithmEd25519,
+			params:    map[string]string{},
+		},
+		{
+			name:      "invalid: Ed25519 with curve",
+			algorithm: AlgorithmEd25519,
+			params:    map[string]string{"curve": "Ed25519"},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			warnings, err := ValidateKeySpec(tt.algorithm, tt.params, tt.allowLegacy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateKeySpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (len(warnings) > 0) != tt.wantWarning {
+				t.Errorf("ValidateKeySpec() warnings = %v, wantWarning %v", warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestGenerateNormalizesParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		opts      GenerateOptions
+		wantParam string
+	}{
+		{
+			name:      "EC curve",
+			opts:      GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": " P-256 "}},
+			wantParam: "-p-256-",
+		},
+		{
+			name:      "RSA keySize",
+			opts:      GenerateOptions{Algorithm: AlgorithmRSA, Params: map[string]string{"keySize": " 3072 "}},
+			wantParam: "-3072-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			kp, err := NewKeyGenerator().Generate(tt.opts)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			defer kp.Wipe()
+			if !strings.Contains(strings.ToLower(kp.KeyID), tt.wantParam) {
+				t.Errorf("KeyID = %q, want it to contain %q", kp.KeyID, tt.wantParam)
+			}
+		})
+	}
+}
+
+func TestSupportedValuesMatchValidateKeySpec(t *testing.T) {
+	t.Parallel()
+
+	algorithms := SupportedAlgorithms()
+	for _, want := range []string{AlgorithmEC, AlgorithmRSA, AlgorithmEd25519} {
+		if !slices.Contains(algorithms, want) {
+			t.Errorf("SupportedAlgorithms() = %v, missing %s", algorithms, want)
+		}
+	}
+	if _, err := ValidateKeySpec("DSA", nil, false); err == nil || slices.Contains(algorithms, "DSA") {
+		t.Errorf("DSA: supported = %v, ValidateKeySpec error = %v; want unsupported and rejected", algorithms, err)
+	}
+
+	curves := SupportedCurves()
+	if !slices.IsSorted(curves) || !slices.Equal(curves, SupportedCurves()) {
+		t.Errorf("SupportedCurves() = %v, want a stable sorted slice", curves)
+	}
+	for _, curve := range curves {
+		if _, err := ValidateKeySpec(AlgorithmEC, map[string]string{"curve": curve}, false); err != nil {
+			t.Errorf("ValidateKeySpec(EC, %s) error = %v, want accepted", curve, err)
+		}
+	}
+	if _, err := ValidateKeySpec(AlgorithmEC, map[string]string{"curve": "P-224"}, false); err == nil {
+		t.Error("ValidateKeySpec(EC, P-224) accepted an unlisted curve")
+	}
+
+	sizes := SupportedRSASizes()
+	if !slices.IsSorted(sizes) || !slices.Equal(sizes, SupportedRSASizes()) {
+		t.Errorf("SupportedRSASizes() = %v, want a stable sorted slice", sizes)
+	}
+	for _, size := range sizes {
+		params := map[string]string{"keySize": strconv.Itoa(size)}
+		if _, err := ValidateKeySpec(AlgorithmRSA, params, true); err != nil {
+			t.Errorf("ValidateKeySpec(RSA, %d) error = %v, want accepted", size, err)
+		}
+	}
+	for _, size := range []int{1024, 8192} {
+		params := map[string]string{"keySize": strconv.Itoa(size)}
+		if _, err := ValidateKeySpec(AlgorithmRSA, params, true); err == nil {
+			t.Errorf("ValidateKeySpec(RSA, %d) accepted an unlisted size", size)
+		}
+	}
+}
+
+func TestNormalizeKeySpec(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		algorithm     string
+		params        map[string]string
+		wantAlgorithm string
+		wantParams    map[string]string
+		wantValid     bool
+	}{
+		{
+			name:          "canonical input unchanged",
+			algorithm:     AlgorithmEC,
+			params:        map[string]string{"curve": CurveP256},
+			wantAlgorithm: AlgorithmEC,
+			wantParams:    map[string]string{"curve": CurveP256},
+			wantValid:     true,
+		},
+		{
+			name:          "lowercase algorithm and curve",
+			algorithm:     "ec",
+			params:        map[string]string{"curve": "p-384"},
+			wantAlgorithm: AlgorithmEC,
+			wantParams:    map[string]string{"curve": CurveP384},
+			wantValid:     true,
+		},
+		{
+			name:          "mixed case with whitespace",
+			algorithm:     " Ec ",
+			params:        map[string]string{"curve": " p-521"},
+			wantAlgorithm: AlgorithmEC,
+			wantParams:    map[string]string{"curve": CurveP521},
+			wantValid:     true,
+		},
+		{
+			name:          "lowercase RSA keeps params",
+			algorithm:     "rsa",
+			params:        map[string]string{"keySize": "3072"},
+			wantAlgorithm: AlgorithmRSA,
+			wantParams:    map[string]string{"keySize": "3072"},
+			wantValid:     true,
+		},
+		{
+			name:          "unknown values left for validation",
+			algorithm:     "dsa",
+			params:        map[string]string{"curve": "p-999"},
+			wantAlgorithm: "dsa",
+			wantParams:    map[string]string{"curve": "p-999"},
+		},
+		{
+			name:          "unknown curve keeps EC normalization",
+			algorithm:     "ec",
+			params:        map[string]string{"curve": "secp256k1"},
+			wantAlgorithm: AlgorithmEC,
+			wantParams:    map[string]string{"curve": "secp256k1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			original := maps.Clone(tt.params)
+			algorithm, params := NormalizeKeySpec(tt.algorithm, tt.params)
+			if algorithm != tt.wantAlgorithm {
+				t.Errorf("algorithm = %q, want %q", algorithm, tt.wantAlgorithm)
+			}
+			if !maps.Equal(params, tt.wantParams) {
+				t.Errorf("params = %v, want %v", params, tt.wantParams)
+			}
+			if !maps.Equal(tt.params, original) {
+				t.Errorf("input params modified to %v", tt.params)
+			}
+			if _, err := ValidateKeySpec(algorithm, params, false); (err == nil) != tt.wantValid {
+				t.Errorf("ValidateKeySpec(%q, %v) error = %v, wantValid %v", algorithm, params, err, tt.wantValid)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+// ParsePrivateKeyPEM parses the first private key PEM block in data.
+// PKCS#8 ("PRIVATE KEY"), PKCS#1 ("RSA PRIVATE KEY") and SEC 1 ("EC PRIVATE KEY") are supported.
+// RSA keys are precomputed and validated. Blocks of other types are skipped;
+// malformed, truncated or inconsistent input yields an error.
+func ParsePrivateKeyPEM(data []byte) (crypto.PrivateKey, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no private key PEM block found")
+		}
+		var (
+			key crypto.PrivateKey
+			err error
+		)
+		switch block.Type {
+		case "PRIVATE KEY":
+			key, err = parsePKCS8PrivateKey(block.Bytes)
+		case "RSA PRIVATE KEY":
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		case "EC PRIVATE KEY":
+			key, err = parseECPrivateKey(block.Bytes)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("malformed %s block: %w", block.Type, err)
+		}
+		if err := validatePrivateKey(key); err != nil {
+			return nil, err
+		}
+		return key, nil
+	}
+}
+
+// validatePrivateKey precomputes the CRT values of a parsed RSA private key
+// and rejects keys whose parameters are inconsistent, so that imported keys
+// get the same checks and signing speed as generated ones. Other key types
+// pass unchanged.
+func validatePrivateKey(key crypto.PrivateKey) error {
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil
+	}
+	rsaKey.Precompute()
+	if err := rsaKey.Validate(); err != nil {
+		return fmt.Errorf("invalid RSA private key: %w", err)
+	}
+	return nil
+}
+
+// ParsePublicKeyPEM parses the first PKIX "PUBLIC KEY" PEM block in data.
+// Blocks of other types are skipped; malformed or truncated input yields an error.
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no public key PEM block found")
+		}
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		key, err := parsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("malformed %s block: %w", block.Type, err)
+		}
+		return key, nil
+	}
+}
+
+// VerifyKeyPair checks that pub is the public half of priv by comparing it
+// with the public key derived from priv.
+// [SEC:T-1]
+func VerifyKeyPair(priv crypto.PrivateKey, pub crypto.PublicKey) error {
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("unsupported private key type %T", priv)
+	}
+	derived, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	if !ok {
+		return fmt.Errorf("unsupported public key type %T", signer.Public())
+	}
+	if !derived.Equal(pub) {
+		return fmt.Errorf("public key does not match private key")
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crypto
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"testing"
+)
+
+func TestVerifyKeyPair(t *testing.T) {
+	t.Parallel()
+
+	for _, opts := range []GenerateOptions{
+		{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP256}},
+		{Algorithm: AlgorithmRSA, Params: map[string]string{"keySize": "3072"}},
+	} {
+		t.Run(opts.Algorithm, func(t *testing.T) {
+			t.Parallel()
+			kpA, err := NewKeyGenerator().Generate(opts)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			defer kpA.Wipe()
+			kpB, err := NewKeyGenerator().Generate(opts)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			defer kpB.Wipe()
+
+			encoder, err := NewKeyEncoder("PEM")
+			if err != nil {
+				t.Fatalf("NewKeyEncoder() error = %v", err)
+			}
+			privPEM, err := encoder.EncodePrivate(kpA.PrivateKey)
+			if err != nil {
+				t.Fatalf("EncodePrivate() error = %v", err)
+			}
+			pubPEM, err := encoder.EncodePublic(kpA.PublicKey)
+			if err != nil {
+				t.Fatalf("EncodePublic() error = %v", err)
+			}
+			priv, err := ParsePrivateKeyPEM(privPEM)
+			if err != nil {
+				t.Fatalf("ParsePrivateKeyPEM() error = %v", err)
+			}
+			pub, err := ParsePublicKeyPEM(append(privPEM, pubPEM...))
+			if err != nil {
+				t.Fatalf("ParsePublicKeyPEM() error = %v", err)
+			}
+
+			if err := VerifyKeyPair(priv, pub); err != nil {
+				t.Errorf("VerifyKeyPair(matching) error = %v", err)
+			}
+			if err := VerifyKeyPair(priv, kpB.PublicKey); err == nil {
+				t.Error("VerifyKeyPair(mismatched) succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestParseKeyPEMMalformed(t *testing.T) {
+	t.Parallel()
+
+	kp, err := NewKeyGenerator().Generate(GenerateOptions{Algorithm: AlgorithmEC, Params: map[string]string{"curve": CurveP256}})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer kp.Wipe()
+	encoder, err := NewKeyEncoder("PEM")
+	if err != nil {
+		t.Fatalf("NewKeyEncoder() error = %v", err)
+	}
+	privPEM, err := encoder.EncodePrivate(kp.PrivateKey)
+	if err != nil {
+		t.Fatalf("EncodePrivate() error = %v", err)
+	}
+	pubPEM, err := encoder.EncodePublic(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("EncodePublic() error = %v", err)
+	}
+	pemBlock := func(typ string, b []byte) []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: b})
+	}
+	privDER, _ := pem.Decode(privPEM)
+	pubDER, _ := pem.Decode(pubPEM)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "garbage", data: []byte("not a key \x00\xff")},
+		{name: "truncated private PEM", data: privPEM[:len(privPEM)/2]},
+		{name: "truncated public PEM", data: pubPEM[:len(pubPEM)/2]},
+		{name: "truncated DER", data: pemBlock("PRIVATE KEY", privDER.Bytes[:len(privDER.Bytes)/2])},
+		{name: "garbage DER", data: pemBlock("PUBLIC KEY", []byte("garbage"))},
+		{name: "wrong block type", data: pemBlock("CERTIFICATE", pubDER.Bytes)},
+		{name: "public key in private block", data: pemBlock("EC PRIVATE KEY", pubDER.Bytes)},
+		{name: "private key in public block", data: pemBlock("PUBLIC KEY", privDER.Bytes)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := ParsePrivateKeyPEM(tt.data); err == nil {
+				t.Error("ParsePrivateKeyPEM() succeeded, want error")
+			}
+			if _, err := ParsePublicKeyPEM(tt.data); err == nil {
+				t.Error("ParsePublicKeyPEM() succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestParsePrivateKeyPEMValidatesRSA(t *testing.T) {
+	t.Parallel()
+
+	kp, err := NewKeyGenerator().Generate(GenerateOptions{
+		Algorithm:          AlgorithmRSA,
+		Params:             map[string]string{"keySize": "2048"},
+		AllowLegacyKeySize: true,
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	defer kp.Wipe()
+	key := kp.PrivateKey.(*rsa.PrivateKey)
+	pkcs1PEM := func(k *rsa.PrivateKey) []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)})
+	}
+
+	parsed, err := ParsePrivateKeyPEM(pkcs1PEM(key))
+	if err != nil {
+		t.Fatalf("ParsePrivateKeyPEM(valid) error = %v", err)
+	}
+	if got := parsed.(*rsa.PrivateKey); got.Precomputed.Dp == nil || !got.Equal(key) {
+		t.Error("ParsePrivateKeyPEM(valid) did not return the precomputed key")
+	}
+
+	// Keys carrying inconsistent CRT values would otherwise only show as wrong
+	// signatures
+	tampered := func(field int) *rsa.PrivateKey {
+		values := []*big.Int{key.Precomputed.Dp, key.Precomputed.Dq, key.Precomputed.Qinv}
+		values[field] = new(big.Int).Add(values[field], big.NewInt(2))
+		return &rsa.PrivateKey{
+			PublicKey:   key.PublicKey,
+			D:           key.D,
+			Primes:      key.Primes,
+			Precomputed: rsa.PrecomputedValues{Dp: values[0], Dq: values[1], Qinv: values[2]},
+		}
+	}
+	for field, name := range []string{"dP", "dQ", "qInv"} {
+		if err := validatePrivateKey(tampered(field)); err == nil {
+			t.Errorf("validatePrivateKey(tampered %s) succeeded, want error", name)
+		}
+	}
+}
@@ -17,41 +17,254 @@ limitations under the License.
 package metrics
 
 import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
+	k8svalidation "k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
 var (
 	// RotationsTotal counts the number of successful key rotations.
-	RotationsTotal = prometheus.NewCounterVec(
+	RotationsTotal = newRotationsTotal(nil)
+
+	// RotationErrorsTotal counts the number of failed rotation attempts.
+	RotationErrorsTotal = newRotationErrorsTotal(nil)
+
+	// KeyGenerationDuration tracks the latency of cryptographic key generation.
+	KeyGenerationDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "openukr_key_generation_duration_seconds",
+			Help:    "Latency of cryptographic key generation",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"algorithm"},
+	)
+
+	// RenderDuration tracks the latency of rendering key material into Secret data
+	// per output format. Keystore formats include certificate generation.
+	RenderDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "openukr_render_duration_seconds",
+			Help:    "Latency of rendering key material into Secret data by output format",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"format"},
+	)
+
+	// ReconcilesTotal counts reconciles of in-scope KeyProfiles by outcome, separating
+	// controller churn (noop) from actual rotations.
+	ReconcilesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "openukr_reconciles_total",
+			Help: "Number of KeyProfile reconciles by result (rotated, noop, error)",
+		},
+		[]string{"namespace", "result"},
+	)
+
+	// PublishPanicsTotal counts publisher panics recovered while publishing, by
+	// target type. Each one fails its target instead of crashing the controller.
+	PublishPanicsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "openukr_publish_panics_total",
+			Help: "Number of recovered publisher panics by publish target type",
+		},
+		[]string{"type"},
+	)
+
+	// KeyNextRotationTimestamp records the scheduled next rotation of each KeyProfile.
+	KeyNextRotationTimestamp = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "openukr_key_next_rotation_timestamp_seconds",
+			Help: "Unix timestamp of the next scheduled key rotation per KeyProfile",
+		},
+		[]string{"namespace", "keyprofile"},
+	)
+)
+
+func newRotationsTotal(custom []string) *prometheus.CounterVec {
+	return prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "openukr_rotations_total",
 			Help: "Number of successful key rotations",
 		},
-		[]string{"algorithm", "namespace"},
+		append([]string{"algorithm", "namespace"}, custom...),
 	)
+}
 
-	// RotationErrorsTotal counts the number of failed rotation attempts.
-	RotationErrorsTotal = prometheus.NewCounterVec(
+func newRotationErrorsTotal(custom []string) *prometheus.CounterVec {
+	return prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "openukr_rotation_errors_total",
 			Help: "Number of failed rotation attempts",
 		},
-		[]string{"reason", "namespace"},
+		append([]string{"reason", "namespace"}, custom...),
 	)
+}
 
-	// KeyGenerationDuration tracks the latency of cryptographic key generation.
-	KeyGenerationDuration = prometheus.NewHistogramVec(
-		prometheus.HistogramOpts{
-			Name:    "openukr_key_generation_duration_seconds",
-			Help:    "Latency of cryptographic key generation",
-			Buckets: prometheus.DefBuckets,
-		},
-		[]string{"algorithm"},
+// profileLabelsDisabled suppresses per-namespace and per-KeyProfile label values.
+// The zero value records them.
+var profileLabelsDisabled atomic.Bool
+
+// SetProfileLabels enables or disables the high-cardinality namespace and keyprofile labels.
+// When disabled, the namespace label is recorded as "" (aggregating all namespaces into one
+// series) and per-KeyProfile gauges are not recorded at all. This bounds series count on
+// large fleets at the cost of per-namespace and per-profile breakdowns.
+func SetProfileLabels(enabled bool) {
+	profileLabelsDisabled.Store(!enabled)
+}
+
+// Namespace returns the namespace label value to record for ns.
+func Namespace(ns string) string {
+	if profileLabelsDisabled.Load() {
+		return ""
+	}
+	return ns
+}
+
+// MaxCustomLabels bounds the number of KeyProfile labels propagated to metrics.
+const MaxCustomLabels = 5
+
+// customLabelKeys lists the KeyProfile label keys recorded on the rotation
+// counters. It is set once at startup by SetCustomLabels.
+var customLabelKeys []string
+
+// CustomLabelName returns the metric label name recording KeyProfile label key:
+// label_ followed by the key with characters invalid in Prometheus label names
+// replaced by underscores, e.g. team.example.com/env → label_team_example_com_env.
+func CustomLabelName(key string) string {
+	return "label_" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// SetCustomLabels propagates the values of the given KeyProfile label keys to
+// RotationsTotal and RotationErrorsTotal, replacing both collectors in reg.
+// Profiles without a label record it as "". It must be called at startup,
+// before any rotation is recorded. Keys must be valid Kubernetes label keys
+// mapping to distinct metric label names; at most MaxCustomLabels are allowed
+// to bound series cardinality.
+func SetCustomLabels(reg prometheus.Registerer, keys []string) error {
+	if len(keys) > MaxCustomLabels {
+		return fmt.Errorf("%d custom metric labels, at most %d allowed", len(keys), MaxCustomLabels)
+	}
+	names := make([]string, 0, len(keys))
+	seen := make(map[string]string, len(keys))
+	for _, key := range keys {
+		if errs := k8svalidation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid custom metric label %q: %s", key, strings.Join(errs, "; "))
+		}
+		name := CustomLabelName(key)
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("custom metric labels %q and %q both map to %s", other, key, name)
+		}
+		seen[name] = key
+		names = append(names, name)
+	}
+
+	reg.Unregister(RotationsTotal)
+	reg.Unregister(RotationErrorsTotal)
+	RotationsTotal = newRotationsTotal(names)
+	RotationErrorsTotal = newRotationErrorsTotal(names)
+	customLabelKeys = keys
+	return errors.Join(
+		register(reg, &RotationsTotal),
+		register(reg, &RotationErrorsTotal),
 	)
+}
+
+// customLabelValues returns the values of the custom label keys in profileLabels.
+func customLabelValues(profileLabels map[string]string) []string {
+	values := make([]string, len(customLabelKeys))
+	for i, key := range customLabelKeys {
+		values[i] = profileLabels[key]
+	}
+	return values
+}
+
+// RecordRotation counts a successful rotation of a KeyProfile with the given labels.
+func RecordRotation(algorithm, namespace string, profileLabels map[string]string) {
+	values := append([]string{algorithm, Namespace(namespace)}, customLabelValues(profileLabels)...)
+	RotationsTotal.WithLabelValues(values...).Inc()
+}
+
+// RecordRotationError counts a failed rotation attempt of a KeyProfile with the given labels.
+func RecordRotationError(reason, namespace string, profileLabels map[string]string) {
+	values := append([]string{reason, Namespace(namespace)}, customLabelValues(profileLabels)...)
+	RotationErrorsTotal.WithLabelValues(values...).Inc()
+}
+
+// Result label values of ReconcilesTotal.
+const (
+	ReconcileRotated = "rotated"
+	ReconcileNoop    = "noop"
+	ReconcileError   = "error"
 )
 
+// RecordReconcile counts a finished reconcile: error if err is non-nil, otherwise
+// rotated or noop.
+func RecordReconcile(namespace string, rotated bool, err error) {
+	result := ReconcileNoop
+	switch {
+	case err != nil:
+		result = ReconcileError
+	case rotated:
+		result = ReconcileRotated
+	}
+	ReconcilesTotal.WithLabelValues(Namespace(namespace), result).Inc()
+}
+
+// SetNextRotation records the next scheduled rotation of a KeyProfile.
+// It is a no-op when profile labels are disabled.
+func SetNextRotation(namespace, name string, next time.Time) {
+	if profileLabelsDisabled.Load() {
+		return
+	}
+	KeyNextRotationTimestamp.WithLabelValues(namespace, name).Set(float64(next.Unix()))
+}
+
+// DeleteProfile removes all per-KeyProfile series of a deleted KeyProfile.
+func DeleteProfile(namespace, name string) {
+	KeyNextRotationTimestamp.DeleteLabelValues(namespace, name)
+}
+
 func init() {
-	// Register custom metrics with the global prometheus registry
-	metrics.Registry.MustRegister(RotationsTotal, RotationErrorsTotal, KeyGenerationDuration)
+	// Register custom metrics with the global controller-runtime registry.
+	// A conflicting collector leaves the metric unexported instead of crashing
+	// the process; callers that need the error can call Register themselves.
+	_ = Register(metrics.Registry)
+}
+
+// Register registers the openUKR collectors with reg. It is safe to call more
+// than once: if an equivalent collector is already registered, the existing one
+// is kept and the package-level variable is pointed at it.
+func Register(reg prometheus.Registerer) error {
+	return errors.Join(
+		register(reg, &RotationsTotal),
+		register(reg, &RotationErrorsTotal),
+		register(reg, &KeyGenerationDuration),
+		register(reg, &RenderDuration),
+		register(reg, &ReconcilesTotal),
+		register(reg, &PublishPanicsTotal),
+		register(reg, &KeyNextRotationTimestamp),
+	)
+}
+
+func register[T prometheus.Collector](reg prometheus.Registerer, c *T) error {
+	err := reg.Register(*c)
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(T); ok {
+			*c = existing
+			return nil
+		}
+	}
+	return err
 }
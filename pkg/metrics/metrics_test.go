@@ -0,0 +1,152 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func TestRegisterTwice(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if err := Register(reg); err != nil {
+		t.Fatalf("first Register: %v", err)
+	}
+	if err := Register(reg); err != nil {
+		t.Fatalf("second Register: %v", err)
+	}
+	// init already registered with the global registry.
+	if err := Register(metrics.Registry); err != nil {
+		t.Fatalf("Register on global registry: %v", err)
+	}
+}
+
+func TestRegisterKeepsExistingCollector(t *testing.T) {
+	orig := RotationsTotal
+	t.Cleanup(func() { RotationsTotal = orig })
+
+	existing := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "openukr_rotations_total",
+			Help: "Number of successful key rotations",
+		},
+		[]string{"algorithm", "namespace"},
+	)
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(existing)
+
+	if err := Register(reg); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	if RotationsTotal != existing {
+		t.Fatal("expected RotationsTotal to point at the already registered collector")
+	}
+}
+
+func TestRegisterConflictingCollector(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "openukr_rotations_total",
+		Help: "conflicting help",
+	}))
+
+	if err := Register(reg); err == nil {
+		t.Fatal("expected error for conflicting collector, got nil")
+	}
+}
+
+func TestProfileLabelsDisabled(t *testing.T) {
+	t.Cleanup(func() {
+		SetProfileLabels(true)
+		KeyNextRotationTimestamp.Reset()
+	})
+	KeyNextRotationTimestamp.Reset()
+
+	SetProfileLabels(false)
+	if got := Namespace("team-a"); got != "" {
+		t.Errorf("Namespace() = %q, want empty", got)
+	}
+	SetNextRotation("team-a", "signer", time.Unix(1700000000, 0))
+	if n := testutil.CollectAndCount(KeyNextRotationTimestamp); n != 0 {
+		t.Errorf("expected no per-profile series, got %d", n)
+	}
+
+	SetProfileLabels(true)
+	if got := Namespace("team-a"); got != "team-a" {
+		t.Errorf("Namespace() = %q, want %q", got, "team-a")
+	}
+	SetNextRotation("team-a", "signer", time.Unix(1700000000, 0))
+	if n := testutil.CollectAndCount(KeyNextRotationTimestamp); n != 1 {
+		t.Errorf("expected 1 per-profile series, got %d", n)
+	}
+}
+
+func TestDeleteProfile(t *testing.T) {
+	t.Cleanup(KeyNextRotationTimestamp.Reset)
+	KeyNextRotationTimestamp.Reset()
+
+	SetNextRotation("team-a", "signer", time.Unix(1700000000, 0))
+	SetNextRotation("team-a", "verifier", time.Unix(1700000000, 0))
+	DeleteProfile("team-a", "signer")
+
+	if n := testutil.CollectAndCount(KeyNextRotationTimestamp); n != 1 {
+		t.Errorf("expected 1 remaining series, got %d", n)
+	}
+}
+
+func TestSetCustomLabels(t *testing.T) {
+	origRotations, origErrors := RotationsTotal, RotationErrorsTotal
+	t.Cleanup(func() {
+		RotationsTotal, RotationErrorsTotal = origRotations, origErrors
+		customLabelKeys = nil
+	})
+
+	reg := prometheus.NewRegistry()
+	if err := SetCustomLabels(reg, []string{"team", "example.com/env"}); err != nil {
+		t.Fatalf("SetCustomLabels() error = %v", err)
+	}
+	RecordRotation("EC", "team-a", map[string]string{"team": "payments", "example.com/env": "prod", "other": "x"})
+	RecordRotationError("publish", "team-a", map[string]string{"team": "payments"})
+
+	rotations := RotationsTotal.WithLabelValues("EC", "team-a", "payments", "prod")
+	if got := testutil.ToFloat64(rotations); got != 1 {
+		t.Errorf("rotations{label_team=payments,label_example_com_env=prod} = %v, want 1", got)
+	}
+	errs := RotationErrorsTotal.With(prometheus.Labels{
+		"reason": "publish", "namespace": "team-a", "label_team": "payments", "label_example_com_env": "",
+	})
+	if got := testutil.ToFloat64(errs); got != 1 {
+		t.Errorf("rotation errors{label_team=payments} = %v, want 1", got)
+	}
+	if n, err := testutil.GatherAndCount(reg, "openukr_rotations_total"); err != nil || n != 1 {
+		t.Errorf("GatherAndCount() = %d, %v, want 1 registered series", n, err)
+	}
+
+	for name, keys := range map[string][]string{
+		"too many":  {"a", "b", "c", "d", "e", "f"},
+		"invalid":   {"not a label"},
+		"collision": {"team.env", "team_env"},
+	} {
+		if err := SetCustomLabels(prometheus.NewRegistry(), keys); err == nil {
+			t.Errorf("%s: SetCustomLabels(%v) expected error, got nil", name, keys)
+		}
+	}
+}
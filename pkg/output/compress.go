@@ -0,0 +1,77 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// compressionAnnotation records the compression applied to rendered JSON entries.
+const compressionAnnotation = "openukr.io/compression"
+
+// gzipSuffix is appended to the data key of compressed entries.
+const gzipSuffix = ".gz"
+
+// compressJSON gzips every .json entry of data, renaming it to .json.gz.
+// Other entries are returned unchanged. The gzip header carries no name or
+// timestamp, so equal input yields equal output and Secrets stay stable.
+func compressJSON(data map[string][]byte) (map[string][]byte, error) {
+	out := make(map[string][]byte, len(data))
+	for k, v := range data {
+		if !strings.HasSuffix(k, ".json") {
+			out[k] = v
+			continue
+		}
+		compressed, err := Gzip(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compress %s: %w", k, err)
+		}
+		out[k+gzipSuffix] = compressed
+	}
+	return out, nil
+}
+
+// Gzip compresses b with gzip at the default level.
+func Gzip(b []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(b); err != nil {
+		return nil, fmt.Errorf("gzip write failed: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("gzip close failed: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// gunzipBytes decompresses gzip data written by Gzip.
+func gunzipBytes(b []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("gzip read failed: %w", err)
+	}
+	defer zr.Close()
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("gzip read failed: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,52 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"net/url"
+
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+// NewCSR creates a PEM-encoded PKCS#10 certificate signing request for the KeyPair,
+// to be signed by an external CA. If spiffeID is set, it is requested as a URI SAN.
+func NewCSR(kp *crypto.KeyPair, spiffeID *url.URL) ([]byte, error) {
+	if kp == nil {
+		return nil, fmt.Errorf("cannot create CSR for nil KeyPair")
+	}
+
+	template := x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   kp.KeyID,
+			Organization: []string{"openUKR"},
+		},
+	}
+	if spiffeID != nil {
+		template.URIs = []*url.URL{spiffeID}
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &template, kp.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate request: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), nil
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+)
+
+// encryptionAnnotation records the encryption applied to private key entries.
+const encryptionAnnotation = "openukr.io/encryption"
+
+// ageSuffix is appended to the data key of age-encrypted entries.
+const ageSuffix = ".age"
+
+// kmsSuffix is appended to the data key of envelope-encrypted entries.
+const kmsSuffix = ".kms.json"
+
+// Encryption schemes recorded in encryptionAnnotation.
+const (
+	encryptionAge           = "age"
+	encryptionAzureKeyVault = "azurekeyvault"
+)
+
+// KeyWrapper wraps the data keys of envelope-encrypted outputs with a key held
+// in a KMS (see OutputEncryption.AzureKeyVault). Implementations must be safe
+// for concurrent use.
+type KeyWrapper interface {
+	// WrapKey encrypts dek with the key of cfg, authenticating with credentials
+	// from namespace, and returns the wrapped key and the ID of the key version
+	// that wrapped it.
+	WrapKey(ctx context.Context, namespace string, cfg openukrv1alpha1.AzureKeyVaultEncryption, dek []byte) ([]byte, string, error)
+}
+
+// WithKeyWrapper enables envelope encryption of outputs with
+// OutputEncryption.AzureKeyVault set; without it they fail to write.
+func WithKeyWrapper(kw KeyWrapper) WriterOption {
+	return func(w *kubeSecretWriter) {
+		w.keyWrapper = kw
+	}
+}
+
+// encryptionScheme returns the encryption scheme of out, "" if it is not encrypted.
+func encryptionScheme(out openukrv1alpha1.OutputConfig) string {
+	switch {
+	case out.Encryption == nil:
+		return ""
+	case out.Encryption.Age != nil:
+		return encryptionAge
+	case out.Encryption.AzureKeyVault != nil:
+		return encryptionAzureKeyVault
+	}
+	return ""
+}
+
+// kmsKeyURL returns the KMS key URL of out, "" if it is not envelope-encrypted.
+func kmsKeyURL(out openukrv1alpha1.OutputConfig) string {
+	if out.Encryption == nil || out.Encryption.AzureKeyVault == nil {
+		return ""
+	}
+	return out.Encryption.AzureKeyVault.KeyURL
+}
+
+// ValidateAgeRecipients checks OutputConfig.Encryption.Age.Recipients: at
+// least one recipient, each an age X25519 public key ("age1...").
+func ValidateAgeRecipients(recipients []string) error {
+	_, err := parseAgeRecipients(recipients)
+	return err
+}
+
+// ageRecipients returns the age recipients of out, nil if it is not encrypted.
+func ageRecipients(out openukrv1alpha1.OutputConfig) []string {
+	if out.Encryption == nil || out.Encryption.Age == nil {
+		return nil
+	}
+	return out.Encryption.Age.Recipients
+}
+
+func parseAgeRecipients(recipients []string) ([]age.Recipient, error) {
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("encryption.age.recipients must not be empty")
+	}
+	parsed := make([]age.Recipient, 0, len(recipients))
+	for i, s := range recipients {
+		r, err := age.ParseX25519Recipient(s)
+		if err != nil {
+			return nil, fmt.Errorf("encryption.age.recipients[%d]: %w", i, err)
+		}
+		parsed = append(parsed, r)
+	}
+	return parsed, nil
+}
+
+// encryptPrivate age-encrypts every entry of data that is not public material
+// to recipients, storing it ASCII-armored as {key}.age so GitOps tooling such
+// as SOPS can commit the Secret as-is. Public entries stay plaintext. The
+// plaintext of encrypted entries is wiped. [SEC:I-2]
+func encryptPrivate(data map[string][]byte, recipients []string, keyNames map[string]string) (map[string][]byte, error) {
+	parsed, err := parseAgeRecipients(recipients)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]byte, len(data))
+	for k, v := range data {
+		if isPublicEntry(k, keyNames) {
+			out[k] = v
+			continue
+		}
+		encrypted, err := ageEncrypt(v, parsed)
+		clear(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt %s: %w", k, err)
+		}
+		out[k+ageSuffix] = encrypted
+	}
+	return out, nil
+}
+
+// ageEncrypt encrypts plaintext to recipients in the armored age format.
+func ageEncrypt(plaintext []byte, recipients []age.Recipient) ([]byte, error) {
+	var buf bytes.Buffer
+	aw := armor.NewWriter(&buf)
+	w, err := age.Encrypt(aw, recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	if err := aw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// kmsEnvelope is the JSON document stored for an envelope-encrypted entry.
+type kmsEnvelope struct {
+	// KeyID is the KMS key version that wrapped the data key.
+	KeyID string `json:"kid"`
+	// Algorithm is the key wrapping algorithm.
+	Algorithm string `json:"alg"`
+	// Encryption is the content encryption algorithm.
+	Encryption string `json:"enc"`
+	WrappedKey []byte `json:"wrappedKey"`
+	Nonce      []byte `json:"nonce"`
+	// Ciphertext is sealed with the entry name as additional data, so
+	// envelopes cannot be swapped between entries.
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// envelopeEncryptPrivate encrypts every entry of data that is not public
+// material with a fresh AES-256-GCM data key, storing it as {key}.kms.json
+// with the data key wrapped by the KMS key of profile. Public entries stay
+// plaintext. The plaintext and the data key are wiped. [SEC:I-2]
+func (w *kubeSecretWriter) envelopeEncryptPrivate(
+	ctx context.Context,
+	profile *openukrv1alpha1.KeyProfile,
+	data map[string][]byte,
+) (map[string][]byte, error) {
+	cfg := profile.Spec.Output.Encryption.AzureKeyVault
+	if w.keyWrapper == nil {
+		return nil, fmt.Errorf("azureKeyVault encryption is not enabled on this controller")
+	}
+
+	dek := make([]byte, 32)
+	defer clear(dek)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	wrapped, keyID, err := w.keyWrapper.WrapKey(ctx, profile.Namespace, *cfg, dek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap data key: %w", err)
+	}
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	keyNames := profile.Spec.Output.KeyNames
+	out := make(map[string][]byte, len(data))
+	for k, v := range data {
+		if isPublicEntry(k, keyNames) {
+			out[k] = v
+			continue
+		}
+		nonce := make([]byte, aead.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, fmt.Errorf("failed to generate nonce: %w", err)
+		}
+		envelope, err := json.Marshal(kmsEnvelope{
+			KeyID:      keyID,
+			Algorithm:  "RSA-OAEP-256",
+			Encryption: "A256GCM",
+			WrappedKey: wrapped,
+			Nonce:      nonce,
+			Ciphertext: aead.Seal(nil, nonce, v, []byte(k)),
+		})
+		clear(v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode envelope of %s: %w", k, err)
+		}
+		out[k+kmsSuffix] = envelope
+	}
+	return out, nil
+}
+
+// OpenEnvelope decrypts the envelope stored under the Secret data key
+// {entry}.kms.json, e.g. entry "tls.key". unwrap decrypts the wrapped data key
+// with the KMS key version keyID, e.g. via the Key Vault unwrapKey operation.
+func OpenEnvelope(
+	entry string,
+	envelope []byte,
+	unwrap func(keyID string, wrapped []byte) ([]byte, error),
+) ([]byte, error) {
+	var env kmsEnvelope
+	if err := json.Unmarshal(envelope, &env); err != nil {
+		return nil, fmt.Errorf("invalid envelope: %w", err)
+	}
+	if env.Encryption != "A256GCM" {
+		return nil, fmt.Errorf("unsupported envelope encryption %q", env.Encryption)
+	}
+	dek, err := unwrap(env.KeyID, env.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	defer clear(dek)
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid envelope nonce")
+	}
+	plaintext, err := aead.Open(nil, env.Nonce, env.Ciphertext, []byte(entry))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: %w", entry, err)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+func TestRenderAgeEncryptRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("GenerateX25519Identity() error = %v", err)
+	}
+	kp := generateTestKey(t)
+	data, err := NewRenderer().Render(kp, RenderOptions{
+		Format:        FormatSplitPEM,
+		AgeRecipients: []string{identity.Recipient().String()},
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if _, ok := data["tls.key"]; ok {
+		t.Fatal("plaintext tls.key still present")
+	}
+	if !bytes.HasPrefix(data["tls.key.age"], []byte(armor.Header)) {
+		t.Fatalf("tls.key.age is not armored: %q", data["tls.key.age"])
+	}
+
+	ar := armor.NewReader(bytes.NewReader(data["tls.key.age"]))
+	r, err := age.Decrypt(ar, identity)
+	if err != nil {
+		t.Fatalf("Decrypt() error = %v", err)
+	}
+	plain, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	priv, err := crypto.ParsePrivateKeyPEM(plain)
+	if err != nil {
+		t.Fatalf("ParsePrivateKeyPEM() error = %v", err)
+	}
+	pub, err := crypto.ParsePublicKeyPEM(data["public.pem"])
+	if err != nil {
+		t.Fatalf("public.pem is not plaintext: %v", err)
+	}
+	if err := crypto.VerifyKeyPair(priv, pub); err != nil {
+		t.Errorf("VerifyKeyPair() error = %v", err)
+	}
+
+	// The public key alone is still verified against the fingerprint
+	fingerprint, err := crypto.ComputeFingerprint(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{encryptionAnnotation: "age"}},
+		Data:       data,
+	}
+	if err := VerifySecret(secret, fingerprint); err != nil {
+		t.Errorf("VerifySecret() error = %v", err)
+	}
+	other, err := crypto.ComputeFingerprint(generateTestKey(t).PublicKey)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+	if err := VerifySecret(secret, other); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("VerifySecret() error = %v, want ErrIntegrity", err)
+	}
+}
+
+func TestValidateAgeRecipients(t *testing.T) {
+	t.Parallel()
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("GenerateX25519Identity() error = %v", err)
+	}
+	if err := ValidateAgeRecipients([]string{identity.Recipient().String()}); err != nil {
+		t.Errorf("ValidateAgeRecipients() error = %v", err)
+	}
+	for name, recipients := range map[string][]string{
+		"empty":       nil,
+		"not age":     {"ssh-ed25519 AAAA"},
+		"identity":    {identity.String()},
+		"one of many": {identity.Recipient().String(), "age1bogus"},
+	} {
+		if err := ValidateAgeRecipients(recipients); err == nil {
+			t.Errorf("%s: ValidateAgeRecipients() expected error, got nil", name)
+		}
+	}
+}
+
+// xorKeyWrapper "wraps" data keys by XOR, standing in for a KMS.
+type xorKeyWrapper struct{}
+
+func (xorKeyWrapper) WrapKey(
+	_ context.Context, _ string, cfg openukrv1alpha1.AzureKeyVaultEncryption, dek []byte,
+) ([]byte, string, error) {
+	return xorKey(dek), cfg.KeyURL + "/v1", nil
+}
+
+func xorKey(key []byte) []byte {
+	out := make([]byte, len(key))
+	for i, b := range key {
+		out[i] = b ^ 0x5a
+	}
+	return out
+}
+
+func TestWriteEnvelopeEncryption(t *testing.T) {
+	t.Parallel()
+
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	const keyURL = "https://my-vault.vault.azure.net/keys/kek"
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Output: openukrv1alpha1.OutputConfig{
+				SecretName: "keys",
+				Format:     FormatSplitPEM,
+				Encryption: &openukrv1alpha1.OutputEncryption{
+					AzureKeyVault: &openukrv1alpha1.AzureKeyVaultEncryption{KeyURL: keyURL},
+				},
+			},
+		},
+	}
+	kp := generateTestKey(t)
+	ctx := context.Background()
+
+	// Without a KMS the output cannot be written
+	if err := NewSecretWriter(c, scheme, NewRenderer()).Write(ctx, profile, kp); err == nil {
+		t.Fatal("Write() without a KeyWrapper succeeded, want error")
+	}
+
+	w := NewSecretWriter(c, scheme, NewRenderer(), WithKeyWrapper(xorKeyWrapper{}))
+	if err := w.Write(ctx, profile, kp); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Name: "keys", Namespace: "default"}, &secret); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if secret.Type != corev1.SecretTypeOpaque {
+		t.Errorf("Type = %s, want Opaque", secret.Type)
+	}
+	if got := secret.Annotations[encryptionAnnotation]; got != "azurekeyvault" {
+		t.Errorf("encryption annotation = %q, want azurekeyvault", got)
+	}
+	if _, ok := secret.Data["tls.key"]; ok {
+		t.Fatal("plaintext tls.key still present")
+	}
+
+	unwrap := func(keyID string, wrapped []byte) ([]byte, error) {
+		if keyID != keyURL+"/v1" {
+			t.Errorf("envelope kid = %q, want %s/v1", keyID, keyURL)
+		}
+		return xorKey(wrapped), nil
+	}
+	plain, err := OpenEnvelope("tls.key", secret.Data["tls.key.kms.json"], unwrap)
+	if err != nil {
+		t.Fatalf("OpenEnvelope() error = %v", err)
+	}
+	priv, err := crypto.ParsePrivateKeyPEM(plain)
+	if err != nil {
+		t.Fatalf("ParsePrivateKeyPEM() error = %v", err)
+	}
+	pub, err := crypto.ParsePublicKeyPEM(secret.Data["public.pem"])
+	if err != nil {
+		t.Fatalf("public.pem is not plaintext: %v", err)
+	}
+	if err := crypto.VerifyKeyPair(priv, pub); err != nil {
+		t.Errorf("VerifyKeyPair() error = %v", err)
+	}
+
+	// An envelope only opens under its own entry name
+	if _, err := OpenEnvelope("other.key", secret.Data["tls.key.kms.json"], unwrap); err == nil {
+		t.Error("OpenEnvelope() under another entry name succeeded, want error")
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+)
+
+// With Spec.Output.KeepHistorySecrets, the Secret is copied into an immutable
+// history Secret before its key is replaced. Rolling back means pointing the
+// consumer at a history Secret; the controller never reads them.
+const (
+	// historyLabel marks history Secrets for pruning.
+	historyLabel = "openukr.io/history"
+	// supersededAtAnnotation records when a history Secret's key was replaced.
+	supersededAtAnnotation = "openukr.io/superseded-at"
+)
+
+// HistorySecretName derives the name of the history Secret holding keyID.
+func HistorySecretName(secretName, keyID string) string {
+	return ImmutableSecretName(secretName+"-history", keyID)
+}
+
+// recordHistory snapshots the profile's Secret if it holds a key other than
+// newKeyID, then prunes history Secrets beyond Spec.Output.KeepHistorySecrets.
+// A missing or foreign Secret is left to Write to handle.
+func (w *kubeSecretWriter) recordHistory(ctx context.Context, profile *openukrv1alpha1.KeyProfile, newKeyID string) error {
+	current := &corev1.Secret{}
+	key := client.ObjectKey{Name: profile.Spec.Output.SecretName, Namespace: profile.Namespace}
+	if err := w.client.Get(ctx, key, current); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	keyID := current.Annotations["openukr.io/key-id"]
+	if keyID == "" || keyID == newKeyID || checkManaged(current, profile) != nil {
+		return nil
+	}
+
+	if profile.Spec.Output.KeepHistorySecrets > 0 {
+		if err := w.snapshot(ctx, profile, current, keyID); err != nil {
+			return err
+		}
+	}
+	return w.pruneHistory(ctx, profile)
+}
+
+// snapshot copies the current key entries of secret into its history Secret.
+// Previous-key entries are not copied; they have their own snapshot.
+func (w *kubeSecretWriter) snapshot(
+	ctx context.Context,
+	profile *openukrv1alpha1.KeyProfile,
+	secret *corev1.Secret,
+	keyID string,
+) error {
+	data := make(map[string][]byte, len(secret.Data))
+	for k, v := range secret.Data {
+		if !isPreviousDataKey(k) {
+			data[k] = v
+		}
+	}
+	immutable := true
+	history := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      HistorySecretName(profile.Spec.Output.SecretName, keyID),
+			Namespace: profile.Namespace, // [SEC:S-1] Enforce same namespace
+			Labels:    w.secretLabels(profile),
+			Annotations: map[string]string{
+				"openukr.io/key-id":    keyID,
+				supersededAtAnnotation: time.Now().UTC().Format(time.RFC3339Nano),
+			},
+		},
+		Immutable: &immutable,
+		Data:      data,
+		Type:      secret.Type,
+	}
+	history.Labels[historyLabel] = "true"
+	for _, k := range []string{"openukr.io/last-rotation", "openukr.io/algorithm", compressionAnnotation} {
+		if v, ok := secret.Annotations[k]; ok {
+			history.Annotations[k] = v
+		}
+	}
+	// Set OwnerReference [SEC:S-1]
+	if err := ctrl.SetControllerReference(profile, history, w.scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference: %w", err)
+	}
+	// A retried Write finds the snapshot already taken
+	if err := w.client.Create(ctx, history); err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("failed to create history secret: %w", err)
+	}
+	return nil
+}
+
+// pruneHistory deletes the oldest history Secrets of the profile beyond
+// Spec.Output.KeepHistorySecrets.
+func (w *kubeSecretWriter) pruneHistory(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error {
+	var secrets corev1.SecretList
+	if err := w.client.List(ctx, &secrets,
+		client.InNamespace(profile.Namespace),
+		client.MatchingLabels{
+			"app.kubernetes.io/managed-by": "openukr",
+			"openukr.io/key-profile":       profile.Name,
+			historyLabel:                   "true",
+		},
+	); err != nil {
+		return fmt.Errorf("failed to list history secrets: %w", err)
+	}
+	keep := max(profile.Spec.Output.KeepHistorySecrets, 0)
+	if len(secrets.Items) <= keep {
+		return nil
+	}
+
+	// Newest first; unparsable timestamps sort as oldest
+	supersededAt := func(s *corev1.Secret) time.Time {
+		t, _ := time.Parse(time.RFC3339Nano, s.Annotations[supersededAtAnnotation])
+		return t
+	}
+	sort.Slice(secrets.Items, func(i, j int) bool {
+		return supersededAt(&secrets.Items[i]).After(supersededAt(&secrets.Items[j]))
+	})
+	for i := keep; i < len(secrets.Items); i++ {
+		if err := w.client.Delete(ctx, &secrets.Items[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete history secret %s: %w", secrets.Items[i].Name, err)
+		}
+	}
+	return nil
+}
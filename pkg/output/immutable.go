@@ -0,0 +1,238 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+// With Spec.Output.Immutable, every rotation creates a new immutable Secret
+// named {secretName}-{keyID}. A mutable pointer ConfigMap named {secretName}
+// tells consumers which Secret is current and which is still in its grace period.
+const (
+	// PointerCurrentKey is the pointer ConfigMap key naming the current Secret.
+	PointerCurrentKey = "secretName"
+	// PointerPreviousKey is the pointer ConfigMap key naming the previous Secret.
+	PointerPreviousKey = "previousSecretName"
+
+	// immutableLabel marks per-key immutable Secrets for cleanup.
+	immutableLabel = "openukr.io/immutable"
+)
+
+// invalidSecretNameChars matches characters not allowed in Secret names.
+var invalidSecretNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// ImmutableSecretName derives the per-key Secret name for an immutable output.
+func ImmutableSecretName(secretName, keyID string) string {
+	name := invalidSecretNameChars.ReplaceAllString(strings.ToLower(secretName+"-"+keyID), "-")
+	if len(name) > 253 {
+		name = name[:253]
+	}
+	return strings.Trim(name, "-.")
+}
+
+// SecretNames returns the names of the Secrets holding the profile's current and
+// previous key. For mutable outputs both live in Spec.Output.SecretName; for
+// immutable outputs they are read from the pointer ConfigMap.
+func SecretNames(ctx context.Context, reader client.Reader, profile *openukrv1alpha1.KeyProfile) (string, string, error) {
+	if !profile.Spec.Output.Immutable {
+		return profile.Spec.Output.SecretName, profile.Spec.Output.SecretName, nil
+	}
+	pointer := &corev1.ConfigMap{}
+	key := client.ObjectKey{Name: profile.Spec.Output.SecretName, Namespace: profile.Namespace}
+	if err := reader.Get(ctx, key, pointer); err != nil {
+		return "", "", fmt.Errorf("failed to get pointer ConfigMap: %w", err)
+	}
+	return pointer.Data[PointerCurrentKey], pointer.Data[PointerPreviousKey], nil
+}
+
+// writeImmutable creates the immutable Secret for kp and points the ConfigMap at it.
+// Re-running for the same key with unchanged render options is a no-op apart from
+// the pointer update; see replaceImmutable for a re-render.
+func (w *kubeSecretWriter) writeImmutable(
+	ctx context.Context,
+	profile *openukrv1alpha1.KeyProfile,
+	kp *crypto.KeyPair,
+	data map[string][]byte,
+	hash, publishHash string,
+	wo writeOptions,
+) error {
+	if kmsKeyURL(profile.Spec.Output) != "" {
+		encrypted, err := w.envelopeEncryptPrivate(ctx, profile, data)
+		if err != nil {
+			return err
+		}
+		data = encrypted
+	}
+	if err := w.checkSize(data); err != nil {
+		return err
+	}
+	name := ImmutableSecretName(profile.Spec.Output.SecretName, kp.KeyID)
+	immutable := true
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: profile.Namespace, // [SEC:S-1] Enforce same namespace
+			Labels:    w.secretLabels(profile),
+			Annotations: map[string]string{
+				"openukr.io/last-rotation": wo.lastRotation(kp),
+				"openukr.io/key-id":        kp.KeyID,
+				"openukr.io/algorithm":     kp.Algorithm,
+				renderHashAnnotation:       hash,
+				publishHashAnnotation:      publishHash,
+			},
+		},
+		Immutable: &immutable,
+		Data:      data,
+		Type:      corev1.SecretTypeOpaque,
+	}
+	secret.Labels[immutableLabel] = "true"
+	if profile.Spec.Output.Compress {
+		secret.Annotations[compressionAnnotation] = "gzip"
+	}
+	scheme := encryptionScheme(profile.Spec.Output)
+	if profile.Spec.Output.Format == FormatSplitPEM && scheme == "" {
+		secret.Type = corev1.SecretTypeTLS
+	}
+	if scheme != "" {
+		secret.Annotations[encryptionAnnotation] = scheme
+	}
+	if wo.schedule {
+		setSchedule(secret.Annotations, profile, wo.nextRotation)
+	}
+	// Set OwnerReference [SEC:S-1]
+	if err := ctrl.SetControllerReference(profile, secret, w.scheme); err != nil {
+		return fmt.Errorf("failed to set controller reference: %w", err)
+	}
+	err := w.client.Create(ctx, secret)
+	if apierrors.IsAlreadyExists(err) {
+		err = w.replaceImmutable(ctx, profile, secret)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create immutable secret: %w", err)
+	}
+
+	pointer := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      profile.Spec.Output.SecretName,
+			Namespace: profile.Namespace,
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, w.client, pointer, func() error {
+		if err := ctrl.SetControllerReference(profile, pointer, w.scheme); err != nil {
+			return fmt.Errorf("failed to set controller reference: %w", err)
+		}
+		pointer.Labels = w.secretLabels(profile)
+		if pointer.Data == nil {
+			pointer.Data = make(map[string]string)
+		}
+		if current := pointer.Data[PointerCurrentKey]; current != "" && current != name {
+			pointer.Data[PointerPreviousKey] = current
+		}
+		pointer.Data[PointerCurrentKey] = name
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update pointer ConfigMap: %w", err)
+	}
+	return nil
+}
+
+// replaceImmutable handles an immutable Secret that already exists for the key
+// of secret. A retry rendered with the same options leaves it as is. A re-render
+// (e.g. a format change) cannot update its data, so the existing Secret is
+// deleted and secret created in its place. Secrets the profile did not write
+// are never replaced. [SEC:S-1]
+func (w *kubeSecretWriter) replaceImmutable(
+	ctx context.Context,
+	profile *openukrv1alpha1.KeyProfile,
+	secret *corev1.Secret,
+) error {
+	existing := &corev1.Secret{}
+	if err := w.client.Get(ctx, client.ObjectKeyFromObject(secret), existing); err != nil {
+		return err
+	}
+	if err := checkManaged(existing, profile); err != nil {
+		return err
+	}
+	if existing.Annotations["openukr.io/key-id"] != secret.Annotations["openukr.io/key-id"] {
+		return fmt.Errorf("%w: %s holds key %q", ErrIntegrity, existing.Name, existing.Annotations["openukr.io/key-id"])
+	}
+	if existing.Annotations[renderHashAnnotation] == secret.Annotations[renderHashAnnotation] {
+		return nil
+	}
+	if err := w.client.Delete(ctx, existing, client.Preconditions{UID: &existing.UID}); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete re-rendered secret: %w", err)
+	}
+	return w.client.Create(ctx, secret)
+}
+
+// dropPreviousImmutable deletes every immutable Secret of the profile except the
+// current one and clears the pointer's previous entry. [SEC:I-2]
+func (w *kubeSecretWriter) dropPreviousImmutable(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error {
+	current, previous, err := SecretNames(ctx, w.client, profile)
+	if err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	var secrets corev1.SecretList
+	if err := w.client.List(ctx, &secrets,
+		client.InNamespace(profile.Namespace),
+		client.MatchingLabels{
+			"app.kubernetes.io/managed-by": "openukr",
+			"openukr.io/key-profile":       profile.Name,
+			immutableLabel:                 "true",
+		},
+	); err != nil {
+		return fmt.Errorf("failed to list immutable secrets: %w", err)
+	}
+	for i := range secrets.Items {
+		if secrets.Items[i].Name == current {
+			continue
+		}
+		if err := w.client.Delete(ctx, &secrets.Items[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete secret %s: %w", secrets.Items[i].Name, err)
+		}
+	}
+
+	if previous == "" {
+		return nil
+	}
+	pointer := &corev1.ConfigMap{}
+	key := client.ObjectKey{Name: profile.Spec.Output.SecretName, Namespace: profile.Namespace}
+	if err := w.client.Get(ctx, key, pointer); err != nil {
+		return fmt.Errorf("failed to get pointer ConfigMap: %w", err)
+	}
+	delete(pointer.Data, PointerPreviousKey)
+	if err := w.client.Update(ctx, pointer); err != nil {
+		return fmt.Errorf("failed to update pointer ConfigMap: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"context"
+	gocrypto "crypto"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+// SecretInfo describes the key currently stored in a profile's Secret.
+type SecretInfo struct {
+	// KeyID is the openukr.io/key-id annotation.
+	KeyID string
+	// Algorithm is the openukr.io/algorithm annotation.
+	Algorithm string
+	// Fingerprint of the stored public key. Zero for formats without parseable
+	// PEM material (e.g. jks, custom formats).
+	Fingerprint crypto.Fingerprint
+	// LastRotation is the openukr.io/last-rotation annotation, zero if absent or malformed.
+	LastRotation time.Time
+	// PublicKey is the stored public key, nil when Fingerprint is zero.
+	PublicKey gocrypto.PublicKey
+	// PublishHash is the PublishConfigHash of the publish targets the key was last
+	// published to, empty for Secrets written before it was recorded.
+	PublishHash string
+}
+
+// InspectSecret reads the key metadata of a Secret without verifying it.
+func InspectSecret(secret *corev1.Secret) (*SecretInfo, error) {
+	info := &SecretInfo{
+		KeyID:       secret.Annotations["openukr.io/key-id"],
+		Algorithm:   secret.Annotations["openukr.io/algorithm"],
+		PublishHash: secret.Annotations[publishHashAnnotation],
+	}
+	if ts, err := time.Parse(time.RFC3339, secret.Annotations["openukr.io/last-rotation"]); err == nil {
+		info.LastRotation = ts
+	}
+
+	pubPEM := secret.Data["public.pem"]
+	if pubPEM == nil {
+		pubPEM = secret.Data["keypair.pem"]
+	}
+	if pubPEM == nil {
+		return info, nil
+	}
+	pub, err := crypto.ParsePublicKeyPEM(pubPEM)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse public key: %w", ErrIntegrity, err)
+	}
+	if info.Fingerprint, err = crypto.ComputeFingerprint(pub); err != nil {
+		return nil, fmt.Errorf("failed to compute fingerprint: %w", err)
+	}
+	info.PublicKey = pub
+	return info, nil
+}
+
+// Inspect returns the metadata of the profile's current Secret, or nil if it
+// does not exist. It never writes.
+func (w *kubeSecretWriter) Inspect(ctx context.Context, profile *openukrv1alpha1.KeyProfile) (*SecretInfo, error) {
+	if profile == nil {
+		return nil, fmt.Errorf("profile cannot be nil")
+	}
+
+	current, _, err := SecretNames(ctx, w.client, profile)
+	if err != nil || current == "" {
+		return nil, client.IgnoreNotFound(err)
+	}
+	secret := &corev1.Secret{}
+	if err := w.client.Get(ctx, client.ObjectKey{Name: current, Namespace: profile.Namespace}, secret); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	return InspectSecret(secret)
+}
+
+// MarkPublished records hash as the publish configuration the profile's current
+// key was published to. A missing Secret is not an error.
+func (w *kubeSecretWriter) MarkPublished(ctx context.Context, profile *openukrv1alpha1.KeyProfile, hash string) error {
+	if profile == nil {
+		return fmt.Errorf("profile cannot be nil")
+	}
+
+	current, _, err := SecretNames(ctx, w.client, profile)
+	if err != nil || current == "" {
+		return client.IgnoreNotFound(err)
+	}
+	secret := &corev1.Secret{}
+	if err := w.client.Get(ctx, client.ObjectKey{Name: current, Namespace: profile.Namespace}, secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if secret.Annotations[publishHashAnnotation] == hash {
+		return nil
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	// Only metadata changes, so this also applies to immutable Secrets
+	secret.Annotations[publishHashAnnotation] = hash
+	if err := w.client.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to record publish configuration: %w", err)
+	}
+	return nil
+}
+
+// MarkSchedule records next and the profile's rotation interval as annotations
+// on the profile's current Secret. A missing Secret is not an error.
+func (w *kubeSecretWriter) MarkSchedule(ctx context.Context, profile *openukrv1alpha1.KeyProfile, next time.Time) error {
+	if profile == nil {
+		return fmt.Errorf("profile cannot be nil")
+	}
+
+	current, _, err := SecretNames(ctx, w.client, profile)
+	if err != nil || current == "" {
+		return client.IgnoreNotFound(err)
+	}
+	secret := &corev1.Secret{}
+	if err := w.client.Get(ctx, client.ObjectKey{Name: current, Namespace: profile.Namespace}, secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	if !setSchedule(secret.Annotations, profile, next) {
+		return nil
+	}
+	// Only metadata changes, so this also applies to immutable Secrets
+	if err := w.client.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to record rotation schedule: %w", err)
+	}
+	return nil
+}
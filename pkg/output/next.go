@@ -0,0 +1,218 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"context"
+	gocrypto "crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+// With Spec.Rotation.PublishNextKey, the key published ahead of its promotion is
+// staged in its own Secret so workloads mounting the output Secret never load
+// it before it becomes current.
+const (
+	// NextKeyDataKey is the staged Secret key holding the PKCS #8 PEM private key.
+	NextKeyDataKey = "next.key"
+
+	nextSecretSuffix = "-next"
+
+	// keySpecHashAnnotation records the KeySpecHash the next key was generated for.
+	keySpecHashAnnotation = "openukr.io/key-spec-hash"
+)
+
+// NextKey is a staged next key with the configuration it was staged and
+// published for.
+type NextKey struct {
+	*crypto.KeyPair
+	// KeySpecHash is the KeySpecHash of the KeySpec the key was generated for.
+	KeySpecHash string
+	// PublishHash is the PublishConfigHash of the targets the key was published
+	// to, empty while it has not been published.
+	PublishHash string
+}
+
+// KeySpecHash returns a short stable hash of spec. A staged key whose hash
+// differs was generated for another KeySpec and must not be promoted.
+func KeySpecHash(spec openukrv1alpha1.KeySpec) (string, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash key spec: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// NextSecretName returns the name of the Secret staging the profile's next key.
+func NextSecretName(profile *openukrv1alpha1.KeyProfile) string {
+	return profile.Spec.Output.SecretName + nextSecretSuffix
+}
+
+func (w *kubeSecretWriter) StageNext(ctx context.Context, profile *openukrv1alpha1.KeyProfile, kp *crypto.KeyPair) error {
+	if profile == nil {
+		return fmt.Errorf("profile cannot be nil")
+	}
+	if kp == nil {
+		return fmt.Errorf("keyPair cannot be nil")
+	}
+	specHash, err := KeySpecHash(profile.Spec.KeySpec)
+	if err != nil {
+		return err
+	}
+	encoder, err := crypto.NewKeyEncoder("DER")
+	if err != nil {
+		return err
+	}
+	der, err := encoder.EncodePrivate(kp.PrivateKey)
+	if err != nil {
+		return fmt.Errorf("failed to encode next key: %w", err)
+	}
+	// [SEC:I-2] Zero the DER copy once encoded
+	defer func() {
+		for i := range der {
+			der[i] = 0
+		}
+	}()
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      NextSecretName(profile),
+			Namespace: profile.Namespace, // [SEC:S-1] Enforce same namespace
+		},
+	}
+	_, err = controllerutil.CreateOrUpdate(ctx, w.client, secret, func() error {
+		// Never take over a Secret the profile did not create [SEC:S-1]
+		if err := checkManaged(secret, profile); err != nil {
+			return err
+		}
+		if err := ctrl.SetControllerReference(profile, secret, w.scheme); err != nil {
+			return fmt.Errorf("failed to set controller reference: %w", err)
+		}
+		secret.Labels = w.secretLabels(profile)
+		secret.Annotations = map[string]string{
+			"openukr.io/key-id":     kp.KeyID,
+			"openukr.io/algorithm":  kp.Algorithm,
+			"openukr.io/created-at": kp.CreatedAt.Format(time.RFC3339),
+			keySpecHashAnnotation:   specHash,
+		}
+		secret.Type = corev1.SecretTypeOpaque
+		secret.Data = map[string][]byte{
+			NextKeyDataKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to stage next key: %w", err)
+	}
+	return nil
+}
+
+func (w *kubeSecretWriter) LoadNext(ctx context.Context, profile *openukrv1alpha1.KeyProfile) (*NextKey, error) {
+	if profile == nil {
+		return nil, fmt.Errorf("profile cannot be nil")
+	}
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Name: NextSecretName(profile), Namespace: profile.Namespace}
+	if err := w.client.Get(ctx, key, secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get next key secret: %w", err)
+	}
+	if err := checkManaged(secret, profile); err != nil {
+		return nil, err
+	}
+
+	priv, err := crypto.ParsePrivateKeyPEM(secret.Data[NextKeyDataKey])
+	if err != nil {
+		return nil, fmt.Errorf("next key secret %s: %w", key, err)
+	}
+	signer, ok := priv.(gocrypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("next key secret %s: unsupported key type %T", key, priv)
+	}
+	createdAt, _ := time.Parse(time.RFC3339, secret.Annotations["openukr.io/created-at"])
+	return &NextKey{
+		KeyPair: &crypto.KeyPair{
+			KeyID:      secret.Annotations["openukr.io/key-id"],
+			PrivateKey: priv,
+			PublicKey:  signer.Public(),
+			Algorithm:  secret.Annotations["openukr.io/algorithm"],
+			CreatedAt:  createdAt,
+		},
+		KeySpecHash: secret.Annotations[keySpecHashAnnotation],
+		PublishHash: secret.Annotations[publishHashAnnotation],
+	}, nil
+}
+
+func (w *kubeSecretWriter) MarkNextPublished(ctx context.Context, profile *openukrv1alpha1.KeyProfile, hash string) error {
+	if profile == nil {
+		return fmt.Errorf("profile cannot be nil")
+	}
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Name: NextSecretName(profile), Namespace: profile.Namespace}
+	if err := w.client.Get(ctx, key, secret); err != nil {
+		return fmt.Errorf("failed to get next key secret: %w", err)
+	}
+	if err := checkManaged(secret, profile); err != nil {
+		return err
+	}
+	if secret.Annotations[publishHashAnnotation] == hash {
+		return nil
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[publishHashAnnotation] = hash
+	if err := w.client.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to record next key publish configuration: %w", err)
+	}
+	return nil
+}
+
+func (w *kubeSecretWriter) DropNext(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error {
+	if profile == nil {
+		return fmt.Errorf("profile cannot be nil")
+	}
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Name: NextSecretName(profile), Namespace: profile.Namespace}
+	if err := w.client.Get(ctx, key, secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if err := checkManaged(secret, profile); err != nil {
+		return err
+	}
+	if err := w.client.Delete(ctx, secret); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to drop next key: %w", err)
+	}
+	return nil
+}
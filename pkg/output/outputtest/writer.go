@@ -0,0 +1,239 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package outputtest provides test doubles for the output package.
+package outputtest
+
+import (
+	"context"
+	gocrypto "crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/output"
+)
+
+// ErrInjected is returned by Write while FakeWriter.FailWrites is positive.
+var ErrInjected = errors.New("outputtest: injected write failure")
+
+// FakeWriter is a recording output.SecretWriter. Configure it through its
+// exported fields before use and read them once the code under test returns.
+// The zero value succeeds on every call and reports no existing Secret.
+type FakeWriter struct {
+	mu sync.Mutex
+
+	// KeyIDs records the key ID of every Write call, failed ones included.
+	KeyIDs []string
+	// Writes counts successful Write calls.
+	Writes int
+	// FailWrites makes the next n Write calls fail with ErrInjected.
+	FailWrites int
+	// WriteErr, if set, fails every Write call after FailWrites is exhausted.
+	WriteErr error
+
+	// Drops counts DropPrevious calls; DropErr is returned by each.
+	Drops   int
+	DropErr error
+
+	// Verifies counts Verify calls; VerifyErr is returned by each.
+	Verifies  int
+	VerifyErr error
+
+	// Info and InspectErr are returned by Inspect.
+	Info       *output.SecretInfo
+	InspectErr error
+
+	// PublishHash records the last hash passed to MarkPublished;
+	// MarkPublishedErr is returned by each call.
+	PublishHash      string
+	MarkPublishedErr error
+
+	// NextRotation records the last time passed to MarkSchedule;
+	// MarkScheduleErr is returned by each call.
+	NextRotation    time.Time
+	MarkScheduleErr error
+
+	// StagedKeyIDs records the key ID of every StageNext call; StageErr is
+	// returned by each. The staged key is kept serialized, like in a Secret,
+	// so callers may Wipe the key they staged.
+	StagedKeyIDs []string
+	StageErr     error
+	next         *stagedKey
+
+	// NextDrops counts DropNext calls.
+	NextDrops int
+}
+
+type stagedKey struct {
+	keyID       string
+	algorithm   string
+	createdAt   time.Time
+	der         []byte
+	keySpecHash string
+	publishHash string
+}
+
+var _ output.SecretWriter = (*FakeWriter)(nil)
+
+// Write records the key ID and fails as configured. WithNextRotation options
+// are ignored.
+func (w *FakeWriter) Write(
+	_ context.Context,
+	_ *openukrv1alpha1.KeyProfile,
+	kp *crypto.KeyPair,
+	_ ...output.WriteOption,
+) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.KeyIDs = append(w.KeyIDs, kp.KeyID)
+	if w.FailWrites > 0 {
+		w.FailWrites--
+		return ErrInjected
+	}
+	if w.WriteErr != nil {
+		return w.WriteErr
+	}
+	w.Writes++
+	return nil
+}
+
+// DropPrevious counts the call and returns DropErr.
+func (w *FakeWriter) DropPrevious(_ context.Context, _ *openukrv1alpha1.KeyProfile) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Drops++
+	return w.DropErr
+}
+
+// Verify counts the call and returns VerifyErr.
+func (w *FakeWriter) Verify(_ context.Context, _ *openukrv1alpha1.KeyProfile) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.Verifies++
+	return w.VerifyErr
+}
+
+// Inspect returns Info and InspectErr.
+func (w *FakeWriter) Inspect(_ context.Context, _ *openukrv1alpha1.KeyProfile) (*output.SecretInfo, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.Info, w.InspectErr
+}
+
+// MarkPublished records hash unless MarkPublishedErr is set.
+func (w *FakeWriter) MarkPublished(_ context.Context, _ *openukrv1alpha1.KeyProfile, hash string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.MarkPublishedErr != nil {
+		return w.MarkPublishedErr
+	}
+	w.PublishHash = hash
+	return nil
+}
+
+// MarkSchedule records next unless MarkScheduleErr is set.
+func (w *FakeWriter) MarkSchedule(_ context.Context, _ *openukrv1alpha1.KeyProfile, next time.Time) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.MarkScheduleErr != nil {
+		return w.MarkScheduleErr
+	}
+	w.NextRotation = next
+	return nil
+}
+
+// StageNext keeps a serialized copy of kp for LoadNext.
+func (w *FakeWriter) StageNext(_ context.Context, profile *openukrv1alpha1.KeyProfile, kp *crypto.KeyPair) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.StagedKeyIDs = append(w.StagedKeyIDs, kp.KeyID)
+	if w.StageErr != nil {
+		return w.StageErr
+	}
+	specHash, err := output.KeySpecHash(profile.Spec.KeySpec)
+	if err != nil {
+		return err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(kp.PrivateKey)
+	if err != nil {
+		return err
+	}
+	w.next = &stagedKey{keyID: kp.KeyID, algorithm: kp.Algorithm, createdAt: kp.CreatedAt, der: der, keySpecHash: specHash}
+	return nil
+}
+
+// LoadNext returns a fresh copy of the staged key, or nil if none is staged.
+func (w *FakeWriter) LoadNext(_ context.Context, _ *openukrv1alpha1.KeyProfile) (*output.NextKey, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.next == nil {
+		return nil, nil
+	}
+	priv, err := x509.ParsePKCS8PrivateKey(w.next.der)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := priv.(gocrypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("outputtest: unsupported staged key type %T", priv)
+	}
+	return &output.NextKey{
+		KeyPair: &crypto.KeyPair{
+			KeyID:      w.next.keyID,
+			PrivateKey: priv,
+			PublicKey:  signer.Public(),
+			Algorithm:  w.next.algorithm,
+			CreatedAt:  w.next.createdAt,
+		},
+		KeySpecHash: w.next.keySpecHash,
+		PublishHash: w.next.publishHash,
+	}, nil
+}
+
+// MarkNextPublished records hash on the staged key.
+func (w *FakeWriter) MarkNextPublished(_ context.Context, _ *openukrv1alpha1.KeyProfile, hash string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.next == nil {
+		return fmt.Errorf("outputtest: no staged key")
+	}
+	w.next.publishHash = hash
+	return nil
+}
+
+// NextKeyID returns the ID of the staged key, or "" if none is staged.
+func (w *FakeWriter) NextKeyID() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.next == nil {
+		return ""
+	}
+	return w.next.keyID
+}
+
+// DropNext counts the call and discards the staged key.
+func (w *FakeWriter) DropNext(_ context.Context, _ *openukrv1alpha1.KeyProfile) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.NextDrops++
+	w.next = nil
+	return nil
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"context"
+	gocrypto "crypto"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+// ReadKeyPair reconstructs the current KeyPair of profile from its managed
+// Secret, so output changes can be re-rendered without a rotation. KeyID,
+// Algorithm and CreatedAt are read from the Secret annotations. The split-pem,
+// single-pem and jwks formats are supported, compressed or not. Custom formats
+// and encrypted outputs are not, nor is jks: its keystore password is not part
+// of the spec, so the controller cannot open a keystore it wrote. Returns nil
+// if the Secret does not exist yet.
+//
+// The Secret must hold the key Status.CurrentKeyID records, and its public key
+// must match Status.CurrentKeyFingerprint or, with no fingerprint recorded, the
+// public key stored next to it; a mismatch wraps ErrIntegrity. [SEC:T-1]
+// Callers must Wipe the returned KeyPair once done. [SEC:I-2]
+func ReadKeyPair(ctx context.Context, reader client.Reader, profile *openukrv1alpha1.KeyProfile) (*crypto.KeyPair, error) {
+	if profile == nil {
+		return nil, fmt.Errorf("profile cannot be nil")
+	}
+	current, _, err := SecretNames(ctx, reader, profile)
+	if err != nil || current == "" {
+		return nil, client.IgnoreNotFound(err)
+	}
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Name: current, Namespace: profile.Namespace}
+	if err := reader.Get(ctx, key, secret); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+
+	keyID := secret.Annotations["openukr.io/key-id"]
+	if keyID != profile.Status.CurrentKeyID {
+		return nil, fmt.Errorf("%w: secret %s holds key %q, status records %q",
+			ErrIntegrity, key, keyID, profile.Status.CurrentKeyID)
+	}
+	priv, err := readPrivateKey(secret, profile)
+	if err != nil {
+		return nil, fmt.Errorf("secret %s: %w", key, err)
+	}
+	signer, ok := priv.(gocrypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("secret %s: unsupported key type %T", key, priv)
+	}
+	algorithm := secret.Annotations["openukr.io/algorithm"]
+	if algorithm == "" {
+		algorithm = profile.Spec.KeySpec.Algorithm
+	}
+	createdAt, _ := time.Parse(time.RFC3339, secret.Annotations["openukr.io/last-rotation"])
+	kp := &crypto.KeyPair{
+		KeyID:      keyID,
+		PrivateKey: priv,
+		PublicKey:  signer.Public(),
+		Algorithm:  algorithm,
+		CreatedAt:  createdAt,
+	}
+
+	if profile.Status.CurrentKeyFingerprint == "" {
+		pub, err := storedPublicKey(secret, profile, keyID)
+		if err == nil {
+			err = crypto.VerifyKeyPair(priv, pub)
+		}
+		if err != nil {
+			kp.Wipe()
+			return nil, fmt.Errorf("%w: secret %s: %w", ErrIntegrity, key, err)
+		}
+		return kp, nil
+	}
+	fingerprint, err := crypto.ParseFingerprint(profile.Status.CurrentKeyFingerprint)
+	if err != nil {
+		kp.Wipe()
+		return nil, fmt.Errorf("%w: recorded fingerprint: %w", ErrIntegrity, err)
+	}
+	if ok, err := fingerprint.Matches(kp.PublicKey); err != nil || !ok {
+		kp.Wipe()
+		return nil, fmt.Errorf("%w: secret %s does not match recorded fingerprint %s", ErrIntegrity, key, fingerprint)
+	}
+	return kp, nil
+}
+
+// storedPublicKey returns the public key secret holds for keyID: its PEM
+// public key or its jwks document's member.
+func storedPublicKey(secret *corev1.Secret, profile *openukrv1alpha1.KeyProfile, keyID string) (gocrypto.PublicKey, error) {
+	info, err := InspectSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	if info.PublicKey != nil {
+		return info.PublicKey, nil
+	}
+	keys, dataKey, err := readJWKS(secret, profile)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		if k.KeyID == keyID {
+			return k.PublicKey, nil
+		}
+	}
+	return nil, fmt.Errorf("no public key to verify against: %s holds no key %q", dataKey, keyID)
+}
+
+// readPrivateKey parses the current private key held in secret.
+func readPrivateKey(secret *corev1.Secret, profile *openukrv1alpha1.KeyProfile) (gocrypto.PrivateKey, error) {
+	compressed := secret.Annotations[compressionAnnotation] == "gzip"
+	switch {
+	case secret.Annotations[encryptionAnnotation] != "":
+		return nil, fmt.Errorf("private key entries are %s-encrypted", secret.Annotations[encryptionAnnotation])
+	case secret.Data["tls.key"] != nil:
+		return crypto.ParsePrivateKeyPEM(secret.Data["tls.key"])
+	case secret.Data["keypair.pem"] != nil:
+		return crypto.ParsePrivateKeyPEM(secret.Data["keypair.pem"])
+	case secret.Data[privateJWKSDataKey] != nil:
+		return readPrivateJWKS(secret.Data[privateJWKSDataKey], secret.Annotations["openukr.io/key-id"])
+	case compressed && secret.Data[privateJWKSDataKey+gzipSuffix] != nil:
+		data, err := gunzipBytes(secret.Data[privateJWKSDataKey+gzipSuffix])
+		if err != nil {
+			return nil, err
+		}
+		defer clear(data)
+		return readPrivateJWKS(data, secret.Annotations["openukr.io/key-id"])
+	default:
+		return nil, fmt.Errorf("reading back %q output is not supported", profile.Spec.Output.Format)
+	}
+}
+
+// readPrivateJWKS parses the private key with the given kid from a JWK Set
+// written by the jwks format.
+func readPrivateJWKS(data []byte, keyID string) (gocrypto.PrivateKey, error) {
+	var set struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", privateJWKSDataKey, err)
+	}
+	defer func() {
+		for _, k := range set.Keys {
+			clear(k)
+		}
+	}()
+	for _, raw := range set.Keys {
+		var member struct {
+			Kid string `json:"kid"`
+		}
+		if err := json.Unmarshal(raw, &member); err != nil || member.Kid != keyID {
+			continue
+		}
+		privPEM, err := crypto.Convert(raw, "JWK", "PEM", true)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", privateJWKSDataKey, err)
+		}
+		defer clear(privPEM)
+		return crypto.ParsePrivateKeyPEM(privPEM)
+	}
+	return nil, fmt.Errorf("%s holds no key %q", privateJWKSDataKey, keyID)
+}
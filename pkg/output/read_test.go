@@ -0,0 +1,173 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+func TestReadKeyPair(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		output  openukrv1alpha1.OutputConfig
+		pemType string
+	}{
+		{name: "split-pem", output: openukrv1alpha1.OutputConfig{Format: FormatSplitPEM}},
+		{name: "split-pem sec1", output: openukrv1alpha1.OutputConfig{Format: FormatSplitPEM}, pemType: crypto.PrivateKeyPEMTypeSEC1},
+		{name: "single-pem", output: openukrv1alpha1.OutputConfig{Format: FormatSinglePEM}},
+		{name: "jwks", output: openukrv1alpha1.OutputConfig{Format: FormatJWKS}},
+		{name: "jwks compressed", output: openukrv1alpha1.OutputConfig{Format: FormatJWKS, Compress: true}},
+		{name: "immutable", output: openukrv1alpha1.OutputConfig{Format: FormatSplitPEM, Immutable: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c, w := newTestWriter(t)
+			profile := &openukrv1alpha1.KeyProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+				Spec: openukrv1alpha1.KeyProfileSpec{
+					KeySpec: openukrv1alpha1.KeySpec{Algorithm: crypto.AlgorithmEC, PrivateKeyPEMType: tt.pemType},
+					Output:  tt.output,
+				},
+			}
+			profile.Spec.Output.SecretName = "keys"
+			ctx := context.Background()
+
+			got, err := ReadKeyPair(ctx, c, profile)
+			if err != nil || got != nil {
+				t.Fatalf("ReadKeyPair() before the first write = %v, %v, want nil", got, err)
+			}
+
+			kp := generateTestKey(t)
+			if err := w.Write(ctx, profile, kp); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			fingerprint, err := crypto.ComputeFingerprint(kp.PublicKey)
+			if err != nil {
+				t.Fatalf("ComputeFingerprint() error = %v", err)
+			}
+			profile.Status.CurrentKeyID = kp.KeyID
+			profile.Status.CurrentKeyFingerprint = fingerprint.String()
+
+			got, err = ReadKeyPair(ctx, c, profile)
+			if err != nil {
+				t.Fatalf("ReadKeyPair() error = %v", err)
+			}
+			defer got.Wipe()
+			if got.KeyID != kp.KeyID || got.Algorithm != kp.Algorithm {
+				t.Errorf("ReadKeyPair() = %s/%s, want %s/%s", got.KeyID, got.Algorithm, kp.KeyID, kp.Algorithm)
+			}
+			if !got.CreatedAt.Equal(kp.CreatedAt.Truncate(time.Second)) {
+				t.Errorf("CreatedAt = %s, want %s", got.CreatedAt, kp.CreatedAt)
+			}
+			if err := crypto.VerifyKeyPair(got.PrivateKey, kp.PublicKey); err != nil {
+				t.Errorf("ReadKeyPair() returned another key: %v", err)
+			}
+
+			// Without a recorded fingerprint the stored public key vouches for the pair
+			profile.Status.CurrentKeyFingerprint = ""
+			unverified, err := ReadKeyPair(ctx, c, profile)
+			if err != nil {
+				t.Fatalf("ReadKeyPair() without fingerprint error = %v", err)
+			}
+			unverified.Wipe()
+		})
+	}
+}
+
+func TestReadKeyPairRejectsTamperedKey(t *testing.T) {
+	t.Parallel()
+
+	c, w := newTestWriter(t)
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Output: openukrv1alpha1.OutputConfig{SecretName: "keys", Format: FormatSplitPEM},
+		},
+	}
+	ctx := context.Background()
+
+	kp, other := generateTestKey(t), generateTestKey(t)
+	if err := w.Write(ctx, profile, kp); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	fingerprint, err := crypto.ComputeFingerprint(other.PublicKey)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+	profile.Status.CurrentKeyID = kp.KeyID
+	profile.Status.CurrentKeyFingerprint = fingerprint.String()
+
+	if _, err := ReadKeyPair(ctx, c, profile); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("ReadKeyPair() error = %v, want an integrity violation", err)
+	}
+
+	// A Secret holding another key than the status records
+	profile.Status.CurrentKeyID = other.KeyID
+	if _, err := ReadKeyPair(ctx, c, profile); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("ReadKeyPair() with another key ID error = %v, want an integrity violation", err)
+	}
+
+	// Without a recorded fingerprint, a public key not matching the private key
+	profile.Status.CurrentKeyID, profile.Status.CurrentKeyFingerprint = kp.KeyID, ""
+	var stored corev1.Secret
+	if err := c.Get(ctx, client.ObjectKey{Name: "keys", Namespace: "default"}, &stored); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	swapped, err := NewRenderer().Render(other, RenderOptions{Format: FormatSplitPEM})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	stored.Data["public.pem"] = swapped["public.pem"]
+	if err := c.Update(ctx, &stored); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if _, err := ReadKeyPair(ctx, c, profile); !errors.Is(err, ErrIntegrity) {
+		t.Errorf("ReadKeyPair() with a swapped public key error = %v, want an integrity violation", err)
+	}
+
+	// Formats without readable key material are refused
+	profile.Spec.Output.Format = FormatJKS
+	profile.Spec.Output.SecretName = "jks-keys"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "jks-keys",
+			Namespace:   "default",
+			Annotations: map[string]string{"openukr.io/key-id": kp.KeyID},
+		},
+		Data: map[string][]byte{"keystore.jks": []byte("opaque")},
+	}
+	if err := c.Create(ctx, secret); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := ReadKeyPair(ctx, c, profile); err == nil || errors.Is(err, ErrIntegrity) {
+		t.Errorf("ReadKeyPair(jks) error = %v, want unsupported format error", err)
+	}
+}
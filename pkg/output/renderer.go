@@ -21,13 +21,22 @@ import (
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/json"
 	"fmt"
+	"maps"
 	"math/big"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	keystore "github.com/pavlo-v-chernykh/keystore-go/v4"
 
 	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/metrics"
 )
 
 // Format constants
@@ -35,13 +44,34 @@ const (
 	FormatSplitPEM  = "split-pem"
 	FormatSinglePEM = "single-pem"
 	FormatJKS       = "jks"
+	FormatJWKS      = "jwks"
 )
 
+// builtinFormats are the formats rendered without a registered RenderFunc.
+var builtinFormats = []string{FormatSplitPEM, FormatSinglePEM, FormatJKS, FormatJWKS}
+
+// SupportedFormats returns the built-in and registered output formats, sorted.
+// It reflects exactly what Render accepts, for UI and policy tooling.
+func SupportedFormats() []string {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	formats := append(make([]string, 0, len(builtinFormats)+len(renderers)), builtinFormats...)
+	for format := range renderers {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	return formats
+}
+
 // RenderOptions specifies parameters for rendering the key output.
 type RenderOptions struct {
-	// Format is the output format (split-pem, single-pem, jks).
+	// Format is the output format (split-pem, single-pem, jks, jwks).
 	Format string
 
+	// PrivateKeyPEMType selects the private key PEM structure (pkcs8, pkcs1, sec1).
+	// Defaults to pkcs8 if empty. JKS always stores PKCS#8.
+	PrivateKeyPEMType string
+
 	// Password is used for JKS encryption.
 	// If empty, a default password might be used or error returned.
 	Password string `json:"-"`
@@ -49,6 +79,93 @@ type RenderOptions struct {
 	// Alias is the alias for the key in JKS.
 	// Defaults to "openukr-key" if empty.
 	Alias string
+
+	// SPIFFEID, if set, is added as a URI SAN to generated certificates.
+	SPIFFEID *url.URL
+
+	// KeyUse is the declared use of the key (sig, enc, tls) and selects the key
+	// usages of generated certificates. Defaults to sig.
+	KeyUse string
+
+	// NotBeforeSkew backdates the NotBefore of generated certificates so
+	// consumers with a slightly slow clock do not reject them as not yet valid.
+	// Zero means DefaultNotBeforeSkew; negative values disable backdating.
+	NotBeforeSkew time.Duration `json:",omitempty"`
+
+	// Compress gzips rendered JSON entries, renaming them from .json to .json.gz.
+	Compress bool
+
+	// KeyNames renames named entries (see DefaultKeyNames) before compression.
+	KeyNames map[string]string
+
+	// PreviousKey is listed next to the current key in the jwks document while
+	// its grace period lasts. Other formats ignore it.
+	PreviousKey *crypto.PublicKeyInfo `json:"-"`
+
+	// HardCutover withdraws the previous public key at rotation (GraceMode
+	// hard): the jwks document never lists PreviousKey.
+	HardCutover bool `json:",omitempty"`
+
+	// AgeRecipients, if set, age-encrypts private key entries to these
+	// recipients after compression, renaming them to {key}.age.
+	AgeRecipients []string `json:",omitempty"`
+
+	// KMSKeyURL is the KMS key private key entries are envelope-encrypted with.
+	// Render ignores it: the writer encrypts, as wrapping calls the KMS.
+	KMSKeyURL string `json:",omitempty"`
+}
+
+// DefaultNotBeforeSkew is the default NotBefore backdating of generated certificates.
+const DefaultNotBeforeSkew = 5 * time.Minute
+
+// DefaultKeyNames are the default Secret data keys of entries that
+// OutputConfig.KeyNames may rename, by entry name.
+var DefaultKeyNames = map[string]string{
+	"jwks": "jwks.json",
+}
+
+// validDataKey matches valid Secret data keys.
+var validDataKey = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+
+// ValidateKeyNames checks OutputConfig.KeyNames: entries must be known and
+// names must be valid, distinct Secret data keys.
+func ValidateKeyNames(names map[string]string) error {
+	seen := make(map[string]string, len(names))
+	for entry, name := range names {
+		if _, ok := DefaultKeyNames[entry]; !ok {
+			return fmt.Errorf("keyNames: unknown entry %q, must be one of: %s",
+				entry, strings.Join(slices.Sorted(maps.Keys(DefaultKeyNames)), ", "))
+		}
+		if !validDataKey.MatchString(name) || name == "." || name == ".." {
+			return fmt.Errorf("keyNames[%s]: %q is not a valid Secret data key", entry, name)
+		}
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("keyNames: %s and %s both map to %q", other, entry, name)
+		}
+		seen[name] = entry
+	}
+	return nil
+}
+
+// renameEntries applies KeyNames to rendered data. Entries the format does not
+// produce are ignored; a name colliding with another entry is an error.
+func renameEntries(data map[string][]byte, names map[string]string) (map[string][]byte, error) {
+	if err := ValidateKeyNames(names); err != nil {
+		return nil, err
+	}
+	for entry, name := range names {
+		def := DefaultKeyNames[entry]
+		v, ok := data[def]
+		if !ok || name == def {
+			continue
+		}
+		if _, exists := data[name]; exists {
+			return nil, fmt.Errorf("keyNames[%s]: %q collides with another entry", entry, name)
+		}
+		delete(data, def)
+		data[name] = v
+	}
+	return data, nil
 }
 
 // FormatRenderer converts a KeyPair into a map of files (bytes) ready for Secret storage.
@@ -56,6 +173,45 @@ type FormatRenderer interface {
 	Render(kp *crypto.KeyPair, opts RenderOptions) (map[string][]byte, error)
 }
 
+// RenderFunc renders a KeyPair into Secret data for a custom format.
+type RenderFunc func(kp *crypto.KeyPair, opts RenderOptions) (map[string][]byte, error)
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]RenderFunc{}
+)
+
+// RegisterRenderer registers fn as the renderer for a custom output format.
+// Built-in formats cannot be overridden and each format may only be registered once.
+// Intended to be called from init or main before the controller starts.
+func RegisterRenderer(format string, fn RenderFunc) error {
+	if format == "" {
+		return fmt.Errorf("format must not be empty")
+	}
+	if fn == nil {
+		return fmt.Errorf("renderer for format %q must not be nil", format)
+	}
+	if slices.Contains(builtinFormats, format) {
+		return fmt.Errorf("format %q is built in and cannot be overridden", format)
+	}
+
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	if _, exists := renderers[format]; exists {
+		return fmt.Errorf("renderer for format %q is already registered", format)
+	}
+	renderers[format] = fn
+	return nil
+}
+
+// lookupRenderer returns the registered renderer for format, if any.
+func lookupRenderer(format string) (RenderFunc, bool) {
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	fn, ok := renderers[format]
+	return fn, ok
+}
+
 // NewRenderer creates a new FormatRenderer.
 func NewRenderer() FormatRenderer {
 	return &defaultRenderer{}
@@ -64,12 +220,38 @@ func NewRenderer() FormatRenderer {
 type defaultRenderer struct{}
 
 func (r *defaultRenderer) Render(kp *crypto.KeyPair, opts RenderOptions) (map[string][]byte, error) {
+	start := time.Now()
+	defer func() {
+		metrics.RenderDuration.WithLabelValues(opts.Format).Observe(time.Since(start).Seconds())
+	}()
+
+	data, err := r.render(kp, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(opts.KeyNames) > 0 {
+		if data, err = renameEntries(data, opts.KeyNames); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Compress {
+		if data, err = compressJSON(data); err != nil {
+			return nil, err
+		}
+	}
+	if len(opts.AgeRecipients) > 0 {
+		return encryptPrivate(data, opts.AgeRecipients, opts.KeyNames)
+	}
+	return data, nil
+}
+
+func (r *defaultRenderer) render(kp *crypto.KeyPair, opts RenderOptions) (map[string][]byte, error) {
 	if kp == nil {
 		return nil, fmt.Errorf("cannot render nil KeyPair")
 	}
 
 	// Always encode to PEM first as intermediate format
-	encoder, err := crypto.NewKeyEncoder("PEM")
+	encoder, err := crypto.NewKeyEncoder("PEM", crypto.WithPrivateKeyPEMType(opts.PrivateKeyPEMType))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create PEM encoder: %w", err)
 	}
@@ -79,7 +261,7 @@ func (r *defaultRenderer) Render(kp *crypto.KeyPair, opts RenderOptions) (map[st
 		return nil, fmt.Errorf("failed to encode private key: %w", err)
 	}
 
-	pubPEM, err := encoder.EncodePublic(kp.PublicKey)
+	pubPEM, err := encoder.EncodePublicWithKID(kp.PublicKey, kp.KeyID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode public key: %w", err)
 	}
@@ -103,11 +285,69 @@ func (r *defaultRenderer) Render(kp *crypto.KeyPair, opts RenderOptions) (map[st
 	case FormatJKS:
 		return r.renderJKS(kp, opts)
 
+	case FormatJWKS:
+		if opts.HardCutover {
+			return renderJWKS(kp, nil)
+		}
+		return renderJWKS(kp, opts.PreviousKey)
+
 	default:
+		if fn, ok := lookupRenderer(opts.Format); ok {
+			return fn(kp, opts)
+		}
 		return nil, fmt.Errorf("unsupported output format: %s", opts.Format)
 	}
 }
 
+// privateJWKSDataKey is the Secret data key of the jwks format's private key set.
+const privateJWKSDataKey = "private-jwks.json"
+
+// renderJWKS renders a JWK Set of the current and, if set, previous public
+// key as "jwks.json", so validators accept either during the grace period,
+// and a set holding only the current private key as "private-jwks.json".
+// Both use the KeyID as "kid".
+func renderJWKS(kp *crypto.KeyPair, previous *crypto.PublicKeyInfo) (map[string][]byte, error) {
+	keys := []*crypto.PublicKeyInfo{kp.Public()}
+	if previous != nil && previous.KeyID != kp.KeyID {
+		keys = append(keys, previous)
+	}
+	set, err := crypto.EncodeJWKS(keys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JWKS: %w", err)
+	}
+
+	encoder, err := crypto.NewKeyEncoder("JWK")
+	if err != nil {
+		return nil, err
+	}
+	privJWK, err := encoder.EncodePrivate(kp.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+	defer clear(privJWK)
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(privJWK, &members); err != nil {
+		return nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+	defer func() {
+		for _, v := range members {
+			clear(v)
+		}
+	}()
+	if members["kid"], err = json.Marshal(kp.KeyID); err != nil {
+		return nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+	privSet, err := json.Marshal(map[string]any{"keys": []any{members}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode private key: %w", err)
+	}
+
+	return map[string][]byte{
+		DefaultKeyNames["jwks"]: set,
+		privateJWKSDataKey:      privSet,
+	}, nil
+}
+
 // renderJKS creates a Java KeyStore containing the key pair.
 // Since JKS requires a certificate chain, we generate a self-signed certificate
 // on the fly wrapping the public key. This certificate is valid for 100 years
@@ -123,7 +363,7 @@ func (r *defaultRenderer) renderJKS(kp *crypto.KeyPair, opts RenderOptions) (map
 	}
 
 	// 1. Generate self-signed certificate
-	certBytes, err := generateSelfSignedCert(kp)
+	certBytes, err := generateSelfSignedCert(kp, opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate self-signed cert for JKS: %w", err)
 	}
@@ -132,11 +372,13 @@ func (r *defaultRenderer) renderJKS(kp *crypto.KeyPair, opts RenderOptions) (map
 	ks := keystore.New()
 
 	// 3. Add Private Key Entry
-	// JKS requires the private key (PKCS8) + certificate chain
+	// JKS requires the private key (PKCS8) + certificate chain. It is marshaled
+	// from kp directly: re-parsing the PEM would leave a key copy behind. [SEC:I-2]
 	privKeyData, err := x509.MarshalPKCS8PrivateKey(kp.PrivateKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal private key for JKS: %w", err)
 	}
+	defer clear(privKeyData)
 
 	entry := keystore.PrivateKeyEntry{
 		CreationTime: time.Now(),
@@ -164,27 +406,67 @@ func (r *defaultRenderer) renderJKS(kp *crypto.KeyPair, opts RenderOptions) (map
 	}, nil
 }
 
+// Declared key uses (KeySpec.Use).
+const (
+	KeyUseSig = "sig"
+	KeyUseEnc = "enc"
+	KeyUseTLS = "tls"
+)
+
+// certUsages maps a declared key use to certificate key usages. Signing keys get
+// no extended key usage, as some validators reject JWT signing keys that carry
+// serverAuth.
+func certUsages(use string) (x509.KeyUsage, []x509.ExtKeyUsage, error) {
+	switch use {
+	case "", KeyUseSig:
+		return x509.KeyUsageDigitalSignature, nil, nil
+	case KeyUseEnc:
+		return x509.KeyUsageKeyEncipherment, nil, nil
+	case KeyUseTLS:
+		return x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+			[]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, nil
+	default:
+		return 0, nil, fmt.Errorf("unsupported key use %q", use)
+	}
+}
+
 // generateSelfSignedCert creates a minimal self-signed certificate for the given KeyPair.
-func generateSelfSignedCert(kp *crypto.KeyPair) ([]byte, error) {
+// NotBefore is backdated by opts.NotBeforeSkew. If opts.SPIFFEID is set, it is encoded as a URI SAN binding the key to the workload identity.
+func generateSelfSignedCert(kp *crypto.KeyPair, opts RenderOptions) ([]byte, error) {
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate serial number: %w", err)
 	}
 
+	skew := opts.NotBeforeSkew
+	switch {
+	case skew == 0:
+		skew = DefaultNotBeforeSkew
+	case skew < 0:
+		skew = 0
+	}
+	now := time.Now()
+
 	template := x509.Certificate{
 		SerialNumber: serialNumber,
 		Subject: pkix.Name{
 			CommonName:   "openUKR Generated Key",
 			Organization: []string{"openUKR"},
 		},
-		NotBefore: time.Now(),
-		NotAfter:  time.Now().Add(100 * 365 * 24 * time.Hour), // 100 years
+		NotBefore: now.Add(-skew),
+		NotAfter:  now.Add(100 * 365 * 24 * time.Hour), // 100 years
 
-		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
-		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
 		BasicConstraintsValid: true,
 	}
+	template.KeyUsage, template.ExtKeyUsage, err = certUsages(opts.KeyUse)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.SPIFFEID != nil {
+		template.URIs = []*url.URL{opts.SPIFFEID}
+	}
 
 	// Self-sign
 	certBytes, err := x509.CreateCertificate(rand.Reader, &template, &template, kp.PublicKey, kp.PrivateKey)
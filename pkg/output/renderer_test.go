@@ -0,0 +1,465 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/metrics"
+)
+
+func generateTestKey(t *testing.T) *crypto.KeyPair {
+	t.Helper()
+	kp, err := crypto.NewKeyGenerator().Generate(crypto.GenerateOptions{
+		Algorithm: crypto.AlgorithmEC,
+		Params:    map[string]string{"curve": crypto.CurveP256},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	t.Cleanup(kp.Wipe)
+	return kp
+}
+
+func TestSelfSignedCertSPIFFESAN(t *testing.T) {
+	t.Parallel()
+
+	kp := generateTestKey(t)
+	spiffeID, err := NewSPIFFEID("cluster.local", "finance", "payments")
+	if err != nil {
+		t.Fatalf("NewSPIFFEID() error = %v", err)
+	}
+
+	der, err := generateSelfSignedCert(kp, RenderOptions{SPIFFEID: spiffeID})
+	if err != nil {
+		t.Fatalf("generateSelfSignedCert() error = %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+
+	const want = "spiffe://cluster.local/ns/finance/sa/payments"
+	if len(cert.URIs) != 1 || cert.URIs[0].String() != want {
+		t.Errorf("cert URIs = %v, want [%s]", cert.URIs, want)
+	}
+}
+
+func TestSelfSignedCertNotBeforeSkew(t *testing.T) {
+	t.Parallel()
+
+	kp := generateTestKey(t)
+	tests := []struct {
+		name     string
+		skew     time.Duration
+		wantSkew time.Duration
+	}{
+		{name: "default", skew: 0, wantSkew: DefaultNotBeforeSkew},
+		{name: "configured", skew: 2 * time.Minute, wantSkew: 2 * time.Minute},
+		{name: "disabled", skew: -1, wantSkew: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			before := time.Now()
+			der, err := generateSelfSignedCert(kp, RenderOptions{NotBeforeSkew: tt.skew})
+			if err != nil {
+				t.Fatalf("generateSelfSignedCert() error = %v", err)
+			}
+			after := time.Now()
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				t.Fatalf("ParseCertificate() error = %v", err)
+			}
+
+			// Certificate times have second precision
+			earliest := before.Add(-tt.wantSkew).Truncate(time.Second)
+			latest := after.Add(-tt.wantSkew)
+			if cert.NotBefore.Before(earliest) || cert.NotBefore.After(latest) {
+				t.Errorf("NotBefore = %s, want %s before issuance (between %s and %s)",
+					cert.NotBefore, tt.wantSkew, earliest, latest)
+			}
+		})
+	}
+}
+
+func TestNewSPIFFEID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		trustDomain    string
+		namespace      string
+		serviceAccount string
+		wantErr        bool
+	}{
+		{name: "valid", trustDomain: "cluster.local", namespace: "finance", serviceAccount: "payments"},
+		{name: "invalid: uppercase trust domain", trustDomain: "Cluster", namespace: "ns", serviceAccount: "sa", wantErr: true},
+		{name: "invalid: empty trust domain", trustDomain: "", namespace: "ns", serviceAccount: "sa", wantErr: true},
+		{name: "invalid: slash in namespace", trustDomain: "td", namespace: "a/b", serviceAccount: "sa", wantErr: true},
+		{name: "invalid: dot-dot service account", trustDomain: "td", namespace: "ns", serviceAccount: "..", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := NewSPIFFEID(tt.trustDomain, tt.namespace, tt.serviceAccount)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewSPIFFEID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	t.Parallel()
+
+	const format = "test-raw-keyid"
+	err := RegisterRenderer(format, func(kp *crypto.KeyPair, _ RenderOptions) (map[string][]byte, error) {
+		return map[string][]byte{"key-id": []byte(kp.KeyID)}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterRenderer() error = %v", err)
+	}
+
+	kp := generateTestKey(t)
+	data, err := NewRenderer().Render(kp, RenderOptions{Format: format})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got := string(data["key-id"]); got != kp.KeyID {
+		t.Errorf("key-id = %q, want %q", got, kp.KeyID)
+	}
+
+	noop := func(*crypto.KeyPair, RenderOptions) (map[string][]byte, error) { return nil, nil }
+	if err := RegisterRenderer(format, noop); err == nil {
+		t.Error("expected error registering a format twice")
+	}
+	if err := RegisterRenderer(FormatSplitPEM, noop); err == nil {
+		t.Error("expected error overriding a built-in format")
+	}
+	if err := RegisterRenderer("", noop); err == nil {
+		t.Error("expected error for empty format")
+	}
+	if err := RegisterRenderer("test-nil", nil); err == nil {
+		t.Error("expected error for nil renderer")
+	}
+}
+
+func TestSupportedFormatsRender(t *testing.T) {
+	t.Parallel()
+
+	formats := SupportedFormats()
+	if !slices.IsSorted(formats) {
+		t.Errorf("SupportedFormats() = %v, want sorted", formats)
+	}
+	for _, want := range []string{FormatSplitPEM, FormatSinglePEM, FormatJKS} {
+		if !slices.Contains(formats, want) {
+			t.Errorf("SupportedFormats() = %v, missing %s", formats, want)
+		}
+	}
+	if slices.Contains(formats, "unknown") {
+		t.Errorf("SupportedFormats() = %v, lists an unknown format", formats)
+	}
+
+	kp := generateTestKey(t)
+	for _, format := range formats {
+		if _, err := NewRenderer().Render(kp, RenderOptions{Format: format, Password: "changeit"}); err != nil {
+			t.Errorf("Render(%s) error = %v, want accepted", format, err)
+		}
+	}
+}
+
+func TestRenderJWKS(t *testing.T) {
+	t.Parallel()
+
+	kp, previous := generateTestKey(t), generateTestKey(t)
+	data, err := NewRenderer().Render(kp, RenderOptions{Format: FormatJWKS, PreviousKey: previous.Public()})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+
+	keys, err := crypto.ParseJWKS(data["jwks.json"])
+	if err != nil {
+		t.Fatalf("ParseJWKS(jwks.json) error = %v", err)
+	}
+	kids := make([]string, 0, len(keys))
+	for _, k := range keys {
+		kids = append(kids, k.KeyID)
+	}
+	if want := []string{kp.KeyID, previous.KeyID}; !slices.Equal(slices.Sorted(slices.Values(kids)), slices.Sorted(slices.Values(want))) {
+		t.Errorf("jwks.json kids = %v, want %v", kids, want)
+	}
+	if bytes.Contains(data["jwks.json"], []byte(`"d"`)) {
+		t.Error("jwks.json contains private key material")
+	}
+
+	var privSet struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data["private-jwks.json"], &privSet); err != nil {
+		t.Fatalf("json.Unmarshal(private-jwks.json) error = %v", err)
+	}
+	if len(privSet.Keys) != 1 {
+		t.Fatalf("private-jwks.json has %d keys, want only the current key", len(privSet.Keys))
+	}
+	priv, err := crypto.Convert(privSet.Keys[0], "JWK", "PEM", true)
+	if err != nil {
+		t.Fatalf("Convert(private JWK) error = %v", err)
+	}
+	parsed, err := crypto.ParsePrivateKeyPEM(priv)
+	if err != nil {
+		t.Fatalf("ParsePrivateKeyPEM() error = %v", err)
+	}
+	if err := crypto.VerifyKeyPair(parsed, kp.PublicKey); err != nil {
+		t.Errorf("private-jwks.json does not hold the current key: %v", err)
+	}
+	var member struct {
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(privSet.Keys[0], &member); err != nil || member.Kid != kp.KeyID {
+		t.Errorf("private JWK kid = %q (err %v), want %s", member.Kid, err, kp.KeyID)
+	}
+
+	// Without a previous key, or after a hard cutover, only the current key is listed
+	for _, opts := range []RenderOptions{
+		{Format: FormatJWKS},
+		{Format: FormatJWKS, PreviousKey: previous.Public(), HardCutover: true},
+	} {
+		data, err = NewRenderer().Render(kp, opts)
+		if err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		if keys, err := crypto.ParseJWKS(data["jwks.json"]); err != nil || len(keys) != 1 {
+			t.Errorf("ParseJWKS(jwks.json) = %d keys, %v, want only the current key", len(keys), err)
+		}
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewRenderer().Render(generateTestKey(t), RenderOptions{Format: "unknown"}); err == nil {
+		t.Fatal("expected error for unknown format, got nil")
+	}
+}
+
+func TestNewCSR(t *testing.T) {
+	t.Parallel()
+
+	kp := generateTestKey(t)
+	spiffeID, err := NewSPIFFEID("example.org", "default", "app")
+	if err != nil {
+		t.Fatalf("NewSPIFFEID() error = %v", err)
+	}
+
+	csrPEM, err := NewCSR(kp, spiffeID)
+	if err != nil {
+		t.Fatalf("NewCSR() error = %v", err)
+	}
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		t.Fatalf("expected CERTIFICATE REQUEST PEM block, got %v", block)
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		t.Fatalf("ParseCertificateRequest() error = %v", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		t.Errorf("CSR signature invalid: %v", err)
+	}
+	if csr.Subject.CommonName != kp.KeyID {
+		t.Errorf("CommonName = %q, want %q", csr.Subject.CommonName, kp.KeyID)
+	}
+	if len(csr.URIs) != 1 || csr.URIs[0].String() != spiffeID.String() {
+		t.Errorf("URIs = %v, want [%s]", csr.URIs, spiffeID)
+	}
+}
+
+func TestRenderCompressRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	const format = "test-json-bundle"
+	doc := []byte(`{"keys":[{"kty":"EC"}]}`)
+	err := RegisterRenderer(format, func(*crypto.KeyPair, RenderOptions) (map[string][]byte, error) {
+		return map[string][]byte{"jwks.json": doc, "public.pem": []byte("pem")}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterRenderer() error = %v", err)
+	}
+
+	kp := generateTestKey(t)
+	data, err := NewRenderer().Render(kp, RenderOptions{Format: format, Compress: true})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if _, ok := data["jwks.json"]; ok {
+		t.Error("uncompressed jwks.json still present")
+	}
+	if got := string(data["public.pem"]); got != "pem" {
+		t.Errorf("public.pem = %q, want it unchanged", got)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data["jwks.json.gz"]))
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	plain, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if !bytes.Equal(plain, doc) {
+		t.Errorf("decompressed = %q, want %q", plain, doc)
+	}
+
+	// Deterministic: equal input renders equal bytes
+	again, err := NewRenderer().Render(kp, RenderOptions{Format: format, Compress: true})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !bytes.Equal(again["jwks.json.gz"], data["jwks.json.gz"]) {
+		t.Error("compressed output is not deterministic")
+	}
+}
+
+func TestSelfSignedCertKeyUse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		use          string
+		wantKeyUsage x509.KeyUsage
+		wantExtUsage []x509.ExtKeyUsage
+		wantErr      bool
+	}{
+		{use: "", wantKeyUsage: x509.KeyUsageDigitalSignature},
+		{use: KeyUseSig, wantKeyUsage: x509.KeyUsageDigitalSignature},
+		{use: KeyUseEnc, wantKeyUsage: x509.KeyUsageKeyEncipherment},
+		{
+			use:          KeyUseTLS,
+			wantKeyUsage: x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+			wantExtUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		},
+		{use: "bogus", wantErr: true},
+	}
+
+	kp := generateTestKey(t)
+	for _, tt := range tests {
+		t.Run("use="+tt.use, func(t *testing.T) {
+			t.Parallel()
+			der, err := generateSelfSignedCert(kp, RenderOptions{KeyUse: tt.use})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("generateSelfSignedCert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				t.Fatalf("ParseCertificate() error = %v", err)
+			}
+			if cert.KeyUsage != tt.wantKeyUsage {
+				t.Errorf("KeyUsage = %v, want %v", cert.KeyUsage, tt.wantKeyUsage)
+			}
+			if !slices.Equal(cert.ExtKeyUsage, tt.wantExtUsage) {
+				t.Errorf("ExtKeyUsage = %v, want %v", cert.ExtKeyUsage, tt.wantExtUsage)
+			}
+		})
+	}
+}
+
+func TestRenderKeyNames(t *testing.T) {
+	t.Parallel()
+
+	const format = "test-json-keynames"
+	err := RegisterRenderer(format, func(*crypto.KeyPair, RenderOptions) (map[string][]byte, error) {
+		return map[string][]byte{"jwks.json": []byte(`{"keys":[]}`), "public.pem": []byte("pem")}, nil
+	})
+	if err != nil {
+		t.Fatalf("RegisterRenderer() error = %v", err)
+	}
+
+	kp := generateTestKey(t)
+	opts := RenderOptions{Format: format, KeyNames: map[string]string{"jwks": "keys.json"}}
+	data, err := NewRenderer().Render(kp, opts)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if _, ok := data["jwks.json"]; ok {
+		t.Error("jwks.json still present after rename")
+	}
+	if got := string(data["keys.json"]); got != `{"keys":[]}` {
+		t.Errorf("keys.json = %q, want the JWKS", got)
+	}
+
+	// Renaming happens before compression
+	opts.Compress = true
+	data, err = NewRenderer().Render(kp, opts)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if _, ok := data["keys.json.gz"]; !ok {
+		t.Errorf("keys.json.gz missing, got keys %v", slices.Sorted(maps.Keys(data)))
+	}
+
+	for name, names := range map[string]map[string]string{
+		"collision":     {"jwks": "public.pem"},
+		"unknown entry": {"certs": "certs.json"},
+		"traversal":     {"jwks": "../jwks.json"},
+	} {
+		if _, err := NewRenderer().Render(kp, RenderOptions{Format: format, KeyNames: names}); err == nil {
+			t.Errorf("%s: Render() expected error, got nil", name)
+		}
+	}
+}
+
+// renderSamples returns the number of render durations observed for format.
+func renderSamples(t *testing.T, format string) uint64 {
+	t.Helper()
+	var m dto.Metric
+	if err := metrics.RenderDuration.WithLabelValues(format).(prometheus.Metric).Write(&m); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+// Not parallel: other tests render the same formats.
+func TestRenderObservesDurationPerFormat(t *testing.T) {
+	kp := generateTestKey(t)
+	for _, format := range []string{FormatSplitPEM, FormatSinglePEM, FormatJKS} {
+		before := renderSamples(t, format)
+		if _, err := NewRenderer().Render(kp, RenderOptions{Format: format, Password: "changeit"}); err != nil {
+			t.Fatalf("Render(%s) error = %v", format, err)
+		}
+		if got := renderSamples(t, format) - before; got != 1 {
+			t.Errorf("%s: observed %d render durations, want 1", format, got)
+		}
+	}
+}
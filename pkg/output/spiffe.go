@@ -0,0 +1,62 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+)
+
+var (
+	// trustDomainPattern matches SPIFFE trust domain names (lowercase only).
+	trustDomainPattern = regexp.MustCompile(`^[a-z0-9._-]+$`)
+	// pathSegmentPattern matches SPIFFE ID path segments.
+	pathSegmentPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+)
+
+// NewSPIFFEID builds the workload identity URI SAN for a ServiceAccount:
+// spiffe://{trustDomain}/ns/{namespace}/sa/{serviceAccount}.
+// All components are validated against the SPIFFE ID specification.
+func NewSPIFFEID(trustDomain, namespace, serviceAccount string) (*url.URL, error) {
+	if !trustDomainPattern.MatchString(trustDomain) {
+		return nil, fmt.Errorf("invalid SPIFFE trust domain %q: must match %s", trustDomain, trustDomainPattern)
+	}
+	for _, segment := range []string{namespace, serviceAccount} {
+		if !pathSegmentPattern.MatchString(segment) || segment == "." || segment == ".." {
+			return nil, fmt.Errorf("invalid SPIFFE path segment %q", segment)
+		}
+	}
+
+	return &url.URL{
+		Scheme: "spiffe",
+		Host:   trustDomain,
+		Path:   fmt.Sprintf("/ns/%s/sa/%s", namespace, serviceAccount),
+	}, nil
+}
+
+// SPIFFEIDForProfile returns the SPIFFE ID bound to a KeyProfile's ServiceAccount,
+// or nil if no SPIFFE trust domain is configured.
+func SPIFFEIDForProfile(profile *openukrv1alpha1.KeyProfile) (*url.URL, error) {
+	td := profile.Spec.Output.SPIFFETrustDomain
+	if td == "" {
+		return nil, nil
+	}
+	return NewSPIFFEID(td, profile.Spec.ServiceAccountRef.Namespace, profile.Spec.ServiceAccountRef.Name)
+}
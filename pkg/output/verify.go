@@ -0,0 +1,75 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+// ErrIntegrity marks Secret key material that is corrupt or has been tampered with.
+// [SEC:T-1]
+var ErrIntegrity = errors.New("secret integrity violation")
+
+// VerifySecret checks that the private and public key stored in the Secret form a
+// valid pair and, if fingerprint is non-zero, that the public key matches it.
+// With encrypted private entries only the public key is checked against the
+// fingerprint. Formats without parseable PEM material (e.g. jks, custom
+// formats) are skipped. Violations wrap ErrIntegrity.
+func VerifySecret(secret *corev1.Secret, fingerprint crypto.Fingerprint) error {
+	var privPEM, pubPEM []byte
+	switch {
+	case secret.Data["tls.key"] != nil:
+		privPEM, pubPEM = secret.Data["tls.key"], secret.Data["public.pem"]
+	case secret.Data["keypair.pem"] != nil:
+		privPEM, pubPEM = secret.Data["keypair.pem"], secret.Data["keypair.pem"]
+	case secret.Annotations[encryptionAnnotation] != "" && secret.Data["public.pem"] != nil:
+		pubPEM = secret.Data["public.pem"]
+	default:
+		return nil
+	}
+
+	pub, err := crypto.ParsePublicKeyPEM(pubPEM)
+	if err != nil {
+		return fmt.Errorf("%w: public key: %w", ErrIntegrity, err)
+	}
+	if privPEM != nil {
+		priv, err := crypto.ParsePrivateKeyPEM(privPEM)
+		if err != nil {
+			return fmt.Errorf("%w: private key: %w", ErrIntegrity, err)
+		}
+		if err := crypto.VerifyKeyPair(priv, pub); err != nil {
+			return fmt.Errorf("%w: %w", ErrIntegrity, err)
+		}
+	}
+
+	if fingerprint.IsZero() {
+		return nil
+	}
+	ok, err := fingerprint.Matches(pub)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrIntegrity, err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: public key does not match recorded fingerprint %s", ErrIntegrity, fingerprint)
+	}
+	return nil
+}
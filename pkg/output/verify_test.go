@@ -0,0 +1,157 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+func TestVerifySecret(t *testing.T) {
+	t.Parallel()
+
+	keyA := generateTestKey(t)
+	keyB := generateTestKey(t)
+	renderer := NewRenderer()
+	splitA, err := renderer.Render(keyA, RenderOptions{Format: FormatSplitPEM})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	splitB, err := renderer.Render(keyB, RenderOptions{Format: FormatSplitPEM})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	singleA, err := renderer.Render(keyA, RenderOptions{Format: FormatSinglePEM})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	fingerprintA, err := crypto.ComputeFingerprint(keyA.PublicKey)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+	fingerprintB, err := crypto.ComputeFingerprint(keyB.PublicKey)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		data          map[string][]byte
+		fingerprint   crypto.Fingerprint
+		wantIntegrity bool
+	}{
+		{
+			name:        "split-pem matching pair",
+			data:        splitA,
+			fingerprint: fingerprintA,
+		},
+		{
+			name:        "single-pem matching pair",
+			data:        singleA,
+			fingerprint: fingerprintA,
+		},
+		{
+			name:          "mismatched pair",
+			data:          map[string][]byte{"tls.key": splitA["tls.key"], "public.pem": splitB["public.pem"]},
+			wantIntegrity: true,
+		},
+		{
+			name:          "fingerprint mismatch",
+			data:          splitA,
+			fingerprint:   fingerprintB,
+			wantIntegrity: true,
+		},
+		{
+			name:          "corrupt private key",
+			data:          map[string][]byte{"tls.key": []byte("garbage"), "public.pem": splitA["public.pem"]},
+			wantIntegrity: true,
+		},
+		{
+			name:          "garbage public key",
+			data:          map[string][]byte{"tls.key": splitA["tls.key"], "public.pem": []byte("\x00\xffgarbage")},
+			wantIntegrity: true,
+		},
+		{
+			name:          "truncated private key PEM",
+			data:          map[string][]byte{"tls.key": splitA["tls.key"][:len(splitA["tls.key"])/2], "public.pem": splitA["public.pem"]},
+			wantIntegrity: true,
+		},
+		{
+			name:          "truncated single-pem",
+			data:          map[string][]byte{"keypair.pem": singleA["keypair.pem"][:len(singleA["keypair.pem"])-40]},
+			fingerprint:   fingerprintA,
+			wantIntegrity: true,
+		},
+		{
+			name: "wrong block type",
+			data: map[string][]byte{
+				"tls.key":    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("not a key")}),
+				"public.pem": splitA["public.pem"],
+			},
+			wantIntegrity: true,
+		},
+		{
+			name: "public key in private key block",
+			data: map[string][]byte{
+				"tls.key":    bytes.Replace(splitA["public.pem"], []byte("PUBLIC KEY"), []byte("PRIVATE KEY"), 2),
+				"public.pem": splitA["public.pem"],
+			},
+			wantIntegrity: true,
+		},
+		{
+			name: "no PEM material",
+			data: map[string][]byte{"keystore.jks": []byte("opaque")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := VerifySecret(&corev1.Secret{Data: tt.data}, tt.fingerprint)
+			if tt.wantIntegrity {
+				if !errors.Is(err, ErrIntegrity) {
+					t.Errorf("VerifySecret() error = %v, want ErrIntegrity", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("VerifySecret() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestInspectSecretMalformed(t *testing.T) {
+	t.Parallel()
+
+	for name, data := range map[string][]byte{
+		"garbage":   []byte("\x00\xffgarbage"),
+		"truncated": []byte("-----BEGIN PUBLIC KEY-----\nMFkwEwYHKoZIzj0CAQYI"),
+		"wrong DER": pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: []byte("not DER")}),
+	} {
+		_, err := InspectSecret(&corev1.Secret{Data: map[string][]byte{"public.pem": data}})
+		if !errors.Is(err, ErrIntegrity) {
+			t.Errorf("%s: InspectSecret() error = %v, want ErrIntegrity", name, err)
+		}
+	}
+}
@@ -17,8 +17,17 @@ limitations under the License.
 package output
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -34,48 +43,424 @@ import (
 
 // SecretWriter manages the lifecycle of Kubernetes Secrets containing key material.
 type SecretWriter interface {
-	// Write creates or updates the Secret for the given KeyProfile and KeyPair.
-	// It handles:
+	// Write creates or updates the Secrets for the given KeyProfile and KeyPair,
+	// one per Spec.Output and Spec.AdditionalOutputs. It handles:
 	// - Rendering the key material (via FormatRenderer)
 	// - Setting OwnerReference
 	// - Atomic Secret update
-	Write(ctx context.Context, profile *openukrv1alpha1.KeyProfile, kp *crypto.KeyPair) error
+	// The previous key material is retained under "-previous" data keys
+	// (e.g. tls-previous.key) until DropPrevious is called.
+	Write(ctx context.Context, profile *openukrv1alpha1.KeyProfile, kp *crypto.KeyPair, opts ...WriteOption) error
+
+	// DropPrevious removes the previous key's private material from every output
+	// Secret once the grace period has ended. Previous public entries are kept.
+	// [SEC:I-2]
+	DropPrevious(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error
+
+	// Verify checks the Secret's current key material with VerifySecret.
+	// The recorded fingerprint is only enforced while the Secret holds the
+	// profile's current key. A missing Secret is not an error. [SEC:T-1]
+	Verify(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error
+
+	// Inspect reads the key metadata of the profile's current Secret without
+	// writing or verifying it. Returns nil if the Secret does not exist.
+	Inspect(ctx context.Context, profile *openukrv1alpha1.KeyProfile) (*SecretInfo, error)
+
+	// MarkPublished records the PublishConfigHash the current key was published
+	// with, after it was re-published without rotation.
+	MarkPublished(ctx context.Context, profile *openukrv1alpha1.KeyProfile, hash string) error
+
+	// MarkSchedule records the next rotation time and the rotation interval on
+	// the profile's current Secret. The Secret is only updated when either
+	// value changed; a zero next clears the next-rotation annotation.
+	MarkSchedule(ctx context.Context, profile *openukrv1alpha1.KeyProfile, next time.Time) error
+
+	// StageNext stores kp as the key to promote at the next rotation, in a
+	// Secret separate from the output (see NextSecretName).
+	StageNext(ctx context.Context, profile *openukrv1alpha1.KeyProfile, kp *crypto.KeyPair) error
+
+	// LoadNext returns the staged next key, or nil if none is staged.
+	// The caller must Wipe the returned key. [SEC:I-2]
+	LoadNext(ctx context.Context, profile *openukrv1alpha1.KeyProfile) (*NextKey, error)
+
+	// MarkNextPublished records the PublishConfigHash the staged next key was
+	// published with.
+	MarkNextPublished(ctx context.Context, profile *openukrv1alpha1.KeyProfile, hash string) error
+
+	// DropNext deletes the staged next key. A missing Secret is not an error.
+	DropNext(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error
+}
+
+// ErrForeignSecret is returned when a Secret the writer names after the profile
+// (e.g. the staged next key) or, with WithRefuseForeignSecrets, the output
+// Secret exists but is neither labeled as managed by the profile nor owned by
+// it. [SEC:S-1]
+var ErrForeignSecret = errors.New("secret is not managed by this KeyProfile")
+
+// renderHashAnnotation records a hash of the RenderOptions the Secret data was rendered with.
+const renderHashAnnotation = "openukr.io/render-hash"
+
+// renderOptionsHash returns a short stable hash of the render options (excluding secrets).
+func renderOptionsHash(opts RenderOptions) (string, error) {
+	b, err := json.Marshal(opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash render options: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// publishHashAnnotation records the PublishConfigHash of the targets the current key was published to.
+const publishHashAnnotation = "openukr.io/publish-hash"
+
+// PublishConfigHash returns a short stable hash of the publish targets. A change
+// means the current key has not reached every configured target yet.
+func PublishConfigHash(targets []openukrv1alpha1.PublishTarget) (string, error) {
+	b, err := json.Marshal(targets)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash publish config: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// Schedule annotations written by MarkSchedule and WithNextRotation.
+const (
+	nextRotationAnnotation     = "openukr.io/next-rotation"
+	rotationIntervalAnnotation = "openukr.io/rotation-interval"
+)
+
+// WriteOption configures a single SecretWriter.Write.
+type WriteOption func(*writeOptions)
+
+type writeOptions struct {
+	// schedule is set when the schedule annotations are written with the key.
+	schedule     bool
+	nextRotation time.Time
+	// rotatedAt, if set, is recorded as the key's last rotation instead of its creation time.
+	rotatedAt time.Time
+}
+
+// WithNextRotation records next and the rotation interval on the current Secret
+// in the same update that writes the key, as MarkSchedule would afterwards.
+func WithNextRotation(next time.Time) WriteOption {
+	return func(o *writeOptions) {
+		o.schedule = true
+		o.nextRotation = next
+	}
+}
+
+// WithRotationTime records at as the key's last rotation instead of its
+// creation time, which is earlier for a key staged ahead of its promotion.
+func WithRotationTime(at time.Time) WriteOption {
+	return func(o *writeOptions) {
+		o.rotatedAt = at
+	}
+}
+
+// lastRotation returns the last-rotation annotation value for kp.
+func (o writeOptions) lastRotation(kp *crypto.KeyPair) string {
+	if o.rotatedAt.IsZero() {
+		return kp.CreatedAt.Format(time.RFC3339)
+	}
+	return o.rotatedAt.UTC().Format(time.RFC3339)
+}
+
+// setSchedule sets the schedule annotations for next and the profile's rotation
+// interval; a zero next removes the next-rotation annotation. Returns true if
+// annotations changed. annotations must not be nil.
+func setSchedule(annotations map[string]string, profile *openukrv1alpha1.KeyProfile, next time.Time) bool {
+	changed := false
+	set := func(k, v string) {
+		if annotations[k] != v {
+			annotations[k] = v
+			changed = true
+		}
+	}
+	set(rotationIntervalAnnotation, profile.Spec.Rotation.Interval.Duration.String())
+	if !next.IsZero() {
+		set(nextRotationAnnotation, next.UTC().Format(time.RFC3339))
+	} else if _, ok := annotations[nextRotationAnnotation]; ok {
+		delete(annotations, nextRotationAnnotation)
+		changed = true
+	}
+	return changed
+}
+
+// previousAlgorithmAnnotation records the algorithm of the previous key, which
+// differs from openukr.io/algorithm after an algorithm migration.
+const previousAlgorithmAnnotation = "openukr.io/previous-algorithm"
+
+// previousSuffix marks Secret data keys holding the previous key's material.
+const previousSuffix = "-previous"
+
+// publicDataKeys lists Secret data keys that only contain public material.
+var publicDataKeys = map[string]bool{
+	"tls.crt":          true,
+	"public.pem":       true,
+	CertificateDataKey: true,
+	CADataKey:          true,
+}
+
+// Secret data keys holding a CA-signed certificate for the key and its issuing CA.
+const (
+	CertificateDataKey = "certificate.pem"
+	CADataKey          = "ca.pem"
+)
+
+// previousDataKey maps a data key to its previous-key counterpart,
+// inserting the suffix before the extension: tls.key → tls-previous.key.
+func previousDataKey(key string) string {
+	ext := path.Ext(key)
+	return strings.TrimSuffix(key, ext) + previousSuffix + ext
+}
+
+// isPreviousDataKey reports whether key holds previous key material.
+func isPreviousDataKey(key string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(key, path.Ext(key)), previousSuffix)
+}
+
+// isPublicDataKey reports whether key (current or previous) holds only public material.
+func isPublicDataKey(key string) bool {
+	if publicDataKeys[key] {
+		return true
+	}
+	for k := range publicDataKeys {
+		if previousDataKey(k) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// isPublicEntry reports whether the data key (current or previous) of an
+// output with the given KeyNames holds only public material. Unlike
+// isPublicDataKey it covers the jwks document, whose name is configurable.
+func isPublicEntry(key string, keyNames map[string]string) bool {
+	if isPublicDataKey(key) {
+		return true
+	}
+	doc := jwksEntryName(keyNames)
+	for _, k := range []string{doc, doc + gzipSuffix} {
+		if key == k || key == previousDataKey(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// retainPrevious returns the data entries to keep as previous key material.
+// If the Secret currently holds a different key, its entries become the
+// previous entries; otherwise existing previous entries are carried over.
+func retainPrevious(secret *corev1.Secret, newKeyID string) (map[string][]byte, string) {
+	retained := make(map[string][]byte)
+	currentKeyID := secret.Annotations["openukr.io/key-id"]
+
+	if currentKeyID != "" && currentKeyID != newKeyID {
+		for k, v := range secret.Data {
+			if !isPreviousDataKey(k) {
+				retained[previousDataKey(k)] = v
+			}
+		}
+		return retained, currentKeyID
+	}
+
+	for k, v := range secret.Data {
+		if isPreviousDataKey(k) {
+			retained[k] = v
+		}
+	}
+	return retained, secret.Annotations["openukr.io/previous-key-id"]
+}
+
+// DefaultMaxSecretSize is the default limit on Secret data written by the
+// SecretWriter, kept below the 1 MiB etcd object limit to leave room for metadata.
+const DefaultMaxSecretSize = 900 << 10
+
+// ErrSecretTooLarge is returned when rendered Secret data exceeds the size limit.
+var ErrSecretTooLarge = errors.New("secret data exceeds size limit")
+
+// WriterOption configures optional behavior of the SecretWriter.
+type WriterOption func(*kubeSecretWriter)
+
+// WithMaxSecretSize sets the maximum total size of Secret data in bytes, checked
+// before every write. Values <= 0 disable the check.
+func WithMaxSecretSize(n int) WriterOption {
+	return func(w *kubeSecretWriter) {
+		w.maxSecretSize = n
+	}
+}
+
+// WithRefuseForeignSecrets makes the writer fail with ErrForeignSecret instead
+// of taking over an existing Secret that is neither labeled as managed by the
+// profile nor owned by it. [SEC:S-1]
+func WithRefuseForeignSecrets(refuse bool) WriterOption {
+	return func(w *kubeSecretWriter) {
+		w.refuseForeign = refuse
+	}
 }
 
 // NewSecretWriter creates a new SecretWriter.
-func NewSecretWriter(client client.Client, scheme *runtime.Scheme, renderer FormatRenderer) SecretWriter {
-	return &kubeSecretWriter{
-		client:   client,
-		scheme:   scheme,
-		renderer: renderer,
+func NewSecretWriter(client client.Client, scheme *runtime.Scheme, renderer FormatRenderer, opts ...WriterOption) SecretWriter {
+	w := &kubeSecretWriter{
+		client:        client,
+		scheme:        scheme,
+		renderer:      renderer,
+		maxSecretSize: DefaultMaxSecretSize,
+	}
+	for _, opt := range opts {
+		opt(w)
 	}
+	return w
 }
 
 type kubeSecretWriter struct {
-	client   client.Client
-	scheme   *runtime.Scheme
-	renderer FormatRenderer
+	client        client.Client
+	scheme        *runtime.Scheme
+	renderer      FormatRenderer
+	maxSecretSize int
+	keyWrapper    KeyWrapper
+	refuseForeign bool
+}
+
+// checkSize fails with ErrSecretTooLarge if data exceeds the configured limit,
+// instead of letting the API server reject the write with an opaque error.
+func (w *kubeSecretWriter) checkSize(data map[string][]byte) error {
+	if w.maxSecretSize <= 0 {
+		return nil
+	}
+	size := 0
+	for k, v := range data {
+		size += len(k) + len(v)
+	}
+	if size <= w.maxSecretSize {
+		return nil
+	}
+	return fmt.Errorf("%w: %d bytes rendered, limit %d; reduce the number of retained keys "+
+		"(key history, keys per profile) or enable output compression", ErrSecretTooLarge, size, w.maxSecretSize)
 }
 
-func (w *kubeSecretWriter) Write(ctx context.Context, profile *openukrv1alpha1.KeyProfile, kp *crypto.KeyPair) error {
+// additionalOutputLabel marks the Secrets of Spec.AdditionalOutputs, so the
+// Secret of a removed entry can be found and deleted.
+const additionalOutputLabel = "openukr.io/additional-output"
+
+// outputProfiles returns profile for Spec.Output, followed by a shallow copy
+// per Spec.AdditionalOutputs with Spec.Output replaced, so every output Secret
+// is written and cleaned up through the same code path.
+func outputProfiles(profile *openukrv1alpha1.KeyProfile) []*openukrv1alpha1.KeyProfile {
+	profiles := make([]*openukrv1alpha1.KeyProfile, 0, 1+len(profile.Spec.AdditionalOutputs))
+	profiles = append(profiles, profile)
+	for _, out := range profile.Spec.AdditionalOutputs {
+		p := *profile
+		p.Spec.Output = out
+		profiles = append(profiles, &p)
+	}
+	return profiles
+}
+
+func (w *kubeSecretWriter) Write(
+	ctx context.Context,
+	profile *openukrv1alpha1.KeyProfile,
+	kp *crypto.KeyPair,
+	opts ...WriteOption,
+) error {
 	if profile == nil {
 		return fmt.Errorf("profile cannot be nil")
 	}
 	if kp == nil {
 		return fmt.Errorf("keyPair cannot be nil")
 	}
+	var wo writeOptions
+	for _, opt := range opts {
+		opt(&wo)
+	}
+
+	for i, p := range outputProfiles(profile) {
+		// The schedule is mirrored onto the current Secret only (see MarkSchedule)
+		ow := wo
+		ow.schedule = wo.schedule && i == 0
+		if err := w.writeOutput(ctx, p, kp, i > 0, ow); err != nil {
+			if i == 0 {
+				return err
+			}
+			return fmt.Errorf("additional output %s: %w", p.Spec.Output.SecretName, err)
+		}
+	}
+	return w.deleteRemovedOutputs(ctx, profile)
+}
+
+// deleteRemovedOutputs deletes the Secrets of entries removed from
+// Spec.AdditionalOutputs. [SEC:I-2]
+func (w *kubeSecretWriter) deleteRemovedOutputs(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error {
+	var secrets corev1.SecretList
+	if err := w.client.List(ctx, &secrets,
+		client.InNamespace(profile.Namespace),
+		client.MatchingLabels{
+			"app.kubernetes.io/managed-by": "openukr",
+			"openukr.io/key-profile":       profile.Name,
+			additionalOutputLabel:          "true",
+		},
+	); err != nil {
+		return fmt.Errorf("failed to list additional output secrets: %w", err)
+	}
+	current := map[string]bool{profile.Spec.Output.SecretName: true}
+	for _, out := range profile.Spec.AdditionalOutputs {
+		current[out.SecretName] = true
+	}
+	for i := range secrets.Items {
+		if current[secrets.Items[i].Name] {
+			continue
+		}
+		if err := w.client.Delete(ctx, &secrets.Items[i]); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to delete removed additional output %s: %w", secrets.Items[i].Name, err)
+		}
+	}
+	return nil
+}
 
+// writeOutput writes kp to the Secret of profile.Spec.Output. Additional
+// outputs keep no history: pruning is by profile, and KeepHistorySecrets is
+// only supported on Spec.Output.
+func (w *kubeSecretWriter) writeOutput(
+	ctx context.Context,
+	profile *openukrv1alpha1.KeyProfile,
+	kp *crypto.KeyPair,
+	additional bool,
+	wo writeOptions,
+) error {
 	// 1. Render data
 	// TODO: Password/Alias handling from profile.Spec.Output (not yet in CRD spec, defaulting to empty/default)
 	// For JKS, future iterations will need to read password from another Secret.
 	// For now, we assume defaults or empty password (which errors for JKS).
 	// [Gap]: JKS Password support in CRD needed.
 	opts := RenderOptions{
-		Format: profile.Spec.Output.Format,
+		Format:            profile.Spec.Output.Format,
+		PrivateKeyPEMType: profile.Spec.KeySpec.PrivateKeyPEMType,
+		Compress:          profile.Spec.Output.Compress,
+		KeyUse:            profile.Spec.KeySpec.Use,
+		KeyNames:          profile.Spec.Output.KeyNames,
+		AgeRecipients:     ageRecipients(profile.Spec.Output),
+		KMSKeyURL:         kmsKeyURL(profile.Spec.Output),
+		HardCutover:       profile.Spec.Rotation.GraceMode == openukrv1alpha1.GraceModeHard,
 		// Password: "", // TODO: Fetch from SecretRef defined in CRD
 		// Alias: "",    // TODO: Define in CRD or default
 	}
 
+	// Bind generated certificates to the workload identity
+	spiffeID, err := SPIFFEIDForProfile(profile)
+	if err != nil {
+		return fmt.Errorf("failed to build SPIFFE ID: %w", err)
+	}
+	opts.SPIFFEID = spiffeID
+
+	// The jwks document keeps listing the replaced key during its grace period,
+	// unless it is withdrawn at rotation
+	if opts.Format == FormatJWKS && !profile.Spec.Output.Immutable && !opts.HardCutover {
+		if opts.PreviousKey, err = w.previousJWKSKey(ctx, profile, kp.KeyID); err != nil {
+			return err
+		}
+	}
+
 	// If using JKS, we need a password hardcoded or mocked for now until CRD update.
 	// But let's stick to what's possible. If JKS is selected but no password provided, Renderer will error.
 	// We proceed, error propagation handles it.
@@ -84,6 +469,26 @@ func (w *kubeSecretWriter) Write(ctx context.Context, profile *openukrv1alpha1.K
 	if err != nil {
 		return fmt.Errorf("failed to render key material: %w", err)
 	}
+	hash, err := renderOptionsHash(opts)
+	if err != nil {
+		return err
+	}
+	// The key is written after it was published to the current targets
+	publishHash, err := PublishConfigHash(profile.Spec.Publish)
+	if err != nil {
+		return err
+	}
+
+	if profile.Spec.Output.Immutable {
+		return w.writeImmutable(ctx, profile, kp, data, hash, publishHash, wo)
+	}
+
+	// Snapshot the key about to be replaced for rollback
+	if !additional {
+		if err := w.recordHistory(ctx, profile, kp.KeyID); err != nil {
+			return err
+		}
+	}
 
 	// 2. Prepare Secret
 	secret := &corev1.Secret{
@@ -96,6 +501,11 @@ func (w *kubeSecretWriter) Write(ctx context.Context, profile *openukrv1alpha1.K
 	// 3. Create or Update (CreateOrUpdate is not ideal for Secrets due to potential data races, but good for simplicity here)
 	// A better approach for atomicity is strictly ensuring we own it.
 	op, err := controllerutil.CreateOrUpdate(ctx, w.client, secret, func() error {
+		// Only take over a Secret the profile did not create if allowed [SEC:S-1]
+		if err := w.checkOwnership(secret, profile); err != nil {
+			return err
+		}
+
 		// Set OwnerReference [SEC:S-1]
 		if err := ctrl.SetControllerReference(profile, secret, w.scheme); err != nil {
 			return fmt.Errorf("failed to set controller reference: %w", err)
@@ -105,20 +515,68 @@ func (w *kubeSecretWriter) Write(ctx context.Context, profile *openukrv1alpha1.K
 		if secret.Labels == nil {
 			secret.Labels = make(map[string]string)
 		}
-		// Merge user labels
-		for k, v := range profile.Spec.Output.Labels {
+		for k, v := range w.secretLabels(profile) {
 			secret.Labels[k] = v
 		}
-		// Enforce management label
-		secret.Labels["app.kubernetes.io/managed-by"] = "openukr"
-		secret.Labels["openukr.io/key-profile"] = profile.Name
+		if additional {
+			secret.Labels[additionalOutputLabel] = "true"
+		} else {
+			delete(secret.Labels, additionalOutputLabel)
+		}
+
+		// An unchanged key rendered with unchanged options keeps its existing
+		// data byte-for-byte, so re-renders do not churn the Secret
+		sameKey := secret.Annotations["openukr.io/key-id"] == kp.KeyID
+		stable := sameKey && secret.Annotations[renderHashAnnotation] == hash && len(secret.Data) > 0
+
+		// Retain the previous key until the grace period ends. A hard cutover
+		// withdraws its public material right away: only the private material
+		// lingers until it is wiped
+		previous, previousKeyID := retainPrevious(secret, kp.KeyID)
+		if opts.HardCutover {
+			maps.DeleteFunc(previous, func(k string, _ []byte) bool {
+				return isPublicEntry(k, opts.KeyNames)
+			})
+		}
+		previousAlgorithm := secret.Annotations[previousAlgorithmAnnotation]
+		if previousKeyID != "" && previousKeyID == secret.Annotations["openukr.io/key-id"] {
+			// The replaced key may use another algorithm, e.g. during an algorithm migration
+			previousAlgorithm = secret.Annotations["openukr.io/algorithm"]
+		}
+
+		if !stable {
+			// Wrapping the data key calls the KMS, so only data actually written is encrypted
+			if opts.KMSKeyURL != "" {
+				encrypted, err := w.envelopeEncryptPrivate(ctx, profile, data)
+				if err != nil {
+					return err
+				}
+				data = encrypted
+			}
+
+			// Certificates issued for this key survive a layout change
+			if sameKey {
+				for _, k := range []string{CertificateDataKey, CADataKey} {
+					if v, ok := secret.Data[k]; ok {
+						previous[k] = v
+					}
+				}
+			}
 
-		// Set Data
-		secret.Data = data
+			// Set Data
+			secret.Data = data
+			for k, v := range previous {
+				secret.Data[k] = v
+			}
+		}
+		if err := w.checkSize(secret.Data); err != nil {
+			return err
+		}
 		secret.Type = corev1.SecretTypeOpaque // or corev1.SecretTypeTLS if split-pem
 
-		// Optimization: if format is split-pem, we can use SecretTypeTLS
-		if profile.Spec.Output.Format == FormatSplitPEM {
+		// Optimization: if format is split-pem, we can use SecretTypeTLS.
+		// kubernetes.io/tls requires a plaintext tls.key, so not when encrypted.
+		if profile.Spec.Output.Format == FormatSplitPEM && encryptionScheme(profile.Spec.Output) == "" {
 			secret.Type = corev1.SecretTypeTLS
 		}
 
@@ -126,9 +584,33 @@ func (w *kubeSecretWriter) Write(ctx context.Context, profile *openukrv1alpha1.K
 		if secret.Annotations == nil {
 			secret.Annotations = make(map[string]string)
 		}
-		secret.Annotations["openukr.io/last-rotation"] = kp.CreatedAt.Format(time.RFC3339)
+		// last-rotation only moves when the key actually changes
+		if !sameKey || secret.Annotations["openukr.io/last-rotation"] == "" {
+			secret.Annotations["openukr.io/last-rotation"] = wo.lastRotation(kp)
+		}
 		secret.Annotations["openukr.io/key-id"] = kp.KeyID
 		secret.Annotations["openukr.io/algorithm"] = kp.Algorithm
+		secret.Annotations[renderHashAnnotation] = hash
+		secret.Annotations[publishHashAnnotation] = publishHash
+		if opts.Compress {
+			secret.Annotations[compressionAnnotation] = "gzip"
+		} else {
+			delete(secret.Annotations, compressionAnnotation)
+		}
+		if scheme := encryptionScheme(profile.Spec.Output); scheme != "" {
+			secret.Annotations[encryptionAnnotation] = scheme
+		} else {
+			delete(secret.Annotations, encryptionAnnotation)
+		}
+		if previousKeyID != "" {
+			secret.Annotations["openukr.io/previous-key-id"] = previousKeyID
+		}
+		if previousAlgorithm != "" {
+			secret.Annotations[previousAlgorithmAnnotation] = previousAlgorithm
+		}
+		if wo.schedule {
+			setSchedule(secret.Annotations, profile, wo.nextRotation)
+		}
 
 		return nil
 	})
@@ -141,3 +623,262 @@ func (w *kubeSecretWriter) Write(ctx context.Context, profile *openukrv1alpha1.K
 
 	return nil
 }
+
+// jwksEntryName returns the uncompressed Secret data key of the jwks document.
+func jwksEntryName(keyNames map[string]string) string {
+	if n := keyNames["jwks"]; n != "" {
+		return n
+	}
+	return DefaultKeyNames["jwks"]
+}
+
+// jwksDataKey returns the Secret data key of the jwks document of profile.
+func jwksDataKey(profile *openukrv1alpha1.KeyProfile, compressed bool) string {
+	name := jwksEntryName(profile.Spec.Output.KeyNames)
+	if compressed && strings.HasSuffix(name, ".json") {
+		name += gzipSuffix
+	}
+	return name
+}
+
+// readJWKS parses the jwks document stored in secret and returns its keys and
+// data key. The keys are nil if the Secret holds no document, e.g. because it
+// was written in another format.
+func readJWKS(secret *corev1.Secret, profile *openukrv1alpha1.KeyProfile) ([]*crypto.PublicKeyInfo, string, error) {
+	compressed := secret.Annotations[compressionAnnotation] == "gzip"
+	dataKey := jwksDataKey(profile, compressed)
+	data, ok := secret.Data[dataKey]
+	if !ok {
+		return nil, dataKey, nil
+	}
+	if compressed {
+		var err error
+		if data, err = gunzipBytes(data); err != nil {
+			return nil, dataKey, fmt.Errorf("failed to read %s: %w", dataKey, err)
+		}
+	}
+	keys, err := crypto.ParseJWKS(data)
+	if err != nil {
+		return nil, dataKey, fmt.Errorf("failed to read %s: %w", dataKey, err)
+	}
+	return keys, dataKey, nil
+}
+
+// previousJWKSKey returns the key the jwks document lists next to keyID: the
+// key the Secret holds now if keyID replaces it, otherwise the previous key
+// while the document still lists it. Nil if there is none.
+func (w *kubeSecretWriter) previousJWKSKey(
+	ctx context.Context,
+	profile *openukrv1alpha1.KeyProfile,
+	keyID string,
+) (*crypto.PublicKeyInfo, error) {
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Name: profile.Spec.Output.SecretName, Namespace: profile.Namespace}
+	if err := w.client.Get(ctx, key, secret); err != nil {
+		return nil, client.IgnoreNotFound(err)
+	}
+	previousID := secret.Annotations["openukr.io/key-id"]
+	if previousID == keyID {
+		previousID = secret.Annotations["openukr.io/previous-key-id"]
+	}
+	if previousID == "" || previousID == keyID {
+		return nil, nil
+	}
+	keys, _, err := readJWKS(secret, profile)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		if k.KeyID == previousID {
+			return k, nil
+		}
+	}
+	return nil, nil
+}
+
+// pruneJWKS removes every key but the current one from the Secret's jwks
+// document once the grace period ended. Returns true if the data changed.
+func pruneJWKS(secret *corev1.Secret, profile *openukrv1alpha1.KeyProfile) (bool, error) {
+	keys, dataKey, err := readJWKS(secret, profile)
+	if err != nil || len(keys) == 0 {
+		return false, err
+	}
+	currentID := secret.Annotations["openukr.io/key-id"]
+	kept := slices.DeleteFunc(slices.Clone(keys), func(k *crypto.PublicKeyInfo) bool {
+		return k.KeyID != currentID
+	})
+	if len(kept) == len(keys) {
+		return false, nil
+	}
+	set, err := crypto.EncodeJWKS(kept)
+	if err != nil {
+		return false, err
+	}
+	if secret.Annotations[compressionAnnotation] == "gzip" {
+		if set, err = Gzip(set); err != nil {
+			return false, err
+		}
+	}
+	secret.Data[dataKey] = set
+	return true, nil
+}
+
+// checkOwnership decides whether Write may update an existing output Secret:
+// a foreign one (see checkManaged) is taken over, unless
+// WithRefuseForeignSecrets is set.
+func (w *kubeSecretWriter) checkOwnership(secret *corev1.Secret, profile *openukrv1alpha1.KeyProfile) error {
+	if !w.refuseForeign {
+		return nil
+	}
+	return checkManaged(secret, profile)
+}
+
+// checkManaged fails with ErrForeignSecret for an existing Secret the profile
+// did not write. Secrets carrying the management labels for the profile are
+// ours. Secrets written by earlier releases lack those labels but are owned by
+// the profile; they are adopted and relabeled by Write.
+func checkManaged(secret *corev1.Secret, profile *openukrv1alpha1.KeyProfile) error {
+	if secret.ResourceVersion == "" {
+		return nil // Not created yet
+	}
+	if secret.Labels["app.kubernetes.io/managed-by"] == "openukr" &&
+		secret.Labels["openukr.io/key-profile"] == profile.Name {
+		return nil
+	}
+	for _, ref := range secret.OwnerReferences {
+		if ref.UID == profile.UID {
+			return nil // Legacy Secret: adopt
+		}
+	}
+	return fmt.Errorf("%w: %s/%s", ErrForeignSecret, secret.Namespace, secret.Name)
+}
+
+// secretLabels returns the user labels plus the enforced management labels.
+func (w *kubeSecretWriter) secretLabels(profile *openukrv1alpha1.KeyProfile) map[string]string {
+	labels := make(map[string]string, len(profile.Spec.Output.Labels)+2)
+	// Merge user labels
+	for k, v := range profile.Spec.Output.Labels {
+		labels[k] = v
+	}
+	// Enforce management label
+	labels["app.kubernetes.io/managed-by"] = "openukr"
+	labels["openukr.io/key-profile"] = profile.Name
+	return labels
+}
+
+func (w *kubeSecretWriter) DropPrevious(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error {
+	if profile == nil {
+		return fmt.Errorf("profile cannot be nil")
+	}
+	for i, p := range outputProfiles(profile) {
+		if err := w.dropPreviousOutput(ctx, p); err != nil {
+			if i == 0 {
+				return err
+			}
+			return fmt.Errorf("additional output %s: %w", p.Spec.Output.SecretName, err)
+		}
+	}
+	return nil
+}
+
+// dropPreviousOutput removes the previous private material from the Secret of
+// profile.Spec.Output, and the previous key from its jwks document.
+func (w *kubeSecretWriter) dropPreviousOutput(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error {
+	if profile.Spec.Output.Immutable {
+		return w.dropPreviousImmutable(ctx, profile)
+	}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Name: profile.Spec.Output.SecretName, Namespace: profile.Namespace}
+	if err := w.client.Get(ctx, key, secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	changed := false
+	for k := range secret.Data {
+		if isPreviousDataKey(k) && !isPublicEntry(k, profile.Spec.Output.KeyNames) {
+			// [SEC:I-2] Zero the in-memory copy before dropping it
+			for i := range secret.Data[k] {
+				secret.Data[k][i] = 0
+			}
+			delete(secret.Data, k)
+			changed = true
+		}
+	}
+	// Without its private material the Secret no longer holds a previous key
+	for _, a := range []string{"openukr.io/previous-key-id", previousAlgorithmAnnotation} {
+		if _, ok := secret.Annotations[a]; ok {
+			delete(secret.Annotations, a)
+			changed = true
+		}
+	}
+	if profile.Spec.Output.Format == FormatJWKS {
+		pruned, err := pruneJWKS(secret, profile)
+		if err != nil {
+			return err
+		}
+		changed = changed || pruned
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := w.client.Update(ctx, secret); err != nil {
+		return fmt.Errorf("failed to drop previous key material: %w", err)
+	}
+	return nil
+}
+
+// SetCertificate stores a signed certificate (and optional CA bundle) for keyID in secret.
+// It refuses to attach the certificate if the Secret no longer holds keyID.
+// Returns true if the Secret data changed.
+func SetCertificate(secret *corev1.Secret, keyID string, cert, ca []byte) (bool, error) {
+	if current := secret.Annotations["openukr.io/key-id"]; current != keyID {
+		return false, fmt.Errorf("secret holds key %q, not %q", current, keyID)
+	}
+	if secret.Data == nil {
+		secret.Data = make(map[string][]byte)
+	}
+
+	changed := !bytes.Equal(secret.Data[CertificateDataKey], cert)
+	secret.Data[CertificateDataKey] = cert
+	if len(ca) > 0 {
+		changed = changed || !bytes.Equal(secret.Data[CADataKey], ca)
+		secret.Data[CADataKey] = ca
+	}
+	return changed, nil
+}
+
+func (w *kubeSecretWriter) Verify(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error {
+	return VerifyProfile(ctx, w.client, profile)
+}
+
+// VerifyProfile checks the key material in the profile's current Secret with
+// VerifySecret, against Status.CurrentKeyFingerprint unless the profile keeps
+// fingerprints out of its status. A missing Secret is not an error.
+// Violations wrap ErrIntegrity. [SEC:T-1]
+func VerifyProfile(ctx context.Context, reader client.Reader, profile *openukrv1alpha1.KeyProfile) error {
+	if profile == nil {
+		return fmt.Errorf("profile cannot be nil")
+	}
+
+	current, _, err := SecretNames(ctx, reader, profile)
+	if err != nil || current == "" {
+		return client.IgnoreNotFound(err)
+	}
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Name: current, Namespace: profile.Namespace}
+	if err := reader.Get(ctx, key, secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	// Without a recorded fingerprint only the key pair's consistency is checked
+	var fingerprint crypto.Fingerprint
+	if !profile.Spec.DisableFingerprintStatus && secret.Annotations["openukr.io/key-id"] == profile.Status.CurrentKeyID {
+		// A malformed recorded fingerprint cannot vouch for the Secret
+		if fingerprint, err = crypto.ParseFingerprint(profile.Status.CurrentKeyFingerprint); err != nil {
+			return fmt.Errorf("%w: recorded fingerprint: %w", ErrIntegrity, err)
+		}
+	}
+	return VerifySecret(secret, fingerprint)
+}
@@ -0,0 +1,755 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package output
+
+import (
+	"bytes"
+	"context"
+	gocrypto "crypto"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	if err := openukrv1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	return scheme
+}
+
+func newTestWriter(t *testing.T) (client.Client, SecretWriter) {
+	t.Helper()
+	scheme := newTestScheme(t)
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	return c, NewSecretWriter(c, scheme, NewRenderer())
+}
+
+func TestWriteIsStableForUnchangedKey(t *testing.T) {
+	t.Parallel()
+
+	c, w := newTestWriter(t)
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Output: openukrv1alpha1.OutputConfig{SecretName: "keys", Format: FormatSplitPEM},
+		},
+	}
+	kp := generateTestKey(t)
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "keys", Namespace: "default"}
+
+	if err := w.Write(ctx, profile, kp); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var first corev1.Secret
+	if err := c.Get(ctx, key, &first); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	// Re-render the same key later: nothing may change
+	kp.CreatedAt = kp.CreatedAt.Add(time.Hour)
+	if err := w.Write(ctx, profile, kp); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var second corev1.Secret
+	if err := c.Get(ctx, key, &second); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if second.ResourceVersion != first.ResourceVersion {
+		t.Errorf("Secret updated on no-op re-render: resourceVersion %s → %s",
+			first.ResourceVersion, second.ResourceVersion)
+	}
+
+	// A layout change re-renders data but keeps the rotation timestamp
+	profile.Spec.Output.Format = FormatSinglePEM
+	if err := w.Write(ctx, profile, kp); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var third corev1.Secret
+	if err := c.Get(ctx, key, &third); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, ok := third.Data["keypair.pem"]; !ok {
+		t.Errorf("expected re-rendered single-pem data, got keys %v", third.Data)
+	}
+	if got, want := third.Annotations["openukr.io/last-rotation"], first.Annotations["openukr.io/last-rotation"]; got != want {
+		t.Errorf("last-rotation changed without rotation: %s → %s", want, got)
+	}
+}
+
+func TestWriteJWKSKeepsPreviousKeyDuringGrace(t *testing.T) {
+	t.Parallel()
+
+	for _, compress := range []bool{false, true} {
+		c, w := newTestWriter(t)
+		profile := &openukrv1alpha1.KeyProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+			Spec: openukrv1alpha1.KeyProfileSpec{
+				Output: openukrv1alpha1.OutputConfig{SecretName: "keys", Format: FormatJWKS, Compress: compress},
+			},
+		}
+		ctx := context.Background()
+		kids := func() []string {
+			t.Helper()
+			var secret corev1.Secret
+			if err := c.Get(ctx, types.NamespacedName{Name: "keys", Namespace: "default"}, &secret); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			keys, _, err := readJWKS(&secret, profile)
+			if err != nil {
+				t.Fatalf("readJWKS() error = %v", err)
+			}
+			var ids []string
+			for _, k := range keys {
+				ids = append(ids, k.KeyID)
+			}
+			return slices.Sorted(slices.Values(ids))
+		}
+
+		old, current := generateTestKey(t), generateTestKey(t)
+		if err := w.Write(ctx, profile, old); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if err := w.Write(ctx, profile, current); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		want := slices.Sorted(slices.Values([]string{old.KeyID, current.KeyID}))
+		if got := kids(); !slices.Equal(got, want) {
+			t.Errorf("compress=%v: kids during grace = %v, want %v", compress, got, want)
+		}
+
+		// A re-render within the grace period keeps both keys
+		profile.Spec.KeySpec.Use = "sig"
+		if err := w.Write(ctx, profile, current); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if got := kids(); !slices.Equal(got, want) {
+			t.Errorf("compress=%v: kids after re-render = %v, want %v", compress, got, want)
+		}
+
+		if err := w.DropPrevious(ctx, profile); err != nil {
+			t.Fatalf("DropPrevious() error = %v", err)
+		}
+		if got := kids(); !slices.Equal(got, []string{current.KeyID}) {
+			t.Errorf("compress=%v: kids after grace = %v, want only %s", compress, got, current.KeyID)
+		}
+		// The previous document is public and survives the grace period
+		var secret corev1.Secret
+		if err := c.Get(ctx, types.NamespacedName{Name: "keys", Namespace: "default"}, &secret); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if prev := previousDataKey(jwksDataKey(profile, compress)); len(secret.Data[prev]) == 0 {
+			t.Errorf("compress=%v: %s dropped after grace, got keys %v", compress, prev, slices.Collect(maps.Keys(secret.Data)))
+		}
+	}
+}
+
+func TestWriteHardCutoverWithdrawsPreviousPublicKey(t *testing.T) {
+	t.Parallel()
+
+	c, w := newTestWriter(t)
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Rotation: openukrv1alpha1.RotationPolicy{GraceMode: openukrv1alpha1.GraceModeHard},
+			Output:   openukrv1alpha1.OutputConfig{SecretName: "keys", Format: FormatJWKS},
+		},
+	}
+	ctx := context.Background()
+
+	old, current := generateTestKey(t), generateTestKey(t)
+	for _, kp := range []*crypto.KeyPair{old, current} {
+		if err := w.Write(ctx, profile, kp); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Name: "keys", Namespace: "default"}, &secret); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	keys, _, err := readJWKS(&secret, profile)
+	if err != nil {
+		t.Fatalf("readJWKS() error = %v", err)
+	}
+	if len(keys) != 1 || keys[0].KeyID != current.KeyID {
+		t.Errorf("jwks.json lists %d keys, want only %s", len(keys), current.KeyID)
+	}
+	if _, ok := secret.Data["jwks-previous.json"]; ok {
+		t.Error("previous public key kept after a hard cutover")
+	}
+	// The private material lingers until the grace period ends
+	if _, ok := secret.Data["private-jwks-previous.json"]; !ok {
+		t.Error("previous private key dropped before the grace period ended")
+	}
+}
+
+func TestWriteImmutable(t *testing.T) {
+	t.Parallel()
+
+	c, w := newTestWriter(t)
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Output: openukrv1alpha1.OutputConfig{SecretName: "keys", Format: FormatSplitPEM, Immutable: true},
+		},
+	}
+	ctx := context.Background()
+	pointerKey := types.NamespacedName{Name: "keys", Namespace: "default"}
+
+	first, second := generateTestKey(t), generateTestKey(t)
+	firstName := ImmutableSecretName("keys", first.KeyID)
+	secondName := ImmutableSecretName("keys", second.KeyID)
+
+	if err := w.Write(ctx, profile, first); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	// Retrying the same key is a no-op
+	if err := w.Write(ctx, profile, first); err != nil {
+		t.Fatalf("Write() retry error = %v", err)
+	}
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Name: firstName, Namespace: "default"}, &secret); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if secret.Immutable == nil || !*secret.Immutable {
+		t.Error("Secret is not immutable")
+	}
+	if secret.Annotations["openukr.io/key-id"] != first.KeyID {
+		t.Errorf("key-id annotation = %q, want %q", secret.Annotations["openukr.io/key-id"], first.KeyID)
+	}
+
+	// A re-render cannot update the immutable Secret: it is replaced
+	profile.Spec.Output.Format = FormatSinglePEM
+	if err := w.Write(ctx, profile, first); err != nil {
+		t.Fatalf("Write() re-render error = %v", err)
+	}
+	var rerendered corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Name: firstName, Namespace: "default"}, &rerendered); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if rerendered.Data["keypair.pem"] == nil || rerendered.Data["tls.key"] != nil {
+		t.Errorf("re-rendered Secret holds %v, want the single-pem layout", slices.Sorted(maps.Keys(rerendered.Data)))
+	}
+	profile.Spec.Output.Format = FormatSplitPEM
+
+	// Rotation creates a new Secret and moves the pointer
+	if err := w.Write(ctx, profile, second); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var pointer corev1.ConfigMap
+	if err := c.Get(ctx, pointerKey, &pointer); err != nil {
+		t.Fatalf("Get() pointer error = %v", err)
+	}
+	if pointer.Data[PointerCurrentKey] != secondName || pointer.Data[PointerPreviousKey] != firstName {
+		t.Errorf("pointer = %v, want current %s and previous %s", pointer.Data, secondName, firstName)
+	}
+
+	profile.Status.CurrentKeyID = second.KeyID
+	if err := w.Verify(ctx, profile); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+
+	// Grace period over: the superseded Secret is deleted
+	if err := w.DropPrevious(ctx, profile); err != nil {
+		t.Fatalf("DropPrevious() error = %v", err)
+	}
+	err := c.Get(ctx, types.NamespacedName{Name: firstName, Namespace: "default"}, &secret)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("previous Secret still exists (err = %v)", err)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: secondName, Namespace: "default"}, &secret); err != nil {
+		t.Errorf("current Secret deleted: %v", err)
+	}
+	if err := c.Get(ctx, pointerKey, &pointer); err != nil {
+		t.Fatalf("Get() pointer error = %v", err)
+	}
+	if _, ok := pointer.Data[PointerPreviousKey]; ok {
+		t.Errorf("pointer still names previous Secret: %v", pointer.Data)
+	}
+}
+
+func TestWriteAdoptsLegacySecret(t *testing.T) {
+	t.Parallel()
+
+	scheme := newTestScheme(t)
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Output: openukrv1alpha1.OutputConfig{SecretName: "keys", Format: FormatSplitPEM},
+		},
+	}
+	// Written by a pre-1.0 release: owned by the profile, old label scheme
+	legacy := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "keys",
+			Namespace: "default",
+			Labels:    map[string]string{"openukr.io/managed": "true"},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: openukrv1alpha1.GroupVersion.String(),
+				Kind:       "KeyProfile",
+				Name:       "profile",
+				UID:        "profile-uid",
+			}},
+		},
+		Data: map[string][]byte{"tls.key": []byte("legacy")},
+	}
+	foreign := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("hunter2")},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(legacy, foreign).Build()
+	w := NewSecretWriter(c, scheme, NewRenderer())
+	ctx := context.Background()
+
+	if err := w.Write(ctx, profile, generateTestKey(t)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var got corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Name: "keys", Namespace: "default"}, &got); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Labels["app.kubernetes.io/managed-by"] != "openukr" || got.Labels["openukr.io/key-profile"] != "profile" {
+		t.Errorf("adopted Secret labels = %v, want management labels", got.Labels)
+	}
+	if string(got.Data["tls.key"]) == "legacy" {
+		t.Error("adopted Secret still holds legacy key material")
+	}
+
+	// Same name but neither labeled nor owned: refused only when asked to
+	profile.Spec.Output.SecretName = "other"
+	strict := NewSecretWriter(c, scheme, NewRenderer(), WithRefuseForeignSecrets(true))
+	if err := strict.Write(ctx, profile, generateTestKey(t)); !errors.Is(err, ErrForeignSecret) {
+		t.Errorf("Write() over foreign Secret error = %v, want ErrForeignSecret", err)
+	}
+	if err := w.Write(ctx, profile, generateTestKey(t)); err != nil {
+		t.Errorf("Write() taking over foreign Secret error = %v", err)
+	}
+}
+
+// staticRenderer renders fixed data regardless of the key.
+type staticRenderer map[string][]byte
+
+func (r staticRenderer) Render(*crypto.KeyPair, RenderOptions) (map[string][]byte, error) {
+	data := make(map[string][]byte, len(r))
+	for k, v := range r {
+		data[k] = v
+	}
+	return data, nil
+}
+
+func TestWriteRejectsOversizedSecret(t *testing.T) {
+	t.Parallel()
+
+	scheme := newTestScheme(t)
+	// Synthetic JWKS with a long key history
+	renderer := staticRenderer{"jwks.json": bytes.Repeat([]byte("k"), 4096)}
+
+	for _, immutable := range []bool{false, true} {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		w := NewSecretWriter(c, scheme, renderer, WithMaxSecretSize(1024))
+		profile := &openukrv1alpha1.KeyProfile{
+			ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+			Spec: openukrv1alpha1.KeyProfileSpec{
+				Output: openukrv1alpha1.OutputConfig{SecretName: "keys", Immutable: immutable},
+			},
+		}
+
+		err := w.Write(context.Background(), profile, generateTestKey(t))
+		if !errors.Is(err, ErrSecretTooLarge) {
+			t.Fatalf("Write(immutable=%v) error = %v, want ErrSecretTooLarge", immutable, err)
+		}
+		if !strings.Contains(err.Error(), "reduce the number of retained keys") {
+			t.Errorf("error %q does not suggest reducing key history", err)
+		}
+		var secrets corev1.SecretList
+		if err := c.List(context.Background(), &secrets); err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		if len(secrets.Items) != 0 {
+			t.Errorf("Write(immutable=%v) created %d Secrets, want none", immutable, len(secrets.Items))
+		}
+	}
+
+	// The same data fits without a limit
+	c := fake.NewClientBuilder().WithScheme(scheme).Build()
+	w := NewSecretWriter(c, scheme, renderer, WithMaxSecretSize(0))
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec:       openukrv1alpha1.KeyProfileSpec{Output: openukrv1alpha1.OutputConfig{SecretName: "keys"}},
+	}
+	if err := w.Write(context.Background(), profile, generateTestKey(t)); err != nil {
+		t.Errorf("Write() without limit error = %v", err)
+	}
+}
+
+func TestMarkSchedule(t *testing.T) {
+	t.Parallel()
+
+	c, w := newTestWriter(t)
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Rotation: openukrv1alpha1.RotationPolicy{Interval: metav1.Duration{Duration: 24 * time.Hour}},
+			Output:   openukrv1alpha1.OutputConfig{SecretName: "keys", Format: FormatSplitPEM},
+		},
+	}
+	ctx := context.Background()
+
+	// No Secret yet: nothing to annotate
+	next := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	if err := w.MarkSchedule(ctx, profile, next); err != nil {
+		t.Fatalf("MarkSchedule() without Secret error = %v", err)
+	}
+
+	if err := w.Write(ctx, profile, generateTestKey(t)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	get := func() corev1.Secret {
+		t.Helper()
+		var secret corev1.Secret
+		if err := c.Get(ctx, types.NamespacedName{Name: "keys", Namespace: "default"}, &secret); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		return secret
+	}
+
+	if err := w.MarkSchedule(ctx, profile, next); err != nil {
+		t.Fatalf("MarkSchedule() error = %v", err)
+	}
+	secret := get()
+	if got := secret.Annotations[nextRotationAnnotation]; got != "2026-03-02T10:00:00Z" {
+		t.Errorf("next-rotation = %q, want 2026-03-02T10:00:00Z", got)
+	}
+	if got := secret.Annotations[rotationIntervalAnnotation]; got != "24h0m0s" {
+		t.Errorf("rotation-interval = %q, want 24h0m0s", got)
+	}
+
+	// An unchanged schedule does not touch the Secret
+	version := secret.ResourceVersion
+	if err := w.MarkSchedule(ctx, profile, next); err != nil {
+		t.Fatalf("MarkSchedule() error = %v", err)
+	}
+	if got := get().ResourceVersion; got != version {
+		t.Errorf("ResourceVersion = %s, want unchanged %s", got, version)
+	}
+
+	// A schedule change moves both annotations
+	profile.Spec.Rotation.Interval.Duration = 12 * time.Hour
+	if err := w.MarkSchedule(ctx, profile, next.Add(-12*time.Hour)); err != nil {
+		t.Fatalf("MarkSchedule() error = %v", err)
+	}
+	secret = get()
+	if got := secret.Annotations[nextRotationAnnotation]; got != "2026-03-01T22:00:00Z" {
+		t.Errorf("next-rotation = %q, want 2026-03-01T22:00:00Z", got)
+	}
+	if got := secret.Annotations[rotationIntervalAnnotation]; got != "12h0m0s" {
+		t.Errorf("rotation-interval = %q, want 12h0m0s", got)
+	}
+
+	// No scheduled rotation clears next-rotation
+	if err := w.MarkSchedule(ctx, profile, time.Time{}); err != nil {
+		t.Fatalf("MarkSchedule() error = %v", err)
+	}
+	if _, ok := get().Annotations[nextRotationAnnotation]; ok {
+		t.Error("next-rotation annotation kept, want it removed for a zero next rotation")
+	}
+}
+
+func TestWriteRecordsSchedule(t *testing.T) {
+	t.Parallel()
+
+	c, w := newTestWriter(t)
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Rotation: openukrv1alpha1.RotationPolicy{Interval: metav1.Duration{Duration: 24 * time.Hour}},
+			Output:   openukrv1alpha1.OutputConfig{SecretName: "keys", Format: FormatSplitPEM},
+			AdditionalOutputs: []openukrv1alpha1.OutputConfig{
+				{SecretName: "keys-jwks", Format: FormatJWKS},
+			},
+		},
+	}
+	ctx := context.Background()
+	next := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	for _, next := range []time.Time{next, next.Add(24 * time.Hour)} {
+		if err := w.Write(ctx, profile, generateTestKey(t), WithNextRotation(next)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		var secret corev1.Secret
+		if err := c.Get(ctx, types.NamespacedName{Name: "keys", Namespace: "default"}, &secret); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		if got, want := secret.Annotations[nextRotationAnnotation], next.Format(time.RFC3339); got != want {
+			t.Errorf("next-rotation = %q, want %s", got, want)
+		}
+		if got := secret.Annotations[rotationIntervalAnnotation]; got != "24h0m0s" {
+			t.Errorf("rotation-interval = %q, want 24h0m0s", got)
+		}
+	}
+
+	// Like MarkSchedule, only the current Secret carries the schedule
+	var additional corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Name: "keys-jwks", Namespace: "default"}, &additional); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if _, ok := additional.Annotations[nextRotationAnnotation]; ok {
+		t.Error("additional output carries the next-rotation annotation")
+	}
+}
+
+func TestWriteRecordsRotationTime(t *testing.T) {
+	t.Parallel()
+
+	c, w := newTestWriter(t)
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Output: openukrv1alpha1.OutputConfig{SecretName: "keys", Format: FormatSplitPEM},
+		},
+	}
+	ctx := context.Background()
+
+	// A key staged ahead of its promotion was created before it is rotated in
+	kp := generateTestKey(t)
+	kp.CreatedAt = time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	rotatedAt := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	if err := w.Write(ctx, profile, kp, WithRotationTime(rotatedAt)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	var secret corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Name: "keys", Namespace: "default"}, &secret); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := secret.Annotations["openukr.io/last-rotation"]; got != "2026-03-02T10:00:00Z" {
+		t.Errorf("last-rotation = %q, want the rotation time 2026-03-02T10:00:00Z", got)
+	}
+}
+
+func TestWriteKeepsHistorySecrets(t *testing.T) {
+	t.Parallel()
+
+	c, w := newTestWriter(t)
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Output: openukrv1alpha1.OutputConfig{SecretName: "keys", Format: FormatSplitPEM, KeepHistorySecrets: 2},
+		},
+	}
+	ctx := context.Background()
+
+	var keys []*crypto.KeyPair
+	for i := range 4 {
+		kp := generateTestKey(t)
+		kp.KeyID = fmt.Sprintf("key-%d", i)
+		keys = append(keys, kp)
+		if err := w.Write(ctx, profile, kp); err != nil {
+			t.Fatalf("Write(%s) error = %v", kp.KeyID, err)
+		}
+		// Writing the same key again takes no snapshot
+		if err := w.Write(ctx, profile, kp); err != nil {
+			t.Fatalf("repeated Write(%s) error = %v", kp.KeyID, err)
+		}
+	}
+
+	var history corev1.SecretList
+	if err := c.List(ctx, &history, client.MatchingLabels{historyLabel: "true"}); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var names []string
+	for _, s := range history.Items {
+		names = append(names, s.Name)
+	}
+	sort.Strings(names)
+	want := []string{HistorySecretName("keys", "key-1"), HistorySecretName("keys", "key-2")}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("history secrets = %v, want %v", names, want)
+	}
+
+	// A history Secret holds its key's material for rollback, without previous entries
+	var snapshot corev1.Secret
+	if err := c.Get(ctx, types.NamespacedName{Name: want[1], Namespace: "default"}, &snapshot); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	info, err := InspectSecret(&snapshot)
+	if err != nil {
+		t.Fatalf("InspectSecret() error = %v", err)
+	}
+	if !info.PublicKey.(interface{ Equal(gocrypto.PublicKey) bool }).Equal(keys[2].PublicKey) {
+		t.Error("history secret does not hold key-2's public key")
+	}
+	if _, ok := snapshot.Data["tls.key"]; !ok {
+		t.Error("history secret lacks the private key")
+	}
+	for k := range snapshot.Data {
+		if isPreviousDataKey(k) {
+			t.Errorf("history secret holds previous entry %s", k)
+		}
+	}
+	if snapshot.Immutable == nil || !*snapshot.Immutable {
+		t.Error("history secret is not immutable")
+	}
+	if len(snapshot.OwnerReferences) != 1 || snapshot.OwnerReferences[0].UID != profile.UID {
+		t.Errorf("OwnerReferences = %v, want the profile", snapshot.OwnerReferences)
+	}
+}
+
+func TestWriteAdditionalOutputs(t *testing.T) {
+	t.Parallel()
+
+	c, w := newTestWriter(t)
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Output: openukrv1alpha1.OutputConfig{SecretName: "keys-tls", Format: FormatSplitPEM},
+			AdditionalOutputs: []openukrv1alpha1.OutputConfig{
+				{SecretName: "keys-app", Format: FormatSinglePEM},
+			},
+		},
+	}
+	ctx := context.Background()
+	first, second := generateTestKey(t), generateTestKey(t)
+	if err := w.Write(ctx, profile, first); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Write(ctx, profile, second); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	get := func(name string) *corev1.Secret {
+		t.Helper()
+		var secret corev1.Secret
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, &secret); err != nil {
+			t.Fatalf("Get(%s) error = %v", name, err)
+		}
+		return &secret
+	}
+	for name, want := range map[string]struct {
+		secretType corev1.SecretType
+		dataKey    string
+	}{
+		"keys-tls": {corev1.SecretTypeTLS, "tls.key"},
+		"keys-app": {corev1.SecretTypeOpaque, "keypair.pem"},
+	} {
+		secret := get(name)
+		if secret.Type != want.secretType {
+			t.Errorf("%s: type = %s, want %s", name, secret.Type, want.secretType)
+		}
+		if _, ok := secret.Data[want.dataKey]; !ok {
+			t.Errorf("%s: %s missing, got keys %v", name, want.dataKey, secret.Data)
+		}
+		if secret.Annotations["openukr.io/key-id"] != second.KeyID ||
+			secret.Annotations["openukr.io/previous-key-id"] != first.KeyID {
+			t.Errorf("%s: annotations = %v, want key %s replacing %s", name, secret.Annotations, second.KeyID, first.KeyID)
+		}
+		if len(secret.OwnerReferences) != 1 || secret.OwnerReferences[0].UID != profile.UID {
+			t.Errorf("%s: owner references = %v, want the profile", name, secret.OwnerReferences)
+		}
+	}
+
+	// The grace period ends for every output
+	if err := w.DropPrevious(ctx, profile); err != nil {
+		t.Fatalf("DropPrevious() error = %v", err)
+	}
+	for name, key := range map[string]string{"keys-tls": "tls-previous.key", "keys-app": "keypair-previous.pem"} {
+		secret := get(name)
+		if _, ok := secret.Data[key]; ok {
+			t.Errorf("%s: %s kept after DropPrevious", name, key)
+		}
+		if id, ok := secret.Annotations["openukr.io/previous-key-id"]; ok {
+			t.Errorf("%s: previous-key-id %s kept after DropPrevious", name, id)
+		}
+	}
+}
+
+func TestWriteAdditionalOutputsKeepHistory(t *testing.T) {
+	t.Parallel()
+
+	c, w := newTestWriter(t)
+
+	profile := &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default", UID: "profile-uid"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			Output: openukrv1alpha1.OutputConfig{SecretName: "keys", Format: FormatSplitPEM, KeepHistorySecrets: 2},
+			AdditionalOutputs: []openukrv1alpha1.OutputConfig{
+				{SecretName: "keys-app", Format: FormatSinglePEM},
+			},
+		},
+	}
+	ctx := context.Background()
+	for range 3 {
+		if err := w.Write(ctx, profile, generateTestKey(t)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	// Writing the additional output must not prune the main output's history
+	var history corev1.SecretList
+	if err := c.List(ctx, &history, client.MatchingLabels{historyLabel: "true"}); err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(history.Items) != 2 {
+		t.Fatalf("got %d history secrets, want 2", len(history.Items))
+	}
+	for _, secret := range history.Items {
+		if !strings.HasPrefix(secret.Name, "keys-history-") {
+			t.Errorf("history secret %s, want only snapshots of keys", secret.Name)
+		}
+	}
+
+	// Removing the entry deletes its Secret
+	profile.Spec.AdditionalOutputs = nil
+	if err := w.Write(ctx, profile, generateTestKey(t)); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	err := c.Get(ctx, types.NamespacedName{Name: "keys-app", Namespace: "default"}, &corev1.Secret{})
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("Get(keys-app) error = %v, want NotFound", err)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Name: "keys", Namespace: "default"}, &corev1.Secret{}); err != nil {
+		t.Errorf("Get(keys) error = %v", err)
+	}
+}
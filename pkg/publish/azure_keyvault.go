@@ -0,0 +1,264 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/validation"
+)
+
+// keyVaultScope returns the OAuth scope of the Key Vault data plane of the
+// cloud with the given DNS suffix, e.g. https://vault.azure.net/.default.
+func keyVaultScope(suffix string) string {
+	return "https://" + suffix + "/.default"
+}
+
+// azureKeyVaultAPIVersion is the Key Vault REST API version used.
+const azureKeyVaultAPIVersion = "7.4"
+
+// keyVaultSecretName matches valid Key Vault secret names.
+var keyVaultSecretName = regexp.MustCompile(`^[0-9a-zA-Z-]{1,127}$`)
+
+// textEncodings are stored in Key Vault as-is; all other encodings are base64-encoded.
+var textEncodings = map[string]bool{
+	"PEM": true,
+	"JWK": true,
+}
+
+// AzureKeyVaultPublisher stores public keys as Azure Key Vault secrets.
+type AzureKeyVaultPublisher struct {
+	// client sends Key Vault requests, restricted to hosts
+	client      *http.Client
+	hosts       *validation.HostPolicy
+	tokens      *tokenCache
+	credentials func(ctx context.Context, namespace string, config map[string]string) (CredentialProvider, error)
+}
+
+// NewAzureKeyVaultPublisher creates a new Azure Key Vault publisher denying
+// validation.DefaultDeniedPublishHosts.
+// Client secrets referenced by targets are read via k8sClient.
+func NewAzureKeyVaultPublisher(k8sClient client.Client) *AzureKeyVaultPublisher {
+	return newAzureKeyVaultPublisher(k8sClient, validation.DefaultHostPolicy())
+}
+
+// newAzureKeyVaultPublisher creates an Azure Key Vault publisher that only
+// connects to vaults permitted by hosts. A nil policy allows every vault.
+// Token requests go to the fixed Microsoft Entra ID authority and are not
+// subject to hosts.
+func newAzureKeyVaultPublisher(k8sClient client.Client, hosts *validation.HostPolicy) *AzureKeyVaultPublisher {
+	p := &AzureKeyVaultPublisher{
+		client: &http.Client{
+			Transport: hostGuardedTransport(hosts, nil),
+			Timeout:   10 * time.Second,
+		},
+		hosts:  hosts,
+		tokens: newTokenCache(clock.RealClock{}),
+	}
+	authClient := &http.Client{Timeout: 10 * time.Second}
+	p.credentials = func(ctx context.Context, namespace string, config map[string]string) (CredentialProvider, error) {
+		return newAzureCredential(ctx, k8sClient, authClient, p.tokens, namespace, config)
+	}
+	return p
+}
+
+// Publish stores the public key as a Key Vault secret, once per output.
+// Config required: "vaultURL" (https://<vault>.vault.azure.net, or a sovereign
+// cloud vault, see validation.KeyVaultDNSSuffixes) and "secretName".
+// Authentication uses workload identity unless "clientSecretRef" names a Secret
+// holding a client secret (see newAzureCredential).
+func (p *AzureKeyVaultPublisher) Publish(
+	ctx context.Context,
+	owner Owner,
+	target openukrv1alpha1.PublishTarget,
+	pub *crypto.PublicKeyInfo,
+) error {
+	fingerprint, err := crypto.ComputeFingerprint(pub.PublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to compute fingerprint: %w", err)
+	}
+
+	outputs := resolveOutputs(target)
+	if err := checkKeyVaultSecretNames(outputs); err != nil {
+		return err
+	}
+	var errs []error
+	for i, out := range outputs {
+		if err := p.publishOutput(ctx, owner.Namespace, out, pub, fingerprint.String()); err != nil {
+			errs = append(errs, fmt.Errorf("output[%d] (%s): %w", i, out.encoding, err))
+		}
+	}
+	return joinOutputErrors(errs)
+}
+
+// checkKeyVaultSecretNames fails if two outputs write the same Key Vault
+// secret, which would overwrite each other on every publish. Names are
+// case-insensitive in Key Vault.
+func checkKeyVaultSecretNames(outputs []resolvedOutput) error {
+	seen := make(map[string]int, len(outputs))
+	for i, out := range outputs {
+		key := strings.ToLower(strings.TrimSuffix(out.config["vaultURL"], "/") + "/" + out.config["secretName"])
+		if j, ok := seen[key]; ok {
+			return fmt.Errorf("output[%d] and output[%d] both write secret %q", j, i, out.config["secretName"])
+		}
+		seen[key] = i
+	}
+	return nil
+}
+
+// keyVaultSecret is the subset of the Key Vault secret bundle used here.
+type keyVaultSecret struct {
+	Value       string            `json:"value"`
+	ContentType string            `json:"contentType,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+func (p *AzureKeyVaultPublisher) publishOutput(
+	ctx context.Context,
+	namespace string,
+	out resolvedOutput,
+	pub *crypto.PublicKeyInfo,
+	fingerprint string,
+) error {
+	// [SEC:S-3] Tokens for the Key Vault scope are only ever sent to Key Vault
+	u, suffix, err := validation.ValidateKeyVaultURL(out.config["vaultURL"])
+	if err != nil {
+		return fmt.Errorf("'vaultURL': %w", err)
+	}
+	if (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		return fmt.Errorf("'vaultURL' must not have a path or query (got %q)", out.config["vaultURL"])
+	}
+	if err := p.hosts.CheckHost(u.Hostname()); err != nil {
+		return err
+	}
+	vaultURL := "https://" + u.Host
+	name := out.config["secretName"]
+	if !keyVaultSecretName.MatchString(name) {
+		return fmt.Errorf("invalid 'secretName' %q: must be 1-127 alphanumerics or dashes", name)
+	}
+	if out.compress {
+		return fmt.Errorf("compression is not supported by azurekeyvault targets")
+	}
+
+	contentType, ok := contentTypes[out.encoding]
+	if !ok {
+		return fmt.Errorf("unsupported encoding: %s", out.encoding)
+	}
+	data, err := encodePublic(pub, out.encoding)
+	if err != nil {
+		return err
+	}
+	desired := keyVaultSecret{
+		Value:       string(data),
+		ContentType: contentType,
+		Tags:        map[string]string{"key-id": pub.KeyID, "fingerprint": fingerprint},
+	}
+	if !textEncodings[out.encoding] {
+		desired.Value = base64.StdEncoding.EncodeToString(data)
+		desired.ContentType += ";base64"
+	}
+
+	cred, err := p.credentials(ctx, namespace, out.config)
+	if err != nil {
+		return err
+	}
+	token, err := cred.Token(ctx, keyVaultScope(suffix))
+	if err != nil {
+		return fmt.Errorf("failed to authenticate to Key Vault: %w", err)
+	}
+
+	secretURL := vaultURL + "/secrets/" + url.PathEscape(name) + "?api-version=" + azureKeyVaultAPIVersion
+
+	// Idempotency: every write creates a new secret version, so skip unchanged values
+	var current keyVaultSecret
+	found, err := keyVaultDo(ctx, p.client, http.MethodGet, secretURL, token, nil, &current)
+	if err != nil {
+		return err
+	}
+	if found && current.Value == desired.Value && current.ContentType == desired.ContentType {
+		return nil
+	}
+
+	body, err := json.Marshal(desired)
+	if err != nil {
+		return fmt.Errorf("failed to encode secret: %w", err)
+	}
+	if _, err := keyVaultDo(ctx, p.client, http.MethodPut, secretURL, token, body, nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+// keyVaultDo sends a Key Vault request and decodes a JSON response into into, if non-nil.
+// It reports false without error for 404 Not Found.
+func keyVaultDo(
+	ctx context.Context,
+	httpClient *http.Client,
+	method, endpoint, token string,
+	body []byte,
+	into any,
+) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req) // #nosec G704 -- restricted to Key Vault hosts, HTTPS enforced
+	if err != nil {
+		return false, fmt.Errorf("request to Key Vault failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// [SEC:S-4] Limit response body read to prevent OOM from malicious servers
+	const maxResponseBody = 1 << 20 // 1 MB
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	if err != nil {
+		return false, fmt.Errorf("failed to read Key Vault response: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode >= 400 {
+		return false, fmt.Errorf("request to Key Vault returned %s", resp.Status)
+	}
+	if into != nil {
+		if err := json.Unmarshal(respBody, into); err != nil {
+			return false, fmt.Errorf("failed to decode Key Vault response: %w", err)
+		}
+	}
+	return true, nil
+}
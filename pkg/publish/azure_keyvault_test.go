@@ -0,0 +1,360 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/validation"
+)
+
+type staticCredential string
+
+func (c staticCredential) Token(context.Context, string) (string, error) {
+	return string(c), nil
+}
+
+// scopedCredential returns test-token, reporting every requested scope.
+type scopedCredential func(scope string)
+
+func (c scopedCredential) Token(_ context.Context, scope string) (string, error) {
+	c(scope)
+	return "test-token", nil
+}
+
+// fakeKeyVault serves the Key Vault secrets API from memory.
+type fakeKeyVault struct {
+	mu      sync.Mutex
+	secrets map[string]keyVaultSecret
+	puts    int
+}
+
+func (v *fakeKeyVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer test-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/secrets/")
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	switch r.Method {
+	case http.MethodGet:
+		secret, ok := v.secrets[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(secret)
+	case http.MethodPut:
+		var secret keyVaultSecret
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &secret); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		v.secrets[name] = secret
+		v.puts++
+	}
+}
+
+// keyVaultTestClient returns a client that sends every request to srv and
+// accepts its certificate, so tests can use real Key Vault URLs.
+func keyVaultTestClient(srv *httptest.Server) *http.Client {
+	transport := srv.Client().Transport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+	}
+	transport.TLSClientConfig.ServerName = "example.com" // in the httptest certificate
+	return &http.Client{Transport: transport}
+}
+
+func TestAzureKeyVaultPublisher(t *testing.T) {
+	t.Parallel()
+
+	vault := &fakeKeyVault{secrets: map[string]keyVaultSecret{}}
+	srv := httptest.NewTLSServer(vault)
+	defer srv.Close()
+
+	p := NewAzureKeyVaultPublisher(nil)
+	p.client = keyVaultTestClient(srv)
+	var scopes []string
+	p.credentials = func(context.Context, string, map[string]string) (CredentialProvider, error) {
+		return scopedCredential(func(scope string) { scopes = append(scopes, scope) }), nil
+	}
+
+	kp := generateTestKey(t)
+	target := openukrv1alpha1.PublishTarget{
+		Type:   TargetTypeAzureKeyVault,
+		Config: map[string]string{"vaultURL": "https://my-vault.vault.azure.cn/"},
+		Outputs: []openukrv1alpha1.PublishOutput{
+			{Encoding: "PEM", Config: map[string]string{"secretName": "signing-key-pem"}},
+			{Encoding: "DER", Config: map[string]string{"secretName": "signing-key-der"}},
+		},
+	}
+	for range 2 {
+		if err := p.Publish(context.Background(), testOwner, target, kp.Public()); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	for _, scope := range scopes {
+		if scope != "https://vault.azure.cn/.default" {
+			t.Fatalf("token scope = %q, want the sovereign cloud scope", scope)
+		}
+	}
+	if vault.puts != 2 {
+		t.Errorf("Key Vault received %d writes, want 2 (re-publish must not create new versions)", vault.puts)
+	}
+
+	pemSecret := vault.secrets["signing-key-pem"]
+	if !strings.HasPrefix(pemSecret.Value, "-----BEGIN PUBLIC KEY-----") {
+		t.Errorf("PEM secret value = %q, want a PEM public key", pemSecret.Value)
+	}
+	if pemSecret.Tags["key-id"] != kp.KeyID {
+		t.Errorf("key-id tag = %q, want %q", pemSecret.Tags["key-id"], kp.KeyID)
+	}
+
+	derSecret := vault.secrets["signing-key-der"]
+	der, err := base64.StdEncoding.DecodeString(derSecret.Value)
+	if err != nil {
+		t.Fatalf("DER secret value is not base64: %v", err)
+	}
+	if _, err := x509.ParsePKIXPublicKey(der); err != nil {
+		t.Errorf("DER secret is not a SubjectPublicKeyInfo: %v", err)
+	}
+	if derSecret.ContentType != "application/octet-stream;base64" {
+		t.Errorf("DER secret content type = %q, want application/octet-stream;base64", derSecret.ContentType)
+	}
+}
+
+func TestAzureKeyVaultPublisherRejectsInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	p := NewAzureKeyVaultPublisher(nil)
+	kp := generateTestKey(t)
+	tests := map[string]map[string]string{
+		"http vault":          {"vaultURL": "http://my-vault.vault.azure.net", "secretName": "key"},
+		"foreign host":        {"vaultURL": "https://vault.example.com", "secretName": "key"},
+		"metadata host":       {"vaultURL": "https://169.254.169.254", "secretName": "key"},
+		"suffix lookalike":    {"vaultURL": "https://my-vault.vault.azure.net.example.com", "secretName": "key"},
+		"vault path":          {"vaultURL": "https://my-vault.vault.azure.net/secrets", "secretName": "key"},
+		"missing secret name": {"vaultURL": "https://my-vault.vault.azure.net"},
+		"invalid secret name": {"vaultURL": "https://my-vault.vault.azure.net", "secretName": "key_1"},
+	}
+	for name, config := range tests {
+		target := openukrv1alpha1.PublishTarget{Type: TargetTypeAzureKeyVault, Config: config}
+		if err := p.Publish(context.Background(), testOwner, target, kp.Public()); err == nil {
+			t.Errorf("%s: Publish() succeeded, want error", name)
+		}
+	}
+
+	// Two encodings written to one secret would overwrite each other
+	target := openukrv1alpha1.PublishTarget{
+		Type:   TargetTypeAzureKeyVault,
+		Config: map[string]string{"vaultURL": "https://my-vault.vault.azure.net"},
+		Outputs: []openukrv1alpha1.PublishOutput{
+			{Encoding: "PEM", Config: map[string]string{"secretName": "signing-key"}},
+			{Encoding: "JWK", Config: map[string]string{"secretName": "Signing-Key"}},
+		},
+	}
+	if err := p.Publish(context.Background(), testOwner, target, kp.Public()); err == nil || !strings.Contains(err.Error(), "both write") {
+		t.Errorf("shared secretName: Publish() error = %v, want a collision error", err)
+	}
+}
+
+func TestAzureKeyVaultPublisherHostPolicy(t *testing.T) {
+	t.Parallel()
+
+	denied, err := validation.NewHostPolicy(nil, []string{"*.vault.azure.cn"})
+	if err != nil {
+		t.Fatalf("NewHostPolicy() error = %v", err)
+	}
+	p := newAzureKeyVaultPublisher(nil, denied)
+	p.credentials = func(context.Context, string, map[string]string) (CredentialProvider, error) {
+		t.Error("credentials requested for a denied vault")
+		return nil, errors.New("unexpected")
+	}
+	target := openukrv1alpha1.PublishTarget{
+		Type:   TargetTypeAzureKeyVault,
+		Config: map[string]string{"vaultURL": "https://my-vault.vault.azure.cn", "secretName": "key"},
+	}
+	err = p.Publish(context.Background(), testOwner, target, generateTestKey(t).Public())
+	if err == nil || !strings.Contains(err.Error(), "denied by cluster policy") {
+		t.Errorf("Publish() error = %v, want denied by cluster policy", err)
+	}
+}
+
+func TestAzureClientSecretCredential(t *testing.T) {
+	t.Parallel()
+
+	var form map[string][]string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tenant/oauth2/v2.0/token" || r.ParseForm() != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		form = r.PostForm
+		_, _ = w.Write([]byte(`{"access_token":"test-token"}`))
+	}))
+	defer srv.Close()
+
+	c := newTestClient(t, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "azure", Namespace: "default"},
+		Data:       map[string][]byte{AzureClientSecretKey: []byte("s3cret")},
+	})
+	ctx := context.Background()
+	provider, err := newAzureCredential(ctx, c, srv.Client(), nil, "default", map[string]string{
+		"clientSecretRef": "azure",
+		"tenantID":        "tenant",
+		"clientID":        "client",
+	})
+	if err != nil {
+		t.Fatalf("newAzureCredential() error = %v", err)
+	}
+	provider.(*azureCredential).authorityHost = srv.URL
+
+	token, err := provider.Token(ctx, keyVaultScope("vault.azure.net"))
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if token != "test-token" {
+		t.Errorf("Token() = %q, want test-token", token)
+	}
+	if got := form["client_secret"]; len(got) != 1 || got[0] != "s3cret" {
+		t.Errorf("client_secret = %v, want [s3cret]", got)
+	}
+	if got := form["scope"]; len(got) != 1 || got[0] != "https://vault.azure.net/.default" {
+		t.Errorf("scope = %v, want [https://vault.azure.net/.default]", got)
+	}
+}
+
+func TestAzureCredentialCachesTokens(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = fmt.Fprintf(w, `{"access_token":"token-%d","expires_in":3600}`, requests)
+	}))
+	defer srv.Close()
+
+	clk := clocktesting.NewFakePassiveClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	cred := &azureCredential{
+		client:        srv.Client(),
+		cache:         newTokenCache(clk),
+		authorityHost: srv.URL,
+		tenantID:      "tenant",
+		clientID:      "client",
+		clientSecret:  "s3cret",
+	}
+	token := func(scope string) string {
+		t.Helper()
+		token, err := cred.Token(context.Background(), scope)
+		if err != nil {
+			t.Fatalf("Token() error = %v", err)
+		}
+		return token
+	}
+	scope := keyVaultScope("vault.azure.net")
+
+	if got := token(scope); got != "token-1" {
+		t.Fatalf("Token() = %q, want token-1", got)
+	}
+	clk.SetTime(clk.Now().Add(50 * time.Minute))
+	if got := token(scope); got != "token-1" {
+		t.Errorf("Token() = %q, want the cached token-1", got)
+	}
+	if got := token(keyVaultScope("vault.azure.cn")); got != "token-2" {
+		t.Errorf("Token() for another scope = %q, want token-2", got)
+	}
+
+	// Renewed shortly before expiry
+	clk.SetTime(clk.Now().Add(6 * time.Minute))
+	if got := token(scope); got != "token-3" {
+		t.Errorf("Token() near expiry = %q, want a new token-3", got)
+	}
+
+	// A rotated client secret does not reuse the old secret's token
+	cred.clientSecret = "rotated"
+	if got := token(scope); got != "token-4" {
+		t.Errorf("Token() after secret rotation = %q, want token-4", got)
+	}
+}
+
+func TestAzureKeyVaultKMSWrapKey(t *testing.T) {
+	t.Parallel()
+
+	const kid = "https://my-vault.vault.azure.net/keys/kek/0123456789abcdef0123456789abcdef"
+	var got keyOperation
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/keys/kek/wrapkey" || r.Header.Get("Authorization") != "Bearer test-token" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(keyOperationResult{KeyID: kid, Value: "d3JhcHBlZA"}) // "wrapped"
+	}))
+	defer srv.Close()
+
+	k := NewAzureKeyVaultKMS(nil)
+	k.client = keyVaultTestClient(srv)
+	var gotNamespace string
+	k.credentials = func(_ context.Context, namespace string, _ map[string]string) (CredentialProvider, error) {
+		gotNamespace = namespace
+		return staticCredential("test-token"), nil
+	}
+
+	cfg := openukrv1alpha1.AzureKeyVaultEncryption{KeyURL: "https://my-vault.vault.azure.net/keys/kek/"}
+	wrapped, keyID, err := k.WrapKey(context.Background(), "team-a", cfg, []byte("data-key"))
+	if err != nil {
+		t.Fatalf("WrapKey() error = %v", err)
+	}
+	if string(wrapped) != "wrapped" || keyID != kid {
+		t.Errorf("WrapKey() = %q, %q, want wrapped, %q", wrapped, keyID, kid)
+	}
+	if got.Algorithm != "RSA-OAEP-256" || got.Value != "ZGF0YS1rZXk" {
+		t.Errorf("wrap request = %+v, want RSA-OAEP-256 of the base64url data key", got)
+	}
+	if gotNamespace != "team-a" {
+		t.Errorf("credentials namespace = %q, want team-a", gotNamespace)
+	}
+
+	cfg.KeyURL = "https://keys.example.com/keys/kek"
+	if _, _, err := k.WrapKey(context.Background(), "team-a", cfg, []byte("data-key")); err == nil {
+		t.Error("WrapKey() with a foreign host succeeded, want error")
+	}
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/validation"
+)
+
+// keyWrapAlgorithm is the Key Vault algorithm data keys are wrapped with.
+const keyWrapAlgorithm = "RSA-OAEP-256"
+
+// AzureKeyVaultKMS wraps data keys with an RSA key held in Azure Key Vault, the
+// KMS side of envelope-encrypted outputs (see output.WithKeyWrapper). It shares
+// credentials and token caching with the AzureKeyVaultPublisher.
+type AzureKeyVaultKMS struct {
+	client      *http.Client
+	tokens      *tokenCache
+	credentials func(ctx context.Context, namespace string, config map[string]string) (CredentialProvider, error)
+}
+
+// NewAzureKeyVaultKMS creates a new Azure Key Vault KMS.
+// Client secrets are read via k8sClient.
+func NewAzureKeyVaultKMS(k8sClient client.Client) *AzureKeyVaultKMS {
+	k := &AzureKeyVaultKMS{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		tokens: newTokenCache(clock.RealClock{}),
+	}
+	k.credentials = func(ctx context.Context, namespace string, config map[string]string) (CredentialProvider, error) {
+		return newAzureCredential(ctx, k8sClient, k.client, k.tokens, namespace, config)
+	}
+	return k
+}
+
+// WrapKey wraps dek with the Key Vault key of cfg (RSA-OAEP-256) and returns
+// the wrapped key and the versioned ID of the key that wrapped it. It
+// implements output.KeyWrapper.
+func (k *AzureKeyVaultKMS) WrapKey(
+	ctx context.Context,
+	namespace string,
+	cfg openukrv1alpha1.AzureKeyVaultEncryption,
+	dek []byte,
+) ([]byte, string, error) {
+	keyURL, suffix, err := validation.ValidateKeyVaultKeyURL(cfg.KeyURL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cred, err := k.credentials(ctx, namespace, map[string]string{
+		"clientSecretRef": cfg.ClientSecretRef,
+		"tenantID":        cfg.TenantID,
+		"clientID":        cfg.ClientID,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	token, err := cred.Token(ctx, keyVaultScope(suffix))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to authenticate to Key Vault: %w", err)
+	}
+
+	body, err := json.Marshal(keyOperation{
+		Algorithm: keyWrapAlgorithm,
+		Value:     base64.RawURLEncoding.EncodeToString(dek),
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode wrap request: %w", err)
+	}
+	endpoint := keyURL + "/wrapkey?api-version=" + azureKeyVaultAPIVersion
+	var result keyOperationResult
+	found, err := keyVaultDo(ctx, k.client, http.MethodPost, endpoint, token, body, &result)
+	if err != nil {
+		return nil, "", err
+	}
+	if !found {
+		return nil, "", fmt.Errorf("key %s not found", cfg.KeyURL)
+	}
+	wrapped, err := base64.RawURLEncoding.DecodeString(result.Value)
+	if err != nil || len(wrapped) == 0 {
+		return nil, "", fmt.Errorf("no wrapped key in Key Vault response")
+	}
+	return wrapped, result.KeyID, nil
+}
+
+// keyOperation is a Key Vault wrapkey/unwrapkey request.
+type keyOperation struct {
+	Algorithm string `json:"alg"`
+	Value     string `json:"value"`
+}
+
+// keyOperationResult is a Key Vault key operation response.
+type keyOperationResult struct {
+	KeyID string `json:"kid"`
+	Value string `json:"value"`
+}
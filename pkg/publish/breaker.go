@@ -0,0 +1,141 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+)
+
+// ErrCircuitOpen is reported for targets skipped because they failed repeatedly.
+var ErrCircuitOpen = errors.New("circuit open after repeated failures")
+
+// Circuit breaker defaults: a target is skipped for DefaultCircuitCooldown after
+// DefaultCircuitFailureThreshold consecutive failures.
+const (
+	DefaultCircuitFailureThreshold = 5
+	DefaultCircuitCooldown         = 10 * time.Minute
+)
+
+// circuitBreaker bounds the time spent on dead targets. After threshold
+// consecutive failures a target's circuit opens and it is skipped until the
+// cooldown ends; then a single attempt is let through (half-open). Success
+// closes the circuit, failure opens it for another cooldown.
+// Circuits are shared by the profiles of a namespace publishing to the same
+// target; a circuit is dropped once every profile that failed on it is forgotten.
+// A nil *circuitBreaker lets every attempt through.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	clock     clock.PassiveClock
+	circuits  map[string]*circuit
+}
+
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+	// owners are the profiles whose attempts failed on the circuit.
+	owners map[types.NamespacedName]bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration, clk clock.PassiveClock) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		clock:     clk,
+		circuits:  map[string]*circuit{},
+	}
+}
+
+// allow reports whether the target may be attempted. If not, it also returns
+// when the circuit half-opens.
+func (b *circuitBreaker) allow(key string) (bool, time.Time) {
+	if b == nil {
+		return true, time.Time{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[key]
+	if !ok || c.failures < b.threshold {
+		return true, time.Time{}
+	}
+	if c.probing || b.clock.Now().Before(c.openUntil) {
+		return false, c.openUntil
+	}
+	// Half-open: one attempt decides whether the circuit closes
+	c.probing = true
+	return true, time.Time{}
+}
+
+// record records the outcome of owner's attempt and returns when the circuit
+// half-opens if the attempt left it open, or the zero time otherwise.
+func (b *circuitBreaker) record(key string, owner types.NamespacedName, err error) time.Time {
+	if b == nil {
+		return time.Time{}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		delete(b.circuits, key)
+		return time.Time{}
+	}
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{owners: map[types.NamespacedName]bool{}}
+		b.circuits[key] = c
+	}
+	c.owners[owner] = true
+	c.failures++
+	c.probing = false
+	if c.failures < b.threshold {
+		return time.Time{}
+	}
+	c.openUntil = b.clock.Now().Add(b.cooldown)
+	return c.openUntil
+}
+
+// forget removes owner from every circuit and drops the circuits no other
+// profile failed on.
+func (b *circuitBreaker) forget(owner types.NamespacedName) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for key, c := range b.circuits {
+		delete(c.owners, owner)
+		if len(c.owners) == 0 {
+			delete(b.circuits, key)
+		}
+	}
+}
+
+// circuitKey identifies a target of a namespace across profiles and reconciles.
+func circuitKey(namespace string, target openukrv1alpha1.PublishTarget) string {
+	return namespace + "/" + target.Type + "/" + describeTarget(target)
+}
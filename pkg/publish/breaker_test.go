@@ -0,0 +1,160 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+// flakyPublisher fails while err is set and counts attempts.
+type flakyPublisher struct {
+	mu       sync.Mutex
+	err      error
+	attempts int
+}
+
+func (p *flakyPublisher) Publish(context.Context, Owner, openukrv1alpha1.PublishTarget, *crypto.PublicKeyInfo) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.attempts++
+	return p.err
+}
+
+func TestPublishAllCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clocktesting.NewFakePassiveClock(start)
+	flaky := &flakyPublisher{err: errors.New("connection refused")}
+	m := &Manager{
+		publishers:  map[string]Publisher{"flaky": flaky},
+		concurrency: DefaultConcurrency,
+		breaker:     newCircuitBreaker(3, time.Minute, clk),
+	}
+	targets := []openukrv1alpha1.PublishTarget{{Type: "flaky", Config: map[string]string{"endpoint": "https://dead"}}}
+	ctx := context.Background()
+	pub := generateTestKey(t).Public()
+
+	publishOnce := func() TargetResult {
+		t.Helper()
+		results, err := m.PublishAll(ctx, testOwner, targets, pub)
+		if len(results) != 1 {
+			t.Fatalf("PublishAll() returned %d results, want 1 (err = %v)", len(results), err)
+		}
+		return results[0]
+	}
+
+	// Closed: failures below the threshold are attempted
+	for i := range 2 {
+		if res := publishOnce(); res.Err == nil || !res.CircuitOpenUntil.IsZero() {
+			t.Fatalf("failure %d: result = %+v, want error with closed circuit", i+1, res)
+		}
+	}
+	// The third failure opens the circuit
+	res := publishOnce()
+	if want := start.Add(time.Minute); !res.CircuitOpenUntil.Equal(want) {
+		t.Fatalf("CircuitOpenUntil = %v, want %v", res.CircuitOpenUntil, want)
+	}
+
+	// Open: the target is skipped without an attempt
+	res = publishOnce()
+	if !errors.Is(res.Err, ErrCircuitOpen) || res.CircuitOpenUntil.IsZero() {
+		t.Errorf("open circuit result = %+v, want ErrCircuitOpen", res)
+	}
+	if flaky.attempts != 3 {
+		t.Errorf("attempts = %d while open, want 3", flaky.attempts)
+	}
+
+	// Half-open: after the cooldown a failed probe reopens the circuit at once
+	clk.SetTime(start.Add(time.Minute))
+	res = publishOnce()
+	if flaky.attempts != 4 || errors.Is(res.Err, ErrCircuitOpen) {
+		t.Errorf("half-open probe: attempts = %d, result = %+v, want one attempt", flaky.attempts, res)
+	}
+	if want := start.Add(2 * time.Minute); !res.CircuitOpenUntil.Equal(want) {
+		t.Errorf("CircuitOpenUntil after failed probe = %v, want %v", res.CircuitOpenUntil, want)
+	}
+
+	// A successful probe closes the circuit and resets the failure count
+	clk.SetTime(start.Add(2 * time.Minute))
+	flaky.err = nil
+	if res := publishOnce(); res.Err != nil || !res.CircuitOpenUntil.IsZero() {
+		t.Errorf("successful probe result = %+v, want closed circuit", res)
+	}
+	flaky.err = errors.New("connection refused")
+	if res := publishOnce(); !res.CircuitOpenUntil.IsZero() {
+		t.Errorf("first failure after reset opened the circuit: %+v", res)
+	}
+	if flaky.attempts != 6 {
+		t.Errorf("attempts = %d, want 6", flaky.attempts)
+	}
+}
+
+func TestCircuitBreakerPerTarget(t *testing.T) {
+	t.Parallel()
+
+	clk := clocktesting.NewFakePassiveClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := newCircuitBreaker(1, time.Minute, clk)
+	b.record("default/http/https://a", testOwner.NamespacedName, errors.New("down"))
+
+	if ok, _ := b.allow("default/http/https://a"); ok {
+		t.Error("allow() for the failed target = true, want false")
+	}
+	if ok, _ := b.allow("default/http/https://b"); !ok {
+		t.Error("allow() for another target = false, want true")
+	}
+	if ok, _ := (*circuitBreaker)(nil).allow("any"); !ok {
+		t.Error("nil breaker allow() = false, want true")
+	}
+}
+
+func TestCircuitBreakerForget(t *testing.T) {
+	t.Parallel()
+
+	clk := clocktesting.NewFakePassiveClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := newCircuitBreaker(1, time.Minute, clk)
+	first := types.NamespacedName{Namespace: "default", Name: "first"}
+	second := types.NamespacedName{Namespace: "default", Name: "second"}
+	b.record("default/http/https://shared", first, errors.New("down"))
+	b.record("default/http/https://shared", second, errors.New("down"))
+	b.record("default/http/https://own", first, errors.New("down"))
+
+	// Deleting a profile drops the circuits only it failed on
+	b.forget(first)
+	if _, ok := b.circuits["default/http/https://own"]; ok {
+		t.Error("circuit of the deleted profile kept")
+	}
+	if ok, _ := b.allow("default/http/https://shared"); ok {
+		t.Error("allow() for a target another profile failed on = true, want the circuit kept open")
+	}
+
+	b.forget(second)
+	if len(b.circuits) != 0 {
+		t.Errorf("circuits = %v after forgetting every profile, want none", b.circuits)
+	}
+	(*circuitBreaker)(nil).forget(first)
+}
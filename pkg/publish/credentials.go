@@ -0,0 +1,247 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/clock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CredentialProvider supplies bearer tokens to cloud publishers.
+// Implementations must be safe for concurrent use.
+type CredentialProvider interface {
+	// Token returns an access token valid for scope.
+	Token(ctx context.Context, scope string) (string, error)
+}
+
+// Azure workload identity environment, injected by the azure-workload-identity webhook.
+const (
+	azureFederatedTokenFileEnv = "AZURE_FEDERATED_TOKEN_FILE"
+	azureClientIDEnv           = "AZURE_CLIENT_ID"
+	azureTenantIDEnv           = "AZURE_TENANT_ID"
+	azureAuthorityHostEnv      = "AZURE_AUTHORITY_HOST"
+)
+
+// defaultAzureAuthorityHost is the Microsoft Entra ID endpoint of the public cloud.
+const defaultAzureAuthorityHost = "https://login.microsoftonline.com/"
+
+// AzureClientSecretKey is the key in a client-secret Secret holding the secret value.
+const AzureClientSecretKey = "clientSecret"
+
+// tokenRefreshMargin is how long before expiry a cached token is renewed.
+const tokenRefreshMargin = 5 * time.Minute
+
+// tokenCache holds access tokens until shortly before they expire, so every
+// publish does not request a new token. Safe for concurrent use.
+type tokenCache struct {
+	mu     sync.Mutex
+	clock  clock.PassiveClock
+	tokens map[string]cachedToken
+}
+
+type cachedToken struct {
+	token     string
+	expiresAt time.Time
+}
+
+func newTokenCache(clk clock.PassiveClock) *tokenCache {
+	return &tokenCache{clock: clk, tokens: map[string]cachedToken{}}
+}
+
+// get returns the token cached under key, if it is not about to expire.
+func (c *tokenCache) get(key string) (string, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, ok := c.tokens[key]
+	if !ok || !c.clock.Now().Before(t.expiresAt.Add(-tokenRefreshMargin)) {
+		return "", false
+	}
+	return t.token, true
+}
+
+// put caches token under key for lifetime, dropping expired entries.
+func (c *tokenCache) put(key, token string, lifetime time.Duration) {
+	if c == nil || lifetime <= tokenRefreshMargin {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock.Now()
+	for k, t := range c.tokens {
+		if !now.Before(t.expiresAt) {
+			delete(c.tokens, k)
+		}
+	}
+	c.tokens[key] = cachedToken{token: token, expiresAt: now.Add(lifetime)}
+}
+
+// azureCredential obtains tokens via the OAuth 2.0 client credentials grant,
+// authenticating either with a federated (workload identity) token or a client secret.
+type azureCredential struct {
+	client        *http.Client
+	cache         *tokenCache
+	authorityHost string
+	tenantID      string
+	clientID      string
+	// Exactly one of clientSecret and assertionFile is set
+	clientSecret  string
+	assertionFile string
+}
+
+// newAzureCredential builds the credential for a target.
+// With "clientSecretRef" set, the client secret is read from that Secret in
+// namespace; "tenantID" and "clientID" are then required.
+// Otherwise workload identity is used, with "tenantID" and "clientID"
+// overriding the injected environment. Tokens are cached in cache, if non-nil.
+func newAzureCredential(
+	ctx context.Context,
+	reader client.Reader,
+	httpClient *http.Client,
+	cache *tokenCache,
+	namespace string,
+	config map[string]string,
+) (CredentialProvider, error) {
+	cred := &azureCredential{
+		client:        httpClient,
+		cache:         cache,
+		authorityHost: envOr(azureAuthorityHostEnv, defaultAzureAuthorityHost),
+		tenantID:      config["tenantID"],
+		clientID:      config["clientID"],
+	}
+
+	if ref := config["clientSecretRef"]; ref != "" {
+		if cred.tenantID == "" || cred.clientID == "" {
+			return nil, fmt.Errorf("'tenantID' and 'clientID' are required with 'clientSecretRef'")
+		}
+		if reader == nil {
+			return nil, fmt.Errorf("cannot load client secret: no Kubernetes client configured")
+		}
+		var secret corev1.Secret
+		if err := reader.Get(ctx, client.ObjectKey{Name: ref, Namespace: namespace}, &secret); err != nil {
+			return nil, fmt.Errorf("failed to get client secret %s/%s: %w", namespace, ref, err)
+		}
+		value := secret.Data[AzureClientSecretKey]
+		if len(value) == 0 {
+			return nil, fmt.Errorf("client secret %s/%s has no %q key", namespace, ref, AzureClientSecretKey)
+		}
+		cred.clientSecret = string(value)
+		return cred, nil
+	}
+
+	cred.assertionFile = os.Getenv(azureFederatedTokenFileEnv)
+	if cred.tenantID == "" {
+		cred.tenantID = os.Getenv(azureTenantIDEnv)
+	}
+	if cred.clientID == "" {
+		cred.clientID = os.Getenv(azureClientIDEnv)
+	}
+	if cred.assertionFile == "" || cred.tenantID == "" || cred.clientID == "" {
+		return nil, fmt.Errorf("no Azure credentials: set 'clientSecretRef' or enable workload identity")
+	}
+	return cred, nil
+}
+
+// cacheKey identifies the tokens of this credential for scope. A changed
+// client secret yields a new key.
+func (c *azureCredential) cacheKey(scope string) string {
+	secret := sha256.Sum256([]byte(c.clientSecret))
+	return strings.Join([]string{
+		c.authorityHost, c.tenantID, c.clientID, c.assertionFile, hex.EncodeToString(secret[:]), scope,
+	}, "\x00")
+}
+
+// Token returns a cached access token for scope, or requests one from the
+// tenant's token endpoint.
+func (c *azureCredential) Token(ctx context.Context, scope string) (string, error) {
+	key := c.cacheKey(scope)
+	if token, ok := c.cache.get(key); ok {
+		return token, nil
+	}
+
+	form := url.Values{
+		"grant_type": {"client_credentials"},
+		"client_id":  {c.clientID},
+		"scope":      {scope},
+	}
+	if c.clientSecret != "" {
+		form.Set("client_secret", c.clientSecret)
+	} else {
+		// The projected token is rotated by the kubelet; read it on every request
+		assertion, err := os.ReadFile(c.assertionFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read federated token: %w", err)
+		}
+		form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+		form.Set("client_assertion", strings.TrimSpace(string(assertion)))
+	}
+
+	endpoint := strings.TrimSuffix(c.authorityHost, "/") + "/" + url.PathEscape(c.tenantID) + "/oauth2/v2.0/token"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// [SEC:S-4] Limit response body read to prevent OOM from malicious servers
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned error: %s", resp.Status)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil || token.AccessToken == "" {
+		return "", fmt.Errorf("token endpoint returned no access token")
+	}
+	c.cache.put(key, token.AccessToken, time.Duration(token.ExpiresIn)*time.Second)
+	return token.AccessToken, nil
+}
+
+// envOr returns the environment variable key, or def if it is unset.
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -17,64 +17,308 @@ limitations under the License.
 package publish
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 
 	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
 	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/validation"
 )
 
 // FilesystemPublisher publishes public keys to the local filesystem.
-type FilesystemPublisher struct{}
+type FilesystemPublisher struct {
+	deniedPaths []string
+	signer      *PayloadSigner
+}
+
+// NewFilesystemPublisher creates a new filesystem publisher that refuses paths
+// under deniedPaths (see validation.DefaultDeniedPublishPaths).
+func NewFilesystemPublisher(deniedPaths []string) *FilesystemPublisher {
+	return &FilesystemPublisher{deniedPaths: deniedPaths}
+}
+
+// Publish writes the public key to the configured path, once per output.
+// Config required: "path" (directory). Symlinks are resolved before the denied
+// path checks, and the file must resolve to a location under path.
+// Output file: {path}/{KeyID}.pub (PEM), .der (DER), .spki (spki), .jwk (JWK),
+// .raw (raw-public) or .bin (ec-compressed), unless the optional "filename"
+// (relative to path, e.g. ".well-known/jwks.json") overrides it. The override
+// names a single file, so no two outputs may share it. A key it replaces is kept
+// under the "-previous" name (e.g. jwks-previous.json) while the owner has a
+// key in its grace period. With payload signing enabled, the signature is
+// written next to it with SignatureFileSuffix.
+// The optional "maxBytes" caps the total size of files under path (e.g. a small
+// tmpfs); with "pruneOldest" set to true, the oldest key files of the same
+// extension are removed to make room instead of failing with ErrQuotaExceeded.
+// Only files the owner published under the quota are pruned, never the keys it
+// retains; ownership is recorded in a hidden file under path. Files of the key
+// IDs the owner withdraws are removed, unless "filename" overrides the name.
+func (p *FilesystemPublisher) Publish(
+	ctx context.Context,
+	owner Owner,
+	target openukrv1alpha1.PublishTarget,
+	pub *crypto.PublicKeyInfo,
+) error {
+	outputs := resolveOutputs(target)
+	files := make(map[string]int, len(outputs))
+	for i, out := range outputs {
+		if name := out.config["filename"]; name != "" {
+			file := filepath.Join(out.config["path"], name)
+			if j, ok := files[file]; ok {
+				return fmt.Errorf("outputs %d and %d both publish to %s; set filename per output", j, i, file)
+			}
+			files[file] = i
+		}
+	}
+
+	var errs []error
+	for i, out := range outputs {
+		if err := p.publishOutput(ctx, owner, out, pub); err != nil {
+			errs = append(errs, fmt.Errorf("output[%d] (%s): %w", i, out.encoding, err))
+		}
+	}
+	return joinOutputErrors(errs)
+}
 
-// NewFilesystemPublisher creates a new filesystem publisher.
-func NewFilesystemPublisher() *FilesystemPublisher {
-	return &FilesystemPublisher{}
+// fileExtensions maps encodings to published file extensions. Each encoding
+// has its own, so outputs of one target never overwrite each other.
+var fileExtensions = map[string]string{
+	"PEM": "pub",
+	"DER": "der",
+	"JWK": "jwk",
+
+	crypto.EncodingECCompressed: "bin",
+	crypto.EncodingSPKI:         "spki",
+	crypto.EncodingRawPublic:    "raw",
 }
 
-// Publish writes the public key (PEM format) to the configured path.
-// Config required: "path" (directory).
-// Output file: {path}/{KeyID}.pub
-func (p *FilesystemPublisher) Publish(ctx context.Context, target openukrv1alpha1.PublishTarget, kp *crypto.KeyPair) error {
-	path, ok := target.Config["path"]
+func (p *FilesystemPublisher) publishOutput(
+	ctx context.Context,
+	owner Owner,
+	out resolvedOutput,
+	pub *crypto.PublicKeyInfo,
+) error {
+	path, ok := out.config["path"]
 	if !ok || path == "" {
 		return fmt.Errorf("missing 'path' in config")
 	}
 
-	// [SEC:S-3] Path traversal protection
-	cleanPath := filepath.Clean(path)
-	if !filepath.IsAbs(cleanPath) {
-		return fmt.Errorf("publish path must be absolute, got: %s", path)
+	// [SEC:S-3] Path traversal and sensitive directory protection, also for
+	// where symlinks in path lead
+	if err := validation.ValidatePublishPath(path, p.deniedPaths); err != nil {
+		return err
+	}
+	cleanPath, err := resolvePath(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	if err := validation.ValidatePublishPath(cleanPath, p.deniedPaths); err != nil {
+		return fmt.Errorf("publish path %s resolves to %s: %w", path, cleanPath, err)
+	}
+	q, err := parseQuota(out.config)
+	if err != nil {
+		return err
+	}
+
+	ext, ok := fileExtensions[out.encoding]
+	if !ok {
+		return fmt.Errorf("unsupported encoding: %s", out.encoding)
+	}
+
+	filename := filepath.Join(cleanPath, fmt.Sprintf("%s.%s", pub.KeyID, ext))
+	if name := out.config["filename"]; name != "" {
+		// [SEC:S-3] The override must stay under the publish path
+		if err := validation.ValidatePublishFilename(name); err != nil {
+			return err
+		}
+		filename = filepath.Join(cleanPath, name)
+	}
+	// [SEC:S-3] The file, and any directory in the override, must stay under
+	// the publish path once symlinks are resolved
+	if filename, err = resolvePath(filename); err != nil {
+		return err
 	}
-	if strings.Contains(cleanPath, "..") {
-		return fmt.Errorf("publish path must not contain '..': %s", path)
+	if !strings.HasPrefix(filename, cleanPath+string(filepath.Separator)) {
+		return fmt.Errorf("publish file %s escapes %s", filename, cleanPath)
 	}
 
 	// Ensure directory exists — 0750: owner rwx, group rx, others none
-	if err := os.MkdirAll(cleanPath, 0750); err != nil {
-		return fmt.Errorf("failed to ensure directory %s: %w", cleanPath, err)
+	if err := os.MkdirAll(filepath.Dir(filename), 0750); err != nil {
+		return fmt.Errorf("failed to ensure directory %s: %w", filepath.Dir(filename), err)
 	}
 
-	// Default to PEM encoding for filesystem
-	encoder, err := crypto.NewKeyEncoder("PEM")
+	data, err := encodePublic(pub, out.encoding)
 	if err != nil {
 		return err
 	}
 
-	pubPEM, err := encoder.EncodePublic(kp.PublicKey)
+	var signature []byte
+	writes := map[string]int64{filename: int64(len(data))}
+	if p.signer != nil {
+		if signature, err = p.signer.Sign(ctx, pub.KeyID, data); err != nil {
+			return err
+		}
+		writes[filename+SignatureFileSuffix] = int64(len(signature))
+	}
+	if q != nil {
+		unlock := lockPath(cleanPath)
+		defer unlock()
+	}
+	var kept map[string][]byte
+	if out.config["filename"] != "" {
+		if kept, err = replacedFiles(filename, data, owner.Previous != ""); err != nil {
+			return err
+		}
+		for path, content := range kept {
+			writes[path] = int64(len(content))
+		}
+	}
+	if err := q.reserve(cleanPath, ext, owner, filename, writes); err != nil {
+		return err
+	}
+	if out.config["filename"] != "" {
+		if err := keepPrevious(filename, kept, owner.Previous != ""); err != nil {
+			return err
+		}
+	}
+
+	// The signature goes first so a new key file never appears without it
+	if signature != nil {
+		if err := writeFileAtomic(filename+SignatureFileSuffix, signature); err != nil {
+			return err
+		}
+	}
+
+	if err := writeFileAtomic(filename, data); err != nil {
+		return err
+	}
+	if out.config["filename"] != "" {
+		return nil
+	}
+	return withdraw(cleanPath, ext, owner.Withdraw)
+}
+
+// withdraw removes the files of keyIDs with extension ext directly under dir.
+func withdraw(dir, ext string, keyIDs []string) error {
+	for _, keyID := range keyIDs {
+		if err := removeKeyFile(filepath.Join(dir, keyID+"."+ext)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeKeyFile removes filename and its signature.
+func removeKeyFile(filename string) error {
+	// The signature goes last so a key file never remains without it
+	for _, path := range []string{filename, filename + SignatureFileSuffix} {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to withdraw %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// previousFilename maps a "filename" override to the name keeping the key it
+// replaced, inserting the suffix before the extension: jwks.json → jwks-previous.json.
+func previousFilename(filename string) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "-previous" + ext
+}
+
+// replacedFiles returns the files to keep under previousFilename when data
+// replaces the key in filename, mapped to their content: the file and its
+// signature. Nothing is kept if filename already holds data or keep is false.
+func replacedFiles(filename string, data []byte, keep bool) (map[string][]byte, error) {
+	if !keep {
+		return nil, nil
+	}
+	current, err := os.ReadFile(filename)
+	if os.IsNotExist(err) || bytes.Equal(current, data) {
+		return nil, nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to encode public key: %w", err)
+		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+	previous := previousFilename(filename)
+	files := map[string][]byte{previous: current}
+	signature, err := os.ReadFile(filename + SignatureFileSuffix)
+	switch {
+	case err == nil:
+		files[previous+SignatureFileSuffix] = signature
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to read %s: %w", filename+SignatureFileSuffix, err)
 	}
+	return files, nil
+}
 
-	filename := filepath.Join(cleanPath, fmt.Sprintf("%s.pub", kp.KeyID))
+// keepPrevious writes the replaced key files from replacedFiles under
+// previousFilename. If keep is false, the owner has no key in its grace period
+// and the previous file is removed instead.
+func keepPrevious(filename string, kept map[string][]byte, keep bool) error {
+	previous := previousFilename(filename)
+	if !keep {
+		return removeKeyFile(previous)
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	// The signature goes first so a key file never appears without it
+	stale := previous + SignatureFileSuffix
+	if signature, ok := kept[stale]; ok {
+		if err := writeFileAtomic(stale, signature); err != nil {
+			return err
+		}
+	} else if err := os.Remove(stale); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove %s: %w", stale, err)
+	}
+	return writeFileAtomic(previous, kept[previous])
+}
 
-	// [SEC:S-3] Atomic write: write to temp file, then rename.
-	// This prevents partial writes from being observable.
-	tmpFile := filename + ".tmp"
-	if err := os.WriteFile(tmpFile, pubPEM, 0600); err != nil {
+// resolvePath returns path with its symlinks resolved. Trailing components
+// that do not exist yet are kept as they are, so they are created under the
+// resolved parent.
+func resolvePath(path string) (string, error) {
+	existing, missing := path, ""
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			return filepath.Join(resolved, missing), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("failed to resolve %s: %w", path, err)
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return path, nil
+		}
+		missing = filepath.Join(filepath.Base(existing), missing)
+		existing = parent
+	}
+}
+
+// writeFileAtomic writes data to filename with owner-only permissions.
+func writeFileAtomic(filename string, data []byte) error {
+	// [SEC:S-3] Atomic write: write to a temp file in the same directory, then
+	// rename. This prevents partial writes from being observable; the unique
+	// temp name keeps concurrent publishes of the same file apart.
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file for %s: %w", filename, err)
+	}
+	tmpFile := tmp.Name()
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpFile) // Best-effort cleanup
 		return fmt.Errorf("failed to write temp file %s: %w", tmpFile, err)
 	}
 	if err := os.Rename(tmpFile, filename); err != nil {
@@ -0,0 +1,502 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/validation"
+)
+
+func TestFilesystemPublisherExtensionPerEncoding(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		encoding string
+		wantExt  string
+		check    func([]byte) bool
+	}{
+		{
+			encoding: "PEM",
+			wantExt:  ".pub",
+			check: func(b []byte) bool {
+				block, _ := pem.Decode(b)
+				return block != nil && block.Type == "PUBLIC KEY"
+			},
+		},
+		{
+			encoding: "DER",
+			wantExt:  ".der",
+			check: func(b []byte) bool {
+				_, err := x509.ParsePKIXPublicKey(b)
+				return err == nil
+			},
+		},
+		{
+			encoding: crypto.EncodingSPKI,
+			wantExt:  ".spki",
+			check: func(b []byte) bool {
+				_, err := x509.ParsePKIXPublicKey(b)
+				return err == nil
+			},
+		},
+		{
+			encoding: "JWK",
+			wantExt:  ".jwk",
+			check: func(b []byte) bool {
+				var jwk map[string]any
+				return json.Unmarshal(b, &jwk) == nil && jwk["kty"] == "EC"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			kp := generateTestKey(t)
+			target := openukrv1alpha1.PublishTarget{
+				Type: "filesystem",
+				Outputs: []openukrv1alpha1.PublishOutput{
+					{Encoding: tt.encoding, Config: map[string]string{"path": dir}},
+				},
+			}
+			p := NewFilesystemPublisher(validation.DefaultDeniedPublishPaths)
+			if err := p.Publish(context.Background(), testOwner, target, kp.Public()); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, kp.KeyID+tt.wantExt))
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+			if !tt.check(data) {
+				t.Errorf("%s file content is not valid %s: %q", tt.wantExt, tt.encoding, data)
+			}
+		})
+	}
+}
+
+func TestFilesystemPublisherRejectsDeniedPath(t *testing.T) {
+	t.Parallel()
+
+	kp := generateTestKey(t)
+	target := openukrv1alpha1.PublishTarget{
+		Type:   "filesystem",
+		Config: map[string]string{"path": "/etc/openukr"},
+	}
+	err := NewFilesystemPublisher(validation.DefaultDeniedPublishPaths).Publish(context.Background(), testOwner, target, kp.Public())
+	if err == nil {
+		t.Fatal("expected error publishing under /etc, got nil")
+	}
+
+	// The denylist is configurable: a custom prefix denies the temp dir
+	dir := t.TempDir()
+	target.Config["path"] = dir
+	if err := NewFilesystemPublisher([]string{dir}).Publish(context.Background(), testOwner, target, kp.Public()); err == nil {
+		t.Fatal("expected error publishing under a custom denied prefix, got nil")
+	}
+}
+
+func TestFilesystemPublisherResolvesSymlinks(t *testing.T) {
+	t.Parallel()
+
+	kp := generateTestKey(t)
+	denied, dir := t.TempDir(), t.TempDir()
+	if err := os.Symlink(denied, filepath.Join(dir, "link")); err != nil {
+		t.Fatalf("Symlink() error = %v", err)
+	}
+	p := NewFilesystemPublisher([]string{denied})
+
+	// A publish path linking into a denied directory is denied
+	target := openukrv1alpha1.PublishTarget{
+		Type:   "filesystem",
+		Config: map[string]string{"path": filepath.Join(dir, "link", "keys")},
+	}
+	if err := p.Publish(context.Background(), testOwner, target, kp.Public()); err == nil {
+		t.Error("Publish() through a symlink into a denied directory succeeded, want error")
+	}
+	if _, err := os.Stat(filepath.Join(denied, "keys")); !os.IsNotExist(err) {
+		t.Errorf("directory created in the denied directory (err = %v)", err)
+	}
+
+	// A filename override may not leave the publish path through a symlink
+	target.Config = map[string]string{"path": dir, "filename": "link/jwks.json"}
+	if err := p.Publish(context.Background(), testOwner, target, kp.Public()); err == nil {
+		t.Error("Publish() with a filename linking out of the publish path succeeded, want error")
+	}
+	if _, err := os.Stat(filepath.Join(denied, "jwks.json")); !os.IsNotExist(err) {
+		t.Errorf("file written outside the publish path (err = %v)", err)
+	}
+}
+
+func TestFilesystemPublisherWithdraw(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	p := NewFilesystemPublisher(validation.DefaultDeniedPublishPaths)
+	target := openukrv1alpha1.PublishTarget{Type: "filesystem", Config: map[string]string{"path": dir}}
+	previous, current := generateTestKey(t), generateTestKey(t)
+	if err := p.Publish(context.Background(), testOwner, target, previous.Public()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	// A hard cutover withdraws the previous key as the new one is published
+	owner := testOwner
+	owner.Withdraw = []string{previous.KeyID}
+	if err := p.Publish(context.Background(), owner, target, current.Public()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	for keyID, want := range map[string]bool{previous.KeyID: false, current.KeyID: true} {
+		_, err := os.Stat(filepath.Join(dir, keyID+".pub"))
+		if got := err == nil; got != want {
+			t.Errorf("%s.pub present = %v, want %v", keyID, got, want)
+		}
+	}
+}
+
+func TestFilesystemPublisherCustomFilename(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	kp := generateTestKey(t)
+	target := openukrv1alpha1.PublishTarget{
+		Type: "filesystem",
+		Outputs: []openukrv1alpha1.PublishOutput{
+			{Encoding: "JWK", Config: map[string]string{"path": dir, "filename": ".well-known/jwks.json"}},
+		},
+	}
+	p := NewFilesystemPublisher(validation.DefaultDeniedPublishPaths)
+	if err := p.Publish(context.Background(), testOwner, target, kp.Public()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".well-known", "jwks.json")); err != nil {
+		t.Errorf("custom filename not written: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, kp.KeyID+".jwk")); !os.IsNotExist(err) {
+		t.Errorf("default filename written despite override: %v", err)
+	}
+
+	for _, name := range []string{"../jwks.json", "/tmp/jwks.json", "a/../../jwks.json"} {
+		target.Outputs[0].Config["filename"] = name
+		if err := p.Publish(context.Background(), testOwner, target, kp.Public()); err == nil {
+			t.Errorf("Publish() with filename %q: expected error, got nil", name)
+		}
+	}
+}
+
+func TestFilesystemPublisherCustomFilenameKeepsPrevious(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	target := openukrv1alpha1.PublishTarget{
+		Type: "filesystem",
+		Outputs: []openukrv1alpha1.PublishOutput{
+			{Encoding: "JWK", Config: map[string]string{"path": dir, "filename": "jwks.json"}},
+		},
+	}
+	p := NewFilesystemPublisher(validation.DefaultDeniedPublishPaths)
+	ctx := context.Background()
+	kid := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			return ""
+		}
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		var jwk struct {
+			Kid string `json:"kid"`
+		}
+		if err := json.Unmarshal(data, &jwk); err != nil {
+			t.Fatalf("Unmarshal(%s) error = %v", name, err)
+		}
+		return jwk.Kid
+	}
+
+	first, second := generateTestKey(t), generateTestKey(t)
+	if err := p.Publish(ctx, testOwner, target, first.Public()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	// The rotation replaces first, which stays published during its grace period
+	owner := testOwner
+	owner.Previous = first.KeyID
+	for range 2 {
+		if err := p.Publish(ctx, owner, target, second.Public()); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		if got := kid("jwks.json"); got != second.KeyID {
+			t.Errorf("jwks.json holds %q, want %q", got, second.KeyID)
+		}
+		if got := kid("jwks-previous.json"); got != first.KeyID {
+			t.Errorf("jwks-previous.json holds %q, want %q", got, first.KeyID)
+		}
+	}
+
+	// Grace period over
+	if err := p.Publish(ctx, testOwner, target, second.Public()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if got := kid("jwks-previous.json"); got != "" {
+		t.Errorf("jwks-previous.json holds %q after the grace period", got)
+	}
+}
+
+func TestFilesystemPublisherSharedFilename(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	target := openukrv1alpha1.PublishTarget{
+		Type:   "filesystem",
+		Config: map[string]string{"path": dir, "filename": "key"},
+		Outputs: []openukrv1alpha1.PublishOutput{
+			{Encoding: "PEM"},
+			{Encoding: "JWK"},
+		},
+	}
+	p := NewFilesystemPublisher(validation.DefaultDeniedPublishPaths)
+	kp := generateTestKey(t)
+	if err := p.Publish(context.Background(), testOwner, target, kp.Public()); err == nil {
+		t.Fatal("Publish() with a filename shared by two outputs: expected error, got nil")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "key")); !os.IsNotExist(err) {
+		t.Errorf("shared filename written: %v", err)
+	}
+
+	target.Outputs[1].Config = map[string]string{"filename": "key.jwk"}
+	if err := p.Publish(context.Background(), testOwner, target, kp.Public()); err != nil {
+		t.Fatalf("Publish() with per-output filenames error = %v", err)
+	}
+}
+
+func TestWriteFileAtomicConcurrent(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "jwks.json")
+	var wg sync.WaitGroup
+	errs := make([]error, 8)
+	for i := range errs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = writeFileAtomic(filename, []byte(strings.Repeat(strconv.Itoa(i), 4096)))
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		t.Fatalf("writeFileAtomic() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if strings.Trim(string(data), string(data[:1])) != "" {
+		t.Errorf("file mixes concurrent writes: %.32q...", data)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory holds %d entries, want only the published file", len(entries))
+	}
+}
+
+func TestFilesystemPublisherQuota(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	p := NewFilesystemPublisher(validation.DefaultDeniedPublishPaths)
+	target := openukrv1alpha1.PublishTarget{
+		Type:   "filesystem",
+		Config: map[string]string{"path": dir, "maxBytes": "520"},
+	}
+
+	// A P-256 PEM public key is 178 bytes: two fit with their ownership record,
+	// a third does not
+	first, second := generateTestKey(t), generateTestKey(t)
+	for _, kp := range []*crypto.KeyPair{first, second} {
+		if err := p.Publish(context.Background(), testOwner, target, kp.Public()); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+	}
+	third := generateTestKey(t)
+	err := p.Publish(context.Background(), testOwner, target, third.Public())
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Publish() error = %v, want ErrQuotaExceeded", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, third.KeyID+".pub")); !os.IsNotExist(err) {
+		t.Errorf("key written despite quota: %v", err)
+	}
+
+	// Republishing a key in place does not count its existing file
+	if err := p.Publish(context.Background(), testOwner, target, second.Public()); err != nil {
+		t.Fatalf("Publish() republishing error = %v", err)
+	}
+
+	// With pruning, the oldest key file makes room
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, first.KeyID+".pub"), past, past); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+	target.Config["pruneOldest"] = "true"
+	if err := p.Publish(context.Background(), testOwner, target, third.Public()); err != nil {
+		t.Fatalf("Publish() with pruning error = %v", err)
+	}
+	for keyID, want := range map[string]bool{first.KeyID: false, second.KeyID: true, third.KeyID: true} {
+		_, err := os.Stat(filepath.Join(dir, keyID+".pub"))
+		if got := err == nil; got != want {
+			t.Errorf("%s.pub present = %v, want %v", keyID, got, want)
+		}
+	}
+
+	// A key larger than the whole quota fails without pruning anything
+	target.Config["maxBytes"] = "100"
+	if err := p.Publish(context.Background(), testOwner, target, generateTestKey(t).Public()); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Publish() error = %v, want ErrQuotaExceeded", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, second.KeyID+".pub")); err != nil {
+		t.Errorf("key pruned by a write that cannot fit: %v", err)
+	}
+
+	target.Config["maxBytes"] = "lots"
+	if err := p.Publish(context.Background(), testOwner, target, third.Public()); err == nil {
+		t.Error("expected error for invalid maxBytes, got nil")
+	}
+}
+
+func TestFilesystemPublisherQuotaPrunesOwnKeysOnly(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	p := NewFilesystemPublisher(validation.DefaultDeniedPublishPaths)
+	target := openukrv1alpha1.PublishTarget{
+		Type:   "filesystem",
+		Config: map[string]string{"path": dir, "maxBytes": "800", "pruneOldest": "true"},
+	}
+	other := Owner{NamespacedName: types.NamespacedName{Namespace: "default", Name: "other"}}
+
+	// Three keys fit, the oldest belonging to another profile
+	foreign, previous, old := generateTestKey(t), generateTestKey(t), generateTestKey(t)
+	for i, publish := range []struct {
+		owner Owner
+		kp    *crypto.KeyPair
+	}{{other, foreign}, {testOwner, previous}, {testOwner, old}} {
+		if err := p.Publish(context.Background(), publish.owner, target, publish.kp.Public()); err != nil {
+			t.Fatalf("Publish() error = %v", err)
+		}
+		past := time.Now().Add(-time.Duration(3-i) * time.Hour)
+		if err := os.Chtimes(filepath.Join(dir, publish.kp.KeyID+".pub"), past, past); err != nil {
+			t.Fatalf("Chtimes() error = %v", err)
+		}
+	}
+
+	// The previous key in its grace period is retained: only old can make room
+	owner := testOwner
+	owner.Retain = []string{previous.KeyID}
+	current := generateTestKey(t)
+	if err := p.Publish(context.Background(), owner, target, current.Public()); err != nil {
+		t.Fatalf("Publish() with pruning error = %v", err)
+	}
+	for keyID, want := range map[string]bool{foreign.KeyID: true, previous.KeyID: true, old.KeyID: false, current.KeyID: true} {
+		_, err := os.Stat(filepath.Join(dir, keyID+".pub"))
+		if got := err == nil; got != want {
+			t.Errorf("%s.pub present = %v, want %v", keyID, got, want)
+		}
+	}
+
+	// Nothing else is prunable
+	owner.Retain = append(owner.Retain, current.KeyID)
+	if err := p.Publish(context.Background(), owner, target, generateTestKey(t).Public()); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Publish() error = %v, want ErrQuotaExceeded", err)
+	}
+}
+
+func TestFilesystemPublisherQuotaConcurrent(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	p := NewFilesystemPublisher(validation.DefaultDeniedPublishPaths)
+	target := openukrv1alpha1.PublishTarget{
+		Type:   "filesystem",
+		Config: map[string]string{"path": dir, "maxBytes": "800", "pruneOldest": "true"},
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 32)
+	for i := range errs {
+		kp := generateTestKey(t)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = p.Publish(context.Background(), testOwner, target, kp.Public())
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	var used int64
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			t.Fatalf("Info() error = %v", err)
+		}
+		used += info.Size()
+	}
+	if used > 800 {
+		t.Errorf("publish path holds %d bytes, want at most maxBytes 800", used)
+	}
+
+	// No publish lost another's ownership record, or its key could never be pruned
+	data, err := os.ReadFile(filepath.Join(dir, ownersFile))
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	var owners map[string]string
+	if err := json.Unmarshal(data, &owners); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	for _, entry := range entries {
+		if name := entry.Name(); strings.HasSuffix(name, ".pub") && owners[name] != testOwner.String() {
+			t.Errorf("%s owner = %q, want %q", name, owners[name], testOwner)
+		}
+	}
+}
@@ -20,38 +20,163 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
 	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/output"
+	"github.com/openukr/openukr/pkg/validation"
 )
 
 // HTTPPublisher publishes public keys via HTTP/HTTPS POST.
 type HTTPPublisher struct {
 	k8sClient client.Client
 	client    *http.Client
+	hosts     *validation.HostPolicy
+	signer    *PayloadSigner
 }
 
-// NewHTTPPublisher creates a new HTTP publisher.
+// NewHTTPPublisher creates a new HTTP publisher denying
+// validation.DefaultDeniedPublishHosts.
 func NewHTTPPublisher(k8sClient client.Client) *HTTPPublisher {
-	return &HTTPPublisher{
+	return newHTTPPublisher(k8sClient, validation.DefaultHostPolicy())
+}
+
+// newHTTPPublisher creates an HTTP publisher that only connects to hosts
+// permitted by hosts. A nil policy allows every host.
+func newHTTPPublisher(k8sClient client.Client, hosts *validation.HostPolicy) *HTTPPublisher {
+	p := &HTTPPublisher{
 		k8sClient: k8sClient,
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		hosts:     hosts,
+	}
+	p.client = &http.Client{
+		Transport: p.transport(nil),
+		Timeout:   10 * time.Second,
+	}
+	return p
+}
+
+// transport returns an HTTP transport whose connections are checked against the
+// publisher's host policy (see hostGuardedTransport).
+func (p *HTTPPublisher) transport(tlsConfig *tls.Config) *http.Transport {
+	return hostGuardedTransport(p.hosts, tlsConfig)
+}
+
+// hostGuardedTransport returns a clone of http.DefaultTransport whose
+// connections are checked against hosts. The check runs on the resolved
+// address of every dial, so DNS rebinding between admission and publish cannot
+// reach a denied address. [SEC:S-3]
+// Proxies from HTTP_PROXY/HTTPS_PROXY are honored: the operator-configured
+// proxy is dialed without the check, and the target host is only checked by
+// name (HostPolicy.CheckHost) before the request.
+func hostGuardedTransport(hosts *validation.HostPolicy, tlsConfig *tls.Config) *http.Transport {
+	var proxies sync.Map // "host:port" of proxies returned for requests
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		proxy, err := http.ProxyFromEnvironment(req)
+		if proxy != nil {
+			proxies.Store(proxyAddr(proxy), struct{}{})
+		}
+		return proxy, err
+	}
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		dialer := &net.Dialer{Timeout: 10 * time.Second}
+		if _, ok := proxies.Load(address); !ok {
+			dialer.Control = func(_, resolved string, _ syscall.RawConn) error {
+				addrPort, err := netip.ParseAddrPort(resolved)
+				if err != nil {
+					return err
+				}
+				return hosts.CheckDial(host, addrPort.Addr())
+			}
+		}
+		return dialer.DialContext(ctx, network, address)
+	}
+	return transport
+}
+
+// proxyAddr returns the "host:port" address dialed for proxy.
+func proxyAddr(proxy *url.URL) string {
+	port := proxy.Port()
+	if port == "" {
+		port = map[string]string{"https": "443", "socks5": "1080"}[proxy.Scheme]
+		if port == "" {
+			port = "80"
+		}
 	}
+	return net.JoinHostPort(proxy.Hostname(), port)
 }
 
-// Publish POSTs the public key (PEM format) to the configured endpoint.
+// Publish POSTs the public key to the configured endpoint, once per output.
+// All outputs share the target's TLS configuration.
 // Config required: "endpoint" (URL).
-func (p *HTTPPublisher) Publish(ctx context.Context, target openukrv1alpha1.PublishTarget, kp *crypto.KeyPair) error {
-	endpoint, ok := target.Config["endpoint"]
+func (p *HTTPPublisher) Publish(
+	ctx context.Context,
+	owner Owner,
+	target openukrv1alpha1.PublishTarget,
+	pub *crypto.PublicKeyInfo,
+) error {
+	httpClient, err := p.clientFor(ctx, owner.Namespace, target)
+	if err != nil {
+		return err
+	}
+
+	// The fingerprint identifies the key independent of encoding; with the
+	// encoding it identifies each output, so receivers can dedupe retries [SEC:T-1]
+	fingerprint, err := crypto.ComputeFingerprint(pub.PublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to compute fingerprint: %w", err)
+	}
+
+	var errs []error
+	for i, out := range resolveOutputs(target) {
+		if err := p.publishOutput(ctx, httpClient, target, out, pub, fingerprint.String()); err != nil {
+			errs = append(errs, fmt.Errorf("output[%d] (%s): %w", i, out.encoding, err))
+		}
+	}
+	return joinOutputErrors(errs)
+}
+
+// contentTypes maps encodings to HTTP Content-Type headers.
+// DER is a bare SubjectPublicKeyInfo, not a certificate, so application/pkix-cert
+// would be wrong; no registered media type exists for SPKI.
+var contentTypes = map[string]string{
+	"PEM": "application/x-pem-file",
+	"DER": "application/octet-stream",
+	"JWK": "application/jwk+json",
+
+	crypto.EncodingECCompressed: "application/octet-stream",
+	crypto.EncodingSPKI:         "application/octet-stream",
+	crypto.EncodingRawPublic:    "application/octet-stream",
+}
+
+func (p *HTTPPublisher) publishOutput(
+	ctx context.Context,
+	httpClient *http.Client,
+	target openukrv1alpha1.PublishTarget,
+	out resolvedOutput,
+	pub *crypto.PublicKeyInfo,
+	fingerprint string,
+) error {
+	endpoint, ok := out.config["endpoint"]
 	if !ok || endpoint == "" {
 		return fmt.Errorf("missing 'endpoint' in config")
 	}
@@ -61,55 +186,53 @@ func (p *HTTPPublisher) Publish(ctx context.Context, target openukrv1alpha1.Publ
 	if !strings.HasPrefix(endpoint, "https://") && !isInsecure {
 		return fmt.Errorf("endpoint must use HTTPS (got %q); set insecureSkipVerify to allow HTTP", endpoint)
 	}
-
-	encoder, err := crypto.NewKeyEncoder("PEM")
+	u, err := url.Parse(endpoint)
 	if err != nil {
+		return fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+	}
+	if err := p.hosts.CheckHost(u.Hostname()); err != nil {
 		return err
 	}
 
-	pubPEM, err := encoder.EncodePublic(kp.PublicKey)
-	if err != nil {
-		return fmt.Errorf("failed to encode public key: %w", err)
+	contentType, ok := contentTypes[out.encoding]
+	if !ok {
+		return fmt.Errorf("unsupported encoding: %s", out.encoding)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(pubPEM))
+	body, err := encodePublic(pub, out.encoding)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return err
 	}
-
-	req.Header.Set("Content-Type", "application/x-pem-file")
-	req.Header.Set("X-Key-ID", kp.KeyID) // Add KeyID header for correlation
-
-	// Configure TLS client if specified
-	httpClient := p.client
-	if target.TLS != nil {
-		// Clone default transport to customize TLS per request
-		// [SEC:T-2] If customized transport is needed (e.g. mutual TLS) we must build it here.
-		// For MVP, we only support InsecureSkipVerify or system CA unless we load certs dynamically.
-
-		tlsConfig := &tls.Config{
-			MinVersion: tls.VersionTLS12,
+	var signature []byte
+	if p.signer != nil {
+		if signature, err = p.signer.Sign(ctx, pub.KeyID, body); err != nil {
+			return err
 		}
-
-		if target.TLS.InsecureSkipVerify {
-			tlsConfig.InsecureSkipVerify = true
-		} else {
-			// If CA secret is provided, we would load it here.
-			// This requires accessing k8sClient to get the secret.
-			// For this iteration, we focus on InsecureSkipVerify support.
-			// Full mTLS support is a future improvement.
+	}
+	if out.compress {
+		if body, err = output.Gzip(body); err != nil {
+			return fmt.Errorf("failed to compress body: %w", err)
 		}
+	}
 
-		transport := &http.Transport{
-			TLSClientConfig: tlsConfig,
-			// Copy other defaults from http.DefaultTransport if needed
-		}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
 
-		httpClient = &http.Client{
-			Transport: transport,
-			Timeout:   10 * time.Second,
-		}
+	req.Header.Set("Content-Type", contentType)
+	if out.compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	req.Header.Set("X-Key-ID", pub.KeyID) // Add KeyID header for correlation
+	if signature != nil {
+		req.Header.Set(SignatureHeader, base64.StdEncoding.EncodeToString(signature))
 	}
+	// Idempotency: servers that already hold this key in this encoding may
+	// answer 304 Not Modified. Outputs sharing an endpoint differ in the key.
+	key := idempotencyKey(fingerprint, out.encoding)
+	req.Header.Set("X-Idempotency-Key", key)
+	req.Header.Set("If-None-Match", strconv.Quote(key))
 
 	resp, err := httpClient.Do(req) // #nosec G704 -- Endpoint is controlled by CRD admin, HTTPS enforced
 	if err != nil {
@@ -121,9 +244,50 @@ func (p *HTTPPublisher) Publish(ctx context.Context, target openukrv1alpha1.Publ
 	const maxResponseBody = 1 << 20 // 1 MB
 	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil // Key already present
+	}
 	if resp.StatusCode >= 400 {
 		return fmt.Errorf("server returned error: %s", resp.Status)
 	}
 
 	return nil
 }
+
+// idempotencyKey identifies one published representation of a key, e.g.
+// "SHA256:...;JWK".
+func idempotencyKey(fingerprint, encoding string) string {
+	return fingerprint + ";" + encoding
+}
+
+// clientFor returns the HTTP client for the target's TLS configuration.
+// CA Secrets are resolved in namespace, the publishing KeyProfile's.
+func (p *HTTPPublisher) clientFor(ctx context.Context, namespace string, target openukrv1alpha1.PublishTarget) (*http.Client, error) {
+	if target.TLS == nil {
+		return p.client, nil
+	}
+
+	// Clone default transport to customize TLS per request
+	// [SEC:T-2] If customized transport is needed (e.g. mutual TLS) we must build it here.
+	// Client certificates (mTLS) are a future improvement.
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if target.TLS.InsecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	} else if refs := caSecretRefs(target.TLS); len(refs) > 0 {
+		// Without CA Secrets the system roots are used
+		pool, err := loadCAPool(ctx, p.k8sClient, namespace, refs)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return &http.Client{
+		Transport: p.transport(tlsConfig),
+		Timeout:   10 * time.Second,
+	}, nil
+}
@@ -0,0 +1,345 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/validation"
+)
+
+func TestHTTPPublisherMultipleOutputs(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	bodies := map[string][]byte{}
+	contentTypes := map[string]string{}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		bodies[r.URL.Path] = body
+		contentTypes[r.URL.Path] = r.Header.Get("Content-Type")
+	}))
+	defer srv.Close()
+
+	kp := generateTestKey(t)
+	target := openukrv1alpha1.PublishTarget{
+		Type: "http",
+		Outputs: []openukrv1alpha1.PublishOutput{
+			{Encoding: "PEM", Config: map[string]string{"endpoint": srv.URL + "/pem"}},
+			{Encoding: "JWK", Config: map[string]string{"endpoint": srv.URL + "/jwk"}},
+		},
+		TLS: &openukrv1alpha1.TLSConfig{InsecureSkipVerify: true},
+	}
+
+	if err := NewHTTPPublisher(nil).Publish(context.Background(), testOwner, target, kp.Public()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if block, _ := pem.Decode(bodies["/pem"]); block == nil || block.Type != "PUBLIC KEY" {
+		t.Errorf("/pem body is not a PEM public key: %q", bodies["/pem"])
+	}
+	if got := contentTypes["/pem"]; got != "application/x-pem-file" {
+		t.Errorf("/pem Content-Type = %q, want application/x-pem-file", got)
+	}
+
+	var jwk map[string]any
+	if err := json.Unmarshal(bodies["/jwk"], &jwk); err != nil || jwk["kty"] != "EC" {
+		t.Errorf("/jwk body is not an EC JWK: %q (err %v)", bodies["/jwk"], err)
+	}
+	if _, ok := jwk["d"]; ok {
+		t.Errorf("/jwk body contains private component")
+	}
+	if got := contentTypes["/jwk"]; got != "application/jwk+json" {
+		t.Errorf("/jwk Content-Type = %q, want application/jwk+json", got)
+	}
+}
+
+func TestHTTPPublisherCollectsOutputErrors(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	kp := generateTestKey(t)
+	target := openukrv1alpha1.PublishTarget{
+		Type: "http",
+		Outputs: []openukrv1alpha1.PublishOutput{
+			{Encoding: "PEM", Config: map[string]string{"endpoint": srv.URL + "/ok"}},
+			{Encoding: "JWK", Config: map[string]string{"endpoint": srv.URL + "/fail"}},
+		},
+		TLS: &openukrv1alpha1.TLSConfig{InsecureSkipVerify: true},
+	}
+
+	if err := NewHTTPPublisher(nil).Publish(context.Background(), testOwner, target, kp.Public()); err == nil {
+		t.Fatal("Publish() succeeded, want error for failing output")
+	}
+}
+
+func TestHTTPPublisherContentTypePerEncoding(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		encoding        string
+		wantContentType string
+	}{
+		{encoding: "PEM", wantContentType: "application/x-pem-file"},
+		{encoding: "DER", wantContentType: "application/octet-stream"},
+		{encoding: "JWK", wantContentType: "application/jwk+json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			t.Parallel()
+			var mu sync.Mutex
+			var got string
+			srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+				got = r.Header.Get("Content-Type")
+			}))
+			defer srv.Close()
+
+			kp := generateTestKey(t)
+			target := openukrv1alpha1.PublishTarget{
+				Type: "http",
+				Outputs: []openukrv1alpha1.PublishOutput{
+					{Encoding: tt.encoding, Config: map[string]string{"endpoint": srv.URL}},
+				},
+				TLS: &openukrv1alpha1.TLSConfig{InsecureSkipVerify: true},
+			}
+			if err := NewHTTPPublisher(nil).Publish(context.Background(), testOwner, target, kp.Public()); err != nil {
+				t.Fatalf("Publish() error = %v", err)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+		})
+	}
+}
+
+func TestHTTPPublisherTreatsNotModifiedAsSuccess(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	var statuses []int
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := r.Header.Get("X-Idempotency-Key")
+		if seen[key] {
+			statuses = append(statuses, http.StatusNotModified)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		seen[key] = true
+		statuses = append(statuses, http.StatusCreated)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	kp := generateTestKey(t)
+	target := openukrv1alpha1.PublishTarget{
+		Type:   "http",
+		Config: map[string]string{"endpoint": srv.URL},
+		TLS:    &openukrv1alpha1.TLSConfig{InsecureSkipVerify: true},
+	}
+	p := NewHTTPPublisher(nil)
+	for i := range 2 {
+		if err := p.Publish(context.Background(), testOwner, target, kp.Public()); err != nil {
+			t.Fatalf("Publish() attempt %d error = %v", i+1, err)
+		}
+	}
+
+	fingerprint, err := crypto.ComputeFingerprint(kp.PublicKey)
+	if err != nil {
+		t.Fatalf("ComputeFingerprint() error = %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if want := fingerprint.String() + ";PEM"; !seen[want] || len(seen) != 1 {
+		t.Errorf("idempotency keys = %v, want only %q", seen, want)
+	}
+	if len(statuses) != 2 || statuses[1] != http.StatusNotModified {
+		t.Errorf("statuses = %v, want [201 304]", statuses)
+	}
+}
+
+func TestHTTPPublisherIdempotencyPerEncoding(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	stored := map[string]string{} // If-None-Match → Content-Type
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		tag := r.Header.Get("If-None-Match")
+		if _, ok := stored[tag]; ok {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		stored[tag] = r.Header.Get("Content-Type")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	kp := generateTestKey(t)
+	target := openukrv1alpha1.PublishTarget{
+		Type:    "http",
+		Config:  map[string]string{"endpoint": srv.URL},
+		Outputs: []openukrv1alpha1.PublishOutput{{Encoding: "PEM"}, {Encoding: "JWK"}},
+		TLS:     &openukrv1alpha1.TLSConfig{InsecureSkipVerify: true},
+	}
+	if err := NewHTTPPublisher(nil).Publish(context.Background(), testOwner, target, kp.Public()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// A 304 for the second encoding would leave the JWK unpublished
+	if len(stored) != 2 {
+		t.Errorf("server stored %v, want both encodings", stored)
+	}
+}
+
+func TestHTTPPublisherCompressedOutput(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var encoding string
+	var body []byte
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		encoding = r.Header.Get("Content-Encoding")
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	kp := generateTestKey(t)
+	target := openukrv1alpha1.PublishTarget{
+		Type: "http",
+		Outputs: []openukrv1alpha1.PublishOutput{
+			{Encoding: "JWK", Compress: true, Config: map[string]string{"endpoint": srv.URL}},
+		},
+		TLS: &openukrv1alpha1.TLSConfig{InsecureSkipVerify: true},
+	}
+	if err := NewHTTPPublisher(nil).Publish(context.Background(), testOwner, target, kp.Public()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if encoding != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", encoding)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("gzip.NewReader() error = %v", err)
+	}
+	plain, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	var jwk map[string]any
+	if err := json.Unmarshal(plain, &jwk); err != nil || jwk["kty"] != "EC" {
+		t.Errorf("decompressed body is not an EC JWK: %q (err %v)", plain, err)
+	}
+}
+
+func TestHTTPPublisherEnforcesHostPolicyOnDial(t *testing.T) {
+	t.Parallel()
+
+	var requests int
+	var mu sync.Mutex
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+	}))
+	defer srv.Close()
+
+	// The hostname passes the name check; its resolved loopback address is denied
+	endpoint := strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)
+	target := openukrv1alpha1.PublishTarget{
+		Type:   "http",
+		Config: map[string]string{"endpoint": endpoint},
+		TLS:    &openukrv1alpha1.TLSConfig{InsecureSkipVerify: true},
+	}
+	kp := generateTestKey(t)
+
+	denied, err := validation.NewHostPolicy(nil, []string{"127.0.0.0/8", "::1/128"})
+	if err != nil {
+		t.Fatalf("NewHostPolicy() error = %v", err)
+	}
+	err = newHTTPPublisher(nil, denied).Publish(context.Background(), testOwner, target, kp.Public())
+	if err == nil || !strings.Contains(err.Error(), "denied by cluster policy") {
+		t.Errorf("Publish() error = %v, want denied by cluster policy", err)
+	}
+	mu.Lock()
+	if requests != 0 {
+		t.Errorf("denied host received %d requests", requests)
+	}
+	mu.Unlock()
+
+	// The default policy only denies link-local and metadata addresses
+	if err := NewHTTPPublisher(nil).Publish(context.Background(), testOwner, target, kp.Public()); err != nil {
+		t.Errorf("Publish() with default policy error = %v", err)
+	}
+}
+
+func TestProxyAddr(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"http://proxy.internal:3128": "proxy.internal:3128",
+		"http://proxy.internal":      "proxy.internal:80",
+		"https://proxy.internal":     "proxy.internal:443",
+		"socks5://10.0.0.1":          "10.0.0.1:1080",
+		"http://[fd00::1]":           "[fd00::1]:80",
+	}
+	for raw, want := range tests {
+		proxy, err := url.Parse(raw)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", raw, err)
+		}
+		if got := proxyAddr(proxy); got != want {
+			t.Errorf("proxyAddr(%s) = %q, want %q", raw, got, want)
+		}
+	}
+}
@@ -19,46 +19,242 @@ package publish
 import (
 	"context"
 	"fmt"
+	"maps"
+	"runtime/debug"
+	"slices"
+	"time"
 
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
 	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/metrics"
+	"github.com/openukr/openukr/pkg/validation"
 )
 
+// Built-in publish target types.
+const (
+	TargetTypeAzureKeyVault = "azurekeyvault"
+	TargetTypeFilesystem    = "filesystem"
+	TargetTypeHTTP          = "http"
+)
+
+// builtinPublishers returns the publishers of the built-in target types, keyed
+// by type. Options may replace them but never add types.
+func builtinPublishers(k8sClient client.Client) map[string]Publisher {
+	return map[string]Publisher{
+		TargetTypeFilesystem:    NewFilesystemPublisher(validation.DefaultDeniedPublishPaths),
+		TargetTypeHTTP:          NewHTTPPublisher(k8sClient),
+		TargetTypeAzureKeyVault: NewAzureKeyVaultPublisher(k8sClient),
+	}
+}
+
+// SupportedTargetTypes returns the publish target types PublishAll accepts, sorted.
+func SupportedTargetTypes() []string {
+	return slices.Sorted(maps.Keys(builtinPublishers(nil)))
+}
+
+// DefaultConcurrency is the default number of targets published in parallel.
+const DefaultConcurrency = 4
+
 // Manager orchestrates key publishing to multiple targets.
 type Manager struct {
-	publishers map[string]Publisher
+	k8sClient   client.Client
+	publishers  map[string]Publisher
+	concurrency int
+	breaker     *circuitBreaker
+	signer      *PayloadSigner
+	// tolerateUnknown skips targets of unknown type instead of failing them
+	tolerateUnknown bool
+}
+
+// Option configures optional behavior of the Manager.
+type Option func(*Manager)
+
+// WithConcurrency bounds how many targets are published in parallel.
+// Values below 1 publish sequentially.
+func WithConcurrency(n int) Option {
+	return func(m *Manager) {
+		m.concurrency = max(n, 1)
+	}
+}
+
+// WithDeniedPublishPaths replaces the directory prefixes the filesystem publisher
+// refuses to write under. An empty list disables the check.
+func WithDeniedPublishPaths(prefixes []string) Option {
+	return func(m *Manager) {
+		m.publishers[TargetTypeFilesystem] = NewFilesystemPublisher(prefixes)
+	}
+}
+
+// WithPublishHostPolicy replaces the host policy HTTP and Azure Key Vault
+// publishing is restricted to. A nil policy allows every host.
+func WithPublishHostPolicy(policy *validation.HostPolicy) Option {
+	return func(m *Manager) {
+		m.publishers[TargetTypeHTTP] = newHTTPPublisher(m.k8sClient, policy)
+		m.publishers[TargetTypeAzureKeyVault] = newAzureKeyVaultPublisher(m.k8sClient, policy)
+	}
+}
+
+// WithCircuitBreaker skips a target for cooldown once it failed threshold times
+// in a row, so a dead endpoint stops adding its timeout to every publish.
+// A threshold below 1 disables the breaker.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(m *Manager) {
+		if threshold < 1 {
+			m.breaker = nil
+			return
+		}
+		m.breaker = newCircuitBreaker(threshold, cooldown, clock.RealClock{})
+	}
+}
+
+// WithPayloadSigner makes the HTTP and filesystem publishers attach a detached
+// signature to every published key (see PayloadSigner).
+func WithPayloadSigner(signer *PayloadSigner) Option {
+	return func(m *Manager) {
+		m.signer = signer
+	}
+}
+
+// WithTolerateUnknownPublishers makes PublishAll skip targets of an unknown
+// publisher type (see TargetResult.Skipped) instead of failing the publish.
+// An unknown type at runtime means the CRD is newer than the controller; the
+// admission webhook rejects them otherwise.
+func WithTolerateUnknownPublishers(tolerate bool) Option {
+	return func(m *Manager) {
+		m.tolerateUnknown = tolerate
+	}
 }
 
 // NewManager creates a new Manager.
-func NewManager(k8sClient client.Client) *Manager {
-	return &Manager{
-		publishers: map[string]Publisher{
-			"filesystem": NewFilesystemPublisher(),
-			"http":       NewHTTPPublisher(k8sClient),
-		},
+func NewManager(k8sClient client.Client, opts ...Option) *Manager {
+	m := &Manager{
+		k8sClient:   k8sClient,
+		publishers:  builtinPublishers(k8sClient),
+		concurrency: DefaultConcurrency,
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	// Applied last: other options may replace these publishers
+	if p, ok := m.publishers[TargetTypeHTTP].(*HTTPPublisher); ok {
+		p.signer = m.signer
+	}
+	if p, ok := m.publishers[TargetTypeFilesystem].(*FilesystemPublisher); ok {
+		p.signer = m.signer
+	}
+	return m
 }
 
-// PublishAll publishes the key pair to all configured targets.
-// It iterates over targets and delegates to the appropriate publisher implementation.
-func (m *Manager) PublishAll(ctx context.Context, targets []openukrv1alpha1.PublishTarget, kp *crypto.KeyPair) error {
-	var errs []error
+// PublishAll publishes the public key to all configured targets.
+// Targets are published in parallel (bounded by the configured concurrency),
+// so total latency is bounded by the slowest target rather than the sum.
+// Every target is attempted; errors are aggregated in target order, except
+// for targets with FailurePolicy "Ignore", whose errors are only reported in
+// their result. A panicking publisher fails only its own target. With
+// WithTolerateUnknownPublishers, targets of unknown type are skipped rather
+// than failed.
+// The returned results hold one entry per target, in target order.
+func (m *Manager) PublishAll(
+	ctx context.Context,
+	owner Owner,
+	targets []openukrv1alpha1.PublishTarget,
+	pub *crypto.PublicKeyInfo,
+) ([]TargetResult, error) {
+	if pub == nil || pub.PublicKey == nil {
+		return nil, fmt.Errorf("cannot publish: public key is nil")
+	}
+	// [SEC:S-2] Publishers must only ever receive public key material
+	if crypto.IsPrivateKey(pub.PublicKey) {
+		return nil, fmt.Errorf("refusing to publish: key material is private (%T)", pub.PublicKey)
+	}
+
+	// One slot per target keeps error ordering deterministic
+	targetErrs := make([]error, len(targets))
+	openUntil := make([]time.Time, len(targets))
+	skipped := make([]string, len(targets))
+	results := make([]TargetResult, len(targets))
+
+	var g errgroup.Group
+	g.SetLimit(max(m.concurrency, 1))
 	for i, target := range targets {
-		pub, ok := m.publishers[target.Type]
+		publisher, ok := m.publishers[target.Type]
+		if !ok && m.tolerateUnknown {
+			skipped[i] = fmt.Sprintf("unknown publisher type %q", target.Type)
+			continue
+		}
 		if !ok {
-			errs = append(errs, fmt.Errorf("target[%d]: unknown publisher type %q", i, target.Type))
+			targetErrs[i] = fmt.Errorf("target[%d]: unknown publisher type %q", i, target.Type)
 			continue
 		}
 
-		if err := pub.Publish(ctx, target, kp); err != nil {
-			errs = append(errs, fmt.Errorf("target[%d] (%s) failed: %w", i, target.Type, err))
+		key := circuitKey(owner.Namespace, target)
+		if ok, until := m.breaker.allow(key); !ok {
+			targetErrs[i] = fmt.Errorf("target[%d] (%s) skipped until %s: %w",
+				i, target.Type, until.UTC().Format(time.RFC3339), ErrCircuitOpen)
+			openUntil[i] = until
+			continue
+		}
+
+		g.Go(func() error {
+			err := safePublish(ctx, publisher, owner, target, pub)
+			openUntil[i] = m.breaker.record(key, owner.NamespacedName, err)
+			if err != nil {
+				targetErrs[i] = fmt.Errorf("target[%d] (%s) failed: %w", i, target.Type, err)
+			}
+			return nil // never cancel sibling targets
+		})
+	}
+	_ = g.Wait()
+
+	var errs []error
+	for i, err := range targetErrs {
+		results[i] = TargetResult{
+			Type:             targets[i].Type,
+			Target:           describeTarget(targets[i]),
+			Err:              err,
+			CircuitOpenUntil: openUntil[i],
+			Skipped:          skipped[i],
+		}
+		if err != nil && targets[i].FailurePolicy != openukrv1alpha1.FailurePolicyIgnore {
+			errs = append(errs, err)
 		}
 	}
 
 	if len(errs) > 0 {
-		return fmt.Errorf("publish errors: %v", errs)
+		return results, fmt.Errorf("publish errors: %v", errs)
 	}
-	return nil
+	return results, nil
+}
+
+// Forget drops the publish state kept for a deleted profile: its failures no
+// longer hold a target's circuit open.
+func (m *Manager) Forget(owner types.NamespacedName) {
+	m.breaker.forget(owner)
+}
+
+// safePublish calls publisher.Publish, converting a panic into an error so a
+// buggy publisher cannot crash the controller. The panic is logged with its
+// stack trace and counted in metrics.PublishPanicsTotal.
+func safePublish(
+	ctx context.Context,
+	publisher Publisher,
+	owner Owner,
+	target openukrv1alpha1.PublishTarget,
+	pub *crypto.PublicKeyInfo,
+) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			metrics.PublishPanicsTotal.WithLabelValues(target.Type).Inc()
+			logf.FromContext(ctx).Error(fmt.Errorf("%v", r), "Publisher panicked",
+				"type", target.Type, "stack", string(debug.Stack()))
+			err = fmt.Errorf("publisher panicked: %v", r)
+		}
+	}()
+	return publisher.Publish(ctx, owner, target, pub)
 }
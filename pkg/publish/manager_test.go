@@ -0,0 +1,269 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/types"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/metrics"
+)
+
+// testOwner is the KeyProfile the tests publish for.
+var testOwner = Owner{NamespacedName: types.NamespacedName{Namespace: "default", Name: "test"}}
+
+// recordingPublisher captures what it receives from the Manager.
+type recordingPublisher struct {
+	mu       sync.Mutex
+	received []*crypto.PublicKeyInfo
+}
+
+func (p *recordingPublisher) Publish(
+	_ context.Context,
+	_ Owner,
+	_ openukrv1alpha1.PublishTarget,
+	pub *crypto.PublicKeyInfo,
+) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.received = append(p.received, pub)
+	return nil
+}
+
+func generateTestKey(t *testing.T) *crypto.KeyPair {
+	t.Helper()
+	kp, err := crypto.NewKeyGenerator().Generate(crypto.GenerateOptions{
+		Algorithm: crypto.AlgorithmEC,
+		Params:    map[string]string{"curve": crypto.CurveP256},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	t.Cleanup(kp.Wipe)
+	return kp
+}
+
+func TestPublishAllOnlyPublicMaterial(t *testing.T) {
+	t.Parallel()
+
+	kp := generateTestKey(t)
+	rec := &recordingPublisher{}
+	m := &Manager{publishers: map[string]Publisher{"test": rec}, concurrency: DefaultConcurrency}
+	targets := []openukrv1alpha1.PublishTarget{{Type: "test"}}
+
+	if _, err := m.PublishAll(context.Background(), testOwner, targets, kp.Public()); err != nil {
+		t.Fatalf("PublishAll() error = %v", err)
+	}
+	if len(rec.received) != 1 {
+		t.Fatalf("publisher received %d keys, want 1", len(rec.received))
+	}
+	if crypto.IsPrivateKey(rec.received[0].PublicKey) {
+		t.Errorf("publisher received private key material: %T", rec.received[0].PublicKey)
+	}
+}
+
+func TestPublishAllRejectsPrivateMaterial(t *testing.T) {
+	t.Parallel()
+
+	kp := generateTestKey(t)
+	rec := &recordingPublisher{}
+	m := &Manager{publishers: map[string]Publisher{"test": rec}, concurrency: DefaultConcurrency}
+	targets := []openukrv1alpha1.PublishTarget{{Type: "test"}}
+
+	leaked := &crypto.PublicKeyInfo{KeyID: kp.KeyID, PublicKey: kp.PrivateKey}
+	if _, err := m.PublishAll(context.Background(), testOwner, targets, leaked); err == nil {
+		t.Fatal("PublishAll() with private key material succeeded, want error")
+	}
+	if len(rec.received) != 0 {
+		t.Errorf("publisher was invoked %d times, want 0", len(rec.received))
+	}
+}
+
+// sleepingPublisher simulates a slow target.
+type sleepingPublisher struct {
+	delay time.Duration
+}
+
+func (p *sleepingPublisher) Publish(ctx context.Context, _ Owner, _ openukrv1alpha1.PublishTarget, _ *crypto.PublicKeyInfo) error {
+	select {
+	case <-time.After(p.delay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestPublishAllParallel(t *testing.T) {
+	t.Parallel()
+
+	const delay = 200 * time.Millisecond
+	kp := generateTestKey(t)
+	m := &Manager{
+		publishers:  map[string]Publisher{"slow": &sleepingPublisher{delay: delay}},
+		concurrency: DefaultConcurrency,
+	}
+	targets := []openukrv1alpha1.PublishTarget{{Type: "slow"}, {Type: "slow"}, {Type: "slow"}}
+
+	start := time.Now()
+	if _, err := m.PublishAll(context.Background(), testOwner, targets, kp.Public()); err != nil {
+		t.Fatalf("PublishAll() error = %v", err)
+	}
+	elapsed := time.Since(start)
+
+	// Sequential publishing would take 3×delay
+	if elapsed >= 2*delay {
+		t.Errorf("PublishAll() took %s, want < %s (bounded by slowest target)", elapsed, 2*delay)
+	}
+}
+
+func TestPublishAllAggregatesErrors(t *testing.T) {
+	t.Parallel()
+
+	kp := generateTestKey(t)
+	m := &Manager{
+		publishers:  map[string]Publisher{"test": &recordingPublisher{}},
+		concurrency: DefaultConcurrency,
+	}
+	targets := []openukrv1alpha1.PublishTarget{{Type: "unknown"}, {Type: "test"}, {Type: "missing"}}
+
+	results, err := m.PublishAll(context.Background(), testOwner, targets, kp.Public())
+	if err == nil {
+		t.Fatal("PublishAll() succeeded, want aggregated error")
+	}
+	if len(results) != len(targets) {
+		t.Fatalf("PublishAll() returned %d results, want %d", len(results), len(targets))
+	}
+	if results[0].Err == nil || results[1].Err != nil || results[2].Err == nil {
+		t.Errorf("PublishAll() results = %+v, want errors for targets 0 and 2 only", results)
+	}
+	for _, want := range []string{"target[0]", "target[2]"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("PublishAll() error = %q, want it to mention %s", err, want)
+		}
+	}
+}
+
+func TestPublishAllFailurePolicyIgnore(t *testing.T) {
+	t.Parallel()
+
+	kp := generateTestKey(t)
+	m := &Manager{
+		publishers:  map[string]Publisher{"test": &recordingPublisher{}},
+		concurrency: DefaultConcurrency,
+	}
+	targets := []openukrv1alpha1.PublishTarget{
+		{Type: "test"},
+		{Type: "missing", FailurePolicy: openukrv1alpha1.FailurePolicyIgnore},
+	}
+
+	results, err := m.PublishAll(context.Background(), testOwner, targets, kp.Public())
+	if err != nil {
+		t.Fatalf("PublishAll() error = %v, want the ignored target's failure tolerated", err)
+	}
+	if results[1].Err == nil {
+		t.Error("ignored target failure not reported in its result")
+	}
+
+	targets[1].FailurePolicy = openukrv1alpha1.FailurePolicyFail
+	if _, err := m.PublishAll(context.Background(), testOwner, targets, kp.Public()); err == nil {
+		t.Error("PublishAll() succeeded, want the Fail target's error")
+	}
+}
+
+func TestPublishAllToleratesUnknownTypes(t *testing.T) {
+	t.Parallel()
+
+	kp := generateTestKey(t)
+	rec := &recordingPublisher{}
+	m := &Manager{
+		publishers:  map[string]Publisher{"test": rec},
+		concurrency: DefaultConcurrency,
+	}
+	WithTolerateUnknownPublishers(true)(m)
+	targets := []openukrv1alpha1.PublishTarget{{Type: "future"}, {Type: "test"}}
+
+	results, err := m.PublishAll(context.Background(), testOwner, targets, kp.Public())
+	if err != nil {
+		t.Fatalf("PublishAll() error = %v, want unknown types skipped", err)
+	}
+	if results[0].Err != nil || !strings.Contains(results[0].Skipped, `"future"`) {
+		t.Errorf("results[0] = %+v, want skipped without error", results[0])
+	}
+	if results[1].Err != nil || results[1].Skipped != "" {
+		t.Errorf("results[1] = %+v, want published", results[1])
+	}
+	if len(rec.received) != 1 {
+		t.Errorf("known target received %d keys, want 1", len(rec.received))
+	}
+}
+
+// panickingPublisher simulates a buggy third-party publisher.
+type panickingPublisher struct{}
+
+func (panickingPublisher) Publish(context.Context, Owner, openukrv1alpha1.PublishTarget, *crypto.PublicKeyInfo) error {
+	panic("boom")
+}
+
+func TestPublishAllRecoversPanic(t *testing.T) {
+	t.Parallel()
+
+	kp := generateTestKey(t)
+	rec := &recordingPublisher{}
+	m := &Manager{
+		publishers:  map[string]Publisher{"panicking": panickingPublisher{}, "test": rec},
+		concurrency: DefaultConcurrency,
+	}
+	before := testutil.ToFloat64(metrics.PublishPanicsTotal.WithLabelValues("panicking"))
+	targets := []openukrv1alpha1.PublishTarget{{Type: "panicking"}, {Type: "test"}}
+
+	results, err := m.PublishAll(context.Background(), testOwner, targets, kp.Public())
+	if err == nil || !strings.Contains(err.Error(), "panicked: boom") {
+		t.Fatalf("PublishAll() error = %v, want the recovered panic", err)
+	}
+	if results[0].Err == nil || results[1].Err != nil {
+		t.Errorf("PublishAll() results = %+v, want only the panicking target to fail", results)
+	}
+	if len(rec.received) != 1 {
+		t.Errorf("sibling target received %d keys, want 1", len(rec.received))
+	}
+	if got := testutil.ToFloat64(metrics.PublishPanicsTotal.WithLabelValues("panicking")) - before; got != 1 {
+		t.Errorf("openukr_publish_panics_total increased by %v, want 1", got)
+	}
+}
+
+func TestSupportedTargetTypes(t *testing.T) {
+	t.Parallel()
+
+	types := SupportedTargetTypes()
+	m := NewManager(nil)
+	if len(types) != len(m.publishers) {
+		t.Errorf("SupportedTargetTypes() = %v, but Manager has %d publishers", types, len(m.publishers))
+	}
+	for _, typ := range types {
+		if _, ok := m.publishers[typ]; !ok {
+			t.Errorf("SupportedTargetTypes() lists %q, which has no publisher", typ)
+		}
+	}
+}
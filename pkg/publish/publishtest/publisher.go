@@ -0,0 +1,112 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package publishtest provides test doubles for the publish package.
+package publishtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/publish"
+)
+
+// FakePublisher is a recording publisher. It implements publish.Publisher for a
+// single target and PublishAll, the interface the rotation manager publishes
+// through. Configure it through its exported fields before use and read them
+// once the code under test returns.
+type FakePublisher struct {
+	mu sync.Mutex
+
+	// KeyIDs records the key ID of every Publish and PublishAll call.
+	KeyIDs []string
+	// Targets records every target published to, in call order.
+	Targets []openukrv1alpha1.PublishTarget
+
+	// Err, if set, fails every target.
+	Err error
+	// TypeErrs fails targets of the given type; it takes precedence over Err.
+	TypeErrs map[string]error
+}
+
+var _ publish.Publisher = (*FakePublisher)(nil)
+
+// Publish records a single-target publish and fails as configured.
+func (p *FakePublisher) Publish(
+	_ context.Context,
+	_ publish.Owner,
+	target openukrv1alpha1.PublishTarget,
+	pub *crypto.PublicKeyInfo,
+) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := checkPublic(pub); err != nil {
+		return err
+	}
+	p.KeyIDs = append(p.KeyIDs, pub.KeyID)
+	return p.publishLocked(target)
+}
+
+// PublishAll records one call for all targets and returns one result per target,
+// with errors aggregated like publish.Manager.
+func (p *FakePublisher) PublishAll(
+	_ context.Context,
+	_ publish.Owner,
+	targets []openukrv1alpha1.PublishTarget,
+	pub *crypto.PublicKeyInfo,
+) ([]publish.TargetResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := checkPublic(pub); err != nil {
+		return nil, err
+	}
+	p.KeyIDs = append(p.KeyIDs, pub.KeyID)
+
+	results := make([]publish.TargetResult, len(targets))
+	var errs []error
+	for i, target := range targets {
+		results[i] = publish.TargetResult{Type: target.Type, Err: p.publishLocked(target)}
+		if results[i].Err != nil {
+			errs = append(errs, fmt.Errorf("target[%d] (%s) failed: %w", i, target.Type, results[i].Err))
+		}
+	}
+	if len(errs) > 0 {
+		return results, fmt.Errorf("publish errors: %v", errs)
+	}
+	return results, nil
+}
+
+func (p *FakePublisher) publishLocked(target openukrv1alpha1.PublishTarget) error {
+	p.Targets = append(p.Targets, target)
+	if err, ok := p.TypeErrs[target.Type]; ok {
+		return err
+	}
+	return p.Err
+}
+
+// checkPublic enforces the publish contract that only public keys are passed. [SEC:S-2]
+func checkPublic(pub *crypto.PublicKeyInfo) error {
+	if pub == nil || pub.PublicKey == nil {
+		return fmt.Errorf("publishtest: public key is nil")
+	}
+	if crypto.IsPrivateKey(pub.PublicKey) {
+		return fmt.Errorf("publishtest: received private key material (%T)", pub.PublicKey)
+	}
+	return nil
+}
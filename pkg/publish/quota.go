@@ -0,0 +1,221 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openukr/openukr/pkg/validation"
+)
+
+// ErrQuotaExceeded reports that publishing would grow a filesystem target's
+// publish path beyond its "maxBytes" quota.
+var ErrQuotaExceeded = errors.New("publish quota exceeded")
+
+// quota limits the total size of a filesystem publish path, e.g. a small
+// tmpfs credentials directory.
+type quota struct {
+	maxBytes int64
+	// prune removes the oldest key files instead of failing when over quota
+	prune bool
+}
+
+// parseQuota reads the optional "maxBytes" and "pruneOldest" config of a
+// filesystem output. A nil quota means unlimited.
+func parseQuota(config map[string]string) (*quota, error) {
+	value := config["maxBytes"]
+	if value == "" {
+		return nil, nil
+	}
+	maxBytes, err := validation.ParsePublishMaxBytes(value)
+	if err != nil {
+		return nil, err
+	}
+	q := &quota{maxBytes: maxBytes}
+	if v := config["pruneOldest"]; v != "" {
+		if q.prune, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid pruneOldest %q: %w", v, err)
+		}
+	}
+	return q, nil
+}
+
+// ownersFile records, per publish path with a quota, which KeyProfile
+// published each key file so pruning only removes a profile's own files.
+const ownersFile = ".openukr-owners.json"
+
+// pathLocks serializes publishes to a publish path with a quota; parallel
+// publishes would otherwise each count on room only one of them has.
+var pathLocks sync.Map // cleaned publish path → *sync.Mutex
+
+// lockPath locks dir for quota accounting and returns the unlock function.
+func lockPath(dir string) func() {
+	v, _ := pathLocks.LoadOrStore(dir, &sync.Mutex{})
+	mu := v.(*sync.Mutex)
+	mu.Lock()
+	return mu.Unlock
+}
+
+// readOwners returns the owners of the key files under dir, keyed by their
+// path relative to dir.
+func readOwners(dir string) (map[string]string, error) {
+	owners := map[string]string{}
+	data, err := os.ReadFile(filepath.Join(dir, ownersFile))
+	if os.IsNotExist(err) {
+		return owners, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", ownersFile, err)
+	}
+	if err := json.Unmarshal(data, &owners); err != nil {
+		return nil, fmt.Errorf("invalid %s in %s: %w", ownersFile, dir, err)
+	}
+	return owners, nil
+}
+
+// quotaFile is a key file found under the publish path. size includes its signature.
+type quotaFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// reserve makes room under dir for writes, mapping file paths to their new
+// sizes, and records owner as the publisher of filename. Files being replaced
+// do not count towards the current usage. When the writes do not fit and
+// pruning is enabled, the oldest files with extension ext directly under dir
+// (older keys' history) that owner published are removed with their
+// signatures; keys owner retains are never removed. ErrQuotaExceeded is
+// returned, with nothing removed, if the writes do not fit even after pruning
+// or pruning is disabled. The caller must hold lockPath(dir).
+func (q *quota) reserve(dir, ext string, owner Owner, filename string, writes map[string]int64) error {
+	if q == nil {
+		return nil
+	}
+
+	owners, err := readOwners(dir)
+	if err != nil {
+		return err
+	}
+	ownerName := owner.String()
+	var used, incoming int64
+	var candidates []quotaFile
+	live := map[string]string{}
+	err = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || path == filepath.Join(dir, ownersFile) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if o, ok := owners[rel]; ok {
+			live[rel] = o
+		}
+		if _, replaced := writes[path]; replaced {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		used += info.Size()
+		keyID, isKey := strings.CutSuffix(filepath.Base(path), "."+ext)
+		if isKey && filepath.Dir(path) == dir && owners[rel] == ownerName && !owner.retains(keyID) {
+			f := quotaFile{path: path, size: info.Size(), modTime: info.ModTime()}
+			if sig, err := os.Stat(path + SignatureFileSuffix); err == nil {
+				f.size += sig.Size()
+			}
+			candidates = append(candidates, f)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to measure %s: %w", dir, err)
+	}
+
+	// Entries of removed files are dropped; the record counts towards the quota
+	rel, err := filepath.Rel(dir, filename)
+	if err != nil {
+		return err
+	}
+	live[rel] = ownerName
+	record, err := json.Marshal(live)
+	if err != nil {
+		return err
+	}
+	for _, size := range writes {
+		incoming += size
+	}
+	incoming += int64(len(record))
+	if used+incoming > q.maxBytes {
+		var prunable int64
+		for _, f := range candidates {
+			prunable += f.size
+		}
+		if !q.prune || used-prunable+incoming > q.maxBytes {
+			return fmt.Errorf("%w: %s holds %d bytes, writing %d more exceeds maxBytes %d",
+				ErrQuotaExceeded, dir, used, incoming, q.maxBytes)
+		}
+		if err := prune(dir, candidates, live, used+incoming-q.maxBytes); err != nil {
+			return err
+		}
+		if record, err = json.Marshal(live); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(filepath.Join(dir, ownersFile), record)
+}
+
+// prune removes the oldest candidates until at least excess bytes are freed,
+// dropping them from owners.
+func prune(dir string, candidates []quotaFile, owners map[string]string, excess int64) error {
+	// Oldest first; the name breaks ties between files written in the same instant
+	slices.SortFunc(candidates, func(a, b quotaFile) int {
+		if c := a.modTime.Compare(b.modTime); c != 0 {
+			return c
+		}
+		return strings.Compare(a.path, b.path)
+	})
+	for _, f := range candidates {
+		if excess <= 0 {
+			break
+		}
+		// The signature goes first so a key file never remains without it
+		for _, path := range []string{f.path + SignatureFileSuffix, f.path} {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to prune %s: %w", path, err)
+			}
+		}
+		delete(owners, filepath.Base(f.path))
+		excess -= f.size
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"context"
+	gocrypto "crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+const (
+	// SignatureHeader carries the base64-encoded payload signature of HTTP publishes.
+	SignatureHeader = "X-Signature"
+
+	// SignatureFileSuffix is appended to a published file's name for its signature.
+	SignatureFileSuffix = ".sig"
+
+	// SigningKeySecretKey is the data key of the PEM private key in the signing Secret.
+	SigningKeySecretKey = "tls.key"
+)
+
+// PayloadSigner signs published payloads with the controller's signing key so
+// receivers can check a key came from openUKR. The signed message is the key ID
+// and a newline followed by the encoded public key bytes before compression, so
+// a signature cannot be replayed for the same key under another ID:
+//
+//   - EC and RSA keys: ASN.1 ECDSA or PKCS #1 v1.5 signature over the SHA-256 digest
+//   - Ed25519 keys: Ed25519 signature over the message
+//
+// Receivers verify with the signing key's public half, e.g.
+//
+//	{ printf '%s\n' "$KID"; cat <file>; } | openssl dgst -sha256 -verify signer.pub -signature <file>.sig
+//
+// The Secret is read through reader on every signature, so replacing it takes
+// effect without a restart; pass a cached client to keep this off the API
+// server. The parsed key is reused until the Secret changes.
+type PayloadSigner struct {
+	reader client.Reader
+	secret types.NamespacedName
+
+	mu sync.Mutex
+	// resourceVersion is the version of the Secret signer was parsed from.
+	resourceVersion string
+	signer          gocrypto.Signer
+}
+
+// NewPayloadSigner creates a signer using the PEM private key stored under
+// SigningKeySecretKey in secret.
+func NewPayloadSigner(reader client.Reader, secret types.NamespacedName) *PayloadSigner {
+	return &PayloadSigner{reader: reader, secret: secret}
+}
+
+// Sign returns the detached signature of payload published as key keyID.
+func (s *PayloadSigner) Sign(ctx context.Context, keyID string, payload []byte) ([]byte, error) {
+	signer, err := s.key(ctx)
+	if err != nil {
+		return nil, err
+	}
+	payload = signedMessage(keyID, payload)
+	var sig []byte
+	if _, ok := signer.(ed25519.PrivateKey); ok {
+		sig, err = signer.Sign(rand.Reader, payload, gocrypto.Hash(0))
+	} else {
+		digest := sha256.Sum256(payload)
+		sig, err = signer.Sign(rand.Reader, digest[:], gocrypto.SHA256)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign payload: %w", err)
+	}
+	return sig, nil
+}
+
+// signedMessage returns the bytes a signature covers: keyID, a newline and payload.
+func signedMessage(keyID string, payload []byte) []byte {
+	msg := make([]byte, 0, len(keyID)+1+len(payload))
+	msg = append(msg, keyID...)
+	msg = append(msg, '\n')
+	return append(msg, payload...)
+}
+
+func (s *PayloadSigner) key(ctx context.Context) (gocrypto.Signer, error) {
+	secret := &corev1.Secret{}
+	if err := s.reader.Get(ctx, s.secret, secret); err != nil {
+		return nil, fmt.Errorf("failed to read signing key secret %s: %w", s.secret, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.signer != nil && secret.ResourceVersion != "" && secret.ResourceVersion == s.resourceVersion {
+		return s.signer, nil
+	}
+	data, ok := secret.Data[SigningKeySecretKey]
+	if !ok {
+		return nil, fmt.Errorf("signing key secret %s has no %q entry", s.secret, SigningKeySecretKey)
+	}
+	priv, err := crypto.ParsePrivateKeyPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("signing key secret %s: %w", s.secret, err)
+	}
+	signer, ok := priv.(gocrypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("signing key secret %s: unsupported key type %T", s.secret, priv)
+	}
+	s.signer, s.resourceVersion = signer, secret.ResourceVersion
+	return signer, nil
+}
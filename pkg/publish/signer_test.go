@@ -0,0 +1,169 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+)
+
+// newSigningSecret returns a P-256 signing key and the Secret holding it.
+func newSigningSecret(t *testing.T) (*ecdsa.PrivateKey, *corev1.Secret) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+	}
+	return key, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "signing-key", Namespace: "openukr-system"},
+		Data: map[string][]byte{
+			SigningKeySecretKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		},
+	}
+}
+
+func TestPublishAllSignsPayloads(t *testing.T) {
+	t.Parallel()
+
+	signingKey, secret := newSigningSecret(t)
+	c := newTestClient(t, secret)
+	signer := NewPayloadSigner(c, types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name})
+
+	var mu sync.Mutex
+	var body []byte
+	var header string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header.Get(SignatureHeader)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	targets := []openukrv1alpha1.PublishTarget{
+		{
+			Type:   TargetTypeHTTP,
+			Config: map[string]string{"endpoint": srv.URL},
+			TLS:    &openukrv1alpha1.TLSConfig{InsecureSkipVerify: true},
+		},
+		{Type: TargetTypeFilesystem, Config: map[string]string{"path": dir}},
+	}
+	// Later publisher options must not drop the signer
+	m := NewManager(c, WithPayloadSigner(signer), WithDeniedPublishPaths(nil), WithPublishHostPolicy(nil))
+
+	kp := generateTestKey(t)
+	if _, err := m.PublishAll(context.Background(), testOwner, targets, kp.Public()); err != nil {
+		t.Fatalf("PublishAll() error = %v", err)
+	}
+
+	verify := func(name string, payload, sig []byte) {
+		t.Helper()
+		digest := sha256.Sum256(signedMessage(kp.KeyID, payload))
+		if !ecdsa.VerifyASN1(&signingKey.PublicKey, digest[:], sig) {
+			t.Errorf("%s: signature does not verify with the signing public key", name)
+		}
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(header)
+	if err != nil {
+		t.Fatalf("decoding %s header %q: %v", SignatureHeader, header, err)
+	}
+	verify("http", body, sig)
+
+	file := filepath.Join(dir, kp.KeyID+".pub")
+	payload, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	sig, err = os.ReadFile(file + SignatureFileSuffix)
+	if err != nil {
+		t.Fatalf("ReadFile() signature error = %v", err)
+	}
+	verify("filesystem", payload, sig)
+
+	// A signature over other bytes, or for another key ID, must not verify
+	digest := sha256.Sum256(signedMessage(kp.KeyID, append(payload, '\n')))
+	if ecdsa.VerifyASN1(&signingKey.PublicKey, digest[:], sig) {
+		t.Error("signature verifies for a modified payload")
+	}
+	digest = sha256.Sum256(signedMessage("other-key", payload))
+	if ecdsa.VerifyASN1(&signingKey.PublicKey, digest[:], sig) {
+		t.Error("signature verifies for another key ID")
+	}
+}
+
+func TestPayloadSignerPicksUpReplacedKey(t *testing.T) {
+	t.Parallel()
+
+	_, secret := newSigningSecret(t)
+	c := newTestClient(t, secret)
+	signer := NewPayloadSigner(c, types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name})
+	ctx := context.Background()
+	if _, err := signer.Sign(ctx, "key", []byte("payload")); err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	replacement, replaced := newSigningSecret(t)
+	if err := c.Get(ctx, types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}, secret); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	secret.Data = replaced.Data
+	if err := c.Update(ctx, secret); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	sig, err := signer.Sign(ctx, "key", []byte("payload"))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	digest := sha256.Sum256(signedMessage("key", []byte("payload")))
+	if !ecdsa.VerifyASN1(&replacement.PublicKey, digest[:], sig) {
+		t.Error("signature after replacing the Secret does not verify with the new key")
+	}
+}
+
+func TestPayloadSignerMissingSecret(t *testing.T) {
+	t.Parallel()
+
+	signer := NewPayloadSigner(newTestClient(t), types.NamespacedName{Namespace: "openukr-system", Name: "missing"})
+	if _, err := signer.Sign(context.Background(), "key", []byte("payload")); err == nil {
+		t.Fatal("Sign() without a signing Secret succeeded, want error")
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+)
+
+// CACertKey is the Secret data key holding a PEM CA certificate bundle.
+const CACertKey = "ca.crt"
+
+// caSecretRefs returns the CA Secret names of a TLS config: CACertSecretRef
+// first, then CACertSecretRefs, without empty entries or duplicates.
+func caSecretRefs(cfg *openukrv1alpha1.TLSConfig) []string {
+	if cfg == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var refs []string
+	for _, ref := range append([]string{cfg.CACertSecretRef}, cfg.CACertSecretRefs...) {
+		if ref == "" || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// loadCAPool concatenates the CA bundles of the referenced Secrets into one pool.
+// [SEC:T-2] Every Secret must contribute at least one parseable certificate, so a
+// misconfigured reference fails loudly instead of silently narrowing trust.
+func loadCAPool(ctx context.Context, reader client.Reader, namespace string, refs []string) (*x509.CertPool, error) {
+	if reader == nil {
+		return nil, fmt.Errorf("cannot load CA Secrets: no Kubernetes client configured")
+	}
+	pool := x509.NewCertPool()
+	for _, name := range refs {
+		var secret corev1.Secret
+		if err := reader.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, &secret); err != nil {
+			return nil, fmt.Errorf("failed to get CA Secret %s/%s: %w", namespace, name, err)
+		}
+		certs, err := parseCertificates(secret.Data[CACertKey])
+		if err != nil {
+			return nil, fmt.Errorf("CA Secret %s/%s: %w", namespace, name, err)
+		}
+		if len(certs) == 0 {
+			return nil, fmt.Errorf("CA Secret %s/%s: no certificate found in %q", namespace, name, CACertKey)
+		}
+		for _, cert := range certs {
+			pool.AddCert(cert)
+		}
+	}
+	return pool, nil
+}
+
+// parseCertificates parses all CERTIFICATE blocks of a PEM bundle.
+// Other block types are ignored.
+func parseCertificates(bundle []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		certs = append(certs, cert)
+	}
+}
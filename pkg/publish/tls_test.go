@@ -0,0 +1,171 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+)
+
+// newTestCA returns a self-signed CA certificate.
+func newTestCA(t *testing.T, name string) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() error = %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate() error = %v", err)
+	}
+	return cert
+}
+
+func caSecret(name string, certs ...*x509.Certificate) *corev1.Secret {
+	var bundle []byte
+	for _, cert := range certs {
+		bundle = append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Data:       map[string][]byte{CACertKey: bundle},
+	}
+}
+
+func newTestClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme() error = %v", err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func TestLoadCAPoolFromTwoSecrets(t *testing.T) {
+	t.Parallel()
+
+	root, intermediate := newTestCA(t, "root"), newTestCA(t, "intermediate")
+	c := newTestClient(t, caSecret("roots", root), caSecret("intermediates", intermediate))
+
+	pool, err := loadCAPool(context.Background(), c, "default", []string{"roots", "intermediates"})
+	if err != nil {
+		t.Fatalf("loadCAPool() error = %v", err)
+	}
+	for _, cert := range []*x509.Certificate{root, intermediate} {
+		if _, err := cert.Verify(x509.VerifyOptions{Roots: pool}); err != nil {
+			t.Errorf("%s not trusted by pool: %v", cert.Subject.CommonName, err)
+		}
+	}
+}
+
+func TestLoadCAPoolRejectsInvalidSecrets(t *testing.T) {
+	t.Parallel()
+
+	empty := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "empty", Namespace: "default"},
+		Data:       map[string][]byte{CACertKey: []byte("not a certificate")},
+	}
+	c := newTestClient(t, caSecret("roots", newTestCA(t, "root")), empty)
+
+	tests := []struct {
+		name string
+		refs []string
+	}{
+		{name: "no certificate", refs: []string{"roots", "empty"}},
+		{name: "missing secret", refs: []string{"roots", "absent"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if _, err := loadCAPool(context.Background(), c, "default", tt.refs); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestCASecretRefs(t *testing.T) {
+	t.Parallel()
+
+	got := caSecretRefs(&openukrv1alpha1.TLSConfig{
+		CACertSecretRef:  "roots",
+		CACertSecretRefs: []string{"intermediates", "roots", ""},
+	})
+	if len(got) != 2 || got[0] != "roots" || got[1] != "intermediates" {
+		t.Errorf("caSecretRefs() = %v, want [roots intermediates]", got)
+	}
+	if got := caSecretRefs(nil); got != nil {
+		t.Errorf("caSecretRefs(nil) = %v, want nil", got)
+	}
+}
+
+func TestHTTPPublisherTrustsCASecrets(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer srv.Close()
+
+	c := newTestClient(t, caSecret("roots", newTestCA(t, "unrelated")), caSecret("server", srv.Certificate()))
+	target := openukrv1alpha1.PublishTarget{
+		Type:   "http",
+		Config: map[string]string{"endpoint": srv.URL},
+		TLS:    &openukrv1alpha1.TLSConfig{CACertSecretRef: "roots"},
+	}
+	ctx := context.Background()
+	kp := generateTestKey(t)
+
+	// Only the unrelated root: the server certificate is not trusted
+	if err := NewHTTPPublisher(c).Publish(ctx, testOwner, target, kp.Public()); err == nil {
+		t.Fatal("expected TLS verification error, got nil")
+	}
+
+	target.TLS.CACertSecretRefs = []string{"server"}
+	if err := NewHTTPPublisher(c).Publish(ctx, testOwner, target, kp.Public()); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+}
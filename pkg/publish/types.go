@@ -18,6 +18,12 @@ package publish
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
 
 	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
 	"github.com/openukr/openukr/pkg/crypto"
@@ -26,6 +32,135 @@ import (
 // Publisher defines the interface for publishing public keys.
 type Publisher interface {
 	// Publish publishes the PUBLIC key to the configured target.
-	// The implementation MUST ensure idempotency.
-	Publish(ctx context.Context, target openukrv1alpha1.PublishTarget, kp *crypto.KeyPair) error
+	// Publishers only receive the public component — private key material
+	// is never exposed to them. [SEC:S-2]
+	// The implementation MUST ensure idempotency and be safe for concurrent use,
+	// as the Manager publishes multiple targets in parallel.
+	// owner is the KeyProfile whose key is published; Secret references (e.g.
+	// CA bundles, client secrets) are resolved in its namespace.
+	Publish(ctx context.Context, owner Owner, target openukrv1alpha1.PublishTarget, pub *crypto.PublicKeyInfo) error
+}
+
+// Owner identifies the KeyProfile a key is published for.
+type Owner struct {
+	types.NamespacedName
+	// Retain lists the owner's key IDs that must stay published alongside the
+	// key, e.g. the previous key during its grace period. Publishers that
+	// remove older keys (see "pruneOldest") never remove these.
+	Retain []string
+	// Withdraw lists the owner's key IDs that must no longer be published, e.g.
+	// the previous key after a hard cutover (GraceMode hard). Publishers that
+	// keep a document per key remove them; those replacing the published key
+	// in place need not act.
+	Withdraw []string
+	// Previous is the owner's key in its grace period once the key is
+	// published: the key a rotation replaces, or the previous key until its
+	// grace period ends. Empty otherwise and after a hard cutover. Publishers
+	// replacing a key in place keep it alongside until then.
+	Previous string
+}
+
+// retains reports whether keyID must stay published.
+func (o Owner) retains(keyID string) bool {
+	return slices.Contains(o.Retain, keyID)
+}
+
+// TargetResult is the outcome of publishing to a single target.
+type TargetResult struct {
+	// Type is the publisher type of the target.
+	Type string
+	// Target identifies the destination (endpoint or path).
+	Target string
+	// Err is nil if the key was published successfully.
+	Err error
+	// CircuitOpenUntil is when the target is attempted again if repeated
+	// failures opened its circuit; zero while the circuit is closed.
+	CircuitOpenUntil time.Time
+	// Skipped is why the target was not attempted without counting as a
+	// failure (see WithTolerateUnknownPublishers); empty if it was attempted.
+	Skipped string
+}
+
+// describeTarget returns a human-readable destination for the target.
+func describeTarget(target openukrv1alpha1.PublishTarget) string {
+	var dests []string
+	for _, out := range resolveOutputs(target) {
+		for _, key := range []string{"endpoint", "path", "vaultURL"} {
+			if v := out.config[key]; v != "" {
+				dests = append(dests, v)
+			}
+		}
+	}
+	return strings.Join(dests, ",")
+}
+
+// resolvedOutput is a single (config, encoding) pair derived from a PublishTarget.
+type resolvedOutput struct {
+	config   map[string]string
+	encoding string
+	compress bool
+}
+
+// resolveOutputs expands a target into the outputs to publish.
+// Targets without Outputs publish a single PEM output using the target Config;
+// otherwise each output's Config is merged over the target Config.
+func resolveOutputs(target openukrv1alpha1.PublishTarget) []resolvedOutput {
+	if len(target.Outputs) == 0 {
+		return []resolvedOutput{{config: target.Config, encoding: "PEM"}}
+	}
+
+	outputs := make([]resolvedOutput, 0, len(target.Outputs))
+	for _, o := range target.Outputs {
+		config := make(map[string]string, len(target.Config)+len(o.Config))
+		for k, v := range target.Config {
+			config[k] = v
+		}
+		for k, v := range o.Config {
+			config[k] = v
+		}
+		encoding := o.Encoding
+		if encoding == "" {
+			encoding = "PEM"
+		}
+		outputs = append(outputs, resolvedOutput{config: config, encoding: encoding, compress: o.Compress})
+	}
+	return outputs
+}
+
+// encodePublic encodes the public key in the given encoding. Private key
+// material is refused, and JWK goes through crypto.PublicJWK. JWK output
+// carries the KeyID as "kid". [SEC:S-2]
+func encodePublic(pub *crypto.PublicKeyInfo, encoding string) ([]byte, error) {
+	if crypto.IsPrivateKey(pub.PublicKey) {
+		return nil, fmt.Errorf("refusing to publish private key material for key %s", pub.KeyID)
+	}
+	if encoding == "JWK" {
+		data, err := crypto.PublicJWK(pub.PublicKey, crypto.WithKeyID(pub.KeyID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode public key as %s: %w", encoding, err)
+		}
+		return data, nil
+	}
+
+	encoder, err := crypto.NewKeyEncoder(encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := encoder.EncodePublicWithKID(pub.PublicKey, pub.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public key as %s: %w", encoding, err)
+	}
+	return data, nil
+}
+
+// joinOutputErrors aggregates per-output errors into a single error.
+func joinOutputErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return fmt.Errorf("output errors: %v", errs)
 }
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package publish
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/json"
+	"testing"
+
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+// TestEncodePublicNeverEmitsPrivateMaterial guards the publish path against
+// emitting private key material in any encoding. [SEC:S-2]
+func TestEncodePublicNeverEmitsPrivateMaterial(t *testing.T) {
+	t.Parallel()
+
+	keys := []struct {
+		name      string
+		opts      crypto.GenerateOptions
+		encodings []string
+	}{
+		{
+			name:      "EC",
+			opts:      crypto.GenerateOptions{Algorithm: crypto.AlgorithmEC, Params: map[string]string{"curve": crypto.CurveP256}},
+			encodings: []string{"PEM", "DER", "JWK", crypto.EncodingSPKI, crypto.EncodingRawPublic, crypto.EncodingECCompressed},
+		},
+		{
+			name:      "RSA",
+			opts:      crypto.GenerateOptions{Algorithm: crypto.AlgorithmRSA, Params: map[string]string{"keySize": "2048"}, AllowLegacyKeySize: true},
+			encodings: []string{"PEM", "DER", "JWK", crypto.EncodingSPKI, crypto.EncodingRawPublic},
+		},
+	}
+	for _, k := range keys {
+		kp, err := crypto.NewKeyGenerator().Generate(k.opts)
+		if err != nil {
+			t.Fatalf("%s: Generate() error = %v", k.name, err)
+		}
+		var secret []byte
+		switch priv := kp.PrivateKey.(type) {
+		case *ecdsa.PrivateKey:
+			secret = priv.D.Bytes()
+		case *rsa.PrivateKey:
+			secret = priv.D.Bytes()
+		}
+
+		for _, encoding := range k.encodings {
+			out, err := encodePublic(kp.Public(), encoding)
+			if err != nil {
+				t.Errorf("%s %s: encodePublic() error = %v", k.name, encoding, err)
+				continue
+			}
+			if bytes.Contains(out, secret) || bytes.Contains(out, []byte("PRIVATE KEY")) {
+				t.Errorf("%s %s: published output contains private key material", k.name, encoding)
+			}
+			if encoding != "JWK" {
+				continue
+			}
+			var members map[string]any
+			if err := json.Unmarshal(out, &members); err != nil {
+				t.Fatalf("%s: Unmarshal() error = %v", k.name, err)
+			}
+			for _, private := range []string{"d", "p", "q", "dp", "dq", "qi"} {
+				if _, ok := members[private]; ok {
+					t.Errorf("%s: published JWK contains private member %q", k.name, private)
+				}
+			}
+			if members["kid"] != kp.KeyID {
+				t.Errorf("%s: published JWK kid = %v, want %s", k.name, members["kid"], kp.KeyID)
+			}
+		}
+
+		// A private key slipped into the public info is refused outright
+		leaked := kp.Public()
+		leaked.PublicKey = kp.PrivateKey
+		for _, encoding := range k.encodings {
+			if _, err := encodePublic(leaked, encoding); err == nil {
+				t.Errorf("%s %s: encodePublic() of a private key succeeded, want error", k.name, encoding)
+			}
+		}
+		kp.Wipe()
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"k8s.io/utils/clock"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+// DiffResult describes what applying a KeyProfile change would do.
+type DiffResult struct {
+	// Rotate is true if a new key would be generated.
+	Rotate bool
+	// Rerender is true if the Secret data or metadata would be rewritten.
+	Rerender bool
+	// Republish is true if the public key would be published to changed targets.
+	Republish bool
+	// Reasons explains each detected change in human-readable form.
+	Reasons []string
+}
+
+// Diff previews the effect of changing a KeyProfile from oldProfile to newProfile without
+// mutating anything. It is intended for GitOps previews (kubectl plugins,
+// admission warnings). oldProfile may be nil for a newly created profile; newProfile.Status
+// is used for the time-based rotation check.
+func Diff(ctx context.Context, oldProfile, newProfile *openukrv1alpha1.KeyProfile) (DiffResult, error) {
+	return diff(ctx, clock.RealClock{}, oldProfile, newProfile)
+}
+
+func diff(_ context.Context, clk clock.PassiveClock, oldProfile, newProfile *openukrv1alpha1.KeyProfile) (DiffResult, error) {
+	var res DiffResult
+	if newProfile == nil {
+		return res, fmt.Errorf("new KeyProfile cannot be nil")
+	}
+
+	// A preview of an invalid spec is an error, matching admission behavior
+	if _, err := crypto.ValidateKeySpec(
+		newProfile.Spec.KeySpec.Algorithm,
+		newProfile.Spec.KeySpec.Params,
+		newProfile.Spec.KeySpec.AllowLegacyKeySize,
+	); err != nil {
+		return res, fmt.Errorf("invalid key spec: %w", err)
+	}
+
+	if oldProfile == nil {
+		res.Rotate, res.Rerender = true, true
+		res.Republish = len(newProfile.Spec.Publish) > 0
+		res.Reasons = append(res.Reasons, "new KeyProfile: initial key generation")
+		return res, nil
+	}
+
+	// Key spec change: like EnsureKey, only an algorithm migration rotates right
+	// away; other changes apply to the key generated at the next rotation
+	algorithmChanged := oldProfile.Spec.KeySpec.Algorithm != newProfile.Spec.KeySpec.Algorithm
+	if algorithmChanged || !maps.Equal(oldProfile.Spec.KeySpec.Params, newProfile.Spec.KeySpec.Params) {
+		change := fmt.Sprintf("key spec changed: %s%v → %s%v",
+			oldProfile.Spec.KeySpec.Algorithm, oldProfile.Spec.KeySpec.Params,
+			newProfile.Spec.KeySpec.Algorithm, newProfile.Spec.KeySpec.Params)
+		if algorithmChanged && newProfile.Spec.Rotation.MigrateOnAlgorithmChange {
+			res.Rotate = true
+			res.Reasons = append(res.Reasons, change+" (algorithm migration)")
+		} else {
+			res.Reasons = append(res.Reasons, change+", applied at the next rotation")
+		}
+	}
+
+	// Time-based: the new interval may make the current key due
+	m := &manager{clock: clk}
+	if needed, reason := m.checkRotationNeeded(newProfile); needed {
+		res.Rotate = true
+		res.Reasons = append(res.Reasons, "rotation due: "+reason)
+	}
+
+	// Render hash: anything that changes the Secret's rendered content
+	oldHash, err := renderHash(oldProfile)
+	if err != nil {
+		return res, err
+	}
+	newHash, err := renderHash(newProfile)
+	if err != nil {
+		return res, err
+	}
+	if res.Rotate || oldHash != newHash {
+		res.Rerender = true
+		if !res.Rotate {
+			res.Reasons = append(res.Reasons, "output or encoding changed")
+		}
+	}
+
+	// Publish targets: new or changed targets need the current key
+	if !slices.EqualFunc(oldProfile.Spec.Publish, newProfile.Spec.Publish, publishTargetEqual) {
+		res.Republish = true
+		res.Reasons = append(res.Reasons, "publish targets changed")
+	} else if res.Rotate && len(newProfile.Spec.Publish) > 0 {
+		res.Republish = true
+	}
+
+	return res, nil
+}
+
+// renderInput holds the spec fields that affect the rendered Secret.
+type renderInput struct {
+	Encoding          string
+	PrivateKeyPEMType string
+	Output            openukrv1alpha1.OutputConfig
+	AdditionalOutputs []openukrv1alpha1.OutputConfig `json:",omitempty"`
+}
+
+// renderHash returns a stable hash of the render-affecting spec fields.
+func renderHash(profile *openukrv1alpha1.KeyProfile) (string, error) {
+	// encoding/json sorts map keys, so the encoding is deterministic
+	data, err := json.Marshal(renderInput{
+		Encoding:          profile.Spec.KeySpec.Encoding,
+		PrivateKeyPEMType: profile.Spec.KeySpec.PrivateKeyPEMType,
+		Output:            profile.Spec.Output,
+		AdditionalOutputs: profile.Spec.AdditionalOutputs,
+	})
+	if err != nil {
+		return "", fmt.Errorf("hash render inputs: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func publishTargetEqual(a, b openukrv1alpha1.PublishTarget) bool {
+	aJSON, errA := json.Marshal(a)
+	bJSON, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(aJSON) == string(bJSON)
+}
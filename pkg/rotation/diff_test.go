@@ -0,0 +1,134 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+)
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	lastRotation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clocktesting.NewFakePassiveClock(lastRotation.Add(2 * time.Hour))
+
+	tests := []struct {
+		name          string
+		mutate        func(p *openukrv1alpha1.KeyProfile)
+		wantRotate    bool
+		wantRerender  bool
+		wantRepublish bool
+	}{
+		{
+			name:   "no change",
+			mutate: func(p *openukrv1alpha1.KeyProfile) {},
+		},
+		{
+			name: "algorithm change waits for the next rotation",
+			mutate: func(p *openukrv1alpha1.KeyProfile) {
+				p.Spec.KeySpec.Algorithm = "RSA"
+				p.Spec.KeySpec.Params = map[string]string{"keySize": "3072"}
+			},
+		},
+		{
+			name: "algorithm migration rotates",
+			mutate: func(p *openukrv1alpha1.KeyProfile) {
+				p.Spec.KeySpec.Algorithm = "RSA"
+				p.Spec.KeySpec.Params = map[string]string{"keySize": "3072"}
+				p.Spec.Rotation.MigrateOnAlgorithmChange = true
+			},
+			wantRotate:   true,
+			wantRerender: true,
+		},
+		{
+			name: "params change waits for the next rotation",
+			mutate: func(p *openukrv1alpha1.KeyProfile) {
+				p.Spec.KeySpec.Params = map[string]string{"curve": "P-384"}
+				p.Spec.Rotation.MigrateOnAlgorithmChange = true
+			},
+		},
+		{
+			name: "shorter interval makes key due",
+			mutate: func(p *openukrv1alpha1.KeyProfile) {
+				p.Spec.Rotation.Interval = metav1.Duration{Duration: time.Hour}
+			},
+			wantRotate:   true,
+			wantRerender: true,
+		},
+		{
+			name: "output format change re-renders",
+			mutate: func(p *openukrv1alpha1.KeyProfile) {
+				p.Spec.Output.Format = "single-pem"
+			},
+			wantRerender: true,
+		},
+		{
+			name: "secret label change re-renders",
+			mutate: func(p *openukrv1alpha1.KeyProfile) {
+				p.Spec.Output.Labels = map[string]string{"team": "payments"}
+			},
+			wantRerender: true,
+		},
+		{
+			name: "new publish target re-publishes",
+			mutate: func(p *openukrv1alpha1.KeyProfile) {
+				p.Spec.Publish = append(p.Spec.Publish, openukrv1alpha1.PublishTarget{
+					Type:   "filesystem",
+					Config: map[string]string{"path": "/var/keys"},
+				})
+			},
+			wantRepublish: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			oldProfile := newTestProfile(lastRotation)
+			newProfile := oldProfile.DeepCopy()
+			tt.mutate(newProfile)
+
+			res, err := diff(context.Background(), clk, oldProfile, newProfile)
+			if err != nil {
+				t.Fatalf("diff() error = %v", err)
+			}
+			if res.Rotate != tt.wantRotate || res.Rerender != tt.wantRerender || res.Republish != tt.wantRepublish {
+				t.Errorf("diff() = %+v, want Rotate=%v Rerender=%v Republish=%v",
+					res, tt.wantRotate, tt.wantRerender, tt.wantRepublish)
+			}
+		})
+	}
+}
+
+func TestDiffRejectsInvalidSpec(t *testing.T) {
+	t.Parallel()
+
+	oldProfile := newTestProfile(time.Now())
+	newProfile := oldProfile.DeepCopy()
+	newProfile.Spec.KeySpec.Params = map[string]string{"curve": "P-192"}
+
+	if _, err := Diff(context.Background(), oldProfile, newProfile); err == nil {
+		t.Fatal("Diff() with invalid curve succeeded, want error")
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotation
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+const (
+	// DefaultPendingKeyTTL bounds how long an unpersisted key is kept for retry.
+	DefaultPendingKeyTTL = 5 * time.Minute
+	// DefaultPendingKeyCacheSize bounds how many unpersisted keys are kept at once.
+	DefaultPendingKeyCacheSize = 32
+)
+
+// pendingKeyID identifies the profile revision a pending key was generated for.
+// A spec change bumps the generation, so a stale key is never reused.
+type pendingKeyID struct {
+	uid        types.UID
+	generation int64
+}
+
+type pendingEntry struct {
+	kp      *crypto.KeyPair
+	expires time.Time
+}
+
+// pendingKeyCache holds key pairs that were generated but not yet persisted, so a
+// retry after a failed publish or persist reuses the same key instead of
+// generating a new one. Entries are wiped on expiry, eviction and replacement.
+// Expiry is checked on every access; there is no background sweeper. [SEC:I-2]
+type pendingKeyCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	maxSize int
+	entries map[pendingKeyID]pendingEntry
+}
+
+func newPendingKeyCache(ttl time.Duration, maxSize int) *pendingKeyCache {
+	return &pendingKeyCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[pendingKeyID]pendingEntry),
+	}
+}
+
+// enabled reports whether keys are cached at all.
+func (c *pendingKeyCache) enabled() bool {
+	return c != nil && c.ttl > 0 && c.maxSize > 0
+}
+
+// take removes and returns the pending key for id, or nil if none is cached.
+// Ownership of the returned key passes to the caller.
+func (c *pendingKeyCache) take(id pendingKeyID, now time.Time) *crypto.KeyPair {
+	if !c.enabled() {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.purgeExpired(now)
+	e, ok := c.entries[id]
+	if !ok {
+		return nil
+	}
+	delete(c.entries, id)
+	return e.kp
+}
+
+// put stores kp for id, taking ownership of it. If caching is disabled, kp is wiped.
+func (c *pendingKeyCache) put(id pendingKeyID, kp *crypto.KeyPair, now time.Time) {
+	if !c.enabled() {
+		kp.Wipe()
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.purgeExpired(now)
+	if old, ok := c.entries[id]; ok && old.kp != kp {
+		old.kp.Wipe()
+	}
+	delete(c.entries, id)
+	for len(c.entries) >= c.maxSize {
+		c.evictOldest()
+	}
+	c.entries[id] = pendingEntry{kp: kp, expires: now.Add(c.ttl)}
+}
+
+// purgeExpired wipes and removes expired entries. Callers must hold c.mu.
+func (c *pendingKeyCache) purgeExpired(now time.Time) {
+	for id, e := range c.entries {
+		if !now.Before(e.expires) {
+			e.kp.Wipe()
+			delete(c.entries, id)
+		}
+	}
+}
+
+// evictOldest wipes and removes the entry closest to expiry. Callers must hold c.mu.
+func (c *pendingKeyCache) evictOldest() {
+	var oldest pendingKeyID
+	var oldestExpiry time.Time
+	first := true
+	for id, e := range c.entries {
+		if first || e.expires.Before(oldestExpiry) {
+			oldest, oldestExpiry, first = id, e.expires, false
+		}
+	}
+	if !first {
+		c.entries[oldest].kp.Wipe()
+		delete(c.entries, oldest)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openukr/openukr/pkg/crypto"
+)
+
+func generatePendingKey(t *testing.T) *crypto.KeyPair {
+	t.Helper()
+	kp, err := crypto.NewKeyGenerator().Generate(crypto.GenerateOptions{
+		Algorithm: crypto.AlgorithmEC,
+		Params:    map[string]string{"curve": crypto.CurveP256},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	t.Cleanup(kp.Wipe)
+	return kp
+}
+
+func TestPendingKeyCacheExpiryAndEviction(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := newPendingKeyCache(time.Minute, 2)
+	a, b, d := pendingKeyID{uid: "a"}, pendingKeyID{uid: "b"}, pendingKeyID{uid: "d"}
+
+	// Expired entries are wiped, not returned
+	expired := generatePendingKey(t)
+	c.put(a, expired, now)
+	if got := c.take(a, now.Add(time.Minute)); got != nil {
+		t.Error("take() returned an expired key")
+	}
+	if expired.PrivateKey != nil {
+		t.Error("expired key was not wiped")
+	}
+
+	// Capacity overflow evicts and wipes the oldest entry
+	oldest, middle, newest := generatePendingKey(t), generatePendingKey(t), generatePendingKey(t)
+	c.put(a, oldest, now)
+	c.put(b, middle, now.Add(time.Second))
+	c.put(d, newest, now.Add(2*time.Second))
+	if oldest.PrivateKey != nil {
+		t.Error("evicted key was not wiped")
+	}
+	if got := c.take(b, now.Add(3*time.Second)); got != middle {
+		t.Error("take() did not return the cached key")
+	}
+	if got := c.take(b, now.Add(3*time.Second)); got != nil {
+		t.Error("take() returned a key twice")
+	}
+
+	// A different generation never matches
+	if got := c.take(pendingKeyID{uid: "d", generation: 1}, now.Add(3*time.Second)); got != nil {
+		t.Error("take() returned a key for another generation")
+	}
+}
+
+func TestPendingKeyCacheDisabledWipes(t *testing.T) {
+	t.Parallel()
+
+	c := newPendingKeyCache(0, 0)
+	kp := generatePendingKey(t)
+	c.put(pendingKeyID{uid: "a"}, kp, time.Now())
+	if kp.PrivateKey != nil {
+		t.Error("put() on a disabled cache did not wipe the key")
+	}
+}
@@ -18,16 +18,20 @@ package rotation
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
 
 	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
 	"github.com/openukr/openukr/pkg/crypto"
 	"github.com/openukr/openukr/pkg/metrics"
 	"github.com/openukr/openukr/pkg/output"
+	"github.com/openukr/openukr/pkg/publish"
 )
 
 // RotationResult contains information about the outcome of a rotation check.
@@ -40,20 +44,134 @@ type RotationResult struct {
 	RotationTime time.Time
 	// NextRotation is the calculated time for the next scheduled rotation.
 	NextRotation time.Time
+	// Reason explains why the key was rotated (e.g. "initial key generation").
+	// Empty if Rotated is false.
+	Reason string
 	// Fingerprint of the active key [SEC:T-1]
-	Fingerprint string
+	Fingerprint crypto.Fingerprint
+	// PreviousKeyID of the key still within its grace period (empty once expired).
+	PreviousKeyID string
+	// PreviousFingerprint of the previous key [SEC:T-1]
+	PreviousFingerprint crypto.Fingerprint
+	// PublishResults holds the per-target outcome when a key was published.
+	// On a publish or persist failure, EnsureKey returns a result carrying only
+	// PublishResults, KeyID (of the attempted key) and PendingKeyID alongside the error.
+	PublishResults []publish.TargetResult
+	// PendingKeyID is set when a key was published but persisting it failed.
+	// The controller records it as Status.PendingKeyID so the retry persists the
+	// same key without publishing it again.
+	PendingKeyID string
+	// ClockSkew is how far Status.LastRotation was in the future, if beyond MaxClockSkew.
+	ClockSkew time.Duration
+	// PausedUntil is set while Spec.Rotation.PauseUntil suppresses rotation.
+	PausedUntil time.Time
+	// CSR is the PEM-encoded certificate signing request for a newly rotated key,
+	// set only when Spec.Certificate is configured.
+	CSR []byte
+	// Observe is set when the manager runs in observe mode: the result reflects
+	// the existing Secret and nothing was generated, published or written.
+	Observe bool
+	// NextKeyID is the key published ahead of its promotion (Spec.Rotation.PublishNextKey).
+	NextKeyID string
+	// NextFingerprint of the next key [SEC:T-1]
+	NextFingerprint crypto.Fingerprint
+	// NextKeyDue is when the next key will be published, while that is still ahead.
+	NextKeyDue time.Time
+	// IntegrityViolation is the failed integrity check, wrapping output.ErrIntegrity,
+	// that made this a corrective rotation replacing corrupt or tampered material.
+	IntegrityViolation error
+	// IntegrityVerified is set when the stored key material passed its integrity
+	// check during this call. [SEC:T-1]
+	IntegrityVerified bool
 }
 
+// MaxClockSkew is the tolerated amount by which Status.LastRotation may lie in the
+// future (e.g. node clock drift). Beyond this, the profile is treated as due now.
+const MaxClockSkew = 5 * time.Minute
+
 // RotationManager handles the lifecycle of keys: checking rotation schedules,
 // generating new keys, and persisting them via SecretWriter.
 type RotationManager interface {
 	// EnsureKey checks if a key needs to be generated or rotated for the given profile.
 	EnsureKey(ctx context.Context, profile *openukrv1alpha1.KeyProfile) (*RotationResult, error)
+
+	// ExpirePrevious ends the previous key's grace period now: its private
+	// material is removed from the Secret. The caller clears the previous-key status.
+	ExpirePrevious(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error
 }
 
+// ForceRotateAnnotation requests an immediate rotation of a KeyProfile's key,
+// regardless of its schedule. Rotation pauses still apply. Once the key has
+// been rotated, the controller records the value in Status.LastForcedRotation
+// and removes the annotation.
+const ForceRotateAnnotation = "openukr.io/force-rotate"
+
+// ErrObserveMode is returned by mutating operations while the manager runs in observe mode.
+var ErrObserveMode = errors.New("not permitted in observe mode")
+
 // Publisher abstracts the publishing of public keys to external targets.
+// It only receives the public component of a key pair. [SEC:S-2]
 type Publisher interface {
-	PublishAll(ctx context.Context, targets []openukrv1alpha1.PublishTarget, kp *crypto.KeyPair) error
+	PublishAll(
+		ctx context.Context,
+		owner publish.Owner,
+		targets []openukrv1alpha1.PublishTarget,
+		pub *crypto.PublicKeyInfo,
+	) ([]publish.TargetResult, error)
+}
+
+// Option configures optional behavior of the RotationManager.
+type Option func(*manager)
+
+// WithClock sets the clock used for all rotation timing decisions.
+// Defaults to the real clock; tests inject a fake clock for deterministic timing.
+func WithClock(c clock.PassiveClock) Option {
+	return func(m *manager) {
+		m.clock = c
+	}
+}
+
+// WithPendingKeyCache bounds the cache of generated but not yet persisted keys,
+// which lets a retry after a failed publish or persist reuse the same key.
+// A ttl or maxSize of 0 disables the cache. [SEC:I-2]
+func WithPendingKeyCache(ttl time.Duration, maxSize int) Option {
+	return func(m *manager) {
+		m.pending = newPendingKeyCache(ttl, maxSize)
+	}
+}
+
+// WithObserveMode makes EnsureKey compute the rotation schedule without mutating
+// anything: no key generation, publishing, Secret writes or grace period cleanup.
+// Intended for trialling the operator against existing Secrets before enabling it.
+func WithObserveMode() Option {
+	return func(m *manager) {
+		m.observe = true
+	}
+}
+
+// WithKeygenRateLimit spaces key generation evenly to at most perMinute keys,
+// so a burst of due rotations does not starve the node of CPU. A rotation over
+// budget fails with a KeygenThrottledError instead of generating; the caller
+// retries it once the bucket has refilled. perMinute <= 0 disables the limit.
+func WithKeygenRateLimit(perMinute int) Option {
+	return func(m *manager) {
+		if perMinute <= 0 {
+			m.keygenLimit = nil
+			return
+		}
+		m.keygenLimit = rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), 1)
+	}
+}
+
+// KeygenThrottledError is returned by EnsureKey when a key is due but the
+// WithKeygenRateLimit budget is spent. Nothing was generated, published or written.
+type KeygenThrottledError struct {
+	// RetryAfter is how long until the budget allows the next key generation.
+	RetryAfter time.Duration
+}
+
+func (e *KeygenThrottledError) Error() string {
+	return fmt.Sprintf("key generation throttled, retry in %s", e.RetryAfter)
 }
 
 // NewManager creates a new RotationManager.
@@ -62,13 +180,20 @@ func NewManager(
 	keygen crypto.KeyGenerator,
 	writer output.SecretWriter,
 	publisher Publisher,
+	opts ...Option,
 ) RotationManager {
-	return &manager{
+	m := &manager{
 		log:       log,
 		keygen:    keygen,
 		writer:    writer,
 		publisher: publisher,
+		clock:     clock.RealClock{},
+		pending:   newPendingKeyCache(DefaultPendingKeyTTL, DefaultPendingKeyCacheSize),
+	}
+	for _, opt := range opts {
+		opt(m)
 	}
+	return m
 }
 
 type manager struct {
@@ -76,91 +201,657 @@ type manager struct {
 	keygen    crypto.KeyGenerator
 	writer    output.SecretWriter
 	publisher Publisher
+	clock     clock.PassiveClock
+	pending   *pendingKeyCache
+	observe   bool
+	// keygenLimit throttles key generation; nil means unlimited.
+	keygenLimit *rate.Limiter
 }
 
 func (m *manager) EnsureKey(ctx context.Context, profile *openukrv1alpha1.KeyProfile) (*RotationResult, error) {
-	log := m.log.WithValues("keyprofile", types.NamespacedName{Name: profile.Name, Namespace: profile.Namespace})
+	log := m.logger(ctx).WithValues("keyprofile", types.NamespacedName{Name: profile.Name, Namespace: profile.Namespace})
+
+	// 0. Guard against clock skew / restored backups: a LastRotation in the
+	// future would otherwise schedule a far-future requeue
+	skew := m.clockSkew(profile)
+	if skew > 0 {
+		log.V(0).Info("Last rotation is in the future, treating key as due", "reason", "LastRotationInFuture",
+			"lastRotation", lastRotation(profile), "skew", skew)
+	}
 
 	// 1. Check if rotation is needed
 	needsRotation, reason := m.checkRotationNeeded(profile)
+	if skew > 0 {
+		needsRotation, reason = true, fmt.Sprintf("clock skew: lastRotation %s ahead", skew)
+	}
+	pausedUntil := m.pausedUntil(profile)
+	if needsRotation && !pausedUntil.IsZero() {
+		log.V(1).Info("Rotation paused", "reason", reason, "pauseUntil", pausedUntil)
+		needsRotation = false
+	}
+	if m.observe {
+		return m.observeKey(ctx, profile, needsRotation, reason, skew, pausedUntil)
+	}
+
+	// Integrity: the stored key must be a valid pair matching the recorded fingerprint [SEC:T-1].
+	// Corrupt or tampered material is replaced by a corrective rotation unless paused.
+	var integrityViolation error
+	var integrityVerified bool
 	if !needsRotation {
-		// Calculate next rotation for status
-		nextRot := calculateNextRotation(profile.Status.LastRotation.Time, profile.Spec.Rotation.Interval.Duration)
-		return &RotationResult{
-			Rotated:      false,
-			KeyID:        profile.Status.CurrentKeyID,
-			RotationTime: profile.Status.LastRotation.Time,
-			NextRotation: nextRot,
-			Fingerprint:  profile.Status.CurrentKeyFingerprint,
-		}, nil
+		err := m.writer.Verify(ctx, profile)
+		integrityVerified = err == nil
+		if err != nil {
+			if !errors.Is(err, output.ErrIntegrity) {
+				metrics.RecordRotationError("verify", profile.Namespace, profile.Labels)
+				return nil, fmt.Errorf("secret verification failed: %w", err)
+			}
+			log.V(0).Info("Secret key material failed integrity check", "reason", "IntegrityViolation", "error", err.Error())
+			metrics.RecordRotationError("integrity", profile.Namespace, profile.Labels)
+			if !pausedUntil.IsZero() {
+				return nil, fmt.Errorf("secret verification failed: %w", err)
+			}
+			integrityViolation = err
+			needsRotation, reason = true, "integrity violation: "+err.Error()
+		}
+	}
+
+	// Algorithm migration: the stored key no longer matches the spec. It stays
+	// the previous key for the full grace period, so validators accept both.
+	if !needsRotation && pausedUntil.IsZero() && profile.Spec.Rotation.MigrateOnAlgorithmChange {
+		stored, err := m.storedAlgorithm(ctx, profile)
+		if err != nil {
+			return nil, err
+		}
+		if stored != "" && stored != profile.Spec.KeySpec.Algorithm {
+			needsRotation = true
+			reason = fmt.Sprintf("algorithm migration: %s → %s", stored, profile.Spec.KeySpec.Algorithm)
+		}
+	}
+
+	if !needsRotation {
+		previousFingerprint, err := previousStatusFingerprint(profile)
+		if err != nil {
+			return nil, err
+		}
+		// Calculate next rotation for status; a pause defers it to the resume time
+		nextRot := calculateNextRotation(
+			lastRotation(profile),
+			profile.Spec.Rotation.Interval.Duration,
+			rotationLead(profile),
+		)
+		if !nextRot.IsZero() && (skew > 0 || nextRot.Before(pausedUntil)) {
+			nextRot = pausedUntil
+		}
+		res := &RotationResult{
+			Rotated:             false,
+			KeyID:               profile.Status.CurrentKeyID,
+			RotationTime:        lastRotation(profile),
+			NextRotation:        nextRot,
+			Fingerprint:         statusFingerprint(profile.Status.CurrentKeyFingerprint),
+			PreviousKeyID:       profile.Status.PreviousKeyID,
+			PreviousFingerprint: previousFingerprint,
+			PausedUntil:         pausedUntil,
+			IntegrityVerified:   integrityVerified,
+		}
+
+		// Grace period cleanup: wipe previous private material once expired [SEC:I-2]
+		if m.gracePeriodExpired(profile) {
+			if err := m.writer.DropPrevious(ctx, profile); err != nil {
+				metrics.RecordRotationError("cleanup", profile.Namespace, profile.Labels)
+				return nil, fmt.Errorf("failed to drop previous key material: %w", err)
+			}
+			log.Info("Grace period ended, previous key dropped", "previousKeyID", profile.Status.PreviousKeyID)
+			res.PreviousKeyID = ""
+			res.PreviousFingerprint = crypto.Fingerprint{}
+		}
+
+		// Targets added or edited since the last rotation get the current key now
+		publishResults, err := m.republishIfChanged(ctx, profile)
+		if err != nil {
+			return &RotationResult{KeyID: res.KeyID, PublishResults: publishResults}, err
+		}
+		res.PublishResults = publishResults
+
+		// Validators get the upcoming key ahead of its promotion; a key staged
+		// before PublishNextKey was turned off is never promoted
+		if err := m.dropNextKey(ctx, profile); err != nil {
+			return &RotationResult{KeyID: res.KeyID, PublishResults: publishResults}, err
+		}
+		if err := m.publishNextKey(ctx, profile, res); err != nil {
+			return &RotationResult{KeyID: res.KeyID, PublishResults: publishResults}, err
+		}
+
+		m.markSchedule(ctx, profile, res.NextRotation)
+		return res, nil
 	}
 
 	log.Info("Rotation needed", "reason", reason)
 
 	// 2. Generate new KeyPair [SEC:I-2]
+	// Reuse a key from a previous attempt whose publish or persist failed,
+	// or promote the next key published ahead of this rotation
+	pendingID := pendingKeyID{uid: profile.UID, generation: profile.Generation}
+	publishHash, err := output.PublishConfigHash(profile.Spec.Publish)
+	if err != nil {
+		return nil, err
+	}
+	var nextPublished bool
+	kp := m.pending.take(pendingID, m.clock.Now())
+	if kp != nil {
+		log.Info("Retrying with previously generated key", "keyID", kp.KeyID)
+	} else if profile.Spec.Rotation.PublishNextKey && !ForceRotationRequested(profile) {
+		// A forced rotation replaces a possibly compromised key: the staged
+		// key is discarded after the rotation instead of promoted
+		next, err := m.stagedNextKey(ctx, profile)
+		if err != nil {
+			return nil, err
+		}
+		if next != nil {
+			kp, nextPublished = next.KeyPair, next.PublishHash == publishHash
+			log.Info("Promoting next key", "keyID", kp.KeyID)
+		}
+	}
+	if kp == nil {
+		if profile.Status.PendingKeyID != "" {
+			// The published key is gone (restart, expiry or eviction); a second key gets published
+			log.V(0).Info("Published key was never persisted and is no longer cached, generating a new key",
+				"reason", "PendingKeyLost", "pendingKeyID", profile.Status.PendingKeyID)
+		}
+		var err error
+		if kp, err = m.generateKey(profile); err != nil {
+			return nil, err
+		}
+	}
+	// [SEC:I-2] Memory Wipe guaranteed via defer: immediately once persisted,
+	// otherwise by the pending key cache on retry, expiry or eviction
+	persisted := false
+	defer func() {
+		if persisted {
+			kp.Wipe()
+			return
+		}
+		m.pending.put(pendingID, kp, m.clock.Now())
+	}()
+
+	// Compute Fingerprint [SEC:T-1]
+	// Must be done before Wipe()
+	fingerprint, err := crypto.ComputeFingerprint(kp.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("fingerprint computation failed: %w", err)
+	}
+
+	// 3. Publish Public Key [SEC:S-2.4]
+	// Publish BEFORE distribution to ensure validators receive key first.
+	// Only the public component is handed to publishers [SEC:S-2].
+	// A key already published by an attempt whose persist failed, or published
+	// ahead of its promotion, is not published again.
+	var publishResults []publish.TargetResult
+	if profile.Status.PendingKeyID != "" && profile.Status.PendingKeyID == kp.KeyID {
+		log.Info("Key already published, retrying persist", "keyID", kp.KeyID)
+	} else if nextPublished {
+		log.V(1).Info("Next key already published", "keyID", kp.KeyID)
+	} else {
+		owner := publishOwner(profile, profile.Status.CurrentKeyID)
+		publishResults, err = m.publisher.PublishAll(ctx, owner, profile.Spec.Publish, kp.Public())
+		if err != nil {
+			metrics.RecordRotationError("publish", profile.Namespace, profile.Labels)
+			// Surface partial publish state so per-target status can be recorded
+			partial := &RotationResult{KeyID: kp.KeyID, PublishResults: publishResults}
+			return partial, fmt.Errorf("failed to publish public key: %w", err)
+		}
+	}
+
+	now := m.clock.Now()
+	nextRot := calculateNextRotation(now, profile.Spec.Rotation.Interval.Duration, rotationLead(profile))
+
+	// 4. Persist KeyPair to Secret [SEC:S-1]
+	// SecretWriter handles formatting, ownerRef, and atomic update; the
+	// schedule annotations go into the same update
+	if err := m.writer.Write(ctx, profile, kp, output.WithNextRotation(nextRot), output.WithRotationTime(now)); err != nil {
+		metrics.RecordRotationError("persist", profile.Namespace, profile.Labels)
+		// Record the published key so the retry persists it instead of publishing another
+		partial := &RotationResult{KeyID: kp.KeyID, PublishResults: publishResults, PendingKeyID: kp.KeyID}
+		return partial, fmt.Errorf("failed to persist key material: %w", err)
+	}
+	persisted = true
+	previousKeyID := profile.Status.CurrentKeyID
+	previousFingerprint := statusFingerprint(profile.Status.CurrentKeyFingerprint)
+	if integrityViolation != nil {
+		// The replaced material is untrusted: no grace period for it
+		if err := m.writer.DropPrevious(ctx, profile); err != nil {
+			log.Error(err, "Failed to drop replaced key material", "previousKeyID", previousKeyID)
+		} else {
+			previousKeyID, previousFingerprint = "", crypto.Fingerprint{}
+		}
+	}
+	if profile.Spec.Rotation.PublishNextKey || profile.Status.NextKeyID != "" {
+		// The promoted key, or one skipped by a forced rotation, is discarded;
+		// a leftover is discarded on the next staging
+		if err := m.writer.DropNext(ctx, profile); err != nil {
+			log.Error(err, "Failed to drop staged next key", "keyID", kp.KeyID)
+		}
+	}
+
+	// 5. Build CSR while the private key is still in memory
+	var csr []byte
+	if profile.Spec.Certificate != nil {
+		csr, err = m.buildCSR(profile, kp)
+		if err != nil {
+			// The key is already persisted; the controller reports the missing request
+			log.Error(err, "Failed to build certificate signing request", "keyID", kp.KeyID)
+		}
+	}
+
+	metrics.RecordRotation(kp.Algorithm, profile.Namespace, profile.Labels)
+	log.Info("Key rotated successfully", "keyID", kp.KeyID, "nextRotation", nextRot)
+
+	// 6. Return result for Status update
+	// The replaced key enters its grace period, unless it failed the integrity check
+	return &RotationResult{
+		Rotated:             true,
+		KeyID:               kp.KeyID,
+		RotationTime:        now,
+		NextRotation:        nextRot,
+		Reason:              reason,
+		Fingerprint:         fingerprint,
+		PreviousKeyID:       previousKeyID,
+		PreviousFingerprint: previousFingerprint,
+		PublishResults:      publishResults,
+		ClockSkew:           skew,
+		CSR:                 csr,
+		IntegrityViolation:  integrityViolation,
+	}, nil
+}
+
+func (m *manager) ExpirePrevious(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error {
+	if m.observe {
+		return ErrObserveMode
+	}
+	log := m.logger(ctx).WithValues("keyprofile", types.NamespacedName{Name: profile.Name, Namespace: profile.Namespace})
+
+	// [SEC:I-2] Same cleanup as an elapsed grace period, just earlier
+	if err := m.writer.DropPrevious(ctx, profile); err != nil {
+		metrics.RecordRotationError("cleanup", profile.Namespace, profile.Labels)
+		return fmt.Errorf("failed to drop previous key material: %w", err)
+	}
+	log.Info("Previous key expired before end of grace period", "previousKeyID", profile.Status.PreviousKeyID)
+	return nil
+}
+
+// storedAlgorithm returns the algorithm of the key in the profile's Secret,
+// or "" if there is no Secret or it does not record one.
+func (m *manager) storedAlgorithm(ctx context.Context, profile *openukrv1alpha1.KeyProfile) (string, error) {
+	info, err := m.writer.Inspect(ctx, profile)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect secret: %w", err)
+	}
+	if info == nil {
+		return "", nil
+	}
+	return info.Algorithm, nil
+}
+
+// generateKey generates a key pair for the profile's key spec.
+func (m *manager) generateKey(profile *openukrv1alpha1.KeyProfile) (*crypto.KeyPair, error) {
 	// Using configured algorithm and parameters
 	// Also passing AllowLegacyKeySize for BSI compliance check override
 	opts := crypto.GenerateOptions{
 		Algorithm:          profile.Spec.KeySpec.Algorithm,
 		Params:             profile.Spec.KeySpec.Params,
 		AllowLegacyKeySize: profile.Spec.KeySpec.AllowLegacyKeySize,
+		KeyIDTemplate:      profile.Spec.KeySpec.KeyIDTemplate,
 	}
 
-	start := time.Now()
+	// Over budget: defer instead of generating, nothing is reserved
+	if m.keygenLimit != nil {
+		now := m.clock.Now()
+		r := m.keygenLimit.ReserveN(now, 1)
+		if delay := r.DelayFrom(now); delay > 0 {
+			r.CancelAt(now)
+			return nil, &KeygenThrottledError{RetryAfter: delay}
+		}
+	}
+
+	start := m.clock.Now()
 	kp, err := m.keygen.Generate(opts)
-	duration := time.Since(start).Seconds()
+	duration := m.clock.Since(start).Seconds()
 
 	metrics.KeyGenerationDuration.WithLabelValues(opts.Algorithm).Observe(duration)
 
 	if err != nil {
-		metrics.RotationErrorsTotal.WithLabelValues("keygen", profile.Namespace).Inc()
+		metrics.RecordRotationError("keygen", profile.Namespace, profile.Labels)
 		return nil, fmt.Errorf("key generation failed: %w", err)
 	}
-	// [SEC:I-2] Memory Wipe guaranteed via defer
-	defer kp.Wipe()
+	return kp, nil
+}
 
-	// Compute Fingerprint [SEC:T-1]
-	// Must be done before Wipe()
-	fingerprint, err := crypto.ComputeFingerprint(kp.PublicKey)
+// stagedNextKey returns the staged next key, or nil if none is staged. A staged
+// key that is already current, or was generated for another KeySpec, is dropped.
+func (m *manager) stagedNextKey(ctx context.Context, profile *openukrv1alpha1.KeyProfile) (*output.NextKey, error) {
+	next, err := m.writer.LoadNext(ctx, profile)
 	if err != nil {
-		return nil, fmt.Errorf("fingerprint computation failed: %w", err)
+		return nil, fmt.Errorf("failed to load next key: %w", err)
+	}
+	if next == nil {
+		return nil, nil
+	}
+	specHash, err := output.KeySpecHash(profile.Spec.KeySpec)
+	if err != nil {
+		next.Wipe()
+		return nil, err
+	}
+	if next.KeyID != profile.Status.CurrentKeyID && next.KeySpecHash == specHash {
+		return next, nil
 	}
+	next.Wipe()
+	if err := m.writer.DropNext(ctx, profile); err != nil {
+		return nil, fmt.Errorf("failed to drop stale next key: %w", err)
+	}
+	return nil, nil
+}
 
-	// 3. Publish Public Key [SEC:S-2.4]
-	// Publish BEFORE distribution to ensure validators receive key first
-	if err := m.publisher.PublishAll(ctx, profile.Spec.Publish, kp); err != nil {
-		metrics.RotationErrorsTotal.WithLabelValues("publish", profile.Namespace).Inc()
-		return nil, fmt.Errorf("failed to publish public key: %w", err)
+// dropNextKey discards a key staged while Spec.Rotation.PublishNextKey was set.
+func (m *manager) dropNextKey(ctx context.Context, profile *openukrv1alpha1.KeyProfile) error {
+	if profile.Spec.Rotation.PublishNextKey || profile.Status.NextKeyID == "" {
+		return nil
 	}
+	if err := m.writer.DropNext(ctx, profile); err != nil {
+		metrics.RecordRotationError("cleanup", profile.Namespace, profile.Labels)
+		return fmt.Errorf("failed to drop next key: %w", err)
+	}
+	return nil
+}
 
-	// 4. Persist KeyPair to Secret [SEC:S-1]
-	// SecretWriter handles formatting, ownerRef, and atomic update
-	if err := m.writer.Write(ctx, profile, kp); err != nil {
-		metrics.RotationErrorsTotal.WithLabelValues("persist", profile.Namespace).Inc()
-		return nil, fmt.Errorf("failed to persist key material: %w", err)
+// publishNextKey generates, stages and publishes the key that replaces the
+// current one once res.NextRotation is within RotateBeforeExpiry
+// (Spec.Rotation.PublishNextKey). The staged key survives restarts; a key
+// staged but not yet published, or published before targets were added or
+// edited, is published on the next attempt.
+func (m *manager) publishNextKey(
+	ctx context.Context,
+	profile *openukrv1alpha1.KeyProfile,
+	res *RotationResult,
+) error {
+	lead := profile.Spec.Rotation.RotateBeforeExpiry.Duration
+	if !profile.Spec.Rotation.PublishNextKey || lead <= 0 || res.NextRotation.IsZero() || !res.PausedUntil.IsZero() {
+		return nil
+	}
+	if publishAt := res.NextRotation.Add(-lead); m.clock.Now().Before(publishAt) {
+		res.NextKeyDue = publishAt
+		return nil
 	}
+	log := m.logger(ctx).WithValues("keyprofile", types.NamespacedName{Name: profile.Name, Namespace: profile.Namespace})
 
-	now := time.Now()
-	nextRot := calculateNextRotation(now, profile.Spec.Rotation.Interval.Duration)
+	hash, err := output.PublishConfigHash(profile.Spec.Publish)
+	if err != nil {
+		return err
+	}
+	next, err := m.stagedNextKey(ctx, profile)
+	if err != nil {
+		return err
+	}
+	if next == nil {
+		kp, err := m.generateKey(profile)
+		if err != nil {
+			return err
+		}
+		if err := m.writer.StageNext(ctx, profile, kp); err != nil {
+			kp.Wipe()
+			metrics.RecordRotationError("persist", profile.Namespace, profile.Labels)
+			return fmt.Errorf("failed to stage next key: %w", err)
+		}
+		next = &output.NextKey{KeyPair: kp}
+	}
+	// [SEC:I-2] The staged copy is loaded again for promotion
+	defer next.Wipe()
 
-	metrics.RotationsTotal.WithLabelValues(kp.Algorithm, profile.Namespace).Inc()
-	log.Info("Key rotated successfully", "keyID", kp.KeyID, "nextRotation", nextRot)
+	fingerprint, err := crypto.ComputeFingerprint(next.PublicKey)
+	if err != nil {
+		return fmt.Errorf("fingerprint computation failed: %w", err)
+	}
+	if next.PublishHash != hash {
+		// [SEC:S-2] Only the public component is handed to publishers
+		if _, err := m.publisher.PublishAll(ctx, publishOwner(profile, ""), profile.Spec.Publish, next.Public()); err != nil {
+			metrics.RecordRotationError("publish", profile.Namespace, profile.Labels)
+			return fmt.Errorf("failed to publish next key: %w", err)
+		}
+		if err := m.writer.MarkNextPublished(ctx, profile, hash); err != nil {
+			return err
+		}
+		log.Info("Next key published ahead of rotation", "nextKeyID", next.KeyID, "promotion", res.NextRotation)
+	}
 
-	// 4. Return result for Status update
-	return &RotationResult{
-		Rotated:      true,
-		KeyID:        kp.KeyID,
-		RotationTime: now,
-		NextRotation: nextRot,
-		Fingerprint:  fingerprint,
-	}, nil
+	res.NextKeyID = next.KeyID
+	res.NextFingerprint = fingerprint
+	return nil
+}
+
+// publishOwner identifies profile to publishers. Its keys still in use — the
+// current key, the previous key during its grace period and the staged next
+// key — are retained. After a hard cutover (GraceMode hard), the previous key
+// and replaced, the key a rotation replaces, are withdrawn instead; otherwise
+// replaced, or the previous key, is the owner's Previous.
+func publishOwner(profile *openukrv1alpha1.KeyProfile, replaced string) publish.Owner {
+	owner := publish.Owner{NamespacedName: types.NamespacedName{Name: profile.Name, Namespace: profile.Namespace}}
+	hard := profile.Spec.Rotation.GraceMode == openukrv1alpha1.GraceModeHard
+	if !hard {
+		owner.Previous = profile.Status.PreviousKeyID
+		if replaced != "" {
+			owner.Previous = replaced
+		}
+	}
+	for _, keyID := range []string{profile.Status.CurrentKeyID, profile.Status.PreviousKeyID, profile.Status.NextKeyID} {
+		switch {
+		case keyID == "":
+		case hard && (keyID == replaced || keyID == profile.Status.PreviousKeyID):
+			owner.Withdraw = append(owner.Withdraw, keyID)
+		default:
+			owner.Retain = append(owner.Retain, keyID)
+		}
+	}
+	return owner
+}
+
+// republishIfChanged publishes the current key again when Spec.Publish differs
+// from the configuration it was last published to, without rotating. Secrets
+// written before the publish hash was recorded are re-published once.
+// Returns nil results if nothing was published.
+func (m *manager) republishIfChanged(
+	ctx context.Context,
+	profile *openukrv1alpha1.KeyProfile,
+) ([]publish.TargetResult, error) {
+	log := m.logger(ctx).WithValues("keyprofile", types.NamespacedName{Name: profile.Name, Namespace: profile.Namespace})
+
+	hash, err := output.PublishConfigHash(profile.Spec.Publish)
+	if err != nil {
+		return nil, err
+	}
+	info, err := m.writer.Inspect(ctx, profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect secret: %w", err)
+	}
+	if info == nil || info.PublishHash == hash {
+		return nil, nil
+	}
+	if info.PublicKey == nil {
+		// e.g. keystore formats: new targets receive a key at the next rotation
+		log.V(1).Info("Publish configuration changed, but the Secret holds no public key to re-publish",
+			"keyID", info.KeyID)
+		return nil, nil
+	}
+
+	pub := &crypto.PublicKeyInfo{
+		KeyID:     info.KeyID,
+		PublicKey: info.PublicKey,
+		Algorithm: info.Algorithm,
+		CreatedAt: info.LastRotation,
+	}
+	results, err := m.publisher.PublishAll(ctx, publishOwner(profile, ""), profile.Spec.Publish, pub)
+	if err != nil {
+		metrics.RecordRotationError("publish", profile.Namespace, profile.Labels)
+		return results, fmt.Errorf("failed to re-publish public key: %w", err)
+	}
+	if err := m.writer.MarkPublished(ctx, profile, hash); err != nil {
+		return results, err
+	}
+	log.Info("Publish configuration changed, re-published current key", "keyID", info.KeyID)
+	return results, nil
+}
+
+// markSchedule mirrors the rotation schedule onto the Secret's annotations.
+// They are informational, so a failure is only logged and retried on the
+// next reconcile.
+func (m *manager) markSchedule(ctx context.Context, profile *openukrv1alpha1.KeyProfile, next time.Time) {
+	if err := m.writer.MarkSchedule(ctx, profile, next); err != nil {
+		m.logger(ctx).Error(err, "Failed to record rotation schedule on Secret",
+			"keyprofile", types.NamespacedName{Name: profile.Name, Namespace: profile.Namespace})
+	}
+}
+
+// observeKey reports the state of the existing Secret without changing it.
+// A due rotation is only logged.
+func (m *manager) observeKey(
+	ctx context.Context,
+	profile *openukrv1alpha1.KeyProfile,
+	needsRotation bool,
+	reason string,
+	skew time.Duration,
+	pausedUntil time.Time,
+) (*RotationResult, error) {
+	log := m.logger(ctx).WithValues("keyprofile", types.NamespacedName{Name: profile.Name, Namespace: profile.Namespace})
+
+	info, err := m.writer.Inspect(ctx, profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect secret: %w", err)
+	}
+	previousFingerprint, err := previousStatusFingerprint(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &RotationResult{
+		Observe:             true,
+		KeyID:               profile.Status.CurrentKeyID,
+		RotationTime:        lastRotation(profile),
+		Fingerprint:         statusFingerprint(profile.Status.CurrentKeyFingerprint),
+		PreviousKeyID:       profile.Status.PreviousKeyID,
+		PreviousFingerprint: previousFingerprint,
+		ClockSkew:           skew,
+		PausedUntil:         pausedUntil,
+	}
+	if info != nil {
+		res.KeyID = info.KeyID
+		res.Fingerprint = info.Fingerprint
+		if !info.LastRotation.IsZero() {
+			res.RotationTime = info.LastRotation
+		}
+	}
+	if !res.RotationTime.IsZero() {
+		res.NextRotation = calculateNextRotation(
+			res.RotationTime,
+			profile.Spec.Rotation.Interval.Duration,
+			rotationLead(profile),
+		)
+	}
+
+	if info == nil {
+		log.Info("No key Secret found, not generating in observe mode")
+	} else if needsRotation {
+		log.Info("Rotation due, not rotating in observe mode", "reason", reason, "keyID", res.KeyID)
+	}
+	return res, nil
+}
+
+// statusFingerprint parses the current key's fingerprint recorded in the status.
+// Malformed values yield the zero Fingerprint; the writer's Verify rejects them.
+func statusFingerprint(s string) crypto.Fingerprint {
+	fp, err := crypto.ParseFingerprint(s)
+	if err != nil {
+		return crypto.Fingerprint{}
+	}
+	return fp
+}
+
+// previousStatusFingerprint parses Status.PreviousKeyFingerprint. Nothing
+// verifies the previous key against it, so a malformed value is an error rather
+// than silently dropped from the status. [SEC:T-1]
+func previousStatusFingerprint(profile *openukrv1alpha1.KeyProfile) (crypto.Fingerprint, error) {
+	fp, err := crypto.ParseFingerprint(profile.Status.PreviousKeyFingerprint)
+	if err != nil {
+		return crypto.Fingerprint{}, fmt.Errorf("malformed status.previousKeyFingerprint: %w", err)
+	}
+	return fp, nil
+}
+
+// logger returns the request-scoped logger from ctx (carrying the reconcile ID when
+// called from the controller), falling back to the manager's logger.
+// Verbosity follows logr: Info for state changes, V(1) for per-reconcile detail.
+func (m *manager) logger(ctx context.Context) logr.Logger {
+	if l, err := logr.FromContext(ctx); err == nil {
+		return l.WithName("rotation")
+	}
+	return m.log
+}
+
+// buildCSR creates the certificate signing request for a new key, bound to the
+// profile's SPIFFE ID if configured.
+func (m *manager) buildCSR(profile *openukrv1alpha1.KeyProfile, kp *crypto.KeyPair) ([]byte, error) {
+	spiffeID, err := output.SPIFFEIDForProfile(profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build SPIFFE ID: %w", err)
+	}
+	csr, err := output.NewCSR(kp, spiffeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build CSR: %w", err)
+	}
+	return csr, nil
+}
+
+// lastRotation returns Status.LastRotation, or the zero time if it is unset.
+func lastRotation(profile *openukrv1alpha1.KeyProfile) time.Time {
+	if profile.Status.LastRotation == nil {
+		return time.Time{}
+	}
+	return profile.Status.LastRotation.Time
+}
+
+// clockSkew returns how far Status.LastRotation lies in the future beyond
+// MaxClockSkew, or 0 if within tolerance.
+func (m *manager) clockSkew(profile *openukrv1alpha1.KeyProfile) time.Duration {
+	if lastRotation(profile).IsZero() {
+		return 0
+	}
+	ahead := lastRotation(profile).Sub(m.clock.Now())
+	if ahead <= MaxClockSkew {
+		return 0
+	}
+	return ahead
+}
+
+// gracePeriodExpired reports whether a previous key exists and its grace period has ended.
+func (m *manager) gracePeriodExpired(profile *openukrv1alpha1.KeyProfile) bool {
+	if profile.Status.PreviousKeyID == "" || lastRotation(profile).IsZero() {
+		return false
+	}
+	graceEnd := lastRotation(profile).Add(profile.Spec.Rotation.GracePeriod.Duration)
+	return m.clock.Now().After(graceEnd)
 }
 
 func (m *manager) checkRotationNeeded(profile *openukrv1alpha1.KeyProfile) (bool, string) {
 	// Case 0: No Key yet
-	if profile.Status.CurrentKeyID == "" || profile.Status.LastRotation.IsZero() {
+	if profile.Status.CurrentKeyID == "" {
 		return true, "initial key generation"
 	}
+	// A key without a rotation time (partial status update, manual edit) has an
+	// unknown age: treat it as due rather than guessing
+	if lastRotation(profile).IsZero() {
+		return true, fmt.Sprintf("lastRotation missing for key %s", profile.Status.CurrentKeyID)
+	}
+
+	// Operator-requested rotation, independent of the schedule
+	if ForceRotationRequested(profile) {
+		return true, fmt.Sprintf("forced by %s annotation", ForceRotateAnnotation)
+	}
 
 	// Case 1: Time-based rotation
 	interval := profile.Spec.Rotation.Interval.Duration
@@ -168,27 +859,60 @@ func (m *manager) checkRotationNeeded(profile *openukrv1alpha1.KeyProfile) (bool
 		return false, "rotation disabled (interval=0)"
 	}
 
-	now := time.Now()
-	nextRotation := profile.Status.LastRotation.Time.Add(interval)
+	now := m.clock.Now()
+	lead := rotationLead(profile)
+	nextRotation := calculateNextRotation(lastRotation(profile), interval, lead)
 
 	if now.After(nextRotation) {
+		if lead > 0 {
+			return true, fmt.Sprintf("interval %s expires within %s (due: %s)", interval, lead, nextRotation.Add(lead))
+		}
 		return true, fmt.Sprintf("interval %s expired (due: %s)", interval, nextRotation)
 	}
 
-	// Case 2: Spec change? (Algorithm change requires rotation)
-	// This usually requires comparing stored key metadata vs spec.
-	// Since we don't track *stored* algorithm in Status (yet, only KeyID),
-	// detecting spec change might require inspecting the Secret or adding fields to Status.
-	// For MVP (M1), we rely on time-based or manual trigger (delete Secret or Status).
-	// [Enhancement]: Add 'Status.Algorithm' to detect changes.
-	// Status.LastRotation covers the timing.
-
+	// Algorithm changes are handled by the algorithm migration in EnsureKey.
 	return false, ""
 }
 
-func calculateNextRotation(lastRot time.Time, interval time.Duration) time.Time {
+// pausedUntil returns Spec.Rotation.PauseUntil while it is in the future, or the zero time.
+// Initial key generation is never paused: without a key there is nothing to keep stable.
+func (m *manager) pausedUntil(profile *openukrv1alpha1.KeyProfile) time.Time {
+	pause := profile.Spec.Rotation.PauseUntil
+	if pause == nil || profile.Status.CurrentKeyID == "" || lastRotation(profile).IsZero() {
+		return time.Time{}
+	}
+	if !m.clock.Now().Before(pause.Time) {
+		return time.Time{}
+	}
+	return pause.Time
+}
+
+// ForceRotationRequested reports whether profile has a ForceRotateAnnotation
+// that has not been handled yet, i.e. whose value differs from
+// Status.LastForcedRotation.
+func ForceRotationRequested(profile *openukrv1alpha1.KeyProfile) bool {
+	value, ok := profile.Annotations[ForceRotateAnnotation]
+	return ok && value != profile.Status.LastForcedRotation
+}
+
+// rotationLead returns how far rotation is brought forward. With PublishNextKey
+// the RotateBeforeExpiry lead publishes the next key instead, and rotation
+// happens on the scheduled tick.
+func rotationLead(profile *openukrv1alpha1.KeyProfile) time.Duration {
+	if profile.Spec.Rotation.PublishNextKey {
+		return 0
+	}
+	return profile.Spec.Rotation.RotateBeforeExpiry.Duration
+}
+
+// calculateNextRotation returns when the key is next due: one interval after
+// lastRot, brought forward by the RotateBeforeExpiry lead.
+func calculateNextRotation(lastRot time.Time, interval, lead time.Duration) time.Time {
 	if interval == 0 {
 		return time.Time{} // Forever
 	}
-	return lastRot.Add(interval)
+	if lead <= 0 || lead >= interval {
+		lead = 0 // Rejected by the webhook; ignore rather than rotate constantly
+	}
+	return lastRot.Add(interval - lead)
 }
@@ -0,0 +1,1088 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+	"github.com/openukr/openukr/pkg/crypto"
+	"github.com/openukr/openukr/pkg/output"
+	"github.com/openukr/openukr/pkg/output/outputtest"
+	"github.com/openukr/openukr/pkg/publish"
+	"github.com/openukr/openukr/pkg/publish/publishtest"
+)
+
+func newTestProfile(lastRotation time.Time) *openukrv1alpha1.KeyProfile {
+	return &openukrv1alpha1.KeyProfile{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+		Spec: openukrv1alpha1.KeyProfileSpec{
+			KeySpec: openukrv1alpha1.KeySpec{
+				Algorithm: crypto.AlgorithmEC,
+				Params:    map[string]string{"curve": crypto.CurveP256},
+			},
+			Rotation: openukrv1alpha1.RotationPolicy{
+				Interval:    metav1.Duration{Duration: 24 * time.Hour},
+				GracePeriod: metav1.Duration{Duration: time.Hour},
+			},
+			Output: openukrv1alpha1.OutputConfig{SecretName: "test-key"},
+		},
+		Status: openukrv1alpha1.KeyProfileStatus{
+			CurrentKeyID:           "ec-P-256-current",
+			CurrentKeyFingerprint:  "SHA256:current",
+			PreviousKeyID:          "ec-P-256-previous",
+			PreviousKeyFingerprint: crypto.FingerprintFromDER([]byte("previous")),
+			LastRotation:           &metav1.Time{Time: lastRotation},
+		},
+	}
+}
+
+func TestEnsureKeyGracePeriodCleanup(t *testing.T) {
+	t.Parallel()
+
+	lastRotation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		elapsed           time.Duration
+		wantDrop          bool
+		wantPreviousKeyID string
+	}{
+		{
+			name:              "within grace period keeps previous key",
+			elapsed:           30 * time.Minute,
+			wantDrop:          false,
+			wantPreviousKeyID: "ec-P-256-previous",
+		},
+		{
+			name:              "past grace period drops previous key",
+			elapsed:           2 * time.Hour,
+			wantDrop:          true,
+			wantPreviousKeyID: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			writer := &outputtest.FakeWriter{}
+			clk := clocktesting.NewFakePassiveClock(lastRotation)
+			clk.SetTime(lastRotation.Add(tt.elapsed))
+			m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, &publishtest.FakePublisher{}, WithClock(clk))
+
+			res, err := m.EnsureKey(context.Background(), newTestProfile(lastRotation))
+			if err != nil {
+				t.Fatalf("EnsureKey() error = %v", err)
+			}
+			if res.Rotated {
+				t.Errorf("EnsureKey() rotated, want no rotation")
+			}
+			if (writer.Drops > 0) != tt.wantDrop {
+				t.Errorf("DropPrevious called %d times, wantDrop %v", writer.Drops, tt.wantDrop)
+			}
+			if res.PreviousKeyID != tt.wantPreviousKeyID {
+				t.Errorf("PreviousKeyID = %q, want %q", res.PreviousKeyID, tt.wantPreviousKeyID)
+			}
+		})
+	}
+}
+
+func TestEnsureKeyRejectsMalformedPreviousFingerprint(t *testing.T) {
+	t.Parallel()
+
+	lastRotation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	profile := newTestProfile(lastRotation)
+	profile.Status.PreviousKeyFingerprint = "SHA256:previous"
+	m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), &outputtest.FakeWriter{}, &publishtest.FakePublisher{},
+		WithClock(clocktesting.NewFakePassiveClock(lastRotation.Add(time.Minute))))
+
+	if _, err := m.EnsureKey(context.Background(), profile); err == nil {
+		t.Fatal("EnsureKey() with a malformed previous fingerprint succeeded, want error")
+	}
+}
+
+func TestEnsureKeyRotatesWhenIntervalElapses(t *testing.T) {
+	t.Parallel()
+
+	lastRotation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clocktesting.NewFakePassiveClock(lastRotation)
+	writer := &outputtest.FakeWriter{}
+	m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, &publishtest.FakePublisher{}, WithClock(clk))
+
+	// Just before the interval: no rotation
+	clk.SetTime(lastRotation.Add(24*time.Hour - time.Second))
+	res, err := m.EnsureKey(context.Background(), newTestProfile(lastRotation))
+	if err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if res.Rotated {
+		t.Fatalf("EnsureKey() rotated before interval elapsed")
+	}
+
+	// Just after the interval: rotation, scheduled from the fake clock
+	now := lastRotation.Add(24*time.Hour + time.Second)
+	clk.SetTime(now)
+	res, err = m.EnsureKey(context.Background(), newTestProfile(lastRotation))
+	if err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if !res.Rotated {
+		t.Fatalf("EnsureKey() did not rotate after interval elapsed")
+	}
+	if !res.RotationTime.Equal(now) {
+		t.Errorf("RotationTime = %s, want %s", res.RotationTime, now)
+	}
+	if want := now.Add(24 * time.Hour); !res.NextRotation.Equal(want) {
+		t.Errorf("NextRotation = %s, want %s", res.NextRotation, want)
+	}
+	if res.PreviousKeyID != "ec-P-256-current" {
+		t.Errorf("PreviousKeyID = %q, want %q", res.PreviousKeyID, "ec-P-256-current")
+	}
+	if writer.Writes != 1 {
+		t.Errorf("Write called %d times, want 1", writer.Writes)
+	}
+}
+
+func TestEnsureKeyRotatesBeforeExpiry(t *testing.T) {
+	t.Parallel()
+
+	lastRotation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	lead := 2 * time.Hour
+	clk := clocktesting.NewFakePassiveClock(lastRotation)
+	m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), &outputtest.FakeWriter{}, &publishtest.FakePublisher{}, WithClock(clk))
+	newProfile := func() *openukrv1alpha1.KeyProfile {
+		profile := newTestProfile(lastRotation)
+		profile.Spec.Rotation.RotateBeforeExpiry = metav1.Duration{Duration: lead}
+		return profile
+	}
+
+	// Just before the lead window: no rotation, next rotation brought forward
+	clk.SetTime(lastRotation.Add(24*time.Hour - lead - time.Second))
+	res, err := m.EnsureKey(context.Background(), newProfile())
+	if err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if res.Rotated {
+		t.Fatalf("EnsureKey() rotated before the lead window")
+	}
+	if want := lastRotation.Add(24*time.Hour - lead); !res.NextRotation.Equal(want) {
+		t.Errorf("NextRotation = %s, want %s", res.NextRotation, want)
+	}
+
+	// Inside the lead window, well before the tick: rotation fires early
+	now := lastRotation.Add(24*time.Hour - lead + time.Second)
+	clk.SetTime(now)
+	res, err = m.EnsureKey(context.Background(), newProfile())
+	if err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if !res.Rotated {
+		t.Fatalf("EnsureKey() did not rotate %s before expiry", lead)
+	}
+	if want := now.Add(24*time.Hour - lead); !res.NextRotation.Equal(want) {
+		t.Errorf("NextRotation = %s, want %s", res.NextRotation, want)
+	}
+}
+
+func TestEnsureKeyFutureLastRotation(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clocktesting.NewFakePassiveClock(now)
+	m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), &outputtest.FakeWriter{}, &publishtest.FakePublisher{}, WithClock(clk))
+
+	// Restored from a backup taken "tomorrow"
+	res, err := m.EnsureKey(context.Background(), newTestProfile(now.Add(24*time.Hour)))
+	if err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if !res.Rotated {
+		t.Fatalf("EnsureKey() did not rotate with a future lastRotation")
+	}
+	if res.ClockSkew != 24*time.Hour {
+		t.Errorf("ClockSkew = %s, want %s", res.ClockSkew, 24*time.Hour)
+	}
+	if want := now.Add(24 * time.Hour); !res.NextRotation.Equal(want) {
+		t.Errorf("NextRotation = %s, want %s", res.NextRotation, want)
+	}
+
+	// Within tolerance: no skew reported, no rotation
+	res, err = m.EnsureKey(context.Background(), newTestProfile(now.Add(MaxClockSkew/2)))
+	if err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if res.Rotated || res.ClockSkew != 0 {
+		t.Errorf("EnsureKey() within tolerance: Rotated = %v, ClockSkew = %s", res.Rotated, res.ClockSkew)
+	}
+}
+
+func TestEnsureKeyRotatesOnIntegrityViolation(t *testing.T) {
+	t.Parallel()
+
+	lastRotation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	writer := &outputtest.FakeWriter{VerifyErr: fmt.Errorf("%w: public key does not match private key", output.ErrIntegrity)}
+	clk := clocktesting.NewFakePassiveClock(lastRotation.Add(time.Hour))
+	m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, &publishtest.FakePublisher{}, WithClock(clk))
+
+	// Not due, but the stored material is replaced at once
+	res, err := m.EnsureKey(context.Background(), newTestProfile(lastRotation))
+	if err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if !res.Rotated || !errors.Is(res.IntegrityViolation, output.ErrIntegrity) || res.IntegrityVerified {
+		t.Fatalf("EnsureKey() = {rotated: %v, integrity: %v, verified: %v}, want a corrective rotation",
+			res.Rotated, res.IntegrityViolation, res.IntegrityVerified)
+	}
+	if !strings.HasPrefix(res.Reason, "integrity violation") {
+		t.Errorf("Reason = %q, want integrity violation", res.Reason)
+	}
+	if writer.Writes != 1 {
+		t.Errorf("Write called %d times, want 1", writer.Writes)
+	}
+	// The replaced material gets no grace period
+	if writer.Drops != 1 || res.PreviousKeyID != "" {
+		t.Errorf("DropPrevious called %d times, PreviousKeyID = %q, want the replaced key dropped", writer.Drops, res.PreviousKeyID)
+	}
+
+	// A paused profile keeps failing instead of rotating
+	profile := newTestProfile(lastRotation)
+	profile.Spec.Rotation.PauseUntil = &metav1.Time{Time: lastRotation.Add(48 * time.Hour)}
+	if _, err := m.EnsureKey(context.Background(), profile); !errors.Is(err, output.ErrIntegrity) {
+		t.Fatalf("EnsureKey() paused error = %v, want ErrIntegrity", err)
+	}
+	if writer.Writes != 1 {
+		t.Errorf("Write called %d times while paused, want 1", writer.Writes)
+	}
+}
+
+func TestEnsureKeyPauseUntil(t *testing.T) {
+	t.Parallel()
+
+	lastRotation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	due := lastRotation.Add(24 * time.Hour)
+	pauseUntil := due.Add(6 * time.Hour)
+
+	profile := newTestProfile(lastRotation)
+	profile.Spec.Rotation.PauseUntil = &metav1.Time{Time: pauseUntil}
+
+	writer := &outputtest.FakeWriter{}
+	clk := clocktesting.NewFakePassiveClock(due.Add(time.Hour))
+	m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, &publishtest.FakePublisher{}, WithClock(clk))
+
+	// Overdue but paused: no rotation, integrity still verified, resumes at pauseUntil
+	res, err := m.EnsureKey(context.Background(), profile)
+	if err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if res.Rotated {
+		t.Error("EnsureKey() rotated while paused")
+	}
+	if writer.Verifies != 1 {
+		t.Errorf("Verify called %d times, want 1", writer.Verifies)
+	}
+	if !res.PausedUntil.Equal(pauseUntil) {
+		t.Errorf("PausedUntil = %v, want %v", res.PausedUntil, pauseUntil)
+	}
+	if !res.NextRotation.Equal(pauseUntil) {
+		t.Errorf("NextRotation = %v, want %v", res.NextRotation, pauseUntil)
+	}
+
+	// Crossing the boundary resumes normal scheduling
+	clk.SetTime(pauseUntil.Add(time.Second))
+	res, err = m.EnsureKey(context.Background(), profile)
+	if err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if !res.Rotated {
+		t.Error("EnsureKey() did not rotate after pause ended")
+	}
+	if !res.PausedUntil.IsZero() {
+		t.Errorf("PausedUntil = %v, want zero", res.PausedUntil)
+	}
+	if writer.Writes != 1 {
+		t.Errorf("Write called %d times, want 1", writer.Writes)
+	}
+}
+
+// countingKeyGenerator counts Generate calls.
+type countingKeyGenerator struct {
+	crypto.KeyGenerator
+	calls int
+}
+
+func (g *countingKeyGenerator) Generate(opts crypto.GenerateOptions) (*crypto.KeyPair, error) {
+	g.calls++
+	return g.KeyGenerator.Generate(opts)
+}
+
+func TestEnsureKeyReusesKeyAfterPersistFailure(t *testing.T) {
+	t.Parallel()
+
+	lastRotation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	profile := newTestProfile(lastRotation)
+	profile.UID = "profile-uid"
+	profile.Generation = 1
+
+	keygen := &countingKeyGenerator{KeyGenerator: crypto.NewKeyGenerator()}
+	writer := &outputtest.FakeWriter{FailWrites: 1}
+	clk := clocktesting.NewFakePassiveClock(lastRotation.Add(25 * time.Hour))
+	m := NewManager(logr.Discard(), keygen, writer, &publishtest.FakePublisher{}, WithClock(clk))
+
+	if _, err := m.EnsureKey(context.Background(), profile); err == nil {
+		t.Fatal("EnsureKey() succeeded, want persist error")
+	}
+	res, err := m.EnsureKey(context.Background(), profile)
+	if err != nil {
+		t.Fatalf("EnsureKey() retry error = %v", err)
+	}
+
+	if keygen.calls != 1 {
+		t.Errorf("Generate called %d times, want 1", keygen.calls)
+	}
+	if len(writer.KeyIDs) != 2 || writer.KeyIDs[0] != writer.KeyIDs[1] {
+		t.Errorf("persisted keyIDs = %v, want the same key retried", writer.KeyIDs)
+	}
+	if res.KeyID != writer.KeyIDs[0] {
+		t.Errorf("KeyID = %q, want %q", res.KeyID, writer.KeyIDs[0])
+	}
+
+	// Once persisted the key is no longer cached: the next rotation generates afresh
+	clk.SetTime(clk.Now().Add(25 * time.Hour))
+	profile.Status.LastRotation = &metav1.Time{Time: res.RotationTime}
+	if _, err := m.EnsureKey(context.Background(), profile); err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if keygen.calls != 2 {
+		t.Errorf("Generate called %d times, want 2", keygen.calls)
+	}
+}
+
+func TestEnsureKeyPersistFailsAfterPublish(t *testing.T) {
+	t.Parallel()
+
+	lastRotation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	profile := newTestProfile(lastRotation)
+	profile.UID = "profile-uid"
+	profile.Spec.Publish = []openukrv1alpha1.PublishTarget{
+		{Type: "http", Config: map[string]string{"endpoint": "https://keys.example"}},
+	}
+
+	writer := &outputtest.FakeWriter{FailWrites: 1}
+	publisher := &publishtest.FakePublisher{}
+	clk := clocktesting.NewFakePassiveClock(lastRotation.Add(25 * time.Hour))
+	m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, publisher, WithClock(clk))
+
+	res, err := m.EnsureKey(context.Background(), profile)
+	if err == nil {
+		t.Fatal("EnsureKey() succeeded, want persist error")
+	}
+	if res == nil || res.PendingKeyID == "" || len(res.PublishResults) != 1 {
+		t.Fatalf("EnsureKey() partial result = %+v, want PendingKeyID and publish results", res)
+	}
+
+	// The controller records the pending key; the retry persists it without publishing
+	profile.Status.PendingKeyID = res.PendingKeyID
+	res, err = m.EnsureKey(context.Background(), profile)
+	if err != nil {
+		t.Fatalf("EnsureKey() retry error = %v", err)
+	}
+	if res.KeyID != profile.Status.PendingKeyID {
+		t.Errorf("KeyID = %q, want pending key %q", res.KeyID, profile.Status.PendingKeyID)
+	}
+	if res.PendingKeyID != "" {
+		t.Errorf("PendingKeyID = %q after successful persist, want empty", res.PendingKeyID)
+	}
+	if len(publisher.KeyIDs) != 1 {
+		t.Errorf("published keyIDs = %v, want the pending key published once", publisher.KeyIDs)
+	}
+	if len(writer.KeyIDs) != 2 || writer.KeyIDs[1] != publisher.KeyIDs[0] {
+		t.Errorf("persisted keyIDs = %v, want the published key %s retried", writer.KeyIDs, publisher.KeyIDs[0])
+	}
+}
+
+func TestEnsureKeyRotationReason(t *testing.T) {
+	t.Parallel()
+
+	lastRotation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		noKey       bool
+		force       bool
+		handled     bool
+		wantRotated bool
+		wantReason  string
+	}{
+		{name: "initial key generation", now: lastRotation, noKey: true, wantRotated: true, wantReason: "initial key generation"},
+		{name: "interval expired", now: lastRotation.Add(25 * time.Hour), wantRotated: true, wantReason: "interval 24h0m0s expired"},
+		{name: "clock skew", now: lastRotation.Add(-2 * time.Hour), wantRotated: true, wantReason: "clock skew"},
+		{name: "not due", now: lastRotation.Add(time.Hour)},
+		{name: "forced", now: lastRotation.Add(time.Hour), force: true, wantRotated: true, wantReason: "forced by"},
+		{name: "forced request handled", now: lastRotation.Add(time.Hour), force: true, handled: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			profile := newTestProfile(lastRotation)
+			if tt.noKey {
+				profile.Status = openukrv1alpha1.KeyProfileStatus{}
+			}
+			if tt.force {
+				profile.Annotations = map[string]string{ForceRotateAnnotation: "2026-01-01T01:00:00Z"}
+			}
+			if tt.handled {
+				profile.Status.LastForcedRotation = "2026-01-01T01:00:00Z"
+			}
+			clk := clocktesting.NewFakePassiveClock(tt.now)
+			m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), &outputtest.FakeWriter{}, &publishtest.FakePublisher{}, WithClock(clk))
+
+			res, err := m.EnsureKey(context.Background(), profile)
+			if err != nil {
+				t.Fatalf("EnsureKey() error = %v", err)
+			}
+			if res.Rotated != tt.wantRotated {
+				t.Fatalf("Rotated = %v, want %v", res.Rotated, tt.wantRotated)
+			}
+			if tt.wantReason == "" && res.Reason != "" {
+				t.Errorf("Reason = %q, want empty", res.Reason)
+			}
+			if !strings.HasPrefix(res.Reason, tt.wantReason) {
+				t.Errorf("Reason = %q, want prefix %q", res.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestEnsureKeyNilLastRotation(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	profile := newTestProfile(now)
+	profile.Status.LastRotation = nil
+	// A pause would normally defer rotation; an unknown key age must not be paused
+	profile.Spec.Rotation.PauseUntil = &metav1.Time{Time: now.Add(time.Hour)}
+
+	writer := &outputtest.FakeWriter{}
+	m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, &publishtest.FakePublisher{},
+		WithClock(clocktesting.NewFakePassiveClock(now)))
+
+	res, err := m.EnsureKey(context.Background(), profile)
+	if err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if !res.Rotated {
+		t.Fatal("EnsureKey() did not rotate a key without lastRotation")
+	}
+	if !strings.HasPrefix(res.Reason, "lastRotation missing") {
+		t.Errorf("Reason = %q, want prefix %q", res.Reason, "lastRotation missing")
+	}
+	if res.PreviousKeyID != "ec-P-256-current" {
+		t.Errorf("PreviousKeyID = %q, want ec-P-256-current", res.PreviousKeyID)
+	}
+	if writer.Verifies != 0 || writer.Drops != 0 {
+		t.Errorf("Verify/DropPrevious called %d/%d times, want 0", writer.Verifies, writer.Drops)
+	}
+}
+
+func TestEnsureKeyObserveModeNeverWrites(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Rotation due and grace period expired: an active manager would rotate and drop
+	profile := newTestProfile(now.Add(-48 * time.Hour))
+	secretRotation := now.Add(-30 * time.Hour)
+	fp, err := crypto.ParseFingerprint("SHA256:" + strings.Repeat("A", 43))
+	if err != nil {
+		t.Fatalf("ParseFingerprint() error = %v", err)
+	}
+
+	writer := &outputtest.FakeWriter{Info: &output.SecretInfo{
+		KeyID:        "ec-P-256-existing",
+		Fingerprint:  fp,
+		LastRotation: secretRotation,
+	}}
+	keygen := &countingKeyGenerator{KeyGenerator: crypto.NewKeyGenerator()}
+	publisher := &publishtest.FakePublisher{}
+	m := NewManager(logr.Discard(), keygen, writer, publisher,
+		WithClock(clocktesting.NewFakePassiveClock(now)), WithObserveMode())
+
+	res, err := m.EnsureKey(context.Background(), profile)
+	if err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if keygen.calls != 0 || len(publisher.KeyIDs) != 0 || len(writer.KeyIDs) != 0 || writer.Drops != 0 {
+		t.Errorf("observe mode mutated: keygen %d, publishes %d, writes %d, drops %d, want all 0",
+			keygen.calls, len(publisher.KeyIDs), len(writer.KeyIDs), writer.Drops)
+	}
+	if !res.Observe || res.Rotated {
+		t.Errorf("Observe = %v, Rotated = %v, want true, false", res.Observe, res.Rotated)
+	}
+	if res.KeyID != "ec-P-256-existing" || res.Fingerprint != fp {
+		t.Errorf("KeyID = %q, Fingerprint = %s, want values from the Secret", res.KeyID, res.Fingerprint)
+	}
+	if want := secretRotation.Add(24 * time.Hour); !res.NextRotation.Equal(want) {
+		t.Errorf("NextRotation = %v, want %v", res.NextRotation, want)
+	}
+}
+
+func TestEnsureKeyRepublishesOnPublishConfigChange(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	profile := newTestProfile(now.Add(-time.Minute))
+	published, err := output.PublishConfigHash(profile.Spec.Publish)
+	if err != nil {
+		t.Fatalf("PublishConfigHash() error = %v", err)
+	}
+	// A target is added while the key is not due
+	profile.Spec.Publish = append(profile.Spec.Publish, openukrv1alpha1.PublishTarget{
+		Type:   publish.TargetTypeFilesystem,
+		Config: map[string]string{"path": "/var/run/keys"},
+	})
+
+	kp, err := crypto.NewKeyGenerator().Generate(crypto.GenerateOptions{
+		Algorithm: crypto.AlgorithmEC,
+		Params:    map[string]string{"curve": crypto.CurveP256},
+	})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	writer := &outputtest.FakeWriter{Info: &output.SecretInfo{
+		KeyID:       "ec-P-256-current",
+		Algorithm:   crypto.AlgorithmEC,
+		PublicKey:   kp.PublicKey,
+		PublishHash: published,
+	}}
+	keygen := &countingKeyGenerator{KeyGenerator: crypto.NewKeyGenerator()}
+	publisher := &publishtest.FakePublisher{}
+	m := NewManager(logr.Discard(), keygen, writer, publisher,
+		WithClock(clocktesting.NewFakePassiveClock(now)))
+
+	res, err := m.EnsureKey(context.Background(), profile)
+	if err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if res.Rotated || keygen.calls != 0 || len(writer.KeyIDs) != 0 {
+		t.Errorf("rotated on publish config change: Rotated %v, keygen %d, writes %d",
+			res.Rotated, keygen.calls, len(writer.KeyIDs))
+	}
+	if !slices.Equal(publisher.KeyIDs, []string{"ec-P-256-current"}) {
+		t.Errorf("published key IDs = %v, want [ec-P-256-current]", publisher.KeyIDs)
+	}
+	if res.KeyID != "ec-P-256-current" || len(res.PublishResults) != 1 {
+		t.Errorf("KeyID = %q, %d publish results, want ec-P-256-current, 1", res.KeyID, len(res.PublishResults))
+	}
+	want, err := output.PublishConfigHash(profile.Spec.Publish)
+	if err != nil {
+		t.Fatalf("PublishConfigHash() error = %v", err)
+	}
+	if writer.PublishHash != want {
+		t.Errorf("recorded publish hash = %q, want %q", writer.PublishHash, want)
+	}
+
+	// Unchanged configuration: nothing is published again
+	writer.Info.PublishHash = want
+	if _, err := m.EnsureKey(context.Background(), profile); err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if len(publisher.KeyIDs) != 1 {
+		t.Errorf("published %d times, want 1", len(publisher.KeyIDs))
+	}
+}
+
+func TestPublishOwner(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		graceMode    string
+		replaced     string
+		wantRetain   []string
+		wantWithdraw []string
+		wantPrevious string
+	}{
+		{
+			name:         "overlap retains every key in use",
+			graceMode:    openukrv1alpha1.GraceModeOverlap,
+			replaced:     "current",
+			wantRetain:   []string{"current", "previous", "next"},
+			wantPrevious: "current",
+		},
+		{
+			name:         "overlap keeps the previous key in its grace period",
+			graceMode:    openukrv1alpha1.GraceModeOverlap,
+			wantRetain:   []string{"current", "previous", "next"},
+			wantPrevious: "previous",
+		},
+		{
+			name:         "hard cutover withdraws the previous key",
+			graceMode:    openukrv1alpha1.GraceModeHard,
+			wantRetain:   []string{"current", "next"},
+			wantWithdraw: []string{"previous"},
+		},
+		{
+			name:         "hard cutover withdraws the replaced key",
+			graceMode:    openukrv1alpha1.GraceModeHard,
+			replaced:     "current",
+			wantRetain:   []string{"next"},
+			wantWithdraw: []string{"current", "previous"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			profile := &openukrv1alpha1.KeyProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: "profile", Namespace: "default"},
+				Spec:       openukrv1alpha1.KeyProfileSpec{Rotation: openukrv1alpha1.RotationPolicy{GraceMode: tt.graceMode}},
+				Status: openukrv1alpha1.KeyProfileStatus{
+					CurrentKeyID: "current", PreviousKeyID: "previous", NextKeyID: "next",
+				},
+			}
+			owner := publishOwner(profile, tt.replaced)
+			if owner.Namespace != "default" || owner.Name != "profile" {
+				t.Errorf("owner = %s, want default/profile", owner)
+			}
+			if !slices.Equal(owner.Retain, tt.wantRetain) {
+				t.Errorf("Retain = %v, want %v", owner.Retain, tt.wantRetain)
+			}
+			if !slices.Equal(owner.Withdraw, tt.wantWithdraw) {
+				t.Errorf("Withdraw = %v, want %v", owner.Withdraw, tt.wantWithdraw)
+			}
+			if owner.Previous != tt.wantPrevious {
+				t.Errorf("Previous = %q, want %q", owner.Previous, tt.wantPrevious)
+			}
+		})
+	}
+}
+
+func TestExpirePreviousBeforeGraceElapses(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Rotated a minute ago: the previous key is well within its one-hour grace period
+	profile := newTestProfile(now.Add(-time.Minute))
+
+	writer := &outputtest.FakeWriter{}
+	m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, &publishtest.FakePublisher{},
+		WithClock(clocktesting.NewFakePassiveClock(now)))
+	if err := m.ExpirePrevious(context.Background(), profile); err != nil {
+		t.Fatalf("ExpirePrevious() error = %v", err)
+	}
+	if writer.Drops != 1 {
+		t.Errorf("DropPrevious called %d times, want 1", writer.Drops)
+	}
+
+	observer := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, &publishtest.FakePublisher{}, WithObserveMode())
+	if err := observer.ExpirePrevious(context.Background(), profile); !errors.Is(err, ErrObserveMode) {
+		t.Errorf("ExpirePrevious() in observe mode error = %v, want ErrObserveMode", err)
+	}
+	if writer.Drops != 1 {
+		t.Errorf("DropPrevious called in observe mode")
+	}
+}
+
+func TestEnsureKeyFullFlow(t *testing.T) {
+	t.Parallel()
+
+	lastRotation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	profile := newTestProfile(lastRotation)
+	profile.UID = "profile-uid"
+	profile.Spec.Publish = []openukrv1alpha1.PublishTarget{
+		{Type: publish.TargetTypeHTTP, Config: map[string]string{"endpoint": "https://keys.example"}},
+		{Type: publish.TargetTypeFilesystem, Config: map[string]string{"path": "/var/lib/keys"}},
+	}
+
+	writer := &outputtest.FakeWriter{}
+	publisher := &publishtest.FakePublisher{
+		TypeErrs: map[string]error{publish.TargetTypeHTTP: errors.New("connection refused")},
+	}
+	clk := clocktesting.NewFakePassiveClock(lastRotation.Add(25 * time.Hour))
+	m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, publisher, WithClock(clk))
+
+	// 1. One target fails: nothing is persisted, per-target results are surfaced
+	res, err := m.EnsureKey(context.Background(), profile)
+	if err == nil {
+		t.Fatal("EnsureKey() succeeded, want publish error")
+	}
+	if res == nil || len(res.PublishResults) != 2 ||
+		res.PublishResults[0].Err == nil || res.PublishResults[1].Err != nil {
+		t.Fatalf("EnsureKey() partial result = %+v, want the http target failed only", res)
+	}
+	if writer.Writes != 0 {
+		t.Errorf("Write succeeded %d times after publish failure, want 0", writer.Writes)
+	}
+
+	// 2. The target recovers: the same key is published to every target and persisted
+	publisher.TypeErrs = nil
+	res, err = m.EnsureKey(context.Background(), profile)
+	if err != nil {
+		t.Fatalf("EnsureKey() retry error = %v", err)
+	}
+	if !res.Rotated || res.PreviousKeyID != "ec-P-256-current" {
+		t.Errorf("EnsureKey() = %+v, want rotation replacing the current key", res)
+	}
+	if len(publisher.KeyIDs) != 2 || publisher.KeyIDs[0] != publisher.KeyIDs[1] {
+		t.Errorf("published keyIDs = %v, want the same key retried", publisher.KeyIDs)
+	}
+	if len(publisher.Targets) != 4 {
+		t.Errorf("published to %d targets, want 4", len(publisher.Targets))
+	}
+	if !slices.Equal(writer.KeyIDs, []string{res.KeyID}) || writer.Writes != 1 {
+		t.Errorf("persisted keyIDs = %v, want [%s]", writer.KeyIDs, res.KeyID)
+	}
+
+	// 3. Not due yet: the Secret is verified, nothing is published or written
+	profile.Status.PreviousKeyID = profile.Status.CurrentKeyID
+	profile.Status.CurrentKeyID = res.KeyID
+	profile.Status.LastRotation = &metav1.Time{Time: res.RotationTime}
+	hash, err := output.PublishConfigHash(profile.Spec.Publish)
+	if err != nil {
+		t.Fatalf("PublishConfigHash() error = %v", err)
+	}
+	writer.Info = &output.SecretInfo{KeyID: res.KeyID, PublishHash: hash}
+	clk.SetTime(res.RotationTime.Add(30 * time.Minute))
+	if res, err = m.EnsureKey(context.Background(), profile); err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if res.Rotated || res.PreviousKeyID == "" {
+		t.Errorf("EnsureKey() = %+v, want no rotation within the grace period", res)
+	}
+	if writer.Verifies != 1 || writer.Drops != 0 || len(publisher.KeyIDs) != 2 {
+		t.Errorf("verifies = %d, drops = %d, published = %v, want a verify only",
+			writer.Verifies, writer.Drops, publisher.KeyIDs)
+	}
+	if !res.IntegrityVerified {
+		t.Error("IntegrityVerified = false, want the verified Secret reported")
+	}
+
+	// 4. The grace period ends: the previous key is dropped
+	clk.SetTime(clk.Now().Add(time.Hour))
+	if res, err = m.EnsureKey(context.Background(), profile); err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if writer.Drops != 1 || res.PreviousKeyID != "" {
+		t.Errorf("drops = %d, PreviousKeyID = %q, want the previous key dropped", writer.Drops, res.PreviousKeyID)
+	}
+}
+
+func TestEnsureKeyPublishesNextKeyBeforePromotion(t *testing.T) {
+	t.Parallel()
+
+	lastRotation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	profile := newTestProfile(lastRotation)
+	profile.Spec.Rotation.RotateBeforeExpiry = metav1.Duration{Duration: 2 * time.Hour}
+	profile.Spec.Rotation.PublishNextKey = true
+	profile.Spec.Publish = []openukrv1alpha1.PublishTarget{
+		{Type: publish.TargetTypeHTTP, Config: map[string]string{"endpoint": "https://keys.example"}},
+	}
+
+	writer := &outputtest.FakeWriter{}
+	publisher := &publishtest.FakePublisher{}
+	clk := clocktesting.NewFakePassiveClock(lastRotation.Add(21 * time.Hour))
+	m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, publisher, WithClock(clk))
+	ctx := context.Background()
+
+	// Before the lead: nothing is published, the controller is told when to return
+	res, err := m.EnsureKey(ctx, profile)
+	if err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if want := lastRotation.Add(22 * time.Hour); !res.NextKeyDue.Equal(want) {
+		t.Errorf("NextKeyDue = %v, want %v", res.NextKeyDue, want)
+	}
+	if want := lastRotation.Add(24 * time.Hour); !res.NextRotation.Equal(want) {
+		t.Errorf("NextRotation = %v, want the scheduled tick %v", res.NextRotation, want)
+	}
+	if len(publisher.KeyIDs) != 0 {
+		t.Fatalf("published %v before the lead", publisher.KeyIDs)
+	}
+
+	// Within the lead: the next key is staged and published, the current key stays
+	clk.SetTime(lastRotation.Add(22*time.Hour + 30*time.Minute))
+	if res, err = m.EnsureKey(ctx, profile); err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if res.Rotated || res.KeyID != "ec-P-256-current" {
+		t.Errorf("EnsureKey() = %+v, want the current key kept", res)
+	}
+	nextKeyID := res.NextKeyID
+	if nextKeyID == "" || res.NextFingerprint.IsZero() {
+		t.Fatalf("EnsureKey() = %+v, want a next key", res)
+	}
+	if !slices.Equal(publisher.KeyIDs, []string{nextKeyID}) || writer.NextKeyID() != nextKeyID {
+		t.Errorf("published %v, staged %q, want next key %s", publisher.KeyIDs, writer.NextKeyID(), nextKeyID)
+	}
+	if writer.Writes != 0 {
+		t.Errorf("output Secret written %d times before promotion", writer.Writes)
+	}
+
+	// Recorded in status: later reconciles do not publish again
+	profile.Status.NextKeyID = nextKeyID
+	profile.Status.NextKeyFingerprint = res.NextFingerprint.String()
+	if res, err = m.EnsureKey(ctx, profile); err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if res.NextKeyID != nextKeyID || len(publisher.KeyIDs) != 1 {
+		t.Errorf("NextKeyID = %q, published %v, want %s published once", res.NextKeyID, publisher.KeyIDs, nextKeyID)
+	}
+
+	// The lead does not bring rotation forward
+	clk.SetTime(lastRotation.Add(24*time.Hour - time.Second))
+	if res, err = m.EnsureKey(ctx, profile); err != nil || res.Rotated {
+		t.Fatalf("EnsureKey() = %+v, %v, want no rotation before the tick", res, err)
+	}
+
+	// On the tick the published key is promoted without publishing it again
+	clk.SetTime(lastRotation.Add(24*time.Hour + time.Second))
+	if res, err = m.EnsureKey(ctx, profile); err != nil {
+		t.Fatalf("EnsureKey() error = %v", err)
+	}
+	if !res.Rotated || res.KeyID != nextKeyID {
+		t.Errorf("EnsureKey() = %+v, want %s promoted", res, nextKeyID)
+	}
+	if res.NextKeyID != "" {
+		t.Errorf("NextKeyID = %q after promotion, want empty", res.NextKeyID)
+	}
+	if len(publisher.KeyIDs) != 1 {
+		t.Errorf("published %v, want the promoted key published once, before promotion", publisher.KeyIDs)
+	}
+	if !slices.Equal(writer.KeyIDs, []string{nextKeyID}) || writer.NextDrops != 1 || writer.NextKeyID() != "" {
+		t.Errorf("persisted %v, next drops %d, want %s persisted and unstaged", writer.KeyIDs, writer.NextDrops, nextKeyID)
+	}
+}
+
+func TestEnsureKeyRestagesNextKey(t *testing.T) {
+	t.Parallel()
+
+	lastRotation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newProfile := func() *openukrv1alpha1.KeyProfile {
+		profile := newTestProfile(lastRotation)
+		profile.Spec.Rotation.RotateBeforeExpiry = metav1.Duration{Duration: 2 * time.Hour}
+		profile.Spec.Rotation.PublishNextKey = true
+		profile.Spec.Publish = []openukrv1alpha1.PublishTarget{
+			{Type: publish.TargetTypeHTTP, Config: map[string]string{"endpoint": "https://keys.example"}},
+		}
+		return profile
+	}
+	// stage publishes a next key within the lead and records it in status
+	stage := func(t *testing.T, m RotationManager, profile *openukrv1alpha1.KeyProfile) string {
+		t.Helper()
+		res, err := m.EnsureKey(context.Background(), profile)
+		if err != nil {
+			t.Fatalf("EnsureKey() error = %v", err)
+		}
+		if res.NextKeyID == "" {
+			t.Fatalf("EnsureKey() = %+v, want a next key", res)
+		}
+		profile.Status.NextKeyID = res.NextKeyID
+		profile.Status.NextKeyFingerprint = res.NextFingerprint.String()
+		return res.NextKeyID
+	}
+
+	t.Run("key spec changed", func(t *testing.T) {
+		t.Parallel()
+		profile := newProfile()
+		writer := &outputtest.FakeWriter{}
+		publisher := &publishtest.FakePublisher{}
+		clk := clocktesting.NewFakePassiveClock(lastRotation.Add(23 * time.Hour))
+		m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, publisher, WithClock(clk))
+		staged := stage(t, m, profile)
+
+		// Same algorithm, other curve: the staged key must not be promoted
+		profile.Spec.KeySpec.Params = map[string]string{"curve": crypto.CurveP384}
+		next := stage(t, m, profile)
+		if next == staged || writer.NextDrops != 1 {
+			t.Errorf("next key %s (was %s), drops %d, want the staged key replaced", next, staged, writer.NextDrops)
+		}
+		if !slices.Equal(publisher.KeyIDs, []string{staged, next}) {
+			t.Errorf("published %v, want %s then %s", publisher.KeyIDs, staged, next)
+		}
+	})
+
+	t.Run("targets added", func(t *testing.T) {
+		t.Parallel()
+		profile := newProfile()
+		writer := &outputtest.FakeWriter{}
+		publisher := &publishtest.FakePublisher{}
+		clk := clocktesting.NewFakePassiveClock(lastRotation.Add(23 * time.Hour))
+		m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, publisher, WithClock(clk))
+		staged := stage(t, m, profile)
+
+		profile.Spec.Publish = append(profile.Spec.Publish, openukrv1alpha1.PublishTarget{
+			Type: publish.TargetTypeHTTP, Config: map[string]string{"endpoint": "https://more-keys.example"},
+		})
+		if next := stage(t, m, profile); next != staged {
+			t.Errorf("next key %s, want %s kept", next, staged)
+		}
+		if !slices.Equal(publisher.KeyIDs, []string{staged, staged}) {
+			t.Errorf("published %v, want %s published again to the new targets", publisher.KeyIDs, staged)
+		}
+
+		// On promotion the key is not published again
+		clk.SetTime(lastRotation.Add(24*time.Hour + time.Second))
+		res, err := m.EnsureKey(context.Background(), profile)
+		if err != nil {
+			t.Fatalf("EnsureKey() error = %v", err)
+		}
+		if res.KeyID != staged || len(publisher.KeyIDs) != 2 {
+			t.Errorf("EnsureKey() = %+v, published %v, want %s promoted", res, publisher.KeyIDs, staged)
+		}
+	})
+
+	t.Run("forced rotation", func(t *testing.T) {
+		t.Parallel()
+		profile := newProfile()
+		writer := &outputtest.FakeWriter{}
+		publisher := &publishtest.FakePublisher{}
+		clk := clocktesting.NewFakePassiveClock(lastRotation.Add(23 * time.Hour))
+		m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, publisher, WithClock(clk))
+		staged := stage(t, m, profile)
+
+		profile.Annotations = map[string]string{ForceRotateAnnotation: "2026-01-01T23:00:00Z"}
+		res, err := m.EnsureKey(context.Background(), profile)
+		if err != nil {
+			t.Fatalf("EnsureKey() error = %v", err)
+		}
+		if !res.Rotated || res.KeyID == staged {
+			t.Errorf("EnsureKey() = %+v, want a fresh key instead of %s", res, staged)
+		}
+		if writer.NextKeyID() != "" {
+			t.Errorf("staged key %s kept after a forced rotation", writer.NextKeyID())
+		}
+	})
+
+	t.Run("publishNextKey disabled", func(t *testing.T) {
+		t.Parallel()
+		profile := newProfile()
+		writer := &outputtest.FakeWriter{}
+		publisher := &publishtest.FakePublisher{}
+		clk := clocktesting.NewFakePassiveClock(lastRotation.Add(23 * time.Hour))
+		m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, publisher, WithClock(clk))
+		stage(t, m, profile)
+
+		profile.Spec.Rotation.PublishNextKey = false
+		res, err := m.EnsureKey(context.Background(), profile)
+		if err != nil {
+			t.Fatalf("EnsureKey() error = %v", err)
+		}
+		if res.NextKeyID != "" || writer.NextKeyID() != "" || writer.NextDrops != 1 {
+			t.Errorf("NextKeyID = %q, staged %q, drops %d, want the staged key dropped",
+				res.NextKeyID, writer.NextKeyID(), writer.NextDrops)
+		}
+	})
+}
+
+func TestEnsureKeyMigratesAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	lastRotation := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := lastRotation.Add(time.Hour)
+
+	for _, migrate := range []bool{false, true} {
+		t.Run(fmt.Sprintf("migrate=%v", migrate), func(t *testing.T) {
+			t.Parallel()
+			profile := newTestProfile(lastRotation)
+			profile.Spec.Rotation.MigrateOnAlgorithmChange = migrate
+			// The spec moved to EC while the Secret still holds the RSA key
+			profile.Status.CurrentKeyID = "rsa-3072-current"
+			writer := &outputtest.FakeWriter{
+				Info: &output.SecretInfo{KeyID: "rsa-3072-current", Algorithm: crypto.AlgorithmRSA},
+			}
+			m := NewManager(logr.Discard(), crypto.NewKeyGenerator(), writer, &publishtest.FakePublisher{},
+				WithClock(clocktesting.NewFakePassiveClock(now)))
+
+			res, err := m.EnsureKey(context.Background(), profile)
+			if err != nil {
+				t.Fatalf("EnsureKey() error = %v", err)
+			}
+			if res.Rotated != migrate {
+				t.Fatalf("EnsureKey() rotated = %v, want %v", res.Rotated, migrate)
+			}
+			if !migrate {
+				return
+			}
+			if !strings.HasPrefix(res.Reason, "algorithm migration: RSA → EC") {
+				t.Errorf("Reason = %q, want an algorithm migration", res.Reason)
+			}
+			if !strings.HasPrefix(res.KeyID, "ec-") {
+				t.Errorf("KeyID = %q, want a new EC key", res.KeyID)
+			}
+			// The RSA key stays valid for the full grace period
+			if res.PreviousKeyID != "rsa-3072-current" || writer.Drops != 0 {
+				t.Errorf("PreviousKeyID = %q, drops = %d, want the RSA key retained",
+					res.PreviousKeyID, writer.Drops)
+			}
+		})
+	}
+}
+
+func TestEnsureKeyThrottlesKeyGeneration(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := clocktesting.NewFakePassiveClock(now)
+	keygen := &countingKeyGenerator{KeyGenerator: crypto.NewKeyGenerator()}
+	m := NewManager(logr.Discard(), keygen, &outputtest.FakeWriter{}, &publishtest.FakePublisher{},
+		WithClock(clk), WithKeygenRateLimit(2))
+	newProfile := func(uid string) *openukrv1alpha1.KeyProfile {
+		profile := newTestProfile(now)
+		profile.UID = types.UID(uid)
+		profile.Status = openukrv1alpha1.KeyProfileStatus{}
+		return profile
+	}
+
+	// The first key is generated at once
+	if res, err := m.EnsureKey(context.Background(), newProfile("a")); err != nil || !res.Rotated {
+		t.Fatalf("EnsureKey() = %+v, %v, want a rotation", res, err)
+	}
+
+	// The next one is deferred until the bucket refills: 2 per minute
+	_, err := m.EnsureKey(context.Background(), newProfile("b"))
+	var throttled *KeygenThrottledError
+	if !errors.As(err, &throttled) {
+		t.Fatalf("EnsureKey() error = %v, want KeygenThrottledError", err)
+	}
+	if throttled.RetryAfter != 30*time.Second {
+		t.Errorf("RetryAfter = %s, want 30s", throttled.RetryAfter)
+	}
+	if keygen.calls != 1 {
+		t.Errorf("Generate called %d times while throttled, want 1", keygen.calls)
+	}
+
+	// A throttled attempt does not consume budget: the rotation proceeds once refilled
+	clk.SetTime(now.Add(throttled.RetryAfter))
+	if res, err := m.EnsureKey(context.Background(), newProfile("b")); err != nil || !res.Rotated {
+		t.Fatalf("EnsureKey() = %+v, %v, want the deferred rotation", res, err)
+	}
+	if keygen.calls != 2 {
+		t.Errorf("Generate called %d times, want 2", keygen.calls)
+	}
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	openukrv1alpha1 "github.com/openukr/openukr/api/v1alpha1"
+)
+
+// ScheduleKind returns the kind of schedule ref names. References written
+// before the kind was defaulted name a RotationSchedule.
+func ScheduleKind(ref *openukrv1alpha1.ScheduleReference) string {
+	if ref.Kind == "" {
+		return openukrv1alpha1.RotationScheduleKind
+	}
+	return ref.Kind
+}
+
+// ScheduleInterval reads the rotation interval of the schedule ref names for a
+// KeyProfile in namespace: a RotationSchedule in namespace or a
+// ClusterRotationSchedule.
+func ScheduleInterval(
+	ctx context.Context,
+	reader client.Reader,
+	namespace string,
+	ref *openukrv1alpha1.ScheduleReference,
+) (time.Duration, error) {
+	kind := ScheduleKind(ref)
+	var spec openukrv1alpha1.RotationScheduleSpec
+	switch kind {
+	case openukrv1alpha1.RotationScheduleKind:
+		var schedule openukrv1alpha1.RotationSchedule
+		if err := reader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref.Name}, &schedule); err != nil {
+			return 0, fmt.Errorf("failed to get %s %q: %w", kind, ref.Name, err)
+		}
+		spec = schedule.Spec
+	case openukrv1alpha1.ClusterRotationScheduleKind:
+		var schedule openukrv1alpha1.ClusterRotationSchedule
+		if err := reader.Get(ctx, client.ObjectKey{Name: ref.Name}, &schedule); err != nil {
+			return 0, fmt.Errorf("failed to get %s %q: %w", kind, ref.Name, err)
+		}
+		spec = schedule.Spec
+	default:
+		return 0, fmt.Errorf("unsupported schedule kind %q", kind)
+	}
+	return spec.Interval.Duration, nil
+}
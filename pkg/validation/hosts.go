@@ -0,0 +1,156 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// DefaultDeniedPublishHosts are the hosts and CIDRs HTTP publishers may not reach
+// by default: link-local ranges, which include the cloud instance metadata
+// endpoints (169.254.169.254, fd00:ec2::254), and the GCE metadata hostname.
+// Publishing there could leak credentials via SSRF. [SEC:S-3]
+var DefaultDeniedPublishHosts = []string{
+	"169.254.0.0/16",
+	"fe80::/10",
+	"fd00:ec2::254/128",
+	"metadata.google.internal",
+}
+
+// HostPolicy restricts the hosts HTTP publishers may connect to. Entries are
+// CIDRs, IP addresses, hostnames, or "*.domain" wildcards matching subdomains.
+// Denied entries always win. A non-empty allowlist admits only hosts whose name
+// or resolved address matches it. A nil *HostPolicy allows every host.
+type HostPolicy struct {
+	allowedNames    []string
+	allowedPrefixes []netip.Prefix
+	deniedNames     []string
+	deniedPrefixes  []netip.Prefix
+}
+
+// NewHostPolicy parses allowed and denied host entries.
+func NewHostPolicy(allowed, denied []string) (*HostPolicy, error) {
+	p := &HostPolicy{}
+	var err error
+	if p.allowedNames, p.allowedPrefixes, err = parseHostEntries(allowed); err != nil {
+		return nil, fmt.Errorf("invalid allowed host: %w", err)
+	}
+	if p.deniedNames, p.deniedPrefixes, err = parseHostEntries(denied); err != nil {
+		return nil, fmt.Errorf("invalid denied host: %w", err)
+	}
+	return p, nil
+}
+
+// DefaultHostPolicy returns the policy denying DefaultDeniedPublishHosts.
+func DefaultHostPolicy() *HostPolicy {
+	p, err := NewHostPolicy(nil, DefaultDeniedPublishHosts)
+	if err != nil {
+		panic(err) // DefaultDeniedPublishHosts is static and valid
+	}
+	return p
+}
+
+func parseHostEntries(entries []string) ([]string, []netip.Prefix, error) {
+	var names []string
+	var prefixes []netip.Prefix
+	for _, e := range entries {
+		e = strings.ToLower(strings.TrimSpace(e))
+		switch {
+		case e == "":
+			continue
+		case strings.Contains(e, "/"):
+			prefix, err := netip.ParsePrefix(e)
+			if err != nil {
+				return nil, nil, err
+			}
+			prefixes = append(prefixes, prefix.Masked())
+		default:
+			if addr, err := netip.ParseAddr(e); err == nil {
+				prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+				continue
+			}
+			names = append(names, strings.TrimSuffix(e, "."))
+		}
+	}
+	return names, prefixes, nil
+}
+
+// CheckHost checks an endpoint host at admission time, before it is resolved.
+// IP literals are checked against the CIDRs; hostnames only against the names,
+// as their addresses are checked by CheckDial when publishing.
+func (p *HostPolicy) CheckHost(host string) error {
+	if p == nil {
+		return nil
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return p.CheckDial(host, addr)
+	}
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if matchHostName(p.deniedNames, host) {
+		return fmt.Errorf("publish host %s is denied by cluster policy", host)
+	}
+	// A hostname may still resolve into an allowed CIDR
+	if len(p.allowedNames) > 0 && len(p.allowedPrefixes) == 0 && !matchHostName(p.allowedNames, host) {
+		return fmt.Errorf("publish host %s is not in the allowed hosts", host)
+	}
+	return nil
+}
+
+// CheckDial checks the address a connection to host is about to be made to.
+// Called for every connection, so a hostname re-resolving to a denied address
+// (DNS rebinding) is rejected.
+func (p *HostPolicy) CheckDial(host string, addr netip.Addr) error {
+	if p == nil {
+		return nil
+	}
+	addr = addr.Unmap().WithZone("")
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if matchHostName(p.deniedNames, host) || matchPrefix(p.deniedPrefixes, addr) {
+		return fmt.Errorf("publish host %s (%s) is denied by cluster policy", host, addr)
+	}
+	if len(p.allowedNames)+len(p.allowedPrefixes) == 0 {
+		return nil
+	}
+	if !matchHostName(p.allowedNames, host) && !matchPrefix(p.allowedPrefixes, addr) {
+		return fmt.Errorf("publish host %s (%s) is not in the allowed hosts", host, addr)
+	}
+	return nil
+}
+
+func matchHostName(names []string, host string) bool {
+	for _, name := range names {
+		if suffix, ok := strings.CutPrefix(name, "*"); ok {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+		} else if host == name {
+			return true
+		}
+	}
+	return false
+}
+
+func matchPrefix(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
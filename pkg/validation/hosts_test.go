@@ -0,0 +1,95 @@
+/*
+Copyright 2026 openUKR Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestHostPolicyCheckHost(t *testing.T) {
+	t.Parallel()
+
+	restricted, err := NewHostPolicy([]string{"*.example.com", "203.0.113.0/24"}, DefaultDeniedPublishHosts)
+	if err != nil {
+		t.Fatalf("NewHostPolicy() error = %v", err)
+	}
+	namesOnly, err := NewHostPolicy([]string{"keys.example.com"}, nil)
+	if err != nil {
+		t.Fatalf("NewHostPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		policy  *HostPolicy
+		host    string
+		wantErr bool
+	}{
+		{name: "metadata IPv4", policy: DefaultHostPolicy(), host: "169.254.169.254", wantErr: true},
+		{name: "metadata IPv6", policy: DefaultHostPolicy(), host: "fd00:ec2::254", wantErr: true},
+		{name: "IPv4-mapped metadata", policy: DefaultHostPolicy(), host: "::ffff:169.254.169.254", wantErr: true},
+		{name: "metadata hostname", policy: DefaultHostPolicy(), host: "Metadata.Google.Internal.", wantErr: true},
+		{name: "external host", policy: DefaultHostPolicy(), host: "keys.example.com"},
+		{name: "external IP", policy: DefaultHostPolicy(), host: "203.0.113.10"},
+		{name: "nil policy", policy: nil, host: "169.254.169.254"},
+		{name: "allowed wildcard", policy: restricted, host: "jwks.example.com"},
+		{name: "allowed CIDR", policy: restricted, host: "203.0.113.7"},
+		{name: "IP outside allowlist", policy: restricted, host: "198.51.100.1", wantErr: true},
+		{name: "denied despite allowlist", policy: restricted, host: "169.254.169.254", wantErr: true},
+		{name: "hostname outside name allowlist", policy: namesOnly, host: "evil.example.org", wantErr: true},
+		{name: "hostname in name allowlist", policy: namesOnly, host: "keys.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tt.policy.CheckHost(tt.host); (err != nil) != tt.wantErr {
+				t.Errorf("CheckHost(%q) error = %v, wantErr %v", tt.host, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHostPolicyCheckDial(t *testing.T) {
+	t.Parallel()
+
+	policy, err := NewHostPolicy([]string{"*.example.com"}, DefaultDeniedPublishHosts)
+	if err != nil {
+		t.Fatalf("NewHostPolicy() error = %v", err)
+	}
+
+	// An allowed name re-resolving to a metadata address (DNS rebinding)
+	if err := policy.CheckDial("keys.example.com", netip.MustParseAddr("169.254.169.254")); err == nil {
+		t.Error("CheckDial() allowed a rebinding to the metadata address")
+	}
+	if err := policy.CheckDial("keys.example.com", netip.MustParseAddr("fe80::1%eth0")); err == nil {
+		t.Error("CheckDial() allowed a zoned link-local address")
+	}
+	if err := policy.CheckDial("keys.example.com", netip.MustParseAddr("203.0.113.10")); err != nil {
+		t.Errorf("CheckDial() rejected an allowed external address: %v", err)
+	}
+	if err := policy.CheckDial("other.example.org", netip.MustParseAddr("203.0.113.10")); err == nil {
+		t.Error("CheckDial() allowed a host outside the allowlist")
+	}
+}
+
+func TestNewHostPolicyRejectsInvalidCIDR(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewHostPolicy(nil, []string{"10.0.0.0/33"}); err == nil {
+		t.Error("NewHostPolicy() accepted an invalid CIDR")
+	}
+}
@@ -21,7 +21,14 @@ package validation
 
 import (
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/openukr/openukr/pkg/crypto"
 )
 
 // MinGracePeriod is the minimum allowed grace period per NIST SP 800-57.
@@ -31,6 +38,47 @@ const MinGracePeriod = 5 * time.Minute
 // MinIntervalToGraceRatio is the minimum ratio of interval to grace period.
 const MinIntervalToGraceRatio = 3
 
+// DefaultMinInterval is the default cluster-wide floor for rotation intervals.
+// The ratio rule alone admits intervals as short as 15m; across many profiles
+// that turns key generation (RSA-4096 takes seconds) and publishing into a
+// constant load on the controller and on every publish target, while buying
+// little security over hourly rotation.
+const DefaultMinInterval = time.Hour
+
+// StalePauseThreshold is how far in the past a PauseUntil may lie before it is
+// reported as a likely mistake.
+const StalePauseThreshold = 24 * time.Hour
+
+// DefaultDeniedPublishPaths are the directory prefixes filesystem publish paths
+// may not point into by default, protecting system files on misconfigured
+// host-path mounts. [SEC:S-3]
+var DefaultDeniedPublishPaths = []string{"/etc", "/usr", "/bin", "/proc", "/sys"}
+
+// KeyVaultDNSSuffixes are the Azure Key Vault data plane DNS suffixes of the
+// public cloud and the sovereign clouds (China, US Government, Germany).
+var KeyVaultDNSSuffixes = []string{
+	"vault.azure.net",
+	"vault.azure.cn",
+	"vault.usgovcloudapi.net",
+	"vault.microsoftazure.de",
+}
+
+// keyVaultKeyPath matches the path of a Key Vault key URL, with optional version.
+var keyVaultKeyPath = regexp.MustCompile(`^/keys/[0-9a-zA-Z-]{1,127}(/[0-9a-fA-F]{32})?/?$`)
+
+// keystoreFormats are output formats that wrap the key in a keystore. Keystores
+// carry a certificate chain, so the key must be able to sign an X.509 certificate.
+var keystoreFormats = map[string]bool{
+	"jks":    true,
+	"pkcs12": true,
+}
+
+// keystoreAlgorithms are the algorithms with an X.509 certificate path.
+var keystoreAlgorithms = map[string]bool{
+	crypto.AlgorithmEC:  true,
+	crypto.AlgorithmRSA: true,
+}
+
 // ValidateNamespaceMatch ensures the serviceAccountRef namespace matches
 // the object namespace. This prevents cross-namespace key requests.
 // [SEC:S-1]
@@ -49,11 +97,8 @@ func ValidateNamespaceMatch(objectNamespace, specNamespace string) error {
 //   - GracePeriod must be >= MinGracePeriod (5m) [COMP:G-4]
 //   - Interval must be >= MinIntervalToGraceRatio × GracePeriod
 func ValidateRotationPolicy(interval, gracePeriod time.Duration) error {
-	if gracePeriod < MinGracePeriod {
-		return fmt.Errorf(
-			"gracePeriod %s is below minimum %s (NIST SP 800-57)",
-			gracePeriod, MinGracePeriod,
-		)
+	if err := ValidateGracePeriod(gracePeriod); err != nil {
+		return err
 	}
 
 	minInterval := time.Duration(MinIntervalToGraceRatio) * gracePeriod
@@ -66,3 +111,215 @@ func ValidateRotationPolicy(interval, gracePeriod time.Duration) error {
 
 	return nil
 }
+
+// ValidateGracePeriod checks the grace period alone, for profiles whose
+// interval is only known once their RotationSchedule is resolved.
+// GracePeriod must be >= MinGracePeriod (5m) [COMP:G-4]
+func ValidateGracePeriod(gracePeriod time.Duration) error {
+	if gracePeriod < MinGracePeriod {
+		return fmt.Errorf(
+			"gracePeriod %s is below minimum %s (NIST SP 800-57)",
+			gracePeriod, MinGracePeriod,
+		)
+	}
+	return nil
+}
+
+// ValidateRotateBeforeExpiry validates the rotation lead time.
+// It must not be negative and must be less than the interval, otherwise every
+// reconcile would find the key due.
+func ValidateRotateBeforeExpiry(interval, lead time.Duration) error {
+	if lead < 0 {
+		return fmt.Errorf("rotateBeforeExpiry %s must not be negative", lead)
+	}
+	if lead > 0 && lead >= interval {
+		return fmt.Errorf("rotateBeforeExpiry %s must be less than interval %s", lead, interval)
+	}
+	return nil
+}
+
+// CheckMinInterval returns a message if interval is below the cluster floor
+// minInterval. It complements the ratio rule of ValidateRotationPolicy, which
+// only relates the interval to the grace period. Returns "" if minInterval is
+// not positive or interval is at or above it.
+func CheckMinInterval(interval, minInterval time.Duration) string {
+	if minInterval <= 0 || interval >= minInterval {
+		return ""
+	}
+	return fmt.Sprintf("interval %s is below the cluster minimum %s", interval, minInterval)
+}
+
+// CheckPauseUntil returns a warning if pauseUntil lies more than StalePauseThreshold
+// before now. Such a pause has no effect and usually indicates a typo in the date.
+// Returns "" if pauseUntil is zero or recent enough.
+func CheckPauseUntil(pauseUntil, now time.Time) string {
+	if pauseUntil.IsZero() || now.Sub(pauseUntil) <= StalePauseThreshold {
+		return ""
+	}
+	return fmt.Sprintf(
+		"rotation.pauseUntil %s is more than %s in the past and has no effect",
+		pauseUntil.UTC().Format(time.RFC3339), StalePauseThreshold,
+	)
+}
+
+// ValidateFormatAlgorithm checks that the output format can hold a key of the
+// given algorithm. Format names match pkg/output; formats not listed here (PEM
+// layouts, custom renderers) accept any algorithm.
+// Rules:
+//   - jks, pkcs12 require an algorithm with a certificate path (EC on a NIST
+//     curve, RSA)
+//   - jwks requires RSA, Ed25519 or an EC curve with a JWK "crv" value
+func ValidateFormatAlgorithm(format, algorithm string, params map[string]string) error {
+	switch {
+	case keystoreFormats[format]:
+		if !keystoreAlgorithms[algorithm] {
+			return fmt.Errorf(
+				"output format %q requires a signing key with a certificate path (EC or RSA), got %q",
+				format, algorithm,
+			)
+		}
+		// crypto/x509 cannot sign a certificate with a non-NIST curve
+		if algorithm == crypto.AlgorithmEC && crypto.NonNISTCurve(strings.TrimSpace(params["curve"])) {
+			return fmt.Errorf("output format %q does not support EC curve %q", format, params["curve"])
+		}
+	case format == "jwks":
+		switch algorithm {
+		case crypto.AlgorithmRSA, crypto.AlgorithmEd25519:
+		case crypto.AlgorithmEC:
+			if curve := strings.TrimSpace(params["curve"]); crypto.JWKCurveName(curve) == "" {
+				return fmt.Errorf("output format %q does not support EC curve %q", format, curve)
+			}
+		default:
+			return fmt.Errorf("output format %q does not support algorithm %q", format, algorithm)
+		}
+	}
+	return nil
+}
+
+// ValidateKeyUse checks that a key of the given algorithm may be declared for
+// use (sig, enc or tls; empty means sig).
+// Rules:
+//   - non-NIST EC curves are only accepted for enc: ECDSA on them runs on the
+//     generic, variable-time elliptic.CurveParams arithmetic, so signatures may
+//     leak the private key through timing
+func ValidateKeyUse(use, algorithm string, params map[string]string) error {
+	if algorithm != crypto.AlgorithmEC || use == "enc" {
+		return nil
+	}
+	if curve := strings.TrimSpace(params["curve"]); crypto.NonNISTCurve(curve) {
+		if use == "" {
+			use = "sig"
+		}
+		return fmt.Errorf("EC curve %q has no constant-time signing implementation and requires keySpec.use enc, got %q",
+			curve, use)
+	}
+	return nil
+}
+
+// publishEncodingAlgorithms lists the algorithms supported by publish encodings
+// that do not accept every key. Encodings not listed accept any algorithm.
+var publishEncodingAlgorithms = map[string]map[string]bool{
+	crypto.EncodingECCompressed: {crypto.AlgorithmEC: true},
+	crypto.EncodingRawPublic:    {crypto.AlgorithmEC: true, crypto.AlgorithmRSA: true, crypto.AlgorithmEd25519: true},
+}
+
+// ValidatePublishEncoding checks that a publish output encoding can represent a
+// key of the given algorithm, e.g. ec-compressed is EC only.
+func ValidatePublishEncoding(encoding, algorithm string) error {
+	allowed, restricted := publishEncodingAlgorithms[encoding]
+	if restricted && !allowed[algorithm] {
+		return fmt.Errorf("publish encoding %q does not support algorithm %q", encoding, algorithm)
+	}
+	return nil
+}
+
+// ValidatePublishPath checks a filesystem publish path: it must be absolute, free
+// of "..", not the filesystem root, and not equal to or under any denied prefix.
+// [SEC:S-3]
+func ValidatePublishPath(path string, denied []string) error {
+	cleanPath := filepath.Clean(path)
+	if !filepath.IsAbs(cleanPath) {
+		return fmt.Errorf("publish path must be absolute, got: %s", path)
+	}
+	if strings.Contains(cleanPath, "..") {
+		return fmt.Errorf("publish path must not contain '..': %s", path)
+	}
+	if cleanPath == "/" {
+		return fmt.Errorf("publish path must not be the filesystem root")
+	}
+	for _, prefix := range denied {
+		prefix = filepath.Clean(prefix)
+		if prefix == "/" || cleanPath == prefix || strings.HasPrefix(cleanPath, prefix+"/") {
+			return fmt.Errorf("publish path %s is under denied directory %s", path, prefix)
+		}
+	}
+	return nil
+}
+
+// ValidatePublishFilename checks a filesystem publish filename override: it is
+// joined under the publish path, so it must be relative, already clean and free
+// of "..". Subdirectories (e.g. ".well-known/jwks.json") are allowed. [SEC:S-3]
+func ValidatePublishFilename(name string) error {
+	if name == "" || name == "." {
+		return fmt.Errorf("publish filename must not be empty")
+	}
+	if filepath.IsAbs(name) {
+		return fmt.Errorf("publish filename must be relative, got: %s", name)
+	}
+	if filepath.Clean(name) != name {
+		return fmt.Errorf("publish filename must be a clean path, got: %s", name)
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return fmt.Errorf("publish filename must not contain '..': %s", name)
+		}
+	}
+	return nil
+}
+
+// ParsePublishMaxBytes parses a filesystem publish quota ("maxBytes"): the
+// total size in bytes the publish path may hold. It must be a positive integer.
+func ParsePublishMaxBytes(value string) (int64, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("publish maxBytes must be a positive integer, got: %s", value)
+	}
+	return n, nil
+}
+
+// ValidateKeyVaultURL checks an Azure Key Vault URL: it must be an HTTPS URL
+// without credentials or port whose host is a vault under one of
+// KeyVaultDNSSuffixes, so Key Vault tokens are never sent to other hosts. It
+// returns the parsed URL and the matching suffix. [SEC:S-3]
+func ValidateKeyVaultURL(raw string) (*url.URL, string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid Key Vault URL %q: %w", raw, err)
+	}
+	if u.Scheme != "https" || u.User != nil || u.Port() != "" {
+		return nil, "", fmt.Errorf("invalid Key Vault URL %q: must be https://<vault>.<suffix> without credentials or port", raw)
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, suffix := range KeyVaultDNSSuffixes {
+		vault, ok := strings.CutSuffix(host, "."+suffix)
+		if ok && vault != "" && !strings.Contains(vault, ".") {
+			return u, suffix, nil
+		}
+	}
+	return nil, "", fmt.Errorf("invalid Key Vault URL %q: host is not a vault under %s", raw,
+		strings.Join(KeyVaultDNSSuffixes, ", "))
+}
+
+// ValidateKeyVaultKeyURL checks an Azure Key Vault key URL,
+// https://<vault>.<suffix>/keys/<name>[/<version>] (see ValidateKeyVaultURL).
+// It returns the URL without trailing slash and the matching suffix.
+func ValidateKeyVaultKeyURL(raw string) (string, string, error) {
+	u, suffix, err := ValidateKeyVaultURL(raw)
+	if err != nil {
+		return "", "", err
+	}
+	if !keyVaultKeyPath.MatchString(u.Path) || u.RawQuery != "" {
+		return "", "", fmt.Errorf("invalid Key Vault key URL %q: want https://<vault>.<suffix>/keys/<name>[/<version>]", raw)
+	}
+	return "https://" + u.Host + strings.TrimSuffix(u.Path, "/"), suffix, nil
+}
@@ -125,3 +125,348 @@ func TestValidateRotationPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateRotateBeforeExpiry(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		lead    time.Duration
+		wantErr bool
+	}{
+		{name: "unset", lead: 0},
+		{name: "less than interval", lead: 2 * time.Hour},
+		{name: "equal to interval", lead: 24 * time.Hour, wantErr: true},
+		{name: "greater than interval", lead: 48 * time.Hour, wantErr: true},
+		{name: "negative", lead: -time.Hour, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateRotateBeforeExpiry(24*time.Hour, tt.lead)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRotateBeforeExpiry(24h, %s) error = %v, wantErr %v", tt.lead, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckMinInterval(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		interval    time.Duration
+		minInterval time.Duration
+		wantMessage bool
+	}{
+		{name: "above floor", interval: 24 * time.Hour, minInterval: time.Hour},
+		{name: "at floor", interval: time.Hour, minInterval: time.Hour},
+		{name: "below floor", interval: 15 * time.Minute, minInterval: time.Hour, wantMessage: true},
+		{name: "floor disabled", interval: 15 * time.Minute, minInterval: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := CheckMinInterval(tt.interval, tt.minInterval)
+			if (got != "") != tt.wantMessage {
+				t.Errorf("CheckMinInterval(%s, %s) = %q, wantMessage %v", tt.interval, tt.minInterval, got, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestCheckPauseUntil(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		pauseUntil  time.Time
+		wantWarning bool
+	}{
+		{
+			name:        "unset",
+			pauseUntil:  time.Time{},
+			wantWarning: false,
+		},
+		{
+			name:        "in the future",
+			pauseUntil:  now.Add(6 * time.Hour),
+			wantWarning: false,
+		},
+		{
+			name:        "recently elapsed",
+			pauseUntil:  now.Add(-time.Hour),
+			wantWarning: false,
+		},
+		{
+			name:        "distant past",
+			pauseUntil:  now.AddDate(-1, 0, 0),
+			wantWarning: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := CheckPauseUntil(tt.pauseUntil, now)
+			if (got != "") != tt.wantWarning {
+				t.Errorf("CheckPauseUntil() = %q, wantWarning %v", got, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestValidateFormatAlgorithm(t *testing.T) {
+	t.Parallel()
+
+	ec := map[string]string{"curve": "P-256"}
+	tests := []struct {
+		name      string
+		format    string
+		algorithm string
+		params    map[string]string
+		wantErr   bool
+	}{
+		{name: "split-pem EC", format: "split-pem", algorithm: "EC", params: ec},
+		{name: "split-pem other algorithm", format: "split-pem", algorithm: "X25519"},
+		{name: "single-pem RSA", format: "single-pem", algorithm: "RSA"},
+		{name: "jks EC", format: "jks", algorithm: "EC", params: ec},
+		{name: "jks RSA", format: "jks", algorithm: "RSA"},
+		{name: "jks X25519", format: "jks", algorithm: "X25519", wantErr: true},
+		{name: "pkcs12 RSA", format: "pkcs12", algorithm: "RSA"},
+		{name: "pkcs12 X25519", format: "pkcs12", algorithm: "X25519", wantErr: true},
+		{name: "pkcs12 EC brainpool", format: "pkcs12", algorithm: "EC", params: map[string]string{"curve": "brainpoolP256r1"}, wantErr: true},
+		{name: "jwks EC P-384", format: "jwks", algorithm: "EC", params: map[string]string{"curve": "P-384"}},
+		{name: "jwks RSA", format: "jwks", algorithm: "RSA"},
+		{name: "jwks Ed25519", format: "jwks", algorithm: "Ed25519"},
+		{name: "jks Ed25519", format: "jks", algorithm: "Ed25519", wantErr: true},
+		{name: "jwks EC brainpool", format: "jwks", algorithm: "EC", params: map[string]string{"curve": "brainpoolP384r1"}},
+		{name: "jwks EC unsupported curve", format: "jwks", algorithm: "EC", params: map[string]string{"curve": "secp256k1"}, wantErr: true},
+		{name: "jwks X25519", format: "jwks", algorithm: "X25519", wantErr: true},
+		{name: "custom format", format: "custom", algorithm: "X25519"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateFormatAlgorithm(tt.format, tt.algorithm, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFormatAlgorithm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateKeyUse(t *testing.T) {
+	t.Parallel()
+
+	brainpool := map[string]string{"curve": "brainpoolP256r1"}
+	tests := []struct {
+		name      string
+		use       string
+		algorithm string
+		params    map[string]string
+		wantErr   bool
+	}{
+		{name: "EC P-256 sig", use: "sig", algorithm: "EC", params: map[string]string{"curve": "P-256"}},
+		{name: "RSA default use", algorithm: "RSA"},
+		{name: "brainpool enc", use: "enc", algorithm: "EC", params: brainpool},
+		{name: "brainpool sig", use: "sig", algorithm: "EC", params: brainpool, wantErr: true},
+		{name: "brainpool tls", use: "tls", algorithm: "EC", params: brainpool, wantErr: true},
+		{name: "brainpool default use", algorithm: "EC", params: map[string]string{"curve": "brainpoolP384r1"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateKeyUse(tt.use, tt.algorithm, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateKeyUse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePublishEncoding(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		encoding  string
+		algorithm string
+		wantErr   bool
+	}{
+		{encoding: "PEM", algorithm: "RSA"},
+		{encoding: "ec-compressed", algorithm: "EC"},
+		{encoding: "ec-compressed", algorithm: "RSA", wantErr: true},
+		{encoding: "raw-public", algorithm: "RSA"},
+		{encoding: "raw-public", algorithm: "EC"},
+		{encoding: "raw-public", algorithm: "Ed448", wantErr: true},
+		{encoding: "spki", algorithm: "Ed448"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.encoding+"/"+tt.algorithm, func(t *testing.T) {
+			t.Parallel()
+			err := ValidatePublishEncoding(tt.encoding, tt.algorithm)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePublishEncoding(%s, %s) error = %v, wantErr %v", tt.encoding, tt.algorithm, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePublishPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		path    string
+		denied  []string
+		wantErr bool
+	}{
+		{name: "valid: allowed directory", path: "/var/lib/openukr/keys", denied: DefaultDeniedPublishPaths},
+		{name: "valid: shared prefix is not a parent", path: "/etcd/keys", denied: DefaultDeniedPublishPaths},
+		{name: "valid: empty denylist", path: "/etc/keys", denied: nil},
+		{name: "invalid: denied directory", path: "/etc", denied: DefaultDeniedPublishPaths, wantErr: true},
+		{name: "invalid: under denied directory", path: "/usr/local/keys", denied: DefaultDeniedPublishPaths, wantErr: true},
+		{name: "invalid: unclean path into denied", path: "/var/../proc/keys", denied: DefaultDeniedPublishPaths, wantErr: true},
+		{name: "invalid: trailing slash on prefix", path: "/opt/keys", denied: []string{"/opt/"}, wantErr: true},
+		{name: "invalid: root", path: "/", denied: nil, wantErr: true},
+		{name: "invalid: relative", path: "keys", denied: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidatePublishPath(tt.path, tt.denied)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePublishPath(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePublishFilename(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		filename string
+		wantErr  bool
+	}{
+		{name: "valid: plain file", filename: "jwks.json"},
+		{name: "valid: subdirectory", filename: ".well-known/jwks.json"},
+		{name: "valid: dots in name", filename: "keys..json"},
+		{name: "invalid: empty", filename: "", wantErr: true},
+		{name: "invalid: dot", filename: ".", wantErr: true},
+		{name: "invalid: absolute", filename: "/etc/jwks.json", wantErr: true},
+		{name: "invalid: parent", filename: "../jwks.json", wantErr: true},
+		{name: "invalid: nested parent", filename: "a/../../jwks.json", wantErr: true},
+		{name: "invalid: unclean", filename: "a//jwks.json", wantErr: true},
+		{name: "invalid: trailing slash", filename: "keys/", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidatePublishFilename(tt.filename)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidatePublishFilename(%q) error = %v, wantErr %v", tt.filename, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParsePublishMaxBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "4096", want: 4096},
+		{value: "1", want: 1},
+		{value: "0", wantErr: true},
+		{value: "-1", wantErr: true},
+		{value: "4k", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParsePublishMaxBytes(tt.value)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParsePublishMaxBytes(%q) = %d, %v, want %d, wantErr %v", tt.value, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateKeyVaultURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		url        string
+		wantSuffix string
+		wantErr    bool
+	}{
+		{url: "https://my-vault.vault.azure.net", wantSuffix: "vault.azure.net"},
+		{url: "https://my-vault.vault.azure.net/keys/kek", wantSuffix: "vault.azure.net"},
+		{url: "https://My-Vault.Vault.Azure.Net", wantSuffix: "vault.azure.net"},
+		{url: "https://my-vault.vault.azure.cn", wantSuffix: "vault.azure.cn"},
+		{url: "https://my-vault.vault.usgovcloudapi.net", wantSuffix: "vault.usgovcloudapi.net"},
+		{url: "https://my-vault.vault.microsoftazure.de", wantSuffix: "vault.microsoftazure.de"},
+		{url: "http://my-vault.vault.azure.net", wantErr: true},
+		{url: "https://vault.azure.net", wantErr: true},
+		{url: "https://a.b.vault.azure.net", wantErr: true},
+		{url: "https://my-vault.vault.azure.net.evil.com", wantErr: true},
+		{url: "https://evilvault.azure.net", wantErr: true},
+		{url: "https://my-vault.vault.azure.net:8443", wantErr: true},
+		{url: "https://user@my-vault.vault.azure.net", wantErr: true},
+		{url: "https://169.254.169.254", wantErr: true},
+		{url: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			t.Parallel()
+			_, suffix, err := ValidateKeyVaultURL(tt.url)
+			if (err != nil) != tt.wantErr || suffix != tt.wantSuffix {
+				t.Errorf("ValidateKeyVaultURL(%q) = %q, %v, want %q, wantErr %v", tt.url, suffix, err, tt.wantSuffix, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateKeyVaultKeyURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{url: "https://my-vault.vault.azure.net/keys/kek", want: "https://my-vault.vault.azure.net/keys/kek"},
+		{url: "https://my-vault.vault.azure.net/keys/kek/", want: "https://my-vault.vault.azure.net/keys/kek"},
+		{
+			url:  "https://my-vault.vault.azure.cn/keys/kek/0123456789abcdef0123456789abcdef",
+			want: "https://my-vault.vault.azure.cn/keys/kek/0123456789abcdef0123456789abcdef",
+		},
+		{url: "https://my-vault.vault.azure.net", wantErr: true},
+		{url: "https://my-vault.vault.azure.net/secrets/kek", wantErr: true},
+		{url: "https://my-vault.vault.azure.net/keys/kek/v1", wantErr: true},
+		{url: "https://my-vault.vault.azure.net/keys/kek?api-version=1", wantErr: true},
+		{url: "https://keys.example.com/keys/kek", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			t.Parallel()
+			got, _, err := ValidateKeyVaultKeyURL(tt.url)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ValidateKeyVaultKeyURL(%q) = %q, %v, want %q, wantErr %v", tt.url, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}